@@ -0,0 +1,17 @@
+package fiqlparser
+
+import v2 "github.com/eisenwinter/fiql-parser/v2"
+
+// SelectorAliases maps the public selectors of a API to internal names, e.g. `user` to `user_id`,
+// so the storage layout is not exposed. It is applied while parsing (see WithSelectorAliases)
+// or to a parsed expression (see Rewrite). It is safe for concurrent use as long as its fields are not modified.
+type SelectorAliases = v2.SelectorAliases
+
+// WithSelectorAliases replaces the selectors (and field references) by their internal names at parse time.
+// The aliases are applied after the case policy and the selector pattern check and before the selector mapper.
+// In strict mode unmapped selectors fail with ErrorCodeUnknownSelector, the error unwraps to ErrUnknownSelector.
+//
+// Deprecated: use WithSelectorAliases of github.com/eisenwinter/fiql-parser/v2.
+func WithSelectorAliases(aliases SelectorAliases) Option {
+	return v2.WithSelectorAliases(aliases)
+}
@@ -0,0 +1,9 @@
+package fiqlparser
+
+import v2 "github.com/eisenwinter/fiql-parser/v2"
+
+// CELTranslator translates a expression to a CEL (Common Expression Language) expression,
+// e.g. `name==Jo*;age=gt=18` becomes `name.startsWith("Jo") && age > 18`,
+// so filters can be evaluated by CEL based policy engines.
+// The zero value maps every selector to the variable of the same name.
+type CELTranslator = v2.CELTranslator
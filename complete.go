@@ -0,0 +1,18 @@
+package fiqlparser
+
+import v2 "github.com/eisenwinter/fiql-parser/v2"
+
+// Completion is a candidate for the next token at the cursor, see Complete
+type Completion = v2.Completion
+
+// Complete returns the candidates for the next token at the cursor (a byte offset within input), e.g. for filter builder UIs:
+// the selectors of the schema and `(` where a constraint starts, the comparisons allowed for the selector after it,
+// `true`/`false` for boolean selectors and the opening delimiter of tuples for `=in=` as argument
+// and the logical operators (and `)` within a sub expression) after a complete constraint.
+// Partially typed selectors, comparators and arguments are completed by prefix. Only the input before
+// the cursor is considered, the candidates are sorted by class and text.
+//
+// Deprecated: use Complete of github.com/eisenwinter/fiql-parser/v2.
+func Complete(input string, cursor int, schema Schema) []Completion {
+	return v2.Complete(input, cursor, schema)
+}
@@ -0,0 +1,61 @@
+package fiqlparser
+
+import v2 "github.com/eisenwinter/fiql-parser/v2"
+
+// ErrInvalidNode is generated if a node can not be constructed from the supplied operands
+var ErrInvalidNode = v2.ErrInvalidNode
+
+// NewSelector creates a selector to be used as left operand of a comparison (see NewBinary)
+//
+// Deprecated: use NewSelector of github.com/eisenwinter/fiql-parser/v2.
+func NewSelector(name string) (Node, error) {
+	return v2.NewSelector(name)
+}
+
+// NewUnary creates a selector without constraint, e.g. `deleted` in `deleted;name==x`
+//
+// Deprecated: use NewUnary of github.com/eisenwinter/fiql-parser/v2.
+func NewUnary(name string) (Node, error) {
+	return v2.NewUnary(name)
+}
+
+// NewArgument creates a argument to be used as right operand of a comparison (see NewBinary),
+// the value is taken literally (`*` is no wildcard) and its value recommendation is detected as by the parser
+//
+// Deprecated: use NewArgument of github.com/eisenwinter/fiql-parser/v2.
+func NewArgument(value string) Node {
+	return v2.NewArgument(value)
+}
+
+// NewTuple creates a tuple argument (e.g. for =in=) using the DefaultTupleDelimiters
+//
+// Deprecated: use NewTuple of github.com/eisenwinter/fiql-parser/v2.
+func NewTuple(values ...string) (Node, error) {
+	return v2.NewTuple(values...)
+}
+
+// NewBinary creates a logical operation (op is OperatorAND or OperatorOR) or a comparison
+// (op is a ComparisonDefintion, left a selector and right a argument) without panicking on invalid operands,
+// unlike Add. Root expressions (e.g. parsed ones) are added as sub expressions, so parsed filters can be combined:
+//
+//	combined, err := NewBinary(string(OperatorAND), &userFilter, &tenantFilter)
+//
+// Deprecated: use NewBinary of github.com/eisenwinter/fiql-parser/v2.
+func NewBinary(op string, left Node, right Node) (Node, error) {
+	return v2.NewBinary(op, left, right)
+}
+
+// NewNegation negates the operand, which is enclosed in braces as by the parser.
+// The parser has to be configured WithNegation to parse the FIQL of the result again.
+//
+// Deprecated: use NewNegation of github.com/eisenwinter/fiql-parser/v2.
+func NewNegation(operand Node) (Node, error) {
+	return v2.NewNegation(operand)
+}
+
+// NewExpression creates a root expression from a constructed node, so it can be used like a parsed one
+//
+// Deprecated: use NewExpression of github.com/eisenwinter/fiql-parser/v2.
+func NewExpression(n Node) (Expression, error) {
+	return v2.NewExpression(n)
+}
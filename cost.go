@@ -0,0 +1,35 @@
+package fiqlparser
+
+import v2 "github.com/eisenwinter/fiql-parser/v2"
+
+// SelectorStatistics describes the storage backing a selector, it is used for cost estimation
+type SelectorStatistics = v2.SelectorStatistics
+
+// StatisticsProvider returns the statistics for a selector, ok is false for unknown selectors
+type StatisticsProvider = v2.StatisticsProvider
+
+// CostWarning describes a predicate which will most likely cause a full scan
+type CostWarning = v2.CostWarning
+
+// CostEstimate is the result of a cost estimation
+type CostEstimate = v2.CostEstimate
+
+// ErrTooExpensive is generated if the estimated cost exceeds the allowed maximum
+var ErrTooExpensive = v2.ErrTooExpensive
+
+// EstimateCost estimates the number of rows which have to be scanned to evaluate the expression.
+// It is a naive planner: a indexed predicate is considered free unless it can not use the index
+// (leading wildcard, negation), a AND can be resolved by its cheapest operand while
+// every operand of a OR has to be resolved. Unknown selectors are ignored.
+//
+// Deprecated: use EstimateCost of github.com/eisenwinter/fiql-parser/v2.
+func EstimateCost(e Expression, stats StatisticsProvider) CostEstimate {
+	return v2.EstimateCost(e, stats)
+}
+
+// CheckCost estimates the cost of the expression and returns ErrTooExpensive if more than maxRows have to be scanned
+//
+// Deprecated: use CheckCost of github.com/eisenwinter/fiql-parser/v2.
+func CheckCost(e Expression, stats StatisticsProvider, maxRows int64) (CostEstimate, error) {
+	return v2.CheckCost(e, stats, maxRows)
+}
@@ -0,0 +1,43 @@
+package fiqlparser
+
+import (
+	"time"
+
+	v2 "github.com/eisenwinter/fiql-parser/v2"
+)
+
+// DateTimeLayout parses datetime arguments in a additional format, see WithDateTimeLayouts.
+// Values without offset are in loc, ok is false if the value is not in the format.
+type DateTimeLayout = v2.DateTimeLayout
+
+// DateTimeLayoutOf returns a DateTimeLayout for a time.Parse layout, e.g. time.RFC3339Nano or `02.01.2006`
+//
+// Deprecated: use DateTimeLayoutOf of github.com/eisenwinter/fiql-parser/v2.
+func DateTimeLayoutOf(layout string) DateTimeLayout {
+	return v2.DateTimeLayoutOf(layout)
+}
+
+// DateTimeLayoutEpochSeconds accepts unix timestamps in seconds with 10 digits (2001-09-09 until 2286-11-20),
+// shorter numbers are kept as numbers
+var DateTimeLayoutEpochSeconds = v2.DateTimeLayoutEpochSeconds
+
+// DateTimeLayoutEpochMillis accepts unix timestamps in milliseconds with 13 digits (2001-09-09 until 2286-11-20),
+// shorter numbers are kept as numbers
+var DateTimeLayoutEpochMillis = v2.DateTimeLayoutEpochMillis
+
+// WithDateTimeLayouts registers additional datetime layouts, arguments in one of the layouts are
+// recommended as ValueRecommendationDateTime and converted by AsTime.
+// RFC3339 timestamps and coarse dates (e.g. `2023-01-15`) are always accepted, the layouts are tried in order.
+//
+// Deprecated: use WithDateTimeLayouts of github.com/eisenwinter/fiql-parser/v2.
+func WithDateTimeLayouts(layouts ...DateTimeLayout) Option {
+	return v2.WithDateTimeLayouts(layouts...)
+}
+
+// WithDefaultLocation sets the location of datetimes without offset, coarse dates (e.g. `2023-01-15`)
+// and values of registered layouts without time zone are interpreted in loc instead of UTC by AsTime
+//
+// Deprecated: use WithDefaultLocation of github.com/eisenwinter/fiql-parser/v2.
+func WithDefaultLocation(loc *time.Location) Option {
+	return v2.WithDefaultLocation(loc)
+}
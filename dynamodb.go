@@ -0,0 +1,16 @@
+package fiqlparser
+
+import v2 "github.com/eisenwinter/fiql-parser/v2"
+
+// DynamoDBTranslator translates a expression to a DynamoDB filter expression,
+// e.g. `name==Jo*;age=gt=18` becomes `begins_with(#n0, :v0) AND #n1 > :v1`.
+// Attribute names and values are always passed as placeholders,
+// so reserved words and user input never end up in the expression.
+// The zero value maps every selector to the attribute of the same name, dots separate nested attributes.
+type DynamoDBTranslator = v2.DynamoDBTranslator
+
+// DynamoDBFilter is a translated filter, the fields are passed as
+// FilterExpression, ExpressionAttributeNames and ExpressionAttributeValues.
+// The values are plain Go values (string, int64, float64 or bool) to be converted
+// by the attributevalue package of the AWS SDK, datetimes are RFC 3339 strings in UTC.
+type DynamoDBFilter = v2.DynamoDBFilter
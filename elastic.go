@@ -0,0 +1,17 @@
+package fiqlparser
+
+import v2 "github.com/eisenwinter/fiql-parser/v2"
+
+// ErrUnknownSelector is generated by translators if a selector is not mapped in strict mode
+var ErrUnknownSelector = v2.ErrUnknownSelector
+
+// SelectorResolver resolves a selector to a identifier of the target (e.g. a column or field),
+// resolvers should return a error wrapping ErrUnknownSelector for selectors they do not know
+type SelectorResolver = v2.SelectorResolver
+
+// ElasticsearchField describes how a selector is mapped to a elasticsearch field
+type ElasticsearchField = v2.ElasticsearchField
+
+// ElasticsearchTranslator translates a expression to a elasticsearch bool query,
+// the zero value maps every selector to the field of the same name.
+type ElasticsearchTranslator = v2.ElasticsearchTranslator
@@ -0,0 +1,68 @@
+package fiqlparser
+
+import v2 "github.com/eisenwinter/fiql-parser/v2"
+
+// ErrorCode classifies the reason a ParseError was generated
+type ErrorCode = v2.ErrorCode
+
+// ErrorCodeUnexpectedInput is used for input that can not be tokenized, e.g. an invalid comparison
+const ErrorCodeUnexpectedInput = v2.ErrorCodeUnexpectedInput
+
+// ErrorCodeUnexpectedEOF is used if the input ends in the middle of a token
+const ErrorCodeUnexpectedEOF = v2.ErrorCodeUnexpectedEOF
+
+// ErrorCodeUnexpectedToken is used if a valid token is found where another one was expected
+const ErrorCodeUnexpectedToken = v2.ErrorCodeUnexpectedToken
+
+// ErrorCodeInvalidValue is used if a argument does not satisfy the comparison (e.g. a string for =gt=)
+const ErrorCodeInvalidValue = v2.ErrorCodeInvalidValue
+
+// ErrorCodeUnclosedBrace is used if a sub expression is not closed
+const ErrorCodeUnclosedBrace = v2.ErrorCodeUnclosedBrace
+
+// ErrorCodeInvalidClosingBrace is used if a closing brace has no matching opening brace
+const ErrorCodeInvalidClosingBrace = v2.ErrorCodeInvalidClosingBrace
+
+// ErrorCodeDanglingOperator is used if a logical operator is missing an operand
+const ErrorCodeDanglingOperator = v2.ErrorCodeDanglingOperator
+
+// ErrorCodeDanglingComparator is used if a comparison is missing its selector
+const ErrorCodeDanglingComparator = v2.ErrorCodeDanglingComparator
+
+// ErrorCodeTrailingInput is used if input remains after a complete expression (e.g. `name==John Doe`)
+const ErrorCodeTrailingInput = v2.ErrorCodeTrailingInput
+
+// ErrorCodeInvalidSelector is used if a selector does not match the configured selector pattern
+const ErrorCodeInvalidSelector = v2.ErrorCodeInvalidSelector
+
+// ErrorCodeUnknownSelector is used if a selector is not mapped by the strict aliases configured by WithSelectorAliases
+const ErrorCodeUnknownSelector = v2.ErrorCodeUnknownSelector
+
+// ErrorCodeSpecViolation is used if the input deviates from the FIQL specification in strict mode
+const ErrorCodeSpecViolation = v2.ErrorCodeSpecViolation
+
+// ErrorCodeInputTooLong is used if the input exceeds the length configured by WithMaxLength
+const ErrorCodeInputTooLong = v2.ErrorCodeInputTooLong
+
+// ErrorCodeInvalidDirective is used for malformed directives in filter files
+const ErrorCodeInvalidDirective = v2.ErrorCodeInvalidDirective
+
+// ErrorCodeUnresolvedInclude is used if a included file can not be resolved
+const ErrorCodeUnresolvedInclude = v2.ErrorCodeUnresolvedInclude
+
+// ErrorCodeIncludeCycle is used if files include each other
+const ErrorCodeIncludeCycle = v2.ErrorCodeIncludeCycle
+
+// ErrUnexpectedInput is generated once unexpected input is met
+var ErrUnexpectedInput = v2.ErrUnexpectedInput
+
+// ErrUnexpectedEOF is generated if the input is suspected to be incomplete
+var ErrUnexpectedEOF = v2.ErrUnexpectedEOF
+
+// ParseError is returned for any syntax error found while parsing,
+// use errors.As to retrieve it
+type ParseError = v2.ParseError
+
+// ParseErrors are all syntax errors of a input ordered by position, see Parser.ParseAll.
+// It unwraps to the first error, so errors.As and errors.Is behave as for Parse.
+type ParseErrors = v2.ParseErrors
@@ -0,0 +1,42 @@
+package fiqlparser_test
+
+import (
+	"fmt"
+	"strings"
+
+	fq "github.com/eisenwinter/fiql-parser"
+)
+
+func describe(n fq.Node, depth int) {
+	indent := strings.Repeat("  ", depth)
+	switch node := n.(type) {
+	case *fq.Expression:
+		fmt.Printf("%sgroup\n", indent)
+	case fq.BinaryNode:
+		fmt.Printf("%s%s (logical: %t)\n", indent, node.Operator(), node.IsLogical())
+	case fq.ConstantNode:
+		prefix, suffix := node.Wildcards()
+		fmt.Printf("%s%s (selector: %t, wildcards: %t/%t, %s)\n", indent, node.Value(), node.IsSelector(), prefix, suffix, node.ValueRecommendation())
+	}
+	for _, c := range n.Children() {
+		describe(c, depth+1)
+	}
+}
+
+// This example demonstrates how to inspect the tree without a visitor
+func ExampleBinaryNode() {
+	tree, err := fq.Parse("title==foo*;age=gt=18")
+	if err != nil {
+		return
+	}
+	describe(&tree, 0)
+	// Output:
+	// group
+	//   AND (logical: true)
+	//     == (logical: false)
+	//       title (selector: true, wildcards: false/false, string)
+	//       foo (selector: false, wildcards: false/true, string)
+	//     > (logical: false)
+	//       age (selector: true, wildcards: false/false, string)
+	//       18 (selector: false, wildcards: false/false, number)
+}
@@ -0,0 +1,36 @@
+// Command books is a example HTTP service filtering a embedded SQLite database with FIQL.
+//
+// Run it with `go run ./books` from the examples directory and query it, e.g.
+//
+//	curl 'http://localhost:8080/books?filter=author==Tolkien;year=gt=1950'
+//	curl 'http://localhost:8080/books?filter=available==true&limit=2'
+//	curl 'http://localhost:8080/books/schema'
+//
+// The filter is validated against a schema, translated to SQL with parameters and
+// paginated by id, invalid filters are answered with a structured JSON error.
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"net/http"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+func main() {
+	addr := flag.String("addr", ":8080", "listen address")
+	flag.Parse()
+	db, err := openStore(context.Background())
+	if err != nil {
+		log.Fatalf("unable to open the database: %v", err)
+	}
+	defer db.Close()
+	srv := &http.Server{Addr: *addr, Handler: (&server{db: db}).routes(), ReadHeaderTimeout: 5 * time.Second}
+	log.Printf("listening on %s", *addr)
+	if err := srv.ListenAndServe(); err != nil {
+		log.Fatal(err)
+	}
+}
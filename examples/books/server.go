@@ -0,0 +1,133 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	fiqlparser "github.com/eisenwinter/fiql-parser/v2"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// server answers `GET /books?filter=<fiql>&limit=<n>&cursor=<next_cursor>`
+type server struct {
+	db *sql.DB
+}
+
+// page is the response of the books endpoint, NextCursor is empty on the last page
+type page struct {
+	Items      []book `json:"items"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// apiError is the structured error response, parse errors are positioned at the start of the offending token
+// within the filter, columns start at 1 like those of the issues
+type apiError struct {
+	Code     string       `json:"code"`
+	Message  string       `json:"message"`
+	Line     int          `json:"line,omitempty"`
+	Column   int          `json:"column,omitempty"`
+	Token    string       `json:"token,omitempty"`
+	Expected []string     `json:"expected,omitempty"`
+	Issues   []issueError `json:"issues,omitempty"`
+}
+
+// issueError is a violation of the schema
+type issueError struct {
+	Selector string `json:"selector"`
+	Line     int    `json:"line"`
+	Column   int    `json:"column"`
+	Message  string `json:"message"`
+}
+
+func (s *server) routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/books", s.books)
+	mux.HandleFunc("/books/schema", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, schema.OpenAPIParameter("filter"))
+	})
+	return mux
+}
+
+func (s *server) books(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, apiError{Code: "MethodNotAllowed", Message: "only GET is supported"})
+		return
+	}
+	limit, after, err := pagination(r)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, apiError{Code: "InvalidPagination", Message: err.Error()})
+		return
+	}
+	e, err := fiqlparser.ParseRequest(r, "filter")
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, parseError(err))
+		return
+	}
+	res := schema.ValidateDetailed(e, fiqlparser.ValidationOptions{Coerce: true})
+	if !res.Valid() {
+		apiErr := apiError{Code: "InvalidFilter", Message: res.Err().Error(), Issues: make([]issueError, 0, len(res.Errors))}
+		for _, issue := range res.Errors {
+			apiErr.Issues = append(apiErr.Issues, issueError{Selector: issue.Selector, Line: issue.Position.Line,
+				Column: issue.Position.Column + 1, Message: issue.Err.Error()})
+		}
+		writeJSON(w, http.StatusUnprocessableEntity, apiErr)
+		return
+	}
+	// one more row than requested tells whether there is a next page
+	books, err := findBooks(r.Context(), s.db, res.Expression, after, limit+1)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, apiError{Code: "Internal", Message: err.Error()})
+		return
+	}
+	p := page{Items: books}
+	if len(books) > limit {
+		p.Items = books[:limit]
+		p.NextCursor = strconv.FormatInt(books[limit-1].ID, 10)
+	}
+	writeJSON(w, http.StatusOK, p)
+}
+
+// pagination returns the page size and the id after which the page starts
+func pagination(r *http.Request) (int, int64, error) {
+	limit := defaultPageSize
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > maxPageSize {
+			return 0, 0, errors.New("limit must be a number between 1 and 100")
+		}
+		limit = n
+	}
+	var after int64
+	if v := r.URL.Query().Get("cursor"); v != "" {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || n < 0 {
+			return 0, 0, errors.New("invalid cursor")
+		}
+		after = n
+	}
+	return limit, after, nil
+}
+
+// parseError converts a error of ParseRequest to the structured response
+func parseError(err error) apiError {
+	var perr *fiqlparser.ParseError
+	if !errors.As(err, &perr) {
+		return apiError{Code: "InvalidFilter", Message: err.Error()}
+	}
+	pos := perr.Position()
+	return apiError{Code: string(perr.Code), Message: perr.Error(), Line: pos.Line, Column: pos.Column + 1,
+		Token: perr.Token, Expected: perr.Expected}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestServer(t *testing.T) *httptest.Server {
+	db, err := openStore(context.Background())
+	require.NoError(t, err)
+	ts := httptest.NewServer((&server{db: db}).routes())
+	t.Cleanup(func() {
+		ts.Close()
+		db.Close()
+	})
+	return ts
+}
+
+func get(t *testing.T, ts *httptest.Server, query url.Values, v interface{}) int {
+	res, err := http.Get(ts.URL + "/books?" + query.Encode())
+	require.NoError(t, err)
+	defer res.Body.Close()
+	assert.Equal(t, "application/json", res.Header.Get("Content-Type"))
+	require.NoError(t, json.NewDecoder(res.Body).Decode(v))
+	return res.StatusCode
+}
+
+func titles(p page) []string {
+	res := make([]string, 0, len(p.Items))
+	for _, b := range p.Items {
+		res = append(res, b.Title)
+	}
+	return res
+}
+
+func TestBooks(t *testing.T) {
+	ts := newTestServer(t)
+	var values = []struct {
+		filter   string
+		expected []string
+	}{
+		{filter: "", expected: []string{"The Hobbit", "The Lord of the Rings", "Dune", "Neuromancer",
+			"The Left Hand of Darkness", "The Dispossessed", "Foundation"}},
+		{filter: "author==Tolkien;year=gt=1950", expected: []string{"The Lord of the Rings"}},
+		{filter: "title==The*;available==true", expected: []string{"The Hobbit", "The Lord of the Rings", "The Left Hand of Darkness"}},
+		{filter: "author=in=[Herbert+Gibson]", expected: []string{"Dune", "Neuromancer"}},
+		{filter: "year=bt=1960..1980,price=lt=10", expected: []string{"Dune", "The Left Hand of Darkness", "The Dispossessed", "Foundation"}},
+		// the argument is coerced to a boolean
+		{filter: "available==FALSE", expected: []string{"Dune", "The Dispossessed"}},
+		{filter: "author==Pratchett", expected: []string{}},
+	}
+	for _, v := range values {
+		var p page
+		status := get(t, ts, url.Values{"filter": {v.filter}}, &p)
+		assert.Equal(t, http.StatusOK, status, v.filter)
+		assert.Equal(t, v.expected, titles(p), v.filter)
+		assert.Empty(t, p.NextCursor, v.filter)
+	}
+}
+
+func TestBooksPagination(t *testing.T) {
+	ts := newTestServer(t)
+	pages := make([][]string, 0)
+	cursor := ""
+	for i := 0; i < 5; i++ {
+		var p page
+		status := get(t, ts, url.Values{"filter": {"available==true"}, "limit": {"2"}, "cursor": {cursor}}, &p)
+		require.Equal(t, http.StatusOK, status)
+		pages = append(pages, titles(p))
+		if cursor = p.NextCursor; cursor == "" {
+			break
+		}
+	}
+	assert.Equal(t, [][]string{
+		{"The Hobbit", "The Lord of the Rings"},
+		{"Neuromancer", "The Left Hand of Darkness"},
+		{"Foundation"},
+	}, pages)
+
+	var e apiError
+	assert.Equal(t, http.StatusBadRequest, get(t, ts, url.Values{"limit": {"1000"}}, &e))
+	assert.Equal(t, "InvalidPagination", e.Code)
+}
+
+func TestBooksErrors(t *testing.T) {
+	ts := newTestServer(t)
+
+	var e apiError
+	assert.Equal(t, http.StatusBadRequest, get(t, ts, url.Values{"filter": {"author==Tolkien;year=gt="}}, &e))
+	assert.Equal(t, "UnexpectedToken", e.Code)
+	assert.Equal(t, 1, e.Line)
+	assert.Equal(t, 25, e.Column)
+	assert.Equal(t, []string{"value"}, e.Expected)
+
+	e = apiError{}
+	assert.Equal(t, http.StatusUnprocessableEntity, get(t, ts, url.Values{"filter": {"isbn==123;title=lt=5;year==abc"}}, &e))
+	assert.Equal(t, "InvalidFilter", e.Code)
+	if assert.Len(t, e.Issues, 3) {
+		assert.Equal(t, issueError{Selector: "isbn", Line: 1, Column: 1, Message: "unknown selector `isbn`"}, e.Issues[0])
+		assert.Equal(t, "title", e.Issues[1].Selector)
+		assert.Equal(t, "year", e.Issues[2].Selector)
+	}
+}
+
+func TestBooksSchema(t *testing.T) {
+	ts := newTestServer(t)
+	res, err := http.Get(ts.URL + "/books/schema")
+	require.NoError(t, err)
+	defer res.Body.Close()
+	var param map[string]interface{}
+	require.NoError(t, json.NewDecoder(res.Body).Decode(&param))
+	assert.Equal(t, "filter", param["name"])
+	assert.Equal(t, "query", param["in"])
+}
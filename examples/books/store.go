@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	fiqlparser "github.com/eisenwinter/fiql-parser/v2"
+)
+
+// book is a row of the books table
+type book struct {
+	ID        int64   `json:"id"`
+	Title     string  `json:"title"`
+	Author    string  `json:"author"`
+	Year      int     `json:"year"`
+	Price     float64 `json:"price"`
+	Available bool    `json:"available"`
+}
+
+// schema describes the selectors of the filter parameter, it is used for validation and the documentation
+var schema = fiqlparser.Schema{Selectors: []fiqlparser.SelectorSchema{
+	{Name: "title", Description: "title of the book, wildcards are supported",
+		Comparisons: []fiqlparser.Comparison{fiqlparser.ComparisonEq, fiqlparser.ComparisonNeq}},
+	{Name: "author", Description: "name of the author",
+		Comparisons: []fiqlparser.Comparison{fiqlparser.ComparisonEq, fiqlparser.ComparisonNeq, fiqlparser.ComparisonIn}},
+	{Name: "year", Type: fiqlparser.ValueRecommendationNumber, Description: "year of publication"},
+	{Name: "price", Type: fiqlparser.ValueRecommendationNumber, Description: "price in EUR"},
+	{Name: "available", Type: fiqlparser.ValueRecommendationBoolean, Description: "whether the book is in stock", Unary: true,
+		Comparisons: []fiqlparser.Comparison{fiqlparser.ComparisonEq}},
+}}
+
+// translator maps the selectors to the columns of the books table
+var translator = &fiqlparser.SQLTranslator{
+	Columns: map[string]string{
+		"title":     "title",
+		"author":    "author",
+		"year":      "year",
+		"price":     "price",
+		"available": "available",
+	},
+	Placeholder: fiqlparser.SQLPlaceholderQuestion,
+}
+
+const createBooks = `CREATE TABLE books (
+	id INTEGER PRIMARY KEY,
+	title TEXT NOT NULL,
+	author TEXT NOT NULL,
+	year INTEGER NOT NULL,
+	price REAL NOT NULL,
+	available INTEGER NOT NULL
+)`
+
+var seedBooks = []book{
+	{Title: "The Hobbit", Author: "Tolkien", Year: 1937, Price: 12.5, Available: true},
+	{Title: "The Lord of the Rings", Author: "Tolkien", Year: 1954, Price: 29.9, Available: true},
+	{Title: "Dune", Author: "Herbert", Year: 1965, Price: 15, Available: false},
+	{Title: "Neuromancer", Author: "Gibson", Year: 1984, Price: 11.2, Available: true},
+	{Title: "The Left Hand of Darkness", Author: "Le Guin", Year: 1969, Price: 13.4, Available: true},
+	{Title: "The Dispossessed", Author: "Le Guin", Year: 1974, Price: 14.1, Available: false},
+	{Title: "Foundation", Author: "Asimov", Year: 1951, Price: 9.99, Available: true},
+}
+
+// openStore opens the embedded in-memory database and creates the seeded books table
+func openStore(ctx context.Context) (*sql.DB, error) {
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		return nil, err
+	}
+	// every connection would open its own in-memory database
+	db.SetMaxOpenConns(1)
+	if _, err := db.ExecContext(ctx, createBooks); err != nil {
+		db.Close()
+		return nil, err
+	}
+	for _, b := range seedBooks {
+		_, err := db.ExecContext(ctx, "INSERT INTO books (title, author, year, price, available) VALUES (?, ?, ?, ?, ?)",
+			b.Title, b.Author, b.Year, b.Price, b.Available)
+		if err != nil {
+			db.Close()
+			return nil, err
+		}
+	}
+	return db, nil
+}
+
+// findBooks returns up to limit books matching the expression with a id greater than after, ordered by id
+func findBooks(ctx context.Context, db *sql.DB, e fiqlparser.Expression, after int64, limit int) ([]book, error) {
+	cond, args, err := translator.Translate(e)
+	if err != nil {
+		return nil, err
+	}
+	var q strings.Builder
+	q.WriteString("SELECT id, title, author, year, price, available FROM books WHERE id > ?")
+	if cond != "" {
+		q.WriteString(" AND (")
+		q.WriteString(cond)
+		q.WriteString(")")
+	}
+	q.WriteString(" ORDER BY id LIMIT ?")
+	params := append(append([]interface{}{after}, args...), limit)
+	rows, err := db.QueryContext(ctx, q.String(), params...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	books := make([]book, 0, limit)
+	for rows.Next() {
+		var b book
+		if err := rows.Scan(&b.ID, &b.Title, &b.Author, &b.Year, &b.Price, &b.Available); err != nil {
+			return nil, err
+		}
+		books = append(books, b)
+	}
+	return books, rows.Err()
+}
@@ -0,0 +1,260 @@
+// Package exampletest validates the FIQL examples of documentation and Go example files,
+// so published filters do not rot as the grammar or the accepted selectors evolve.
+//
+// In Markdown every non-empty line of a code fence with the info string `fiql` is a example:
+//
+//	```fiql
+//	name==John;age=gt=18
+//	```
+//
+// In Go files the string literals passed as first argument to functions or methods named `Parse`
+// (e.g. `fiqlparser.Parse("name==John")` or `p.Parse(...)`) are examples.
+// Failures are positioned within the file, use Run to report them from a test.
+package exampletest
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	fiqlparser "github.com/eisenwinter/fiql-parser"
+)
+
+// Failure is a example which does not parse or does not satisfy the schema
+type Failure struct {
+	File string
+	// Line and Column (in bytes, starting at 1) locate the error within the file if it is a parse error,
+	// the start of the example otherwise
+	Line   int
+	Column int
+	// Example is the FIQL of the example
+	Example string
+	Err     error
+}
+
+// String returns the failure in the form `<file>:<line>:<column>: <example>: <error>`
+func (f Failure) String() string {
+	return fmt.Sprintf("%s:%d:%d: `%s`: %s", f.File, f.Line, f.Column, f.Example, f.Err)
+}
+
+// Checker validates examples, the zero value parses them with a default parser
+type Checker struct {
+	// Parser parses the examples, a parser without options is used if nil
+	Parser *fiqlparser.Parser
+	// Schema validates the parsed examples if set
+	Schema *fiqlparser.Schema
+	// Languages are the info strings of the checked code fences, defaults to `fiql`
+	Languages []string
+	// Funcs are the names of the functions and methods whose first argument is checked in Go files,
+	// defaults to `Parse`
+	Funcs []string
+}
+
+// example is a FIQL snippet found at line and column of a file
+type example struct {
+	fiql   string
+	line   int
+	column int
+}
+
+// CheckMarkdown validates the examples within the code fences of the Markdown document src
+func (c Checker) CheckMarkdown(file string, src []byte) []Failure {
+	return c.check(file, markdownExamples(string(src), c.languages()))
+}
+
+// CheckGo validates the examples within the Go source src, the error is set if src is no valid Go file
+func (c Checker) CheckGo(file string, src []byte) ([]Failure, error) {
+	examples, err := goExamples(file, src, c.funcs())
+	if err != nil {
+		return nil, err
+	}
+	return c.check(file, examples), nil
+}
+
+// CheckFiles validates the examples of the Markdown (`.md`) and Go (`.go`) files,
+// other files are rejected
+func (c Checker) CheckFiles(files ...string) ([]Failure, error) {
+	failures := make([]Failure, 0)
+	for _, file := range files {
+		src, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		switch strings.ToLower(filepath.Ext(file)) {
+		case ".md", ".markdown":
+			failures = append(failures, c.CheckMarkdown(file, src)...)
+		case ".go":
+			f, err := c.CheckGo(file, src)
+			if err != nil {
+				return nil, err
+			}
+			failures = append(failures, f...)
+		default:
+			return nil, fmt.Errorf("unsupported example file `%s`", file)
+		}
+	}
+	return failures, nil
+}
+
+// Run checks the files matching the glob patterns and reports every failure as test error
+func Run(t *testing.T, c Checker, patterns ...string) {
+	t.Helper()
+	files := make([]string, 0)
+	for _, pattern := range patterns {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			t.Fatalf("invalid pattern `%s`: %s", pattern, err)
+		}
+		files = append(files, matches...)
+	}
+	if len(files) == 0 {
+		t.Fatalf("no files match %s", strings.Join(patterns, ", "))
+	}
+	failures, err := c.CheckFiles(files...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range failures {
+		t.Error(f.String())
+	}
+}
+
+func (c Checker) languages() []string {
+	if len(c.Languages) == 0 {
+		return []string{"fiql"}
+	}
+	return c.Languages
+}
+
+func (c Checker) funcs() []string {
+	if len(c.Funcs) == 0 {
+		return []string{"Parse"}
+	}
+	return c.Funcs
+}
+
+func (c Checker) check(file string, examples []example) []Failure {
+	p := c.Parser
+	if p == nil {
+		p = fiqlparser.NewParser()
+	}
+	failures := make([]Failure, 0)
+	for _, ex := range examples {
+		e, err := p.Parse(ex.fiql)
+		if err == nil && c.Schema != nil {
+			err = c.Schema.Validate(e)
+		}
+		if err == nil {
+			continue
+		}
+		f := Failure{File: file, Line: ex.line, Column: ex.column, Example: ex.fiql, Err: err}
+		var perr *fiqlparser.ParseError
+		if errors.As(err, &perr) {
+			f.Line, f.Column = errorPosition(ex, perr)
+		}
+		failures = append(failures, f)
+	}
+	return failures
+}
+
+// errorPosition translates the start of the offending token within the example to the file
+func errorPosition(ex example, perr *fiqlparser.ParseError) (int, int) {
+	pos := perr.Position()
+	offset := pos.ByteOffset
+	if offset > len(ex.fiql) {
+		offset = len(ex.fiql)
+	}
+	if pos.Line > 1 {
+		return ex.line + pos.Line - 1, offset - strings.LastIndexByte(ex.fiql[:offset], '\n')
+	}
+	return ex.line, ex.column + offset
+}
+
+// markdownExamples returns the lines of the fenced code blocks with one of the languages
+func markdownExamples(src string, languages []string) []example {
+	examples := make([]example, 0)
+	fence := ""
+	checked := false
+	for i, line := range strings.Split(src, "\n") {
+		line = strings.TrimRight(line, "\r")
+		trimmed := strings.TrimLeft(line, " \t")
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) && strings.TrimLeft(trimmed, fence[:1]) == "" {
+				fence = ""
+				continue
+			}
+			if checked && trimmed != "" {
+				examples = append(examples, example{fiql: strings.TrimRight(trimmed, " \t"), line: i + 1, column: len(line) - len(trimmed) + 1})
+			}
+			continue
+		}
+		if marker := fenceMarker(trimmed); marker != "" {
+			fence = marker
+			info := strings.Fields(trimmed[len(marker):])
+			checked = len(info) > 0 && contains(languages, info[0])
+		}
+	}
+	return examples
+}
+
+// fenceMarker returns the backticks or tildes opening a code fence, empty if the line opens none
+func fenceMarker(line string) string {
+	for _, c := range []string{"`", "~"} {
+		n := len(line) - len(strings.TrimLeft(line, c))
+		if n >= 3 {
+			return line[:n]
+		}
+	}
+	return ""
+}
+
+func contains(values []string, v string) bool {
+	for _, c := range values {
+		if c == v {
+			return true
+		}
+	}
+	return false
+}
+
+// goExamples returns the string literals passed as first argument to the funcs
+func goExamples(file string, src []byte, funcs []string) ([]example, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, file, src, 0)
+	if err != nil {
+		return nil, err
+	}
+	examples := make([]example, 0)
+	ast.Inspect(f, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok || len(call.Args) == 0 {
+			return true
+		}
+		var name string
+		switch fn := call.Fun.(type) {
+		case *ast.Ident:
+			name = fn.Name
+		case *ast.SelectorExpr:
+			name = fn.Sel.Name
+		}
+		lit, ok := call.Args[0].(*ast.BasicLit)
+		if !ok || lit.Kind != token.STRING || !contains(funcs, name) {
+			return true
+		}
+		fiql, err := strconv.Unquote(lit.Value)
+		if err != nil {
+			return true
+		}
+		pos := fset.Position(lit.Pos())
+		examples = append(examples, example{fiql: fiql, line: pos.Line, column: pos.Column + 1})
+		return true
+	})
+	return examples, nil
+}
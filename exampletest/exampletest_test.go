@@ -0,0 +1,81 @@
+package exampletest
+
+import (
+	"testing"
+
+	fiqlparser "github.com/eisenwinter/fiql-parser"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckMarkdown(t *testing.T) {
+	tests := []struct {
+		name     string
+		src      string
+		expected []string
+	}{
+		{"valid", "```fiql\nname==John\n```\n", []string{}},
+		{"other language", "```go\nname==\n```\n", []string{}},
+		{"unclosed fence", "```fiql\nname==John\n", []string{}},
+		{"error within line", "text\n\n```fiql\nname==John\n  name==John;\n```\n", []string{"doc.md:5:14: `name==John;`: ln:1:11 dangling operator"}},
+		{"longer fence", "````fiql\n```\nname==\n````\nname==\n", []string{"doc.md:3:7: `name==`: ln:1:6 syntax error (got `eof` but expected a value)"}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, failureStrings(Checker{}.CheckMarkdown("doc.md", []byte(tc.src))))
+		})
+	}
+}
+
+func TestCheckGo(t *testing.T) {
+	src := "package x\n\nfunc f() {\n\tfiqlparser.Parse(\"name==John\")\n\tp.Parse(`age=gt=`)\n\tother(\"name==\")\n}\n"
+	failures, err := Checker{}.CheckGo("x.go", []byte(src))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"x.go:5:18: `age=gt=`: ln:1:7 syntax error (got `eof` but expected a value)"}, failureStrings(failures))
+
+	failures, err = Checker{Funcs: []string{"other"}}.CheckGo("x.go", []byte(src))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"x.go:6:15: `name==`: ln:1:6 syntax error (got `eof` but expected a value)"}, failureStrings(failures))
+
+	failures, err = Checker{}.CheckGo("x.go", []byte("package x\n\nvar _, _ = p.Parse(`a==y;\nb=gt=x;c==1`)\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"x.go:4:6: `a==y;\nb=gt=x;c==1`: ln:2:6 syntax error (got `x` but expected number or date or duration)"}, failureStrings(failures))
+
+	_, err = Checker{}.CheckGo("x.go", []byte("package"))
+	assert.Error(t, err)
+}
+
+func TestCheckSchema(t *testing.T) {
+	c := Checker{Schema: &fiqlparser.Schema{Selectors: []fiqlparser.SelectorSchema{{Name: "name"}}}}
+	failures := c.CheckMarkdown("doc.md", []byte("```fiql\nname==John\n age==18\n```\n"))
+	if assert.Len(t, failures, 1) {
+		assert.Equal(t, 3, failures[0].Line)
+		assert.Equal(t, 2, failures[0].Column)
+		assert.ErrorIs(t, failures[0].Err, fiqlparser.ErrUnknownSelector)
+	}
+}
+
+func TestCheckFiles(t *testing.T) {
+	failures, err := Checker{}.CheckFiles("testdata/valid.md", "testdata/invalid.md", "testdata/example.go")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"testdata/invalid.md:5:14: `name==John;`: ln:1:11 dangling operator",
+		"testdata/example.go:8:18: `age=gt=`: ln:1:7 syntax error (got `eof` but expected a value)",
+	}, failureStrings(failures))
+
+	_, err = Checker{}.CheckFiles("testdata/missing.md")
+	assert.Error(t, err)
+	_, err = Checker{}.CheckFiles("exampletest_test.txt")
+	assert.Error(t, err)
+}
+
+func TestRun(t *testing.T) {
+	Run(t, Checker{}, "testdata/valid.md", "../README.md")
+}
+
+func failureStrings(failures []Failure) []string {
+	res := make([]string, 0, len(failures))
+	for _, f := range failures {
+		res = append(res, f.String())
+	}
+	return res
+}
@@ -0,0 +1,12 @@
+package example
+
+import fiqlparser "github.com/eisenwinter/fiql-parser"
+
+func Example() {
+	fiqlparser.Parse("name==John")
+	p := fiqlparser.NewParser()
+	p.Parse(`age=gt=`)
+	other("name==")
+}
+
+func other(string) {}
@@ -0,0 +1,17 @@
+package fiqlparser
+
+import v2 "github.com/eisenwinter/fiql-parser/v2"
+
+// ValueRecommendationField suggests a reference to another selector (`@start_date`), see WithFieldReferences
+const ValueRecommendationField = v2.ValueRecommendationField
+
+// WithFieldReferences enables arguments referencing another selector prefixed by `@`,
+// e.g. `end_date=gt=@start_date`. The argument is recommended as ValueRecommendationField,
+// its value is the referenced selector (see ArgumentContext.Field), which is subject to the same
+// case policy, pattern and mapper as the selector of the comparison.
+// Quoted values (`"@start_date"`) remain literals.
+//
+// Deprecated: use WithFieldReferences of github.com/eisenwinter/fiql-parser/v2.
+func WithFieldReferences() Option {
+	return v2.WithFieldReferences()
+}
@@ -0,0 +1,20 @@
+package fiqlparser
+
+import v2 "github.com/eisenwinter/fiql-parser/v2"
+
+// Filter returns the items matching the expression in their original order,
+// binder returns the value of a selector for a item (nil if the item has no such value).
+//
+// Values are compared according to their Go type: strings (wildcards match prefixes and suffixes),
+// signed and unsigned integers, floats, bools, time.Time (durations are relative to the start of the call,
+// see ArgumentContext.AsTimeRelative) and time.Duration. Pointers are dereferenced, nil matches `null`.
+// Slices match if any element matches, `!=` if no element equals the argument.
+// Unary selectors match non-nil values, `=q=` matches case insensitive substrings
+// and field references (see WithFieldReferences) compare with the value bound to the referenced selector.
+// Values which can not be compared with the argument (e.g. `age==abc` for a int) do not match.
+// Like SQLTranslator `!=` does not match nil values, see FilterWithOptions of github.com/eisenwinter/fiql-parser/v2 to include them.
+//
+// Deprecated: use Filter of github.com/eisenwinter/fiql-parser/v2.
+func Filter[T any](expr Expression, items []T, binder func(T, string) any) ([]T, error) {
+	return v2.Filter(expr, items, binder)
+}
@@ -0,0 +1,23 @@
+package fiqlparser
+
+import v2 "github.com/eisenwinter/fiql-parser/v2"
+
+// ErrUndeclaredParameter is generated if a template uses or is bound to a parameter it does not declare
+var ErrUndeclaredParameter = v2.ErrUndeclaredParameter
+
+// TemplateParameter declares a named parameter of a Template
+type TemplateParameter = v2.TemplateParameter
+
+// Template is a saved filter containing named parameters (see WithParameters), e.g. for saved searches
+// or scheduled reports: `created=gt=:since;owner==:user`. The bindings are validated against the
+// declared parameters before the bound expression or FIQL is rendered.
+// It is safe for concurrent use.
+type Template = v2.Template
+
+// NewTemplate parses the supplied fiql with named parameters enabled and checks that every used parameter
+// is declared and vice versa, and that the defaults match their type. opts configure the parser.
+//
+// Deprecated: use NewTemplate of github.com/eisenwinter/fiql-parser/v2.
+func NewTemplate(input string, params []TemplateParameter, opts ...Option) (*Template, error) {
+	return v2.NewTemplate(input, params, opts...)
+}
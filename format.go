@@ -0,0 +1,30 @@
+package fiqlparser
+
+import v2 "github.com/eisenwinter/fiql-parser/v2"
+
+// FormatOptions configures Format
+//
+// Deprecated: use FormatOptions of github.com/eisenwinter/fiql-parser/v2.
+type FormatOptions struct {
+	// Parser parses the input, a parser with the default configuration is used if nil
+	Parser *Parser
+	// Spaced surrounds the logical operators with a space in single line output, e.g. `a==1 ; b==2`
+	Spaced bool
+	// Indent prints every operand of a logical operation on its own line, the operands within braces
+	// are indented by Indent per level (e.g. two spaces or a tab), single line output if empty
+	Indent string
+}
+
+// Format reprints the supplied fiql in a canonical form, e.g. for linters or before storing saved filters:
+// whitespace between tokens is removed, comparisons are written in their short form (`=between=` becomes `=bt=`),
+// values are escaped consistently (see ToFIQL) and braces without effect around operands of the same logical operation are removed.
+// The order of the operands is kept, see Fingerprint to compare expressions regardless of it.
+//
+// Deprecated: use Format of github.com/eisenwinter/fiql-parser/v2.
+func Format(input string, opts FormatOptions) (string, error) {
+	var p *v2.Parser
+	if opts.Parser != nil {
+		p = opts.Parser.parser()
+	}
+	return v2.Format(input, v2.FormatOptions{Parser: p, Spaced: opts.Spaced, Indent: opts.Indent})
+}
@@ -0,0 +1,9 @@
+package fiqlparser
+
+import v2 "github.com/eisenwinter/fiql-parser/v2"
+
+// FrozenExpression is a compact, immutable representation of a expression intended for long lived
+// (e.g. cached) filters. The nodes are stored as records in a single slice and all strings in a single string,
+// so the garbage collector does not need to scan a tree of pointers.
+// Use Accept to traverse it or Thaw to translate it.
+type FrozenExpression = v2.FrozenExpression
@@ -2,10 +2,13 @@ module github.com/eisenwinter/fiql-parser
 
 go 1.18
 
+require (
+	github.com/eisenwinter/fiql-parser/v2 v2.0.0
+	github.com/stretchr/testify v1.8.0
+)
+
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
-	github.com/stretchr/objx v0.4.0 // indirect
-	github.com/stretchr/testify v1.8.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
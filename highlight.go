@@ -0,0 +1,43 @@
+package fiqlparser
+
+import v2 "github.com/eisenwinter/fiql-parser/v2"
+
+// SpanClass classifies a span of a input, see Highlight
+type SpanClass = v2.SpanClass
+
+// SpanSelector is a selector, also of a unary comparison
+const SpanSelector = v2.SpanSelector
+
+// SpanComparator is a comparison, e.g. `==` or `=gt=`
+const SpanComparator = v2.SpanComparator
+
+// SpanLogical is a logical operator (`;` or `,`) or a negation (`!`)
+const SpanLogical = v2.SpanLogical
+
+// SpanValue is a argument including its quotes, tuples are a single span
+const SpanValue = v2.SpanValue
+
+// SpanBrace is a opening or closing brace
+const SpanBrace = v2.SpanBrace
+
+// SpanWildcard is a wildcard of a argument
+const SpanWildcard = v2.SpanWildcard
+
+// SpanLabel is the label of a sub expression including the colon
+const SpanLabel = v2.SpanLabel
+
+// SpanInvalid is input which can not be tokenized, e.g. a unterminated quote, it extends to the end of the input
+const SpanInvalid = v2.SpanInvalid
+
+// Span is a classified part of a input
+type Span = v2.Span
+
+// Highlight classifies the tokens of the input for syntax highlighting, e.g. in web UIs or terminals.
+// Whitespace between tokens is not covered by any span. Spans are ordered by position and do not overlap.
+// The input is tokenized only, so invalid expressions are highlighted as far as possible;
+// the options are applied like by NewTokenizer.
+//
+// Deprecated: use Highlight of github.com/eisenwinter/fiql-parser/v2.
+func Highlight(input string, opts ...Option) []Span {
+	return v2.Highlight(input, opts...)
+}
@@ -0,0 +1,62 @@
+package fiqlparser
+
+import (
+	"context"
+	"net/http"
+
+	v2 "github.com/eisenwinter/fiql-parser/v2"
+)
+
+// DefaultMaxFilterLength is the maximum length (in bytes) of a filter parsed by ParseRequest,
+// use WithMaxLength to change it
+const DefaultMaxFilterLength = v2.DefaultMaxFilterLength
+
+// WithMaxLength rejects expressions longer than max bytes with ErrorCodeInputTooLong,
+// a value <= 0 disables the check
+//
+// Deprecated: use WithMaxLength of github.com/eisenwinter/fiql-parser/v2.
+func WithMaxLength(max int) Option {
+	return v2.WithMaxLength(max)
+}
+
+// ParseRequest parses the filter in the query parameter param of the request.
+// The value is percent-decoded (`+` is kept as is) and limited to DefaultMaxFilterLength bytes
+// unless opts contain WithMaxLength. A missing or empty parameter results in a empty expression.
+// The filter is parsed with the context of the request, so parsing stops once the client is gone.
+//
+// Deprecated: use ParseRequest of github.com/eisenwinter/fiql-parser/v2.
+func ParseRequest(r *http.Request, param string, opts ...Option) (Expression, error) {
+	return v2.ParseRequest(r, param, opts...)
+}
+
+// FilterBranch is a filter parameter of a request combined by ParseRequestFilters
+type FilterBranch = v2.FilterBranch
+
+// ParseRequestFilters parses every value of the query parameters params, repeated (`?filter=a==1&filter=b==2`)
+// as well as different ones (`?filter=a==1&q=b==2`), and combines them with operator:
+// OperatorAND requires every filter to match, OperatorOR any of them.
+// Each filter becomes a sub expression labeled with its parameter (see Expression.Label),
+// the branches are returned in order of appearance with their origin.
+// Values are decoded and parsed like by ParseRequest, the limit of the length applies to all values together
+// so repeating the parameter does not bypass it. Empty values are skipped and without any filter the expression is empty.
+//
+// Deprecated: use ParseRequestFilters of github.com/eisenwinter/fiql-parser/v2.
+func ParseRequestFilters(r *http.Request, params []string, operator OperatorDefintion, opts ...Option) (Expression, []FilterBranch, error) {
+	return v2.ParseRequestFilters(r, params, operator, opts...)
+}
+
+// ExpressionFromContext returns the expression stored by FilterMiddleware
+//
+// Deprecated: use ExpressionFromContext of github.com/eisenwinter/fiql-parser/v2.
+func ExpressionFromContext(ctx context.Context) (Expression, bool) {
+	return v2.ExpressionFromContext(ctx)
+}
+
+// FilterMiddleware parses the query parameter param like ParseRequest and stores the expression in the
+// request context, use ExpressionFromContext to retrieve it. Invalid filters are answered with
+// 400 Bad Request and the error message, the next handler is not called.
+//
+// Deprecated: use FilterMiddleware of github.com/eisenwinter/fiql-parser/v2.
+func FilterMiddleware(param string, opts ...Option) func(http.Handler) http.Handler {
+	return v2.FilterMiddleware(param, opts...)
+}
@@ -0,0 +1,28 @@
+package fiqlparser
+
+import v2 "github.com/eisenwinter/fiql-parser/v2"
+
+// ValueRecommendationUUID suggests a UUID (`123e4567-e89b-12d3-a456-426614174000`), see WithIdentifierRecommendations
+const ValueRecommendationUUID = v2.ValueRecommendationUUID
+
+// ValueRecommendationEmail suggests a email address (`jane@example.com`), see WithIdentifierRecommendations
+const ValueRecommendationEmail = v2.ValueRecommendationEmail
+
+// ValueRecommendationIP suggests a IPv4 or IPv6 address or a CIDR prefix (`10.0.0.0/8`), see WithIdentifierRecommendations
+const ValueRecommendationIP = v2.ValueRecommendationIP
+
+// ErrNoUUID is generated if a argument is not a UUID
+var ErrNoUUID = v2.ErrNoUUID
+
+// ErrNoIP is generated if a argument is not a IP address or CIDR prefix
+var ErrNoIP = v2.ErrNoIP
+
+// WithIdentifierRecommendations recommends unquoted or quoted arguments without wildcards as ValueRecommendationUUID,
+// ValueRecommendationEmail or ValueRecommendationIP if they are one, instead of ValueRecommendationString
+// (or ValueRecommendationNumber for IPv4 addresses like `10.0.0.1`). The option is off by default
+// as it changes the recommendation of existing arguments.
+//
+// Deprecated: use WithIdentifierRecommendations of github.com/eisenwinter/fiql-parser/v2.
+func WithIdentifierRecommendations() Option {
+	return v2.WithIdentifierRecommendations()
+}
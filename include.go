@@ -0,0 +1,36 @@
+package fiqlparser
+
+import (
+	"context"
+
+	v2 "github.com/eisenwinter/fiql-parser/v2"
+)
+
+// ErrIncludeCycle is generated if files include each other
+var ErrIncludeCycle = v2.ErrIncludeCycle
+
+// IncludeResolver resolves the files used by ParseFile
+type IncludeResolver = v2.IncludeResolver
+
+// IncludeResolverFunc is a function adapter for IncludeResolver
+type IncludeResolverFunc = v2.IncludeResolverFunc
+
+// FileError attributes a error to the file it occurred in
+type FileError = v2.FileError
+
+// ParseFile parses a filter file, every non-empty line is parsed as a separate expression (see ParseMulti).
+// Lines starting with `@include "<file>"` are replaced by the expressions of the referenced file,
+// which is loaded by the resolver. The initial file name is resolved as well.
+// Errors in included files are reported as *FileError, include cycles are reported as ErrIncludeCycle.
+//
+// Deprecated: use Parser.ParseFile of github.com/eisenwinter/fiql-parser/v2, it takes a context.
+func (p *Parser) ParseFile(name string, resolver IncludeResolver) ([]Expression, error) {
+	return p.parser().ParseFile(context.Background(), name, resolver)
+}
+
+// ParseFile instant parses a filter file, resolving includes with the supplied resolver
+//
+// Deprecated: use ParseFile of github.com/eisenwinter/fiql-parser/v2, it takes a context.
+func ParseFile(name string, resolver IncludeResolver) ([]Expression, error) {
+	return v2.ParseFile(context.Background(), name, resolver)
+}
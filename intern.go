@@ -0,0 +1,26 @@
+package fiqlparser
+
+import v2 "github.com/eisenwinter/fiql-parser/v2"
+
+// DefaultInternMaxValueLength is the length up to which values are interned by default,
+// longer values are rarely repeated
+const DefaultInternMaxValueLength = v2.DefaultInternMaxValueLength
+
+// Interner deduplicates the selectors, labels and short values of parsed expressions,
+// share one interner between parsers to reduce the memory used by retained expressions.
+// It is safe for concurrent use.
+type Interner = v2.Interner
+
+// NewInterner returns a interner using DefaultInternMaxValueLength
+//
+// Deprecated: use NewInterner of github.com/eisenwinter/fiql-parser/v2.
+func NewInterner() *Interner {
+	return v2.NewInterner()
+}
+
+// WithInterner interns the selectors, labels and short values of all parsed expressions
+//
+// Deprecated: use WithInterner of github.com/eisenwinter/fiql-parser/v2.
+func WithInterner(i *Interner) Option {
+	return v2.WithInterner(i)
+}
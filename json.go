@@ -0,0 +1,20 @@
+package fiqlparser
+
+import (
+	_ "unsafe" // go:linkname
+
+	v2 "github.com/eisenwinter/fiql-parser/v2"
+)
+
+// MaxJSONDepth limits the nesting of nodes written by MarshalJSON, MarshalDetailedJSON and WriteJSONTo,
+// deeper trees (e.g. constructed programmatically or restored from a cache) fail with ErrJSONTooDeep.
+// The default keeps the output readable by encoding/json, which rejects more than 10000 nested objects and arrays.
+// A value <= 0 disables the limit, the JSON is written without recursion regardless of the depth.
+//
+// It is the same variable as MaxJSONDepth of version 2, which writes the JSON of both versions.
+//
+//go:linkname MaxJSONDepth github.com/eisenwinter/fiql-parser/v2.MaxJSONDepth
+var MaxJSONDepth int
+
+// ErrJSONTooDeep is returned if a tree is nested deeper than MaxJSONDepth
+var ErrJSONTooDeep = v2.ErrJSONTooDeep
@@ -0,0 +1,8 @@
+package fiqlparser
+
+import v2 "github.com/eisenwinter/fiql-parser/v2"
+
+// LDAPTranslator translates a expression to a RFC 4515 LDAP search filter,
+// e.g. `cn==Jo*;uid!=admin` becomes `(&(cn=Jo*)(!(uid=admin)))`.
+// The zero value maps every selector to the attribute of the same name.
+type LDAPTranslator = v2.LDAPTranslator
@@ -0,0 +1,32 @@
+package fiqlparser
+
+import v2 "github.com/eisenwinter/fiql-parser/v2"
+
+// DialectCapabilities describes what a backend handles well, it is used by Lint
+type DialectCapabilities = v2.DialectCapabilities
+
+// LintCode classifies a LintWarning
+type LintCode = v2.LintCode
+
+// LintLeadingWildcard is used for arguments starting with a wildcard
+const LintLeadingWildcard = v2.LintLeadingWildcard
+
+// LintOrAcrossSelectors is used for OR operations comparing different selectors
+const LintOrAcrossSelectors = v2.LintOrAcrossSelectors
+
+// LintLargeTuple is used for =in= comparisons with more elements than allowed
+const LintLargeTuple = v2.LintLargeTuple
+
+// LintUnsupportedComparison is used for comparisons the backend does not support
+const LintUnsupportedComparison = v2.LintUnsupportedComparison
+
+// LintWarning describes a construct the backend will handle poorly
+type LintWarning = v2.LintWarning
+
+// Lint returns warnings for constructs the backend described by caps will handle poorly,
+// in order of their position
+//
+// Deprecated: use Lint of github.com/eisenwinter/fiql-parser/v2.
+func Lint(e Expression, caps DialectCapabilities) []LintWarning {
+	return v2.Lint(e, caps)
+}
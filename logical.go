@@ -0,0 +1,18 @@
+package fiqlparser
+
+import v2 "github.com/eisenwinter/fiql-parser/v2"
+
+// NodeTypeLogical is a logical operation with any number of operands
+const NodeTypeLogical = v2.NodeTypeLogical
+
+// LogicalNode is a logical operation (AND, OR) with any number of operands,
+// it is produced by CollapseLogical or the WithLogicalNodes option
+type LogicalNode = v2.LogicalNode
+
+// WithLogicalNodes collapses chains of the same logical operator into LogicalNodes after parsing,
+// see Expression.CollapseLogical
+//
+// Deprecated: use WithLogicalNodes of github.com/eisenwinter/fiql-parser/v2.
+func WithLogicalNodes() Option {
+	return v2.WithLogicalNodes()
+}
@@ -0,0 +1,10 @@
+package fiqlparser
+
+import v2 "github.com/eisenwinter/fiql-parser/v2"
+
+// LuceneTranslator translates a expression to a Lucene query string,
+// e.g. `name==Jo*;age=gt=18` becomes `name:Jo* AND age:{18 TO *}`,
+// so filters can be passed to bleve, Elasticsearch or OpenSearch query string queries.
+// Missing and present values are written as `_exists_:<field>`, which is supported by Elasticsearch and OpenSearch.
+// The zero value maps every selector to the field of the same name.
+type LuceneTranslator = v2.LuceneTranslator
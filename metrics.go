@@ -0,0 +1,39 @@
+package fiqlparser
+
+import (
+	"context"
+
+	v2 "github.com/eisenwinter/fiql-parser/v2"
+)
+
+// ErrorCodeBudgetExceeded is used if a parse exceeds the limits configured by WithBudget
+const ErrorCodeBudgetExceeded = v2.ErrorCodeBudgetExceeded
+
+// ParseMetrics are counted by the parser while parsing a single input,
+// they are cheap internal counters and do not rely on runtime metrics
+type ParseMetrics = v2.ParseMetrics
+
+// ParseBudget limits the work done for a single parse, zero fields are unlimited
+type ParseBudget = v2.ParseBudget
+
+// WithMetrics reports the metrics of every parse to fn, including failed ones
+//
+// Deprecated: use WithMetrics of github.com/eisenwinter/fiql-parser/v2.
+func WithMetrics(fn func(ParseMetrics)) Option {
+	return v2.WithMetrics(fn)
+}
+
+// WithBudget aborts parses exceeding b with ErrorCodeBudgetExceeded,
+// the budget is checked while parsing so pathological inputs fail early
+//
+// Deprecated: use WithBudget of github.com/eisenwinter/fiql-parser/v2.
+func WithBudget(b ParseBudget) Option {
+	return v2.WithBudget(b)
+}
+
+// ParseWithMetrics parses the supplied fiql like Parse and additionally returns its metrics
+//
+// Deprecated: use Parser.ParseWithMetrics of github.com/eisenwinter/fiql-parser/v2, it takes a context.
+func (p *Parser) ParseWithMetrics(input string) (Expression, ParseMetrics, error) {
+	return p.parser().ParseWithMetrics(context.Background(), input)
+}
@@ -0,0 +1,24 @@
+package fiqlparser
+
+import (
+	"context"
+
+	v2 "github.com/eisenwinter/fiql-parser/v2"
+)
+
+// ParseMulti parses every non-empty line of the input as a separate expression,
+// this is intended for files containing one filter per line.
+// Parsing stops at the first invalid line, the error reports the line within the whole input.
+// All expressions parsed up to that point are returned alongside the error.
+//
+// Deprecated: use Parser.ParseMulti of github.com/eisenwinter/fiql-parser/v2, it takes a context.
+func (p *Parser) ParseMulti(input string) ([]Expression, error) {
+	return p.parser().ParseMulti(context.Background(), input)
+}
+
+// ParseMulti instant parses every non-empty line of the input as a separate expression
+//
+// Deprecated: use ParseMulti of github.com/eisenwinter/fiql-parser/v2, it takes a context.
+func ParseMulti(input string) ([]Expression, error) {
+	return v2.ParseMulti(context.Background(), input)
+}
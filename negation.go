@@ -0,0 +1,22 @@
+package fiqlparser
+
+import v2 "github.com/eisenwinter/fiql-parser/v2"
+
+// NodeTypeUnaryLogical is a logical operation with a single operand, the negation (NOT)
+const NodeTypeUnaryLogical = v2.NodeTypeUnaryLogical
+
+// OperatorNOT negates a sub expression, see WithNegation
+const OperatorNOT = v2.OperatorNOT
+
+// NegationNode negates its operand, a sub expression, it is produced by the WithNegation option
+type NegationNode = v2.NegationNode
+
+// WithNegation enables negated sub expressions prefixed by `!`, e.g. `!(a==1;b==2)`,
+// which result in a NegationNode (NodeTypeUnaryLogical).
+// Visitors are notified by VisitOperator with OperatorNOT right before the negated sub expression is entered,
+// so visitors used with this option have to handle OperatorNOT.
+//
+// Deprecated: use WithNegation of github.com/eisenwinter/fiql-parser/v2.
+func WithNegation() Option {
+	return v2.WithNegation()
+}
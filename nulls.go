@@ -0,0 +1,17 @@
+package fiqlparser
+
+import v2 "github.com/eisenwinter/fiql-parser/v2"
+
+// NullSemantics defines whether translators let negated comparisons (e.g. `status!=open`)
+// match records where the selector is null or missing
+type NullSemantics = v2.NullSemantics
+
+// NullSemanticsDefault keeps the behaviour of the backend,
+// SQL and Filter exclude nulls (three-valued logic) while elasticsearch includes missing fields
+const NullSemanticsDefault = v2.NullSemanticsDefault
+
+// NullSemanticsInclude lets negated comparisons match nulls
+const NullSemanticsInclude = v2.NullSemanticsInclude
+
+// NullSemanticsExclude never lets negated comparisons match nulls
+const NullSemanticsExclude = v2.NullSemanticsExclude
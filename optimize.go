@@ -0,0 +1,6 @@
+package fiqlparser
+
+import v2 "github.com/eisenwinter/fiql-parser/v2"
+
+// ErrUnsatisfiable is returned by Optimize if the expression can never match
+var ErrUnsatisfiable = v2.ErrUnsatisfiable
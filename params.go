@@ -0,0 +1,20 @@
+package fiqlparser
+
+import v2 "github.com/eisenwinter/fiql-parser/v2"
+
+// ValueRecommendationParameter suggests a named parameter (`:since`) which is bound later, see WithParameters
+const ValueRecommendationParameter = v2.ValueRecommendationParameter
+
+// ErrUnboundParameter is generated by Bind if no value is supplied for a parameter
+var ErrUnboundParameter = v2.ErrUnboundParameter
+
+// WithParameters enables named parameters prefixed by `:` as arguments, e.g. `created=gt=:since`,
+// so filter templates can be stored and bound to user values later (see Expression.Bind).
+// The argument is recommended as ValueRecommendationParameter, its value is the name of the parameter
+// (see ArgumentContext.Parameter). Names consist of letters, digits and underscores.
+// Quoted values (`":since"`) remain literals, tuple elements can not be parameters.
+//
+// Deprecated: use WithParameters of github.com/eisenwinter/fiql-parser/v2.
+func WithParameters() Option {
+	return v2.WithParameters()
+}
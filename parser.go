@@ -4,718 +4,225 @@
 // which can be found https://datatracker.ietf.org/doc/html/draft-nottingham-atompub-fiql-00.
 //
 // The parser produces a walkable AST which can be walked by using a visitor.
+//
+// The translators (e.g. SQLTranslator or ElasticsearchTranslator) are configured by their fields
+// and keep no state between translations, they are safe for concurrent use as long as their fields are not modified.
+//
+// The implementation lives in github.com/eisenwinter/fiql-parser/v2, this package delegates to it
+// and keeps the version 1 API. The types are aliases of the version 2 types,
+// so values can be passed between both versions and code can be migrated package by package.
+//
+// Deprecated: use github.com/eisenwinter/fiql-parser/v2, its parse functions take a context.
 package fiqlparser
 
 import (
-	"encoding/json"
-	"fmt"
-	"regexp"
-	"strconv"
-	"strings"
-	"time"
+	"context"
+	"io"
+
+	v2 "github.com/eisenwinter/fiql-parser/v2"
 )
 
 // NodeType defines the type of node in the ast
-type NodeType string
+type NodeType = v2.NodeType
 
 // NodeTypeExpression is the root element of any expression
-const NodeTypeExpression NodeType = "Expr"
+const NodeTypeExpression = v2.NodeTypeExpression
 
 // NodeTypeBinary is a binary expression
-const NodeTypeBinary NodeType = "Binary"
+const NodeTypeBinary = v2.NodeTypeBinary
 
 // NodeTypeConstant is a constant value expression
-const NodeTypeConstant NodeType = "Const"
+const NodeTypeConstant = v2.NodeTypeConstant
+
+// NodeTypeUnary is a selector without constraint (e.g. `deleted` in `deleted;name==x`),
+// it is a ConstantNode with IsUnary set
+const NodeTypeUnary = v2.NodeTypeUnary
 
 // OperatorDefintion defines the two operators fiql has
-type OperatorDefintion string
+//
+// Deprecated: use Operator of github.com/eisenwinter/fiql-parser/v2, it is a alias of this type.
+type OperatorDefintion = v2.Operator
 
-// OperatorOR defines the OR operation
+// OperatorOR defines the OR operation, AND binds tighter than OR
 // Associativity: Left to right
-const OperatorOR OperatorDefintion = "OR"
+const OperatorOR = v2.OperatorOR
 
 // OperatorAND defines the AND operation
 // Associativity: Left to right
-const OperatorAND OperatorDefintion = "AND"
+const OperatorAND = v2.OperatorAND
 
 // ComparisonDefintion defines the fiql + custom comparisons
-type ComparisonDefintion string
+//
+// Deprecated: use Comparison of github.com/eisenwinter/fiql-parser/v2, it is a alias of this type.
+type ComparisonDefintion = v2.Comparison
 
 // ComparisonEq equal comparison
-const ComparisonEq ComparisonDefintion = "=="
+const ComparisonEq = v2.ComparisonEq
 
 // ComparisonNeq not equal comparison
-const ComparisonNeq ComparisonDefintion = "<>"
+const ComparisonNeq = v2.ComparisonNeq
 
 // ComparisonGt greater comparison
-const ComparisonGt ComparisonDefintion = ">"
+const ComparisonGt = v2.ComparisonGt
 
 // ComparisonLt less comparison
-const ComparisonLt ComparisonDefintion = "<"
+const ComparisonLt = v2.ComparisonLt
 
 // ComparisonGte greater or equal comparison
-const ComparisonGte ComparisonDefintion = ">="
+const ComparisonGte = v2.ComparisonGte
 
 // ComparisonLte less or equal comparison
-const ComparisonLte ComparisonDefintion = "<="
+const ComparisonLte = v2.ComparisonLte
+
+// ComparisonBetween inclusive range comparison (`=bt=` or `=between=`),
+// the argument is a range literal (`10..20`) or a tuple of two bounds (`[10+20]`)
+const ComparisonBetween = v2.ComparisonBetween
+
+// ComparisonIn membership comparison (`=in=`), the argument is a tuple
+const ComparisonIn = v2.ComparisonIn
+
+// ComparisonQuery full text search (`=q=`), the meaning is up to the backend
+const ComparisonQuery = v2.ComparisonQuery
+
+// AllComparisons returns all comparisons the parser produces, e.g. to check visitors handle every comparison
+//
+// Deprecated: use AllComparisons of github.com/eisenwinter/fiql-parser/v2.
+func AllComparisons() []ComparisonDefintion {
+	return v2.AllComparisons()
+}
 
 // ValueRecommendation suggests a detected datatype for a attribute
-type ValueRecommendation string
+type ValueRecommendation = v2.ValueRecommendation
 
 // ValueRecommendationString suggests a string attribute
-const ValueRecommendationString ValueRecommendation = "string"
+const ValueRecommendationString = v2.ValueRecommendationString
 
 // ValueRecommendationDateTime suggests a date attribute
-const ValueRecommendationDateTime ValueRecommendation = "datetime"
+const ValueRecommendationDateTime = v2.ValueRecommendationDateTime
 
 // ValueRecommendationDuration suggests a duration attribute
-const ValueRecommendationDuration ValueRecommendation = "duration"
+const ValueRecommendationDuration = v2.ValueRecommendationDuration
 
 // ValueRecommendationNumber suggests a number attribute
-const ValueRecommendationNumber ValueRecommendation = "number"
-
-// ArgumentContext habours the value and
-// supplies the recommended type + conversion helpers
-type ArgumentContext struct {
-	pre  bool
-	post bool
-	r    ValueRecommendation
-	val  string
-}
-
-// ValueRecommendation returns the value recommendation
-func (c ArgumentContext) ValueRecommendation() ValueRecommendation {
-	return c.r
-}
+const ValueRecommendationNumber = v2.ValueRecommendationNumber
 
-// StartsWithWildcard indicates whether or not the given argument starts with a wildcard
-func (c ArgumentContext) StartsWithWildcard() bool {
-	return c.pre
-}
+// ValueRecommendationBoolean suggests a boolean attribute (`true` or `false`)
+const ValueRecommendationBoolean = v2.ValueRecommendationBoolean
 
-// EndsWithWildcard indicates whether or not the given argument ends with a wildcard
-func (c ArgumentContext) EndsWithWildcard() bool {
-	return c.post
-}
+// ValueRecommendationNull suggests a missing value (`null`)
+const ValueRecommendationNull = v2.ValueRecommendationNull
 
-// AsString returns the argument as string
-func (c ArgumentContext) AsString() string {
-	return c.val
-}
+// ValueRecommendationRange suggests a inclusive range of numbers, dates or durations (`10..20`)
+const ValueRecommendationRange = v2.ValueRecommendationRange
 
-// AsDuration is a helper method for converting duration values
-func (c ArgumentContext) AsDuration() (ISO8601Duration, error) {
-	return durationConverter.tryParseISO8601Duration(c.val)
-}
+// ValueRecommendationTuple suggests a list of values (`[a+b+c]`), see ArgumentContext.AsTuple
+const ValueRecommendationTuple = v2.ValueRecommendationTuple
 
-// AsTime is a helper method for converting duration values
-func (c ArgumentContext) AsTime() (time.Time, error) {
-	return time.Parse(time.RFC3339, c.val)
-}
-
-// AsInt returns the underlying value as int
-func (c ArgumentContext) AsInt() (int, error) {
-	return strconv.Atoi(c.val)
-}
-
-// AsFloat64 returns the underlying value as float64
-func (c ArgumentContext) AsFloat64() (float64, error) {
-	return strconv.ParseFloat(c.val, 64)
-}
+// ArgumentContext habours the value and
+// supplies the recommended type + conversion helpers
+type ArgumentContext = v2.ArgumentContext
 
 // SelectorContext contains the selector details
-type SelectorContext struct {
-	unary    bool
-	selector string
-}
-
-// Selector returns the selector as string
-func (s SelectorContext) Selector() string {
-	return s.selector
-}
-
-// IsUnary returns true if the selector has no constraint
-func (s SelectorContext) IsUnary() bool {
-	return s.unary
-}
+type SelectorContext = v2.SelectorContext
 
 // OperatorContext contains the operator details
-type OperatorContext struct {
-	op OperatorDefintion
-}
-
-// Operator returns the operator
-func (c OperatorContext) Operator() OperatorDefintion {
-	return c.op
-}
+type OperatorContext = v2.OperatorContext
 
 // ComparisonContext contains the comerator details
-type ComparisonContext struct {
-	comparison ComparisonDefintion
-}
-
-// Comparison returns the used comparison
-func (c ComparisonContext) Comparison() ComparisonDefintion {
-	return c.comparison
-}
-
-//Basically follow naming of https://datatracker.ietf.org/doc/html/draft-nottingham-atompub-fiql-00#section-3.2
+type ComparisonContext = v2.ComparisonContext
 
-// NodeVisitor is used to visit the tree
-type NodeVisitor interface {
-	// VisitExpressionEntered is called when a expression is entered
-	VisitExpressionEntered()
+// NodeVisitor is used to visit the tree by Accept,
+// see Visitor for the interface with error returns, enter and leave of binary nodes and tuple elements
+type NodeVisitor = v2.NodeVisitor
 
-	// VisitExpressionLeft is called when a expression is left
-	VisitExpressionLeft()
-
-	// VisitOperator is called when a operator is visited
-	VisitOperator(operatorCtx OperatorContext)
-
-	// VisitSelector is called when a selector is visited
-	VisitSelector(selectorCtx SelectorContext)
-
-	// VisitComparison is called when a comparison is visited
-	VisitComparison(comparisonCtx ComparisonContext)
-
-	// VisitArgument is called when a argument is visited
-	VisitArgument(argumentCtx ArgumentContext)
-}
+// LabelVisitor can optionally be implemented by a NodeVisitor
+// to be notified about labeled sub expressions
+type LabelVisitor = v2.LabelVisitor
 
 // Node represents a AST node
-type Node interface {
-	// NodeType - node type in the AST - the root node will always be expression
-	NodeType() NodeType
-	// String prints the node
-	String() string
-	// Returns the children of this node
-	Children() []Node
-	// Accepts a Visitor
-	Accept(visitor NodeVisitor)
-	// Add adds a child node to this node
-	Add(Node)
-
-	// isRoot indicates the root node
-	isRoot() bool
-}
-
-// Expression is the root node
-type Expression struct {
-	node Node
-	root bool
-}
-
-func (e *Expression) isRoot() bool {
-	return e.root
-}
-
-// NodeType NodeTypeExpression
-func (e *Expression) NodeType() NodeType {
-	return NodeTypeExpression
-}
-
-// Accept accepts a vistor to visit the tree
-func (e *Expression) Accept(visitor NodeVisitor) {
-	visitor.VisitExpressionEntered()
-	if e.node != nil {
-		e.node.Accept(visitor)
-	}
-	visitor.VisitExpressionLeft()
-}
-
-// Add adds a child to the node, it will panic if more than one child exists on a expression node
-func (e *Expression) Add(node Node) {
-	if e.node != nil {
-		panic("node may not have more than one child")
-	}
-	e.node = node
-}
-
-// MarshalJSON overloading for json marshalling
-func (e *Expression) MarshalJSON() ([]byte, error) {
-	j, err := json.Marshal(struct {
-		Type     string
-		Operator string
-		Nodes    []Node
-	}{
-		Type:  string(e.NodeType()),
-		Nodes: []Node{e.node},
-	})
-	if err != nil {
-		return nil, err
-	}
-	return j, nil
-}
-
-func (e *Expression) String() string {
-	var b strings.Builder
-	b.WriteRune('(')
-	for _, v := range e.Children() {
-		b.WriteString(v.String())
-	}
-	b.WriteRune(')')
-	return b.String()
-}
-
-// Children returns the children of this expression
-func (e *Expression) Children() []Node {
-	return []Node{e.node}
-}
-
-type binaryExpression struct {
-	operator string
-	nodes    [2]Node
-}
-
-func (e *binaryExpression) NodeType() NodeType {
-	return NodeTypeBinary
-}
-
-func (e *binaryExpression) Add(node Node) {
-	if e.nodes[0] == nil {
-		e.nodes[0] = node
-		return
-	}
-	if e.nodes[1] == nil {
-		e.nodes[1] = node
-		return
-	}
-	panic("binary node cant hold more than two values")
-}
-
-// Accept accepts a vistor to visit the tree
-func (e *binaryExpression) Accept(visitor NodeVisitor) {
-	if e.nodes[0] != nil {
-		e.nodes[0].Accept(visitor)
-	}
-	//conjs
-	if e.operator == "AND" || e.operator == "OR" {
-		visitor.VisitOperator(OperatorContext{op: OperatorDefintion(e.operator)})
-	} else {
-		visitor.VisitComparison(ComparisonContext{comparison: ComparisonDefintion(e.operator)})
-	}
-	if e.nodes[1] != nil {
-		e.nodes[1].Accept(visitor)
-	}
-}
-
-func (e *binaryExpression) Children() []Node {
-	nodes := make([]Node, 0)
-	if e.nodes[0] != nil {
-		nodes = append(nodes, e.nodes[0])
-	}
-	if e.nodes[1] != nil {
-		nodes = append(nodes, e.nodes[1])
-	}
-	return nodes
-}
-
-func (e *binaryExpression) isRoot() bool {
-	return false
-}
-
-func (e *binaryExpression) MarshalJSON() ([]byte, error) {
-	j, err := json.Marshal(struct {
-		Type     string
-		Operator string
-		Nodes    [2]Node
-	}{
-		Type:     string(e.NodeType()),
-		Operator: e.operator,
-		Nodes:    e.nodes,
-	})
-	if err != nil {
-		return nil, err
-	}
-	return j, nil
-}
-
-func (e *binaryExpression) String() string {
-	var b strings.Builder
-	if e.nodes[0] != nil {
-		b.WriteString(e.nodes[0].String())
-	}
-	b.WriteRune(' ')
-	b.WriteString(e.operator)
-	b.WriteRune(' ')
-	if e.nodes[1] != nil {
-		b.WriteString(e.nodes[1].String())
-	}
+type Node = v2.Node
 
-	return b.String()
-}
-
-type constantExpression struct {
-	prefixWildcard bool
-	suffixWildcard bool
-	selector       bool
-	value          string
-	recommended    ValueRecommendation
-	unary          bool
-}
-
-func (e *constantExpression) isRoot() bool {
-	return false
-}
-
-func (e *constantExpression) NodeType() NodeType {
-	return NodeTypeConstant
-}
-
-func (e *constantExpression) Add(node Node) {
-	panic("constant should not have a child")
-}
-
-func (e *constantExpression) Accept(visitor NodeVisitor) {
-	if e.selector {
-		visitor.VisitSelector(SelectorContext{unary: e.unary, selector: e.value})
-	} else {
-		visitor.VisitArgument(ArgumentContext{
-			pre:  e.prefixWildcard,
-			post: e.suffixWildcard,
-			r:    e.recommended,
-			val:  e.value,
-		})
-	}
-
-}
+// BinaryNode is either a logical operation (NodeTypeBinary with a OperatorDefintion)
+// or a comparison (NodeTypeBinary with a ComparisonDefintion)
+type BinaryNode = v2.BinaryNode
 
-func (e *constantExpression) MarshalJSON() ([]byte, error) {
-	j, err := json.Marshal(struct {
-		Type  string
-		Value string
-	}{
-		Type:  string(e.NodeType()),
-		Value: e.String(),
-	})
-	if err != nil {
-		return nil, err
-	}
-	return j, nil
-}
+// ConstantNode is either a selector or a argument (NodeTypeConstant), a unary selector (NodeTypeUnary)
+// or a tuple argument (NodeTypeTuple)
+type ConstantNode = v2.ConstantNode
 
-func (e *constantExpression) String() string {
-	var b strings.Builder
-	if e.prefixWildcard {
-		b.WriteRune('*')
-	}
-	b.WriteString(e.value)
-	if e.suffixWildcard {
-		b.WriteRune('*')
-	}
-	return b.String()
-}
+// Expression is the root node
+type Expression = v2.Expression
 
-func (e *constantExpression) Children() []Node {
-	return []Node{}
-}
+// Position is a position within the parsed input
+type Position = v2.Position
 
-// Parser is the fiql parser
+// Parser is the fiql parser, it only holds the configuration and is safe for concurrent use
+// by multiple goroutines, so one parser can be shared (e.g. by all HTTP handlers)
+//
+// Deprecated: use Parser of github.com/eisenwinter/fiql-parser/v2, it is created by New.
 type Parser struct {
-	lex *lexer
-}
-
-func (p *Parser) handleSubExpression(parent Node) (Node, error) {
-	expr := &Expression{node: nil}
-	n, err := p.build(expr)
-	if err != nil {
-		return expr, err
-	}
-	expr.node = n
-	return expr, nil
-}
-
-var numericRegex = regexp.MustCompile(`^(\+|-|)[0-9\.]+$`)
-var durationRegex = regexp.MustCompile(`^(\+|-|)P(?:\d+(?:\.\d+)?Y)?(?:\d+(?:\.\d+)?M)?(?:\d+(?:\.\d+)?W)?(?:\d+(?:\.\d+)?D)?(?:T(?:\d+(?:\.\d+)?H)?(?:\d+(?:\.\d+)?M)?(?:\d+(?:\.\d+)?S)?)?$`)
-
-func isDateValue(stringDate string) bool {
-	_, err := time.Parse(time.RFC3339, stringDate)
-	return err == nil
-}
-
-type argumentValidator func(string) (bool, ValueRecommendation, string)
-
-func numberOrDateExpressionValidator(i string) (bool, ValueRecommendation, string) {
-	if numericRegex.MatchString(i) {
-		return true, ValueRecommendationNumber, ""
-	}
-	//time or duration e.g. 2003-12-13T18:30:02Z or  -P1D12
-	if isDateValue(i) {
-		return true, ValueRecommendationDateTime, ""
-	}
-	if durationRegex.MatchString(i) {
-		return true, ValueRecommendationDuration, ""
-	}
-
-	return false, ValueRecommendationString, "number or date or duration"
-}
-
-func defaultValidator(i string) (bool, ValueRecommendation, string) {
-	if isDateValue(i) {
-		return true, ValueRecommendationDateTime, ""
-	}
-	if durationRegex.MatchString(i) {
-		return true, ValueRecommendationDuration, ""
-	}
-	if numericRegex.MatchString(i) {
-		return true, ValueRecommendationNumber, ""
-	}
-	return true, ValueRecommendationString, ""
-}
-
-func (p *Parser) handleArgumentConstant(validator argumentValidator) (Node, error) {
-	t, err := p.lex.ConsumeToken()
-	if err != nil {
-		return nil, err
-	}
-	prefixWildcard := false
-	if t == tokenWildcard {
-		t, err = p.lex.ConsumeToken()
-		if err != nil {
-			return nil, err
-		}
-		prefixWildcard = true
-	}
-	if t == tokenValue {
-		ok, rec, msg := validator(p.lex.lastValue())
-		if !ok {
-			return nil, fmt.Errorf("ln:%d:%d syntax error (got `%s` but expected %s)", p.lex.ln, p.lex.posInLine, p.lex.lastValue(), msg)
-		}
-		con := &constantExpression{prefixWildcard: prefixWildcard, value: p.lex.lastValue(), recommended: rec}
-		n, _, err := p.lex.PeekNextToken()
-		if err != nil {
-			return nil, err
-		}
-		if n == tokenWildcard {
-			_, err = p.lex.ConsumeToken()
-			if err != nil {
-				return nil, err
-			}
-			con.suffixWildcard = true
-		}
-		return con, nil
-	}
-	return nil, fmt.Errorf("ln:%d:%d syntax error (got `%s` but expected a value)", p.lex.ln, p.lex.posInLine, t.String())
-}
-
-func (p Parser) handleUnaryExpression(parent Node) (Node, error) {
-	unary := &constantExpression{value: p.lex.lastValue(), selector: true, recommended: ValueRecommendationString, unary: true}
-	next, _, err := p.lex.PeekNextToken()
-	if err != nil {
-		return unary, err
-	}
-	if isLogicToken(next) {
-		t, err := p.lex.ConsumeToken()
-		if err != nil {
-			return unary, err
-		}
-		conj := &binaryExpression{nodes: [2]Node{nil, nil}}
-		conj.operator = t.String()
-		conj.Add(unary)
-		rhs, err := p.build(conj)
-		if err != nil {
-			return conj, err
-		}
-		conj.Add(rhs)
-		return conj, nil
-	}
-	if isCompareToken(next) {
-		return unary, fmt.Errorf("ln:%d:%d dangling comparator", p.lex.ln, p.lex.posInLine)
-	}
-	if next == tokenBraceClose && parent.isRoot() {
-		return unary, fmt.Errorf("ln:%d:%d syntax error (invalid closing brace `)` )", p.lex.ln, p.lex.posInLine)
-	}
-	return unary, nil
-}
-
-func (p *Parser) handleBinaryExpression(t tokenType, parent Node) (Node, error) {
-	bin := &binaryExpression{nodes: [2]Node{nil, nil}}
-	bin.operator = t.String()
-	bin.Add(&constantExpression{value: p.lex.lastValue(), selector: true, recommended: ValueRecommendationString})
-	t, err := p.lex.ConsumeToken()
-	if err != nil {
-		return bin, err
-	}
-	if isCompareToken(t) {
-		bin.operator = t.String()
-	} else {
-		return bin, fmt.Errorf("ln:%d:%d syntax error (got `%s` but expected a value)", p.lex.ln, p.lex.posInLine, t.String())
-	}
-
-	validator := defaultValidator
-	if isNumberOrDateComparision(t) {
-		validator = numberOrDateExpressionValidator
-	}
-	con, err := p.handleArgumentConstant(validator)
-	if err != nil {
-		return bin, err
-	}
-	bin.Add(con)
-
-	next, _, err := p.lex.PeekNextToken()
-	if err != nil {
-		return bin, err
-	}
-	if isLogicToken(next) {
-		t, err = p.lex.ConsumeToken()
-		if err != nil {
-			return bin, err
-		}
-		conj := &binaryExpression{nodes: [2]Node{nil, nil}}
-		conj.operator = t.String()
-		conj.Add(bin)
-		rhs, err := p.build(conj)
-		if err != nil {
-			return conj, err
-		}
-		conj.Add(rhs)
-		return conj, nil
-	}
-	if isCompareToken(next) {
-		return bin, fmt.Errorf("ln:%d:%d dangling comparator", p.lex.ln, p.lex.posInLine)
-	}
-	if next == tokenBraceClose && parent.isRoot() {
-		return bin, fmt.Errorf("ln:%d:%d syntax error (invalid closing brace `)` )", p.lex.ln, p.lex.posInLine)
-	}
-	return bin, nil
-}
-
-func (p *Parser) checkDanglingChild(n Node) bool {
-	if n.NodeType() == NodeTypeBinary {
-		if len(n.Children()) != 2 {
-			return true
-		}
-	}
-	return false
+	p *v2.Parser
 }
 
-// checkImpossibleTokensOnEnter checks for tokens that should not appear on enter `build`
-func (p *Parser) checkImpossibleTokensOnEnter(t tokenType) error {
-	if t == tokenBraceClose {
-		return fmt.Errorf("ln:%d:%d syntax error (invalid closing brace `)` )", p.lex.ln, p.lex.posInLine)
-	}
-
-	if isLogicToken(t) {
-		return fmt.Errorf("ln:%d:%d dangling operator", p.lex.ln, p.lex.posInLine)
-	}
+// Option configures a Parser
+type Option = v2.Option
 
-	if isCompareToken(t) {
-		return fmt.Errorf("ln:%d:%d dangling comparator", p.lex.ln, p.lex.posInLine)
+// parser returns the version 2 parser, the zero value parses with the default configuration
+func (p *Parser) parser() *v2.Parser {
+	if p.p == nil {
+		return defaultParser
 	}
-	return nil
+	return p.p
 }
 
-// checkForEOF checks for correct end of file
-func (p *Parser) checkForEOF(t tokenType, node Node) (bool, error) {
-	if t == tokenEOF {
-		if p.checkDanglingChild(node) {
-			return true, fmt.Errorf("ln:%d:%d dangling operator", p.lex.ln, p.lex.posInLine)
-
-		}
-		return true, nil
-	}
-	return false, nil
-}
+var defaultParser = v2.New()
 
-func (p *Parser) checkEndOrError(t tokenType, parent Node) (bool, error) {
-	if ok, err := p.checkForEOF(t, parent); ok {
-		return ok, err
-	}
-	if err := p.checkImpossibleTokensOnEnter(t); err != nil {
-		return true, err
-	}
-	return false, nil
-}
-
-func (p *Parser) mergeSubExpression(sub, parent Node) (Node, error) {
-	t, err := p.lex.ConsumeToken()
-	if err != nil {
-		return parent, err
-	}
-	conj := &binaryExpression{nodes: [2]Node{nil, nil}}
-	conj.operator = t.String()
-	conj.Add(sub)
-
-	rhs, err := p.build(conj)
-	if err != nil {
-		return conj, err
-	}
-	conj.Add(rhs)
-	parent.Add(conj)
-	return parent, nil
+// WithSpacesInValues allows whitespace within unquoted arguments, e.g. `name==John Doe;age=gt=1`,
+// whitespace before a operator, a closing brace or the end of the input is not part of the argument
+//
+// Deprecated: use WithSpacesInValues of github.com/eisenwinter/fiql-parser/v2.
+func WithSpacesInValues() Option {
+	return v2.WithSpacesInValues()
 }
 
-func (p *Parser) build(parent Node) (Node, error) {
-	t, err := p.lex.ConsumeToken()
-	if err != nil {
-		return parent, err
-	}
-	if ok, err := p.checkEndOrError(t, parent); ok {
-		return parent, err
-	}
-	if t == tokenBraceOpen {
-		sub, err := p.handleSubExpression(parent)
-		if err != nil {
-			return parent, err
-		}
-		t, err := p.lex.ConsumeToken()
-		if err != nil {
-			return parent, err
-		}
-		if t != tokenBraceClose {
-			return parent, fmt.Errorf("ln:%d:%d syntax error (unclosed brace `)` )", p.lex.ln, p.lex.posInLine)
-		}
-
-		next, _, err := p.lex.PeekNextToken()
-		if err != nil {
-			return parent, err
-		}
-		if isLogicToken(next) {
-			return p.mergeSubExpression(sub, parent)
-		}
-		if parent.NodeType() == NodeTypeExpression {
-			parent.Add(sub)
-			return parent, nil
-		}
-		return sub, nil
-	}
-
-	if t == tokenValue {
-		next, _, err := p.lex.PeekNextToken()
-		if err != nil {
-			return parent, err
-		}
-		var nextExpr Node
-		if isSeperatorUnary(next) {
-			nextExpr, err = p.handleUnaryExpression(parent)
-		} else {
-			nextExpr, err = p.handleBinaryExpression(t, parent)
-		}
-		if parent.isRoot() {
-			parent.Add(nextExpr)
-			return parent, err
-		}
-		return nextExpr, err
-	}
-	return parent, err
-
+// Parse parses the supplied fiql and returns either a Expression or an error.
+// Parse never panics, any input (including invalid UTF-8) results in a Expression or a error,
+// this is verified by the fuzz target FuzzParse.
+//
+// Deprecated: use Parser.Parse of github.com/eisenwinter/fiql-parser/v2, it takes a context.
+func (p *Parser) Parse(input string) (Expression, error) {
+	return p.parser().Parse(context.Background(), input)
 }
 
-// Parse parses the supplied fiql and returns either a Expression or an error
-func (p *Parser) Parse(input string) (Expression, error) {
-	p.lex = &lexer{[]rune(input), 0, 1, 0, ""}
-	exp := Expression{root: true}
-	_, err := p.build(&exp)
-	return exp, err
+// ParseReader parses the fiql read from r, e.g. a request body. It does not stream:
+// the whole input is buffered in memory and only lexed once r is exhausted,
+// the buffer is kept once as read (UTF-8) and lexed in place without further conversion.
+// With WithMaxLength reading stops as soon as the limit is exceeded, which bounds the buffer,
+// so r may be a untrusted request body.
+//
+// Deprecated: use Parser.ParseReader of github.com/eisenwinter/fiql-parser/v2, it takes a context.
+func (p *Parser) ParseReader(r io.Reader) (Expression, error) {
+	return p.parser().ParseReader(context.Background(), r)
 }
 
 // NewParser returns a new fiql parser
-func NewParser() *Parser {
-	return &Parser{}
+//
+// Deprecated: use New of github.com/eisenwinter/fiql-parser/v2.
+func NewParser(opts ...Option) *Parser {
+	return &Parser{p: v2.New(opts...)}
 }
 
 // Parse instant parses the supplied fiql and returns either a Expression or an error
+//
+// Deprecated: use Parse of github.com/eisenwinter/fiql-parser/v2, it takes a context.
 func Parse(input string) (Expression, error) {
-	p := &Parser{}
-	return p.Parse(input)
+	return v2.Parse(context.Background(), input)
+}
+
+// ParseReader instant parses the fiql read from r,
+// the input is buffered like by Parser.ParseReader
+//
+// Deprecated: use ParseReader of github.com/eisenwinter/fiql-parser/v2, it takes a context.
+func ParseReader(r io.Reader) (Expression, error) {
+	return v2.ParseReader(context.Background(), r)
 }
@@ -1,366 +1,85 @@
 package fiqlparser
 
 import (
-	"encoding/json"
-	"errors"
-	"fmt"
+	"context"
 	"strings"
 	"testing"
-	"time"
 
+	v2 "github.com/eisenwinter/fiql-parser/v2"
 	"github.com/stretchr/testify/assert"
 )
 
-func ExampleParse() {
-	res, err := Parse("column==value")
-	if err != nil {
-		return
-	}
-	json, _ := json.Marshal(&res)
-	fmt.Printf("%s", json)
-	// Output:
-	// {"Type":"Expr","Operator":"","Nodes":[{"Type":"Binary","Operator":"==","Nodes":[{"Type":"Const","Value":"column"},{"Type":"Const","Value":"value"}]}]}
-}
+func TestParseDelegates(t *testing.T) {
+	e, err := Parse("a==1;b=gt=2")
+	assert.NoError(t, err)
+	assert.Equal(t, "(a == 1 AND b > 2)", e.String())
 
-type testVisitor struct {
-	sb strings.Builder
-}
+	// expressions and constants are interchangeable with version 2
+	other, err := v2.Parse(context.Background(), "a==1;b=gt=2")
+	assert.NoError(t, err)
+	assert.True(t, e.Equal(&other))
+	var op OperatorDefintion = v2.OperatorAND
+	assert.Equal(t, OperatorAND, op)
+	var cmp v2.Comparison = ComparisonGt
+	assert.Equal(t, ComparisonGt, cmp)
 
-func (t *testVisitor) VisitExpressionEntered() { t.sb.WriteString("(") }
-func (t *testVisitor) VisitExpressionLeft()    { t.sb.WriteString(")") }
-func (t *testVisitor) VisitOperator(operatorCtx OperatorContext) {
-	t.sb.WriteString(string(operatorCtx.Operator()))
-}
-func (t *testVisitor) VisitSelector(selectorCtx SelectorContext) {
-	t.sb.WriteString(selectorCtx.Selector())
-}
-func (t *testVisitor) VisitComparison(comparisonCtx ComparisonContext) {
-	t.sb.WriteString(string(comparisonCtx.Comparison()))
-}
-func (t *testVisitor) VisitArgument(argumentCtx ArgumentContext) {
-	if argumentCtx.StartsWithWildcard() {
-		t.sb.WriteString("*")
-	}
-	t.sb.WriteString(argumentCtx.AsString())
-	if argumentCtx.EndsWithWildcard() {
-		t.sb.WriteString("*")
-	}
+	_, err = Parse("a==")
+	var perr *ParseError
+	assert.ErrorAs(t, err, &perr)
 }
 
-func (t *testVisitor) String() string { return t.sb.String() }
-
-func TestParse(t *testing.T) {
+func TestParserDelegates(t *testing.T) {
 	var values = []struct {
-		fiql        string
-		stringOuput string
-		errorOutput error
+		parser *Parser
+		fiql   string
+		output string
 	}{
-		{fiql: "(column==value)", stringOuput: "((column == value))", errorOutput: nil},
-		{fiql: "column==value", stringOuput: "(column == value)", errorOutput: nil},
-		{fiql: "column!=value", stringOuput: "(column <> value)", errorOutput: nil},
-		{fiql: "column=ge=1", stringOuput: "(column >= 1)", errorOutput: nil},
-		{fiql: "column=le=1", stringOuput: "(column <= 1)", errorOutput: nil},
-		{fiql: "column=gt=1.1", stringOuput: "(column > 1.1)", errorOutput: nil},
-		{fiql: "column=lt=100", stringOuput: "(column < 100)", errorOutput: nil},
-		{fiql: "column=lt=+100", stringOuput: "(column < +100)", errorOutput: nil},
-		{fiql: "column=lt=-100", stringOuput: "(column < -100)", errorOutput: nil},
-		{fiql: "column      ==        value", stringOuput: "(column == value)", errorOutput: nil},
-		{fiql: "updated=gt=2003-12-13T00:00:00Z", stringOuput: "(updated > 2003-12-13T00:00:00Z)", errorOutput: nil},
-		{fiql: "column==value,b==a", stringOuput: "(column == value OR b == a)", errorOutput: nil},
-		{fiql: "column==value  ,   b==a", stringOuput: "(column == value OR b == a)", errorOutput: nil},
-		{fiql: "     column==value  ,   b==a     ", stringOuput: "(column == value OR b == a)", errorOutput: nil},
-		{fiql: "     column  ==  value  ,   b  ==  a     ", stringOuput: "(column == value OR b == a)", errorOutput: nil},
-		{fiql: "(column==value)", stringOuput: "((column == value))", errorOutput: nil},
-		{fiql: "column==va\\,lue,b==a", stringOuput: "(column == va,lue OR b == a)", errorOutput: nil},
-		{fiql: "title==foo*;(updated=lt=-P1D,title==*bar)", stringOuput: "(title == foo* AND (updated < -P1D OR title == *bar))", errorOutput: nil},
-		{fiql: "(title==foo*);(fml==x,(xfs==a;f==fx))", stringOuput: "((title == foo*) AND (fml == x OR (xfs == a AND f == fx)))", errorOutput: nil},
-		{fiql: "(title==foo*,test==a,fx==fa);(fml==x)", stringOuput: "((title == foo* OR test == a OR fx == fa) AND (fml == x))", errorOutput: nil},
-		{fiql: "(title==foo*);(fml==x,(xfs==a;f==fx)", stringOuput: "", errorOutput: errors.New("ln:1:36 syntax error (unclosed brace `)` )")},
-		{fiql: "title=ffoo*", stringOuput: "", errorOutput: errors.New("ln:1:6 unexpected input (got `=f` but expected one of ==,!=,=gt=,=ge=,=lt=,=le=)")},
-		{fiql: "title==fo,o*", stringOuput: "", errorOutput: errors.New("ln:1:12 syntax error (got `*` but expected a value)")},
-
-		{fiql: `a==value
-		; 
-		b==value`, stringOuput: "(a == value AND b == value)", errorOutput: nil},
-
-		{fiql: `
-		
-		)`, stringOuput: "", errorOutput: errors.New("ln:3:3 syntax error (invalid closing brace `)` )")},
-		{fiql: "column=ge=invalid", stringOuput: "", errorOutput: errors.New("ln:1:17 syntax error (got `invalid` but expected number or date or duration)")},
-		{fiql: "column=le=P1.4Y2M", stringOuput: "(column <= P1.4Y2M)", errorOutput: nil},
-		{fiql: "column=le=+P5W", stringOuput: "(column <= +P5W)", errorOutput: nil},
-		{fiql: "column=le=-P5W", stringOuput: "(column <= -P5W)", errorOutput: nil},
-		{fiql: "column=lt=P3DT4H59M", stringOuput: "(column < P3DT4H59M)", errorOutput: nil},
+		{parser: &Parser{}, fiql: "a==1,b==2", output: "(a == 1 OR b == 2)"},
+		{parser: NewParser(), fiql: "a==1,b==2", output: "(a == 1 OR b == 2)"},
+		{parser: NewParser(WithLogicalNodes()), fiql: "a==1;b==2;c==3", output: "(a == 1 AND b == 2 AND c == 3)"},
 	}
 	for _, v := range values {
-		res, err := Parse(v.fiql)
-		if v.errorOutput != nil {
-			assert.EqualError(t, err, v.errorOutput.Error())
-		} else {
-			if err != nil {
-				fmt.Printf(v.fiql)
-			}
-			assert.Nil(t, err)
-			assert.NotNil(t, res)
-			assert.Equal(t, v.stringOuput, res.String())
+		e, err := v.parser.Parse(v.fiql)
+		if assert.NoError(t, err, v.fiql) {
+			assert.Equal(t, v.output, e.String(), v.fiql)
+		}
+		e, err = v.parser.ParseReader(strings.NewReader(v.fiql))
+		if assert.NoError(t, err, v.fiql) {
+			assert.Equal(t, v.output, e.String(), v.fiql)
+		}
+		e, err = v.parser.ParseAll(v.fiql)
+		if assert.NoError(t, err, v.fiql) {
+			assert.Equal(t, v.output, e.String(), v.fiql)
 		}
-
-	}
-}
-
-func TestDangling(t *testing.T) {
-	_, err := Parse("a==b;")
-	assert.Error(t, err)
-	assert.Equal(t, errors.New("ln:1:5 dangling operator"), err)
-
-	_, err = Parse("a==b,")
-	assert.Error(t, err)
-	assert.Equal(t, errors.New("ln:1:5 dangling operator"), err)
-
-	_, err = Parse(",a==b")
-	assert.Error(t, err)
-	assert.Equal(t, errors.New("ln:1:1 dangling operator"), err)
-
-	_, err = Parse(";a==b")
-	assert.Error(t, err)
-	assert.Equal(t, errors.New("ln:1:1 dangling operator"), err)
-
-	_, err = Parse("==a==b")
-	assert.Error(t, err)
-	assert.Equal(t, errors.New("ln:1:2 dangling comparator"), err)
-
-	_, err = Parse("a==b!=")
-	assert.Error(t, err)
-	assert.Equal(t, errors.New("ln:1:4 dangling comparator"), err)
-
-	_, err = Parse("(a==b")
-	assert.Error(t, err)
-	assert.Equal(t, errors.New("ln:1:5 syntax error (unclosed brace `)` )"), err)
-
-	_, err = Parse("a==b)")
-	assert.Error(t, err)
-	assert.Equal(t, errors.New("ln:1:4 syntax error (invalid closing brace `)` )"), err)
-
-	_, err = Parse("()")
-	assert.Error(t, err)
-	assert.Equal(t, errors.New("ln:1:2 syntax error (invalid closing brace `)` )"), err)
-
-	_, err = Parse("a==")
-	assert.Error(t, err)
-	assert.Equal(t, errors.New("ln:1:3 syntax error (got `eof` but expected a value)"), err)
-}
-func TestVisitor(t *testing.T) {
-	p := NewParser()
-	tree, err := p.Parse("(title==foo*);(fml==x,(xfs==a;f==fx))")
-	assert.NoError(t, err)
-	v := &testVisitor{}
-	tree.Accept(v)
-	assert.Equal(t, "((title==foo*)AND(fml==xOR(xfs==aANDf==fx)))", v.String())
-}
-
-type testTypeVisitor struct {
-	sb  strings.Builder
-	raw interface{}
-}
-
-func (t *testTypeVisitor) VisitExpressionEntered()                         {}
-func (t *testTypeVisitor) VisitExpressionLeft()                            {}
-func (t *testTypeVisitor) VisitOperator(operatorCtx OperatorContext)       {}
-func (t *testTypeVisitor) VisitSelector(selectorCtx SelectorContext)       {}
-func (t *testTypeVisitor) VisitComparison(comparisonCtx ComparisonContext) {}
-func (t *testTypeVisitor) VisitArgument(argumentCtx ArgumentContext) {
-	t.sb.WriteString(string(argumentCtx.ValueRecommendation()))
-	switch argumentCtx.ValueRecommendation() {
-	case ValueRecommendationDateTime:
-		t.raw, _ = argumentCtx.AsTime()
-	case ValueRecommendationDuration:
-		t.raw, _ = argumentCtx.AsDuration()
-	case ValueRecommendationNumber:
-		t.raw, _ = argumentCtx.AsFloat64()
-	default:
-		t.raw = argumentCtx.AsString()
-	}
-}
-
-func (t *testTypeVisitor) String() string { return t.sb.String() }
-
-func TestRecommendedTypeString(t *testing.T) {
-	p := NewParser()
-	tree, err := p.Parse("title==foo*")
-	assert.NoError(t, err)
-	if err != nil {
-		return
-	}
-	v := &testTypeVisitor{}
-	tree.Accept(v)
-	assert.Equal(t, "string", v.String())
-	assert.Equal(t, "foo", v.raw)
-}
-
-func TestRecommendedTypeStrings(t *testing.T) {
-	p := NewParser()
-	tree, err := p.Parse("title==foo,blah==blub")
-	assert.NoError(t, err)
-	if err != nil {
-		return
-	}
-	v := &testTypeVisitor{}
-	tree.Accept(v)
-	assert.Equal(t, "stringstring", v.String())
-}
-
-func TestRecommendedTypeNumber(t *testing.T) {
-	p := NewParser()
-	tree, err := p.Parse("title=lt=-100.0")
-	assert.NoError(t, err)
-	if err != nil {
-		return
-	}
-	v := &testTypeVisitor{}
-	tree.Accept(v)
-	assert.Equal(t, "number", v.String())
-	assert.Equal(t, -100.0, v.raw)
-}
-
-func TestRecommendedTypeNumberEq(t *testing.T) {
-	p := NewParser()
-	tree, err := p.Parse("title==-100.0")
-	assert.NoError(t, err)
-	if err != nil {
-		return
-	}
-	v := &testTypeVisitor{}
-	tree.Accept(v)
-	assert.Equal(t, "number", v.String())
-	assert.Equal(t, -100.0, v.raw)
-}
-
-func TestRecommendedTypeDateTime(t *testing.T) {
-	p := NewParser()
-	tree, err := p.Parse("title=gt=2003-12-13T18:30:02Z")
-	assert.NoError(t, err)
-	if err != nil {
-		return
-	}
-	v := &testTypeVisitor{}
-	tree.Accept(v)
-	assert.Equal(t, "datetime", v.String())
-	assert.Equal(t, time.Date(2003, 12, 13, 18, 30, 2, 0, time.UTC), v.raw)
-}
-
-func TestRecommendedTypeDateTimeEq(t *testing.T) {
-	p := NewParser()
-	tree, err := p.Parse("title==2003-12-13T18:30:02Z")
-	assert.NoError(t, err)
-	if err != nil {
-		return
-	}
-	v := &testTypeVisitor{}
-	tree.Accept(v)
-	assert.Equal(t, "datetime", v.String())
-	assert.Equal(t, time.Date(2003, 12, 13, 18, 30, 2, 0, time.UTC), v.raw)
-}
-
-func TestRecommendedTypeDuration(t *testing.T) {
-	p := NewParser()
-	tree, err := p.Parse("title=gt=-P5Y")
-	assert.NoError(t, err)
-	if err != nil {
-		return
 	}
-	v := &testTypeVisitor{}
-	tree.Accept(v)
-	assert.Equal(t, "duration", v.String())
-	assert.Equal(t, ISO8601Duration{Negative: true, Years: 5, _string: "-P5Y"}, v.raw)
-}
-
-func TestRecommendedTypeDurationEq(t *testing.T) {
-	p := NewParser()
-	tree, err := p.Parse("title==-P5Y")
+	exprs, err := NewParser().ParseMulti("a==1\nb==2")
 	assert.NoError(t, err)
-	if err != nil {
-		return
+	assert.Len(t, exprs, 2)
+	_, err = NewParser(WithMaxLength(3)).Parse("a==1")
+	var perr *ParseError
+	if assert.ErrorAs(t, err, &perr) {
+		assert.Equal(t, ErrorCodeInputTooLong, perr.Code)
 	}
-	v := &testTypeVisitor{}
-	tree.Accept(v)
-	assert.Equal(t, "duration", v.String())
-	assert.Equal(t, ISO8601Duration{Negative: true, Years: 5, _string: "-P5Y"}, v.raw)
 }
 
-func TestJsonMarshall(t *testing.T) {
-	var values = []struct {
-		fiql        string
-		stringOuput string
-		errorOutput error
-	}{
-		{fiql: "column==value", stringOuput: `{"Type":"Expr","Operator":"","Nodes":[{"Type":"Binary","Operator":"==","Nodes":[{"Type":"Const","Value":"column"},{"Type":"Const","Value":"value"}]}]}`, errorOutput: nil},
-		{fiql: "column!=value", stringOuput: `{"Type":"Expr","Operator":"","Nodes":[{"Type":"Binary","Operator":"\u003c\u003e","Nodes":[{"Type":"Const","Value":"column"},{"Type":"Const","Value":"value"}]}]}`, errorOutput: nil},
-		{fiql: "title==foo*;(updated=lt=-P1D,title==*bar)", stringOuput: `{"Type":"Expr","Operator":"","Nodes":[{"Type":"Binary","Operator":"AND","Nodes":[{"Type":"Binary","Operator":"==","Nodes":[{"Type":"Const","Value":"title"},{"Type":"Const","Value":"foo*"}]},{"Type":"Expr","Operator":"","Nodes":[{"Type":"Binary","Operator":"OR","Nodes":[{"Type":"Binary","Operator":"\u003c","Nodes":[{"Type":"Const","Value":"updated"},{"Type":"Const","Value":"-P1D"}]},{"Type":"Binary","Operator":"==","Nodes":[{"Type":"Const","Value":"title"},{"Type":"Const","Value":"*bar"}]}]}]}]}]}`, errorOutput: nil},
-		{fiql: "(title==foo*);(fml==x,(xfs==a;f==fx))", stringOuput: `{"Type":"Expr","Operator":"","Nodes":[{"Type":"Binary","Operator":"AND","Nodes":[{"Type":"Expr","Operator":"","Nodes":[{"Type":"Binary","Operator":"==","Nodes":[{"Type":"Const","Value":"title"},{"Type":"Const","Value":"foo*"}]}]},{"Type":"Expr","Operator":"","Nodes":[{"Type":"Binary","Operator":"OR","Nodes":[{"Type":"Binary","Operator":"==","Nodes":[{"Type":"Const","Value":"fml"},{"Type":"Const","Value":"x"}]},{"Type":"Expr","Operator":"","Nodes":[{"Type":"Binary","Operator":"AND","Nodes":[{"Type":"Binary","Operator":"==","Nodes":[{"Type":"Const","Value":"xfs"},{"Type":"Const","Value":"a"}]},{"Type":"Binary","Operator":"==","Nodes":[{"Type":"Const","Value":"f"},{"Type":"Const","Value":"fx"}]}]}]}]}]}]}]}`, errorOutput: nil},
-	}
-
-	for _, v := range values {
-		res, err := Parse(v.fiql)
-		assert.NoError(t, err)
-		assert.NotNil(t, res)
-		j, err := json.Marshal(&res)
-		assert.NoError(t, err)
-		assert.Equal(t, v.stringOuput, string(j))
-	}
-}
-
-func TestHandling(t *testing.T) {
-	p := NewParser()
-	tree, err := p.Parse("a==b;c==d,f==g")
-	assert.NoError(t, err)
-	j, err := json.Marshal(&tree)
-	assert.NoError(t, err)
-	assert.Equal(t, `{"Type":"Expr","Operator":"","Nodes":[{"Type":"Binary","Operator":"AND","Nodes":[{"Type":"Binary","Operator":"==","Nodes":[{"Type":"Const","Value":"a"},{"Type":"Const","Value":"b"}]},{"Type":"Binary","Operator":"OR","Nodes":[{"Type":"Binary","Operator":"==","Nodes":[{"Type":"Const","Value":"c"},{"Type":"Const","Value":"d"}]},{"Type":"Binary","Operator":"==","Nodes":[{"Type":"Const","Value":"f"},{"Type":"Const","Value":"g"}]}]}]}]}`, string(j))
-
-	tree, err = p.Parse("(a==b;c==d),f==g")
+func TestFormatParser(t *testing.T) {
+	out, err := Format("a==1;(b==2;c==3)", FormatOptions{Spaced: true})
 	assert.NoError(t, err)
-	j, err = json.Marshal(&tree)
+	assert.Equal(t, "a==1 ; b==2 ; c==3", out)
+	_, err = Format("!(a==1)", FormatOptions{})
+	assert.Error(t, err)
+	out, err = Format("!(a==1)", FormatOptions{Parser: NewParser(WithNegation())})
 	assert.NoError(t, err)
-	assert.Equal(t, `{"Type":"Expr","Operator":"","Nodes":[{"Type":"Binary","Operator":"OR","Nodes":[{"Type":"Expr","Operator":"","Nodes":[{"Type":"Binary","Operator":"AND","Nodes":[{"Type":"Binary","Operator":"==","Nodes":[{"Type":"Const","Value":"a"},{"Type":"Const","Value":"b"}]},{"Type":"Binary","Operator":"==","Nodes":[{"Type":"Const","Value":"c"},{"Type":"Const","Value":"d"}]}]}]},{"Type":"Binary","Operator":"==","Nodes":[{"Type":"Const","Value":"f"},{"Type":"Const","Value":"g"}]}]}]}`, string(j))
-
+	assert.Equal(t, "!(a==1)", out)
 }
 
-func TestChildren(t *testing.T) {
-	p := NewParser()
-	tree, err := p.Parse("a==b;c==d")
+func TestMaxJSONDepthShared(t *testing.T) {
+	assert.Equal(t, 5000, MaxJSONDepth)
+	defer func(depth int) { MaxJSONDepth = depth }(MaxJSONDepth)
+	MaxJSONDepth = 1
+	assert.Equal(t, 1, v2.MaxJSONDepth)
+	e, err := Parse("a==1;b==2")
 	assert.NoError(t, err)
-	assert.NotNil(t, tree.Children()[0])
-	assert.NotNil(t, tree.Children()[0].Children()[0])
-	assert.NotNil(t, tree.Children()[0].Children()[1])
-
-	assert.NotNil(t, tree.Children()[0].Children()[0].Children()[0])
-	assert.NotNil(t, tree.Children()[0].Children()[0].Children()[1])
-	assert.NotNil(t, tree.Children()[0].Children()[1].Children()[0])
-	assert.NotNil(t, tree.Children()[0].Children()[1].Children()[1])
-}
-
-func TestParseUnary(t *testing.T) {
-	var values = []struct {
-		fiql        string
-		stringOuput string
-		errorOutput error
-	}{
-		{fiql: "column", stringOuput: "(column)", errorOutput: nil},
-		{fiql: "columnA,columnB==c", stringOuput: "(columnA OR columnB == c)", errorOutput: nil},
-		{fiql: "columnA,(columnB==c;columnC)", stringOuput: "(columnA OR (columnB == c AND columnC))", errorOutput: nil},
-	}
-	for _, v := range values {
-		res, err := Parse(v.fiql)
-		if v.errorOutput != nil {
-			assert.EqualError(t, err, v.errorOutput.Error())
-		} else {
-			if err != nil {
-				fmt.Printf(v.fiql)
-			}
-			assert.Nil(t, err)
-			assert.NotNil(t, res)
-			assert.Equal(t, v.stringOuput, res.String())
-		}
-
-	}
+	_, err = e.MarshalJSON()
+	assert.ErrorIs(t, err, ErrJSONTooDeep)
 }
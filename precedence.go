@@ -0,0 +1,11 @@
+package fiqlparser
+
+import v2 "github.com/eisenwinter/fiql-parser/v2"
+
+// WithLegacyPrecedence disables operator precedence, logical operators are then
+// grouped right to left as they are encountered (`a;b,c` is `a;(b,c)`) like in previous versions
+//
+// Deprecated: use WithLegacyPrecedence of github.com/eisenwinter/fiql-parser/v2.
+func WithLegacyPrecedence() Option {
+	return v2.WithLegacyPrecedence()
+}
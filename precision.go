@@ -0,0 +1,27 @@
+package fiqlparser
+
+import v2 "github.com/eisenwinter/fiql-parser/v2"
+
+// DatePeriod is a set of coarse date literal kinds
+type DatePeriod = v2.DatePeriod
+
+// DatePeriodMonth is a month literal, e.g. `2024-05`
+const DatePeriodMonth = v2.DatePeriodMonth
+
+// DatePeriodDay is a day literal, e.g. `2024-05-13`
+const DatePeriodDay = v2.DatePeriodDay
+
+// DatePeriodWeek is a ISO week literal, e.g. `2024-W05`
+const DatePeriodWeek = v2.DatePeriodWeek
+
+// DatePeriodQuarter is a quarter literal, e.g. `2024-Q2`
+const DatePeriodQuarter = v2.DatePeriodQuarter
+
+// DatePeriodAll contains all coarse date literal kinds
+const DatePeriodAll = v2.DatePeriodAll
+
+// ErrNoTimeRange is generated if a argument is not a date
+var ErrNoTimeRange = v2.ErrNoTimeRange
+
+// DateExpansion configures how coarse dates are expanded into ranges
+type DateExpansion = v2.DateExpansion
@@ -0,0 +1,6 @@
+package fiqlparser
+
+import v2 "github.com/eisenwinter/fiql-parser/v2"
+
+// ErrNoRange is generated if a argument is not a range
+var ErrNoRange = v2.ErrNoRange
@@ -0,0 +1,45 @@
+package fiqlparser
+
+import (
+	"context"
+
+	v2 "github.com/eisenwinter/fiql-parser/v2"
+)
+
+// ParseLenient parses the supplied fiql on a best effort basis, e.g. for interactive query builders.
+// Instead of stopping at the first syntax error the input is repaired and parsed again until it is valid:
+// missing arguments are replaced by placeholders (see ConstantNode.IsPlaceholder),
+// invalid arguments by placeholders of the expected type, unclosed braces and quotes are closed
+// and any other offending token (e.g. a dangling operator) is dropped.
+// All errors met are returned positioned within input, so are the positions of the nodes.
+// Errors caused by earlier repairs are not reported.
+// Inputs exceeding WithMaxLength or the budget as well as violations of WithStrictSpec are not repaired,
+// the empty expression is returned with the error.
+//
+// Deprecated: use Parser.ParseLenient of github.com/eisenwinter/fiql-parser/v2, it takes a context.
+func (p *Parser) ParseLenient(input string) (Expression, []*ParseError) {
+	return p.parser().ParseLenient(context.Background(), input)
+}
+
+// ParseAll parses the supplied fiql like Parse, but does not stop at the first syntax error:
+// the error is of type ParseErrors and reports every problem found with its position, e.g. to underline all of them at once.
+// The errors are collected as by ParseLenient, the expression is only valid if the error is nil.
+//
+// Deprecated: use Parser.ParseAll of github.com/eisenwinter/fiql-parser/v2, it takes a context.
+func (p *Parser) ParseAll(input string) (Expression, error) {
+	return p.parser().ParseAll(context.Background(), input)
+}
+
+// ParseAll instant parses the supplied fiql and reports all syntax errors, see Parser.ParseAll
+//
+// Deprecated: use ParseAll of github.com/eisenwinter/fiql-parser/v2, it takes a context.
+func ParseAll(input string) (Expression, error) {
+	return v2.ParseAll(context.Background(), input)
+}
+
+// ParseLenient instant parses the supplied fiql on a best effort basis, see Parser.ParseLenient
+//
+// Deprecated: use ParseLenient of github.com/eisenwinter/fiql-parser/v2, it takes a context.
+func ParseLenient(input string) (Expression, []*ParseError) {
+	return v2.ParseLenient(context.Background(), input)
+}
@@ -0,0 +1,25 @@
+package fiqlparser
+
+import v2 "github.com/eisenwinter/fiql-parser/v2"
+
+// ErrUnresolvedArgument is generated for dynamic arguments without resolver, see DynamicArguments
+var ErrUnresolvedArgument = v2.ErrUnresolvedArgument
+
+// ArgumentResolver returns the value of a dynamic argument from the context, e.g. the id of the authenticated user.
+// The value is typed like a parsed argument, e.g. `42` is a number and `2024-05-01` a datetime.
+type ArgumentResolver = v2.ArgumentResolver
+
+// DynamicArguments binds arguments which are only known when a expression is translated or evaluated,
+// e.g. `owner==@me` where `@me` is the authenticated user.
+// It is safe for concurrent use as long as its fields are not modified.
+type DynamicArguments = v2.DynamicArguments
+
+// WithDynamicArguments accepts unquoted `@name` arguments for the comparisons validating their argument,
+// e.g. `age=gt=@limit` or `created=bt=@period`, the resolved values are typed by DynamicArguments.Resolve.
+// Equality comparisons and tuples accept them without this option. The bounds of range tuples
+// (`=bt=[@a+@b]`) can not be dynamic.
+//
+// Deprecated: use WithDynamicArguments of github.com/eisenwinter/fiql-parser/v2.
+func WithDynamicArguments() Option {
+	return v2.WithDynamicArguments()
+}
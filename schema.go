@@ -0,0 +1,17 @@
+package fiqlparser
+
+import v2 "github.com/eisenwinter/fiql-parser/v2"
+
+// ErrComparisonNotAllowed is generated by Schema.Validate if a selector is used with a comparison it does not allow
+var ErrComparisonNotAllowed = v2.ErrComparisonNotAllowed
+
+// ErrInvalidArgumentType is generated by Schema.Validate if a argument does not match the type of the selector
+var ErrInvalidArgumentType = v2.ErrInvalidArgumentType
+
+// SelectorSchema describes a selector accepted by a endpoint
+type SelectorSchema = v2.SelectorSchema
+
+// Schema is the set of selectors a endpoint accepts, it validates expressions (see Validate)
+// and generates the documentation of the filter parameter (see JSONSchema, OpenAPIParameter, Markdown and HTML),
+// so both are derived from the same definition
+type Schema = v2.Schema
@@ -0,0 +1,25 @@
+package fiqlparser
+
+import v2 "github.com/eisenwinter/fiql-parser/v2"
+
+// DefaultSelectorPathSeparator separates the segments of nested selectors unless configured otherwise, e.g. `author.name`
+const DefaultSelectorPathSeparator = v2.DefaultSelectorPathSeparator
+
+// PathSegment is a segment of a selector path, either a field name or a array index (`[0]`)
+type PathSegment = v2.PathSegment
+
+// WithSelectorPathSeparator configures the separator of nested selectors (see SelectorContext.Path),
+// e.g. WithSelectorPathSeparator('/') for `author/name`, DefaultSelectorPathSeparator is used otherwise
+//
+// Deprecated: use WithSelectorPathSeparator of github.com/eisenwinter/fiql-parser/v2.
+func WithSelectorPathSeparator(separator rune) Option {
+	return v2.WithSelectorPathSeparator(separator)
+}
+
+// SplitSelectorPath splits the selector into its segments, e.g. for translators working on Predicate.Selector.
+// Trailing `[n]` of a segment are array indices if n is a non-negative integer, otherwise they are part of the name.
+//
+// Deprecated: use SplitSelectorPath of github.com/eisenwinter/fiql-parser/v2.
+func SplitSelectorPath(selector string, separator rune) []PathSegment {
+	return v2.SplitSelectorPath(selector, separator)
+}
@@ -0,0 +1,62 @@
+package fiqlparser
+
+import (
+	"regexp"
+
+	v2 "github.com/eisenwinter/fiql-parser/v2"
+)
+
+// WithSelectorPattern enforces a naming convention for selectors, e.g. `^[a-z][a-z0-9_.]{0,63}$`,
+// selectors not matching the pattern fail with ErrorCodeInvalidSelector
+//
+// Deprecated: use WithSelectorPattern of github.com/eisenwinter/fiql-parser/v2.
+func WithSelectorPattern(pattern *regexp.Regexp) Option {
+	return v2.WithSelectorPattern(pattern)
+}
+
+// SelectorCase is the policy for the case of selectors
+type SelectorCase = v2.SelectorCase
+
+const (
+	// SelectorCasePreserve keeps selectors as written
+	SelectorCasePreserve = v2.SelectorCasePreserve
+	// SelectorCaseLower lower cases all selectors, e.g. `Name` and `NAME` both result in `name`
+	SelectorCaseLower = v2.SelectorCaseLower
+	// SelectorCaseInsensitive matches selectors case insensitively against the known selectors
+	// and replaces them by the known spelling, e.g. `CREATEDAT` results in `createdAt`.
+	// Unknown selectors and selectors matching multiple known selectors are kept as written.
+	SelectorCaseInsensitive = v2.SelectorCaseInsensitive
+)
+
+// WithSelectorCase sets the case policy for selectors, known are the selectors of the schema
+// (e.g. the keys of SQLTranslator.Columns) matched by SelectorCaseInsensitive.
+// The policy is applied before the selector pattern check and the selector mapper,
+// so validation, rewriting and serialization all work on the same selector.
+//
+// Deprecated: use WithSelectorCase of github.com/eisenwinter/fiql-parser/v2.
+func WithSelectorCase(policy SelectorCase, known ...string) Option {
+	return v2.WithSelectorCase(policy, known...)
+}
+
+// WithCaseInsensitiveSelectors lower cases all selectors, e.g. `Name` and `name` both result in `name`,
+// it is a shorthand for WithSelectorCase(SelectorCaseLower)
+//
+// Deprecated: use WithCaseInsensitiveSelectors of github.com/eisenwinter/fiql-parser/v2.
+func WithCaseInsensitiveSelectors() Option {
+	return v2.WithCaseInsensitiveSelectors()
+}
+
+// WithSelectorMapper maps all selectors at parse time, e.g. to field names (see SelectorSnakeCase).
+// The mapper is applied after the case policy and the selector pattern check.
+//
+// Deprecated: use WithSelectorMapper of github.com/eisenwinter/fiql-parser/v2.
+func WithSelectorMapper(mapper func(selector string) string) Option {
+	return v2.WithSelectorMapper(mapper)
+}
+
+// SelectorSnakeCase converts camel case selectors to snake case, e.g. `user.createdAt` to `user.created_at`
+//
+// Deprecated: use SelectorSnakeCase of github.com/eisenwinter/fiql-parser/v2.
+func SelectorSnakeCase(selector string) string {
+	return v2.SelectorSnakeCase(selector)
+}
@@ -0,0 +1,21 @@
+package fiqlparser
+
+import v2 "github.com/eisenwinter/fiql-parser/v2"
+
+// SharedSubexpression is a sub filter contained in multiple expressions
+type SharedSubexpression = v2.SharedSubexpression
+
+// SharedSubexpressions computes the sub filters contained in at least minShared of the supplied expressions
+// (minShared is at least 2). This includes common predicates, common sub expressions and common
+// subsets of the operands of AND operations (e.g. `a==1;b==2` for `a==1;b==2;c==3` and `b==2;d==4;a==1`).
+// The result is minimal, a sub filter is omitted if a larger sub filter containing it is shared by the same expressions.
+// It is intended to find candidates for materialized views or cached segments.
+// The result is sorted by the number of expressions sharing the sub filter, then by the sub filter.
+//
+// Deprecated: use SharedSubexpressions of github.com/eisenwinter/fiql-parser/v2.
+func SharedSubexpressions(exprs []Expression, minShared int) []SharedSubexpression {
+	return v2.SharedSubexpressions(exprs, minShared)
+}
+
+// RepeatedSubexpression is a sub filter occurring multiple times within one expression
+type RepeatedSubexpression = v2.RepeatedSubexpression
@@ -0,0 +1,46 @@
+package fiqlparser
+
+import v2 "github.com/eisenwinter/fiql-parser/v2"
+
+// SQLPlaceholderStyle defines how parameters are referenced in the generated SQL
+type SQLPlaceholderStyle = v2.SQLPlaceholderStyle
+
+// SQLPlaceholderQuestion uses `?` placeholders (MySQL, SQLite, sqlx before Rebind, GORM)
+const SQLPlaceholderQuestion = v2.SQLPlaceholderQuestion
+
+// SQLPlaceholderDollar uses numbered `$1` placeholders (PostgreSQL)
+const SQLPlaceholderDollar = v2.SQLPlaceholderDollar
+
+// SQLPlaceholderNamed uses named `:fiql_arg_1` placeholders (sqlx named queries, database/sql named args),
+// see TranslateNamed for the bindings
+const SQLPlaceholderNamed = v2.SQLPlaceholderNamed
+
+// SQLFullTextFunc translates a full text search (`=q=`) on the column,
+// param is the placeholder of the search term
+type SQLFullTextFunc = v2.SQLFullTextFunc
+
+// SQLFullTextPostgres translates full text searches using tsvector and plainto_tsquery,
+// config is the text search configuration (e.g. `english`), empty for the default configuration
+//
+// Deprecated: use SQLFullTextPostgres of github.com/eisenwinter/fiql-parser/v2.
+func SQLFullTextPostgres(config string) SQLFullTextFunc {
+	return v2.SQLFullTextPostgres(config)
+}
+
+// SQLFullTextMySQL translates full text searches using MATCH AGAINST in natural language mode,
+// the column needs a FULLTEXT index
+//
+// Deprecated: use SQLFullTextMySQL of github.com/eisenwinter/fiql-parser/v2.
+func SQLFullTextMySQL(column string, param string) string {
+	return v2.SQLFullTextMySQL(column, param)
+}
+
+// SQLTranslator translates a expression to a SQL condition.
+// Values are never inlined, they are always passed as parameters.
+// Selectors are only accepted if they are mapped to a column, so user input never ends up in the SQL.
+//
+// The result can be passed to GORM (db.Where(cond, args...)) or sqlx.
+type SQLTranslator = v2.SQLTranslator
+
+// SQLStatement is a translated condition with a key identifying its shape
+type SQLStatement = v2.SQLStatement
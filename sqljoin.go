@@ -0,0 +1,7 @@
+package fiqlparser
+
+import v2 "github.com/eisenwinter/fiql-parser/v2"
+
+// SQLRelation describes a table related by a selector path,
+// e.g. the relation `customer` is used for the selector `customer.name`
+type SQLRelation = v2.SQLRelation
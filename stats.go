@@ -0,0 +1,7 @@
+package fiqlparser
+
+import v2 "github.com/eisenwinter/fiql-parser/v2"
+
+// ExpressionStats describes the size and complexity of a expression, e.g. to reject or bill
+// complex queries or to log query complexity metrics
+type ExpressionStats = v2.ExpressionStats
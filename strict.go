@@ -0,0 +1,17 @@
+package fiqlparser
+
+import v2 "github.com/eisenwinter/fiql-parser/v2"
+
+// WithStrictSpec enforces the grammar of draft-nottingham-atompub-fiql-00, deviations fail with ErrorCodeSpecViolation:
+//   - selectors consist of unreserved characters (ALPHA, DIGIT, `-`, `.`, `_`, `~`) and percent-encodings
+//   - arguments additionally allow `!`, `$`, `'`, `*`, `+` and `=`, quotes have no special meaning
+//   - only the comparisons of the specification are allowed (no =bt=, =in=, =q=)
+//   - wildcards are only allowed at the start or end of == and != arguments
+//   - whitespace, escapes, labels and tuples are not allowed
+//
+// Percent-encodings are validated but not decoded.
+//
+// Deprecated: use WithStrictSpec of github.com/eisenwinter/fiql-parser/v2.
+func WithStrictSpec() Option {
+	return v2.WithStrictSpec()
+}
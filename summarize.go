@@ -0,0 +1,18 @@
+package fiqlparser
+
+import v2 "github.com/eisenwinter/fiql-parser/v2"
+
+// SummaryPart is a condition of a Summary, the fields allow rendering it in other languages
+type SummaryPart = v2.SummaryPart
+
+// Summary is a short description of a expression, see Summarize
+type Summary = v2.Summary
+
+// Summarize describes the expression in a few words, e.g. for chips in list views.
+// The conditions of the top level logical operation are summarized up to maxPredicates (at least one),
+// the remaining conditions are counted. Long arguments are truncated at character boundaries.
+//
+// Deprecated: use Summarize of github.com/eisenwinter/fiql-parser/v2.
+func Summarize(e Expression, maxPredicates int) Summary {
+	return v2.Summarize(e, maxPredicates)
+}
@@ -0,0 +1,37 @@
+package fiqlparser
+
+import v2 "github.com/eisenwinter/fiql-parser/v2"
+
+// TemplateSnippets are the text/template snippets used by a TemplateRenderer per node kind,
+// empty snippets fall back to a FIQL like default
+type TemplateSnippets = v2.TemplateSnippets
+
+// TemplateLogical is passed to the Logical snippet
+type TemplateLogical = v2.TemplateLogical
+
+// TemplateGroup is passed to the Group snippet
+type TemplateGroup = v2.TemplateGroup
+
+// TemplatePredicate is passed to the Predicate snippet
+type TemplatePredicate = v2.TemplatePredicate
+
+// TemplateUnary is passed to the Unary snippet
+type TemplateUnary = v2.TemplateUnary
+
+// TemplateNot is passed to the Not snippet
+type TemplateNot = v2.TemplateNot
+
+// TemplateValue is passed to the Values snippets
+type TemplateValue = v2.TemplateValue
+
+// TemplateRenderer renders a expression by executing a template per node kind,
+// it is intended for simple target formats which do not justify a visitor.
+// A renderer is safe for concurrent use.
+type TemplateRenderer = v2.TemplateRenderer
+
+// NewTemplateRenderer parses the snippets, `join` (strings.Join) is available in all snippets
+//
+// Deprecated: use NewTemplateRenderer of github.com/eisenwinter/fiql-parser/v2.
+func NewTemplateRenderer(snippets TemplateSnippets) (*TemplateRenderer, error) {
+	return v2.NewTemplateRenderer(snippets)
+}
@@ -0,0 +1,2 @@
+users.name LIKE ? ESCAPE '!' AND users.age > ?
+[]interface {}{"Jo%", 18}
@@ -0,0 +1,2 @@
+users.created_at BETWEEN ? AND ?
+[]interface {}{time.Date(2003, time.December, 13, 0, 0, 0, 0, time.UTC), time.Date(2003, time.December, 14, 0, 0, 0, 0, time.UTC)}
@@ -0,0 +1,2 @@
+users.name = ?
+[]interface {}{"John"}
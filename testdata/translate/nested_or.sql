@@ -0,0 +1,2 @@
+users.name = ? AND (users.age = ? OR users.status = ?)
+[]interface {}{"a", 1, "b"}
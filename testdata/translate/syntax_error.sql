@@ -0,0 +1 @@
+error: ln:1:8 dangling operator
@@ -0,0 +1,2 @@
+users.age IN (?, ?, ?)
+[]interface {}{18, 21, 65}
@@ -0,0 +1,2 @@
+users.deleted_at IS NOT NULL
+[]interface {}{}
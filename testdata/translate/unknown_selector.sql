@@ -0,0 +1 @@
+error: unknown selector `email`
@@ -0,0 +1,31 @@
+package fiqlparser
+
+import v2 "github.com/eisenwinter/fiql-parser/v2"
+
+// The token types reported by Tokenizer and WithTokenTrace,
+// comparisons are reported by their ComparisonDefintion (e.g. `==`, `>` or `IN`)
+const (
+	TokenTypeValue    = v2.TokenTypeValue
+	TokenTypeWildcard = v2.TokenTypeWildcard
+	TokenTypeLabel    = v2.TokenTypeLabel
+	TokenTypeNot      = v2.TokenTypeNot
+	TokenTypeOpen     = v2.TokenTypeOpen
+	TokenTypeClose    = v2.TokenTypeClose
+	TokenTypeAND      = v2.TokenTypeAND
+	TokenTypeOR       = v2.TokenTypeOR
+	TokenTypeEOF      = v2.TokenTypeEOF
+)
+
+// Tokenizer splits a input into the tokens consumed by the parser,
+// so syntax highlighters, formatters and completion can work on the same tokens.
+// Tuple arguments of `=in=` and `=bt=` are a single Value token (e.g. `[a+b]`).
+// The input is only tokenized, a sequence of valid tokens is not necessarily a valid expression.
+type Tokenizer = v2.Tokenizer
+
+// NewTokenizer returns a tokenizer of input, the options affecting the tokens
+// (WithTupleDelimiters, WithNegation, WithSpacesInValues and WithStrictSpec) are applied, others are ignored
+//
+// Deprecated: use NewTokenizer of github.com/eisenwinter/fiql-parser/v2.
+func NewTokenizer(input string, opts ...Option) *Tokenizer {
+	return v2.NewTokenizer(input, opts...)
+}
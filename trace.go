@@ -0,0 +1,14 @@
+package fiqlparser
+
+import v2 "github.com/eisenwinter/fiql-parser/v2"
+
+// Token is a token consumed by the parser, see WithTokenTrace and Tokenizer
+type Token = v2.Token
+
+// WithTokenTrace records the consumed tokens and attaches them to a ParseError (ParseError.Tokens),
+// intended for diagnosing parse failures from logs. Successful parses are not affected.
+//
+// Deprecated: use WithTokenTrace of github.com/eisenwinter/fiql-parser/v2.
+func WithTokenTrace() Option {
+	return v2.WithTokenTrace()
+}
@@ -0,0 +1,89 @@
+package fiqlparser_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	fiqlparser "github.com/eisenwinter/fiql-parser"
+	"github.com/eisenwinter/fiql-parser/translatetest"
+)
+
+var goldenColumns = map[string]string{
+	"name":    "users.name",
+	"age":     "users.age",
+	"created": "users.created_at",
+	"deleted": "users.deleted_at",
+	"status":  "users.status",
+}
+
+func TestGoldenSQL(t *testing.T) {
+	translator := &fiqlparser.SQLTranslator{Columns: goldenColumns}
+	translatetest.Run(t, "testdata/translate", "sql", func(e fiqlparser.Expression) (string, error) {
+		sql, args, err := translator.Translate(e)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%s\n%#v", sql, args), nil
+	})
+}
+
+func TestGoldenElasticsearch(t *testing.T) {
+	translator := &fiqlparser.ElasticsearchTranslator{Strict: true, Fields: map[string]fiqlparser.ElasticsearchField{
+		"name": {Name: "name"}, "age": {Name: "age"}, "created": {Name: "created"}, "deleted": {Name: "deleted"}, "status": {Name: "status"},
+	}}
+	translatetest.Run(t, "testdata/translate", "elastic", func(e fiqlparser.Expression) (string, error) {
+		query, err := translator.Translate(e)
+		if err != nil {
+			return "", err
+		}
+		b, err := json.MarshalIndent(query, "", "  ")
+		return string(b), err
+	})
+}
+
+func TestGoldenFIQL(t *testing.T) {
+	translatetest.Run(t, "testdata/translate", "canonical", func(e fiqlparser.Expression) (string, error) {
+		return e.ToFIQL(), nil
+	})
+}
+
+func TestGoldenCEL(t *testing.T) {
+	translator := &fiqlparser.CELTranslator{Strict: true, Variables: map[string]string{
+		"name": "user.name", "age": "user.age", "created": "user.created", "deleted": "user.deleted", "status": "user.status",
+	}}
+	translatetest.Run(t, "testdata/translate", "cel", func(e fiqlparser.Expression) (string, error) {
+		return translator.Translate(e)
+	})
+}
+
+func TestGoldenLDAP(t *testing.T) {
+	translator := &fiqlparser.LDAPTranslator{Strict: true, Attributes: map[string]string{
+		"name": "cn", "age": "age", "created": "createTimestamp", "deleted": "deletedAt", "status": "status",
+	}}
+	translatetest.Run(t, "testdata/translate", "ldap", func(e fiqlparser.Expression) (string, error) {
+		return translator.Translate(e)
+	})
+}
+
+func TestGoldenLucene(t *testing.T) {
+	translator := &fiqlparser.LuceneTranslator{Strict: true, Fields: map[string]string{
+		"name": "name", "age": "age", "created": "created", "deleted": "deleted_at", "status": "status",
+	}}
+	translatetest.Run(t, "testdata/translate", "lucene", func(e fiqlparser.Expression) (string, error) {
+		return translator.Translate(e)
+	})
+}
+
+func TestGoldenDynamoDB(t *testing.T) {
+	translator := &fiqlparser.DynamoDBTranslator{Strict: true, Attributes: map[string]string{
+		"name": "name", "age": "age", "created": "created", "deleted": "deleted_at", "status": "status",
+	}}
+	translatetest.Run(t, "testdata/translate", "dynamodb", func(e fiqlparser.Expression) (string, error) {
+		f, err := translator.Translate(e)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%s\n%v\n%#v", f.Expression, f.Names, f.Values), nil
+	})
+}
@@ -0,0 +1,76 @@
+// Package translatetest runs golden file tests for translators.
+//
+// A test directory contains `.fiql` inputs and, per target, the expected output in a file with the same
+// name and the target as extension, e.g. `and.fiql` is translated to SQL and compared with `and.sql`.
+// Parse and translation errors are compared as `error: <message>`.
+// Run the tests with `-translatetest.update` to (re)write the expected outputs.
+package translatetest
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	fiqlparser "github.com/eisenwinter/fiql-parser"
+)
+
+var update = flag.Bool("translatetest.update", false, "write the translated outputs to the golden files")
+
+// TranslateFunc translates a expression into the output of a target
+type TranslateFunc func(e fiqlparser.Expression) (string, error)
+
+// Run translates all `.fiql` files in dir with translate and compares the output with the
+// golden file `<name>.<target>`, each input runs as a sub test. opts configure the parser.
+func Run(t *testing.T, dir string, target string, translate TranslateFunc, opts ...fiqlparser.Option) {
+	t.Helper()
+	inputs, err := filepath.Glob(filepath.Join(dir, "*.fiql"))
+	if err != nil {
+		t.Fatalf("invalid test directory `%s`: %s", dir, err)
+	}
+	if len(inputs) == 0 {
+		t.Fatalf("no .fiql files in `%s`", dir)
+	}
+	parser := fiqlparser.NewParser(opts...)
+	for _, input := range inputs {
+		name := strings.TrimSuffix(filepath.Base(input), ".fiql")
+		golden := filepath.Join(dir, name+"."+target)
+		t.Run(name, func(t *testing.T) {
+			fiql, err := os.ReadFile(input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := output(parser, strings.TrimRight(string(fiql), "\r\n"), translate)
+			if *update {
+				if err := os.WriteFile(golden, []byte(got+"\n"), 0o644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			expected, err := os.ReadFile(golden)
+			if os.IsNotExist(err) {
+				t.Fatalf("missing golden file `%s`, run with -translatetest.update to create it", golden)
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := strings.TrimRight(string(expected), "\r\n"); got != want {
+				t.Errorf("`%s` translated to %s\n got: %s\nwant: %s", fiql, target, got, want)
+			}
+		})
+	}
+}
+
+// output returns the translation or the error in the form `error: <message>`
+func output(parser *fiqlparser.Parser, fiql string, translate TranslateFunc) string {
+	e, err := parser.Parse(fiql)
+	if err != nil {
+		return "error: " + err.Error()
+	}
+	out, err := translate(e)
+	if err != nil {
+		return "error: " + err.Error()
+	}
+	return out
+}
@@ -0,0 +1,24 @@
+package fiqlparser
+
+import v2 "github.com/eisenwinter/fiql-parser/v2"
+
+// TupleDelimiters are the characters enclosing and separating the elements of a tuple argument
+type TupleDelimiters = v2.TupleDelimiters
+
+// DefaultTupleDelimiters are used for tuples unless configured otherwise, e.g. `status=in=[open+closed]`
+var DefaultTupleDelimiters = v2.DefaultTupleDelimiters
+
+// WithTupleDelimiters configures the delimiters of tuple arguments,
+// e.g. WithTupleDelimiters('(', ',', ')') for `status=in=(open,closed)`
+//
+// Deprecated: use WithTupleDelimiters of github.com/eisenwinter/fiql-parser/v2.
+func WithTupleDelimiters(open rune, separator rune, close rune) Option {
+	return v2.WithTupleDelimiters(open, separator, close)
+}
+
+// NodeTypeTuple is a tuple argument (e.g. `[a+b]` of `=in=`), it is a ConstantNode
+// with a ConstantNode per element as children
+const NodeTypeTuple = v2.NodeTypeTuple
+
+// ErrNoTuple is generated if a argument is not a tuple
+var ErrNoTuple = v2.ErrNoTuple
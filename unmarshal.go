@@ -0,0 +1,6 @@
+package fiqlparser
+
+import v2 "github.com/eisenwinter/fiql-parser/v2"
+
+// ErrInvalidNodeJSON is generated by UnmarshalJSON if the JSON is no valid expression tree
+var ErrInvalidNodeJSON = v2.ErrInvalidNodeJSON
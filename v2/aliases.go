@@ -0,0 +1,60 @@
+package fiqlparser
+
+import (
+	"fmt"
+	"sort"
+)
+
+// SelectorAliases maps the public selectors of a API to internal names, e.g. `user` to `user_id`,
+// so the storage layout is not exposed. It is applied while parsing (see WithSelectorAliases)
+// or to a parsed expression (see Rewrite). It is safe for concurrent use as long as its fields are not modified.
+type SelectorAliases struct {
+	// Aliases maps the public selectors to their internal names
+	Aliases map[string]string
+	// Strict rejects selectors without alias instead of keeping them as written
+	Strict bool
+}
+
+// WithSelectorAliases replaces the selectors (and field references) by their internal names at parse time.
+// The aliases are applied after the case policy and the selector pattern check and before the selector mapper.
+// In strict mode unmapped selectors fail with ErrorCodeUnknownSelector, the error unwraps to ErrUnknownSelector.
+func WithSelectorAliases(aliases SelectorAliases) Option {
+	return func(p *Parser) {
+		p.aliases = &aliases
+	}
+}
+
+// Rewrite returns a copy of the expression with the selectors (and field references) replaced by their internal names.
+// In strict mode a error wrapping ErrUnknownSelector is returned for the first unmapped selector.
+func (a SelectorAliases) Rewrite(e Expression) (Expression, error) {
+	var err error
+	res := rewriteExpression(e, func(n Node) Node {
+		c, ok := n.(*constantExpression)
+		if !ok || err != nil || (!c.selector && c.recommended != ValueRecommendationField) {
+			return n
+		}
+		internal, ok := a.Aliases[c.value]
+		if !ok {
+			if a.Strict {
+				err = fmt.Errorf("%w `%s`", ErrUnknownSelector, c.value)
+			}
+			return n
+		}
+		c.value = internal
+		return c
+	})
+	if err != nil {
+		return e, err
+	}
+	return res, nil
+}
+
+// known returns the public selectors in alphabetical order
+func (a SelectorAliases) known() []string {
+	known := make([]string, 0, len(a.Aliases))
+	for public := range a.Aliases {
+		known = append(known, public)
+	}
+	sort.Strings(known)
+	return known
+}
@@ -0,0 +1,74 @@
+package fiqlparser
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var userAliases = map[string]string{"user": "user_id", "created": "created_at", "deleted": "deleted_at"}
+
+func TestWithSelectorAliases(t *testing.T) {
+	var values = []struct {
+		input    string
+		strict   bool
+		expected string
+		err      bool
+	}{
+		{input: "user==1;created=gt=2024-05-01", expected: "user_id==1;created_at=gt=2024-05-01"},
+		{input: "user==1,name==x", expected: "user_id==1,name==x"},
+		{input: "user==1;deleted", strict: true, expected: "user_id==1;deleted_at"},
+		{input: "user==1,name==x", strict: true, err: true},
+		{input: "created=gt=@deleted", strict: true, expected: "created_at=gt=@deleted_at"},
+		{input: "created=gt=@updated", strict: true, err: true},
+	}
+	for _, v := range values {
+		p := New(WithFieldReferences(), WithSelectorAliases(SelectorAliases{Aliases: userAliases, Strict: v.strict}))
+		e, err := p.Parse(context.Background(), v.input)
+		if v.err {
+			assert.ErrorIs(t, err, ErrUnknownSelector, v.input)
+			continue
+		}
+		if assert.NoError(t, err, v.input) {
+			assert.Equal(t, v.expected, e.ToFIQL(), v.input)
+		}
+	}
+}
+
+func TestWithSelectorAliasesError(t *testing.T) {
+	p := New(WithSelectorAliases(SelectorAliases{Aliases: userAliases, Strict: true}))
+	_, err := p.Parse(context.Background(), "user==1;\n  name==x")
+	var perr *ParseError
+	if assert.True(t, errors.As(err, &perr)) {
+		assert.Equal(t, ErrorCodeUnknownSelector, perr.Code)
+		assert.Equal(t, "name", perr.Token)
+		assert.Equal(t, []string{"created", "deleted", "user"}, perr.Expected)
+		assert.Equal(t, 2, perr.Line)
+		assert.Equal(t, 3, perr.Column)
+	}
+}
+
+func TestWithSelectorAliasesOrder(t *testing.T) {
+	p := New(WithCaseInsensitiveSelectors(), WithSelectorAliases(SelectorAliases{Aliases: userAliases}),
+		WithSelectorMapper(func(s string) string { return "t." + s }))
+	e, err := p.Parse(context.Background(), "User==1;name==x")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "t.user_id==1;t.name==x", e.ToFIQL())
+	}
+}
+
+func TestSelectorAliasesRewrite(t *testing.T) {
+	e := mustParse(t, "user==1;(created=gt=2024-05-01,name==x)")
+	res, err := SelectorAliases{Aliases: userAliases}.Rewrite(e)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "user_id==1;(created_at=gt=2024-05-01,name==x)", res.ToFIQL())
+	}
+	// the input is not modified
+	assert.Equal(t, "user==1;(created=gt=2024-05-01,name==x)", e.ToFIQL())
+
+	_, err = SelectorAliases{Aliases: userAliases, Strict: true}.Rewrite(e)
+	assert.ErrorIs(t, err, ErrUnknownSelector)
+	assert.EqualError(t, err, "unknown selector `name`")
+}
@@ -0,0 +1,179 @@
+package fiqlparser
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"strings"
+)
+
+func isLogicalOperator(operator string) bool {
+	return operator == string(OperatorAND) || operator == string(OperatorOR)
+}
+
+// unwrapExpression strips sub expressions (braces) from the node
+func unwrapExpression(n Node) Node {
+	for {
+		e, ok := n.(*Expression)
+		if !ok || e.node == nil {
+			return n
+		}
+		n = e.node
+	}
+}
+
+// flattenLogical collects all operands of a chain of the same logical operator
+func flattenLogical(n Node, operator string, operands []Node) []Node {
+	if e, ok := n.(*Expression); ok && e.label == "" && e.node != nil {
+		if op, ok := logicalOperator(e.node); ok && op == operator {
+			n = e.node
+		}
+	}
+	if op, ok := logicalOperator(n); ok && op == operator {
+		for _, c := range n.Children() {
+			operands = flattenLogical(c, operator, operands)
+		}
+		return operands
+	}
+	return append(operands, n)
+}
+
+// logicalOperator returns the operator if the node is a binary or n-ary logical operation
+func logicalOperator(n Node) (string, bool) {
+	switch node := n.(type) {
+	case *binaryExpression:
+		return node.operator, isLogicalOperator(node.operator)
+	case *logicalExpression:
+		return node.operator, true
+	}
+	return "", false
+}
+
+// logicalOperands returns the operator and the flattened operands if the node is a logical operation
+func logicalOperands(n Node) (string, []Node, bool) {
+	n = unwrapExpression(n)
+	op, ok := logicalOperator(n)
+	if !ok {
+		return "", nil, false
+	}
+	return op, flattenLogical(n, op, nil), true
+}
+
+// canonicalFIQL returns a normalized FIQL representation of the node,
+// sub expressions without effect are removed and the operands of logical
+// operations are sorted and deduplicated, so semantically equal trees result in the same string
+func canonicalFIQL(n Node) string {
+	c, _ := canonicalNode(n)
+	return c
+}
+
+// canonicalNode returns the canonical form and whether or not it is a logical operation with multiple operands
+func canonicalNode(n Node) (string, bool) {
+	n = unwrapExpression(n)
+	if op, operands, ok := logicalOperands(n); ok {
+		return joinCanonicalOperands(op, canonicalOperands(operands))
+	}
+	var b strings.Builder
+	switch node := n.(type) {
+	case *binaryExpression:
+		if node.nodes[0] != nil {
+			b.WriteString(canonicalFIQL(node.nodes[0]))
+		}
+		b.WriteString(fiqlOperators[node.operator])
+		if node.nodes[1] != nil {
+			b.WriteString(canonicalFIQL(node.nodes[1]))
+		}
+	case *notExpression:
+		b.WriteString("!(")
+		b.WriteString(canonicalFIQL(node.node))
+		b.WriteRune(')')
+	case *constantExpression:
+		if node.tuple != nil {
+			writeTuple(&b, node.tuple)
+			break
+		}
+		if node.recommended == ValueRecommendationParameter {
+			b.WriteString(":" + node.value)
+			break
+		}
+		if node.recommended == ValueRecommendationField {
+			b.WriteRune('@')
+		}
+		if node.prefixWildcard {
+			b.WriteRune('*')
+		}
+		quote := rune(0)
+		if quoteChangesMeaning(node) {
+			quote = '"'
+		}
+		writeFIQLValue(&b, node.value, quote)
+		if node.suffixWildcard {
+			b.WriteRune('*')
+		}
+	}
+	return b.String(), false
+}
+
+// quoteChangesMeaning reports whether the constant has a other recommendation than the value would get unquoted,
+// e.g. `"null"` is a string while `null` is the null literal, so it has to stay quoted in the canonical form
+func quoteChangesMeaning(c *constantExpression) bool {
+	if c.prefixWildcard || c.suffixWildcard {
+		return false
+	}
+	_, rec, _ := defaultValidator(c.value)
+	return c.recommended != rec
+}
+
+// canonicalOperand is the canonical form of a operand of a logical operation
+type canonicalOperand struct {
+	fiql     string
+	compound bool
+}
+
+// String returns the operand, compound operands are enclosed in braces
+func (o canonicalOperand) String() string {
+	if o.compound {
+		return "(" + o.fiql + ")"
+	}
+	return o.fiql
+}
+
+func canonicalOperands(operands []Node) []canonicalOperand {
+	res := make([]canonicalOperand, 0, len(operands))
+	for _, o := range operands {
+		c, compound := canonicalNode(o)
+		res = append(res, canonicalOperand{fiql: c, compound: compound})
+	}
+	return res
+}
+
+// joinCanonicalOperands sorts, deduplicates and joins canonical operands
+func joinCanonicalOperands(operator string, operands []canonicalOperand) (string, bool) {
+	sorted := append([]canonicalOperand{}, operands...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].String() < sorted[j].String() })
+	unique := make([]string, 0, len(sorted))
+	for i, v := range sorted {
+		if i == 0 || v.String() != sorted[i-1].String() {
+			unique = append(unique, v.String())
+		}
+	}
+	if len(unique) == 1 {
+		return sorted[0].fiql, sorted[0].compound
+	}
+	return strings.Join(unique, fiqlOperators[operator]), true
+}
+
+// Equal reports whether both expressions are semantically equal, that is if they are equal after
+// removing braces without effect and sorting and deduplicating the operands of logical operations.
+// Labels and whitespace are ignored, quotes only if they do not change the meaning of the value
+// (e.g. `a=="x"` equals `a==x` but `a=="null"` does not equal `a==null`).
+func (e *Expression) Equal(other *Expression) bool {
+	return canonicalFIQL(e) == canonicalFIQL(other)
+}
+
+// Fingerprint returns a stable hash (hex encoded SHA-256) of the canonical form of the expression,
+// semantically equal expressions (see Equal) have the same fingerprint, e.g. to key cached query results
+func (e *Expression) Fingerprint() string {
+	sum := sha256.Sum256([]byte(canonicalFIQL(e)))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,67 @@
+package fiqlparser
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpressionEqual(t *testing.T) {
+	var values = []struct {
+		a     string
+		b     string
+		equal bool
+	}{
+		{a: "a==b;c==d", b: "c==d;a==b", equal: true},
+		{a: "a==b;c==d", b: " a == b ; c == d ", equal: true},
+		{a: "((a==b));c==d", b: "a==b;(c==d)", equal: true},
+		{a: "a==b,a==b", b: "a==b", equal: true},
+		{a: `name=="x"`, b: "name==x", equal: true},
+		{a: `name=='x'`, b: `name=="x"`, equal: true},
+		{a: `a=="10"`, b: "a==10", equal: true},
+		{a: `a=="null"`, b: "a==null", equal: false},
+		{a: `a=='null'`, b: `a=="null"`, equal: true},
+		{a: `a=="true"`, b: "a==true", equal: false},
+		{a: `a=="1..5"`, b: "a==1..5", equal: false},
+		{a: `a=="null",a==null`, b: "a==null", equal: false},
+		{a: "a==b;(c==d,e==f)", b: "(f==e,c==d);a==b", equal: false},
+		{a: "a==b;(c==d,e==f)", b: "(e==f,c==d);a==b", equal: true},
+		{a: "a==b;c==d", b: "a==b,c==d", equal: false},
+		{a: "a==b*", b: "a==b", equal: false},
+		{a: "a=gt=1", b: "a=ge=1", equal: false},
+		{a: "", b: "", equal: true},
+		{a: "", b: "a==b", equal: false},
+	}
+	for _, v := range values {
+		a, err := Parse(context.Background(), v.a)
+		assert.NoError(t, err)
+		b, err := Parse(context.Background(), v.b)
+		assert.NoError(t, err)
+		assert.Equal(t, v.equal, a.Equal(&b), "`%s` `%s`", v.a, v.b)
+		assert.Equal(t, v.equal, a.Fingerprint() == b.Fingerprint(), "`%s` `%s`", v.a, v.b)
+	}
+}
+
+func TestExpressionEqualQuotedLiterals(t *testing.T) {
+	// the SQL translator treats both forms differently, so they must not share a cache key
+	quoted, err := Parse(context.Background(), `a=="null"`)
+	assert.NoError(t, err)
+	null, err := Parse(context.Background(), "a==null")
+	assert.NoError(t, err)
+	translator := &SQLTranslator{Columns: map[string]string{"a": "a"}}
+	quotedSQL, _, _ := translator.Translate(quoted)
+	nullSQL, _, _ := translator.Translate(null)
+	assert.Equal(t, "a = ?", quotedSQL)
+	assert.Equal(t, "a IS NULL", nullSQL)
+	assert.False(t, quoted.Equal(&null))
+	assert.NotEqual(t, quoted.Fingerprint(), null.Fingerprint())
+}
+
+func TestExpressionFingerprintStable(t *testing.T) {
+	res, err := Parse(context.Background(), "a==b;c==d")
+	assert.NoError(t, err)
+	// sha256 of the canonical form `a==b;c==d`
+	assert.Len(t, res.Fingerprint(), 64)
+	assert.Equal(t, "d2de799ff0ca3013992fc25a6e17509a06e2edc3898f5b8aa7cff597e64dee74", res.Fingerprint())
+}
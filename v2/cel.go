@@ -0,0 +1,303 @@
+package fiqlparser
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CELTranslator translates a expression to a CEL (Common Expression Language) expression,
+// e.g. `name==Jo*;age=gt=18` becomes `name.startsWith("Jo") && age > 18`,
+// so filters can be evaluated by CEL based policy engines.
+// The zero value maps every selector to the variable of the same name.
+type CELTranslator struct {
+	// Variables maps selectors to CEL field paths (e.g. `resource.owner.name`), the paths are used as is
+	Variables map[string]string
+	// Strict rejects selectors which are not mapped in Variables
+	Strict bool
+	// Resolver resolves the path of selectors which are not mapped in Variables
+	Resolver SelectorResolver
+	// Now is the CEL expression of the current time (e.g. `request.time`),
+	// durations are added to it (`updated=gt=-P1D` becomes `updated > request.time + duration("-86400s")`).
+	// Durations are rejected if it is empty.
+	Now string
+}
+
+// celPath matches selectors which are valid CEL field paths
+var celPath = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)*$`)
+
+var celOperators = map[string]string{
+	string(OperatorAND):   "&&",
+	string(OperatorOR):    "||",
+	string(ComparisonEq):  "==",
+	string(ComparisonNeq): "!=",
+	string(ComparisonGt):  ">",
+	string(ComparisonGte): ">=",
+	string(ComparisonLt):  "<",
+	string(ComparisonLte): "<=",
+}
+
+// Translate translates the expression to a CEL expression, the empty expression is translated to `true`
+func (t *CELTranslator) Translate(e Expression) (string, error) {
+	if e.node == nil {
+		return "true", nil
+	}
+	var b strings.Builder
+	if err := t.write(&b, &e); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+func (t *CELTranslator) path(selector string) (string, error) {
+	if p, ok := t.Variables[selector]; ok && p != "" {
+		return p, nil
+	}
+	if t.Resolver != nil {
+		return t.Resolver(selector)
+	}
+	if t.Strict || !celPath.MatchString(selector) {
+		return "", fmt.Errorf("%w `%s`", ErrUnknownSelector, selector)
+	}
+	return selector, nil
+}
+
+func (t *CELTranslator) write(b *strings.Builder, n Node) error {
+	switch node := n.(type) {
+	case *Expression:
+		return t.write(b, node.node)
+	case *binaryExpression:
+		if node.IsLogical() {
+			return t.writeLogical(b, node)
+		}
+		return t.writePredicate(b, node)
+	case *logicalExpression:
+		return t.writeLogical(b, node)
+	case *notExpression:
+		b.WriteString("!(")
+		if err := t.write(b, node.node); err != nil {
+			return err
+		}
+		b.WriteRune(')')
+		return nil
+	case *constantExpression:
+		p, err := t.path(node.value)
+		if err != nil {
+			return err
+		}
+		if strings.ContainsRune(p, '.') {
+			b.WriteString("has(")
+			b.WriteString(p)
+			b.WriteRune(')')
+			return nil
+		}
+		b.WriteString(p)
+		b.WriteString(" != null")
+		return nil
+	}
+	return fmt.Errorf("unsupported node `%v`", n)
+}
+
+// writeLogical joins the operands, nested logical operations are enclosed in parentheses
+func (t *CELTranslator) writeLogical(b *strings.Builder, node Node) error {
+	operator, operands, _ := logicalOperands(node)
+	for i, o := range operands {
+		if i > 0 {
+			b.WriteRune(' ')
+			b.WriteString(celOperators[operator])
+			b.WriteRune(' ')
+		}
+		_, _, nested := logicalOperands(unwrapExpression(o))
+		if nested {
+			b.WriteRune('(')
+		}
+		if err := t.write(b, o); err != nil {
+			return err
+		}
+		if nested {
+			b.WriteRune(')')
+		}
+	}
+	return nil
+}
+
+func (t *CELTranslator) writePredicate(b *strings.Builder, node *binaryExpression) error {
+	sel, arg, ok := predicateOperands(node)
+	if !ok {
+		return fmt.Errorf("incomplete comparison `%s`", node.String())
+	}
+	p, err := t.path(sel.value)
+	if err != nil {
+		return err
+	}
+	if arg.recommended == ValueRecommendationRange {
+		return t.writeBetween(b, p, node.operator, arg)
+	}
+	if arg.tuple != nil {
+		if node.operator != string(ComparisonIn) {
+			return fmt.Errorf("unsupported tuple comparison `%s`", node.operator)
+		}
+		return t.writeIn(b, p, arg.tuple)
+	}
+	if ref, ok := arg.Argument().Field(); ok {
+		return t.writeFieldComparison(b, p, node.operator, ref)
+	}
+	switch node.operator {
+	case string(ComparisonEq), string(ComparisonNeq):
+		if arg.prefixWildcard || arg.suffixWildcard {
+			if node.operator == string(ComparisonNeq) {
+				b.WriteRune('!')
+			}
+			writeCELWildcard(b, p, arg)
+			return nil
+		}
+	}
+	op, ok := celOperators[node.operator]
+	if !ok {
+		return fmt.Errorf("unsupported comparison `%s`", node.operator)
+	}
+	value, err := t.value(arg)
+	if err != nil {
+		return err
+	}
+	b.WriteString(p)
+	b.WriteRune(' ')
+	b.WriteString(op)
+	b.WriteRune(' ')
+	b.WriteString(value)
+	return nil
+}
+
+// writeFieldComparison compares two fields (see WithFieldReferences)
+func (t *CELTranslator) writeFieldComparison(b *strings.Builder, p string, operator string, ref string) error {
+	op, ok := celOperators[operator]
+	if !ok || op == "&&" || op == "||" {
+		return fmt.Errorf("unsupported field comparison `%s`", operator)
+	}
+	other, err := t.path(ref)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(b, "%s %s %s", p, op, other)
+	return nil
+}
+
+// writeBetween writes a range comparison, `==` and `=bt=` match the range and `!=` excludes it
+func (t *CELTranslator) writeBetween(b *strings.Builder, p string, operator string, arg *constantExpression) error {
+	low, high, ok := argumentRangeBounds(arg.value, arg.tuple)
+	if !ok {
+		return fmt.Errorf("%w `%s`", ErrNoRange, arg.value)
+	}
+	lv, err := t.value(low)
+	if err != nil {
+		return err
+	}
+	hv, err := t.value(high)
+	if err != nil {
+		return err
+	}
+	switch operator {
+	case string(ComparisonEq), string(ComparisonBetween):
+	case string(ComparisonNeq):
+		b.WriteRune('!')
+	default:
+		return fmt.Errorf("unsupported range comparison `%s`", operator)
+	}
+	fmt.Fprintf(b, "(%s >= %s && %s <= %s)", p, lv, p, hv)
+	return nil
+}
+
+// writeIn writes a membership test, elements with wildcards are matched separately
+func (t *CELTranslator) writeIn(b *strings.Builder, p string, tuple *tupleArgument) error {
+	values := make([]string, 0, len(tuple.elements))
+	wildcards := make([]*constantExpression, 0)
+	for _, el := range tuple.elements {
+		if el.prefixWildcard || el.suffixWildcard {
+			wildcards = append(wildcards, el)
+			continue
+		}
+		v, err := t.value(el)
+		if err != nil {
+			return err
+		}
+		values = append(values, v)
+	}
+	if len(wildcards) == 0 {
+		fmt.Fprintf(b, "%s in [%s]", p, strings.Join(values, ", "))
+		return nil
+	}
+	b.WriteRune('(')
+	if len(values) > 0 {
+		fmt.Fprintf(b, "%s in [%s] || ", p, strings.Join(values, ", "))
+	}
+	for i, el := range wildcards {
+		if i > 0 {
+			b.WriteString(" || ")
+		}
+		writeCELWildcard(b, p, el)
+	}
+	b.WriteRune(')')
+	return nil
+}
+
+// writeCELWildcard writes a prefix, suffix or infix match
+func writeCELWildcard(b *strings.Builder, p string, arg *constantExpression) {
+	b.WriteString(p)
+	switch {
+	case arg.prefixWildcard && arg.suffixWildcard:
+		b.WriteString(".contains(")
+	case arg.suffixWildcard:
+		b.WriteString(".startsWith(")
+	default:
+		b.WriteString(".endsWith(")
+	}
+	b.WriteString(strconv.Quote(arg.value))
+	b.WriteRune(')')
+}
+
+// value converts the argument to a CEL literal according to its recommendation,
+// datetimes become timestamps (coarse dates the start of the period) and durations are added to Now
+func (t *CELTranslator) value(arg *constantExpression) (string, error) {
+	if arg.quote != 0 || arg.prefixWildcard || arg.suffixWildcard {
+		return strconv.Quote(arg.value), nil
+	}
+	switch arg.recommended {
+	case ValueRecommendationNumber:
+		if i, err := strconv.ParseInt(arg.value, 10, 64); err == nil {
+			return strconv.FormatInt(i, 10), nil
+		}
+		f, err := strconv.ParseFloat(arg.value, 64)
+		if err != nil {
+			return "", err
+		}
+		s := strconv.FormatFloat(f, 'f', -1, 64)
+		if !strings.ContainsRune(s, '.') {
+			s += ".0"
+		}
+		return s, nil
+	case ValueRecommendationBoolean, ValueRecommendationNull:
+		return arg.value, nil
+	case ValueRecommendationDateTime:
+		tm, err := arg.Argument().AsTime()
+		if err != nil {
+			return "", err
+		}
+		return "timestamp(" + strconv.Quote(tm.UTC().Format(time.RFC3339Nano)) + ")", nil
+	case ValueRecommendationDuration:
+		if t.Now == "" {
+			return "", fmt.Errorf("duration `%s` requires CELTranslator.Now", arg.value)
+		}
+		d, err := arg.Argument().AsDuration()
+		if err != nil {
+			return "", err
+		}
+		td, err := d.ToTimeDuration()
+		if err != nil {
+			return "", err
+		}
+		return t.Now + " + duration(" + strconv.Quote(strconv.FormatFloat(td.Seconds(), 'f', -1, 64)+"s") + ")", nil
+	}
+	return strconv.Quote(arg.value), nil
+}
@@ -0,0 +1,77 @@
+package fiqlparser
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCELTranslator(t *testing.T) {
+	var values = []struct {
+		fiql string
+		cel  string
+		err  string
+	}{
+		{fiql: "name==John", cel: `name == "John"`},
+		{fiql: "name!=*ohn", cel: `!name.endsWith("ohn")`},
+		{fiql: "name==*oh*", cel: `name.contains("oh")`},
+		{fiql: `name=="18";age=ge=18.5,age=lt=+3`, cel: `(name == "18" && age >= 18.5) || age < 3`},
+		{fiql: "a==1,b==2;c==3", cel: `a == 1 || (b == 2 && c == 3)`},
+		{fiql: "(a==1,b==2);c==3", cel: `(a == 1 || b == 2) && c == 3`},
+		{fiql: "active==true;deleted==null", cel: `active == true && deleted == null`},
+		{fiql: "created=gt=2024-05", cel: `created > timestamp("2024-05-01T00:00:00Z")`},
+		{fiql: "age!=1..5", cel: `!(age >= 1 && age <= 5)`},
+		{fiql: "age=bt=[1+5]", cel: `(age >= 1 && age <= 5)`},
+		{fiql: "name=in=[a+b*+*c]", cel: `(name in ["a"] || name.startsWith("b") || name.endsWith("c"))`},
+		{fiql: "name=in=[a*]", cel: `(name.startsWith("a"))`},
+		{fiql: "deleted", cel: `deleted != null`},
+		{fiql: `note=="say \"hi\""`, cel: `note == "say \"hi\""`},
+		{fiql: "updated=gt=-P1D", err: "duration `-P1D` requires CELTranslator.Now"},
+		{fiql: "text=q=foo", err: "unsupported comparison `QUERY`"},
+		{fiql: "first-name==x", err: "unknown selector `first-name`"},
+	}
+	translator := &CELTranslator{}
+	for _, v := range values {
+		res, err := Parse(context.Background(), v.fiql)
+		if !assert.NoError(t, err, v.fiql) {
+			continue
+		}
+		cel, err := translator.Translate(res)
+		if v.err != "" {
+			assert.EqualError(t, err, v.err, v.fiql)
+			continue
+		}
+		if assert.NoError(t, err, v.fiql) {
+			assert.Equal(t, v.cel, cel, v.fiql)
+		}
+	}
+}
+
+func TestCELTranslatorOptions(t *testing.T) {
+	translator := &CELTranslator{
+		Variables: map[string]string{"updated": "resource.updated"},
+		Now:       "request.time",
+		Resolver: func(selector string) (string, error) {
+			return "resource.attributes." + selector, nil
+		},
+	}
+	res, err := New(WithNegation()).Parse(context.Background(), "updated=gt=-P1D;!(owner==me)")
+	if !assert.NoError(t, err) {
+		return
+	}
+	cel, err := translator.Translate(res)
+	if assert.NoError(t, err) {
+		assert.Equal(t, `resource.updated > request.time + duration("-86400s") && !(resource.attributes.owner == "me")`, cel)
+	}
+
+	res, err = Parse(context.Background(), "updated=gt=-P1M")
+	if assert.NoError(t, err) {
+		_, err = translator.Translate(res)
+		assert.ErrorIs(t, err, ErrNoFixedLength)
+	}
+
+	cel, err = translator.Translate(Expression{})
+	assert.NoError(t, err)
+	assert.Equal(t, "true", cel)
+}
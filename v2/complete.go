@@ -0,0 +1,170 @@
+package fiqlparser
+
+import (
+	"sort"
+	"strings"
+)
+
+// Completion is a candidate for the next token at the cursor, see Complete
+type Completion struct {
+	// Text is the token to insert
+	Text string
+	// Class is the kind of token, e.g. SpanSelector or SpanComparator
+	Class SpanClass
+	// Start is the byte offset the completion replaces the input from up to the cursor,
+	// it is the start of the partially typed token or the cursor itself
+	Start int
+}
+
+// Complete returns the candidates for the next token at the cursor (a byte offset within input), e.g. for filter builder UIs:
+// the selectors of the schema and `(` where a constraint starts, the comparisons allowed for the selector after it,
+// `true`/`false` for boolean selectors and the opening delimiter of tuples for `=in=` as argument
+// and the logical operators (and `)` within a sub expression) after a complete constraint.
+// Partially typed selectors, comparators and arguments are completed by prefix. Only the input before
+// the cursor is considered, the candidates are sorted by class and text.
+func Complete(input string, cursor int, schema Schema) []Completion {
+	if cursor < 0 {
+		cursor = 0
+	}
+	if cursor > len(input) {
+		cursor = len(input)
+	}
+	before := input[:cursor]
+	c := completer{schema: schema, cursor: cursor, res: make([]Completion, 0)}
+	t := NewTokenizer(before)
+	argument := false
+	var last Token
+	lastClass := SpanLogical
+	for {
+		tok, err := t.Next()
+		if err != nil {
+			// a partially typed comparator can not be tokenized yet
+			start := t.lex.start.ByteOffset
+			if partial := before[start:]; lastClass == SpanSelector && strings.HasPrefix(partial, "=") || strings.HasPrefix(partial, "!") {
+				c.comparators(start, partial)
+			}
+			return c.sorted()
+		}
+		if tok.Type == TokenTypeEOF {
+			break
+		}
+		lastClass = tokenClass(tok, &argument)
+		last = tok
+		switch lastClass {
+		case SpanSelector:
+			c.selector = tok.Literal
+		case SpanBrace:
+			if tok.Type == TokenTypeOpen {
+				c.depth++
+			} else {
+				c.depth--
+			}
+		case SpanComparator:
+			c.comparison = tok.Type
+		}
+	}
+	typing := last.End.ByteOffset == cursor && last.Type != ""
+	switch lastClass {
+	case SpanSelector:
+		if typing {
+			c.selectors(last.Position.ByteOffset, last.Literal)
+		}
+		if sel, ok := schema.selector(c.selector); ok || len(schema.Selectors) == 0 {
+			c.comparators(cursor, "")
+			if sel.Unary {
+				c.operators()
+			}
+		}
+	case SpanComparator:
+		c.arguments(cursor, "")
+	case SpanValue, SpanWildcard:
+		if typing && lastClass == SpanValue {
+			c.arguments(last.Position.ByteOffset, last.Literal)
+		}
+		c.operators()
+	case SpanBrace:
+		if last.Type == TokenTypeClose {
+			c.operators()
+			break
+		}
+		c.selectors(cursor, "")
+	default:
+		c.selectors(cursor, "")
+	}
+	return c.sorted()
+}
+
+// completer collects the completions of Complete
+type completer struct {
+	schema Schema
+	cursor int
+	// depth is the number of unclosed braces
+	depth int
+	// selector and comparison are the last ones before the cursor
+	selector   string
+	comparison string
+	res        []Completion
+}
+
+func (c *completer) add(text string, class SpanClass, start int, partial string) {
+	if strings.HasPrefix(text, partial) && text != partial {
+		c.res = append(c.res, Completion{Text: text, Class: class, Start: start})
+	}
+}
+
+// selectors suggests the selectors of the schema and a sub expression
+func (c *completer) selectors(start int, partial string) {
+	for _, sel := range c.schema.Selectors {
+		c.add(sel.Name, SpanSelector, start, partial)
+	}
+	if partial == "" {
+		c.add("(", SpanBrace, start, partial)
+	}
+}
+
+// comparators suggests the comparisons allowed for the selector
+func (c *completer) comparators(start int, partial string) {
+	sel, ok := c.schema.selector(c.selector)
+	if !ok {
+		sel = SelectorSchema{}
+	}
+	for _, op := range sel.comparisons() {
+		c.add(op, SpanComparator, start, partial)
+	}
+}
+
+// arguments suggests the values of boolean selectors and the opening delimiter of tuples
+func (c *completer) arguments(start int, partial string) {
+	if c.comparison == string(ComparisonIn) {
+		c.add(string(DefaultTupleDelimiters.Open), SpanBrace, start, partial)
+		return
+	}
+	sel, _ := c.schema.selector(c.selector)
+	if sel.Type == ValueRecommendationBoolean {
+		c.add("true", SpanValue, start, partial)
+		c.add("false", SpanValue, start, partial)
+	}
+}
+
+// operators suggests the logical operators and the closing brace of a open sub expression
+func (c *completer) operators() {
+	c.add(";", SpanLogical, c.cursor, "")
+	c.add(",", SpanLogical, c.cursor, "")
+	if c.depth > 0 {
+		c.add(")", SpanBrace, c.cursor, "")
+	}
+}
+
+// classOrder orders the completions by class
+var classOrder = map[SpanClass]int{SpanSelector: 0, SpanComparator: 1, SpanValue: 2, SpanLogical: 3, SpanBrace: 4}
+
+func (c *completer) sorted() []Completion {
+	sort.SliceStable(c.res, func(i, j int) bool {
+		a, b := c.res[i], c.res[j]
+		if a.Class != b.Class {
+			return classOrder[a.Class] < classOrder[b.Class]
+		}
+		return a.Text < b.Text
+	})
+	return c.res
+}
@@ -0,0 +1,58 @@
+package fiqlparser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComplete(t *testing.T) {
+	schema := Schema{Selectors: []SelectorSchema{
+		{Name: "name", Comparisons: []Comparison{ComparisonEq, ComparisonNeq}},
+		{Name: "nickname", Comparisons: []Comparison{ComparisonEq}},
+		{Name: "active", Type: ValueRecommendationBoolean, Comparisons: []Comparison{ComparisonEq}, Unary: true},
+		{Name: "tags", Comparisons: []Comparison{ComparisonIn}},
+	}}
+	texts := func(cs []Completion) []string {
+		res := make([]string, 0, len(cs))
+		for _, c := range cs {
+			res = append(res, c.Text)
+		}
+		return res
+	}
+	var values = []struct {
+		input    string
+		expected []string
+	}{
+		{input: "", expected: []string{"active", "name", "nickname", "tags", "("}},
+		{input: "n", expected: []string{"name", "nickname"}},
+		{input: "name", expected: []string{"!=", "=="}},
+		{input: "name=", expected: []string{"=="}},
+		{input: "name!", expected: []string{"!="}},
+		{input: "active", expected: []string{"==", ",", ";"}},
+		{input: "active==", expected: []string{"false", "true"}},
+		{input: "active==t", expected: []string{"true", ",", ";"}},
+		{input: "tags=in=", expected: []string{"["}},
+		{input: "name==John", expected: []string{",", ";"}},
+		{input: "name==John;", expected: []string{"active", "name", "nickname", "tags", "("}},
+		{input: "(name==John", expected: []string{",", ";", ")"}},
+		{input: "(name==John)", expected: []string{",", ";"}},
+		{input: "unknown", expected: []string{}},
+	}
+	for _, v := range values {
+		assert.Equal(t, v.expected, texts(Complete(v.input, len(v.input), schema)), v.input)
+	}
+}
+
+func TestCompleteStart(t *testing.T) {
+	schema := Schema{Selectors: []SelectorSchema{{Name: "name"}}}
+	c := Complete("a==1;na;b==2", 7, schema)
+	if assert.NotEmpty(t, c) {
+		assert.Equal(t, Completion{Text: "name", Class: SpanSelector, Start: 5}, c[0])
+	}
+	c = Complete("name", 100, Schema{})
+	if assert.NotEmpty(t, c) {
+		assert.Equal(t, SpanComparator, c[0].Class)
+		assert.Equal(t, 4, c[0].Start)
+	}
+}
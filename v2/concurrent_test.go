@@ -0,0 +1,87 @@
+package fiqlparser
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// runConcurrently runs fn on several goroutines, run the tests with -race to detect data races
+func runConcurrently(fn func(i int)) {
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				fn(g*50 + i)
+			}
+		}(g)
+	}
+	wg.Wait()
+}
+
+var concurrentFilters = []string{
+	"total=gt=10;customer.name==Jo*",
+	"items.sku=in=[a+b],customer.address.city==Berlin",
+	"total=bt=1..5;(customer.name==x,items.product.name==*y)",
+}
+
+func TestSQLTranslatorConcurrent(t *testing.T) {
+	translator := testSQLRelationTranslator
+	expected := make([]string, len(concurrentFilters))
+	filters := make([]Expression, len(concurrentFilters))
+	for i, f := range concurrentFilters {
+		res, err := Parse(context.Background(), f)
+		assert.NoError(t, err)
+		filters[i] = res
+		expected[i], _, err = translator.Translate(res)
+		assert.NoError(t, err)
+	}
+	runConcurrently(func(i int) {
+		sql, _, err := translator.Translate(filters[i%len(filters)])
+		assert.NoError(t, err)
+		assert.Equal(t, expected[i%len(filters)], sql)
+		_, err = translator.Joins(filters[i%len(filters)])
+		assert.NoError(t, err)
+	})
+}
+
+func TestElasticsearchTranslatorConcurrent(t *testing.T) {
+	translator := &ElasticsearchTranslator{Fields: map[string]ElasticsearchField{"customer.name": {Name: "customer", Keyword: "customer.keyword"}}}
+	expected := make([]string, len(concurrentFilters))
+	filters := make([]Expression, len(concurrentFilters))
+	for i, f := range concurrentFilters {
+		res, err := Parse(context.Background(), f)
+		assert.NoError(t, err)
+		filters[i] = res
+		q, err := translator.TranslateJSON(res)
+		assert.NoError(t, err)
+		expected[i] = string(q)
+	}
+	runConcurrently(func(i int) {
+		q, err := translator.Translate(filters[i%len(filters)])
+		assert.NoError(t, err)
+		j, _ := json.Marshal(q)
+		assert.Equal(t, expected[i%len(filters)], string(j))
+	})
+}
+
+func TestTemplateRendererConcurrent(t *testing.T) {
+	renderer, err := NewTemplateRenderer(TemplateSnippets{})
+	if !assert.NoError(t, err) {
+		return
+	}
+	res, err := Parse(context.Background(), concurrentFilters[2])
+	assert.NoError(t, err)
+	expected, err := renderer.Render(res)
+	assert.NoError(t, err)
+	runConcurrently(func(i int) {
+		out, err := renderer.Render(res)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, out)
+	})
+}
@@ -0,0 +1,157 @@
+package fiqlparser
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidNode is generated if a node can not be constructed from the supplied operands
+var ErrInvalidNode = errors.New("invalid node")
+
+// NewSelector creates a selector to be used as left operand of a comparison (see NewBinary)
+func NewSelector(name string) (Node, error) {
+	if name == "" {
+		return nil, fmt.Errorf("%w: empty selector", ErrInvalidNode)
+	}
+	return &constantExpression{value: name, selector: true, recommended: ValueRecommendationString}, nil
+}
+
+// NewUnary creates a selector without constraint, e.g. `deleted` in `deleted;name==x`
+func NewUnary(name string) (Node, error) {
+	if name == "" {
+		return nil, fmt.Errorf("%w: empty selector", ErrInvalidNode)
+	}
+	return &constantExpression{value: name, selector: true, unary: true, recommended: ValueRecommendationString}, nil
+}
+
+// NewArgument creates a argument to be used as right operand of a comparison (see NewBinary),
+// the value is taken literally (`*` is no wildcard) and its value recommendation is detected as by the parser
+func NewArgument(value string) Node {
+	_, rec, _ := defaultValidator(value)
+	return &constantExpression{value: value, recommended: rec}
+}
+
+// NewTuple creates a tuple argument (e.g. for =in=) using the DefaultTupleDelimiters
+func NewTuple(values ...string) (Node, error) {
+	if len(values) == 0 {
+		return nil, fmt.Errorf("%w: empty tuple", ErrInvalidNode)
+	}
+	tuple := &tupleArgument{delimiters: DefaultTupleDelimiters, elements: make([]*constantExpression, 0, len(values))}
+	for _, v := range values {
+		tuple.elements = append(tuple.elements, NewArgument(v).(*constantExpression))
+	}
+	con := &constantExpression{recommended: ValueRecommendationTuple, tuple: tuple}
+	con.value = coercionNotation(con)
+	return con, nil
+}
+
+// NewBinary creates a logical operation (op is OperatorAND or OperatorOR) or a comparison
+// (op is a Comparison, left a selector and right a argument) without panicking on invalid operands,
+// unlike Add. Root expressions (e.g. parsed ones) are added as sub expressions, so parsed filters can be combined:
+//
+//	combined, err := NewBinary(string(OperatorAND), &userFilter, &tenantFilter)
+func NewBinary(op string, left Node, right Node) (Node, error) {
+	if isLogicalOperator(op) {
+		lhs, err := operandNode(op, left)
+		if err != nil {
+			return nil, err
+		}
+		rhs, err := operandNode(op, right)
+		if err != nil {
+			return nil, err
+		}
+		return newBinary(op, lhs, rhs), nil
+	}
+	if _, ok := fiqlOperators[op]; !ok {
+		return nil, fmt.Errorf("%w: unknown operator `%s`", ErrInvalidNode, op)
+	}
+	sel, ok := left.(*constantExpression)
+	if !ok || !sel.selector || sel.unary {
+		return nil, fmt.Errorf("%w: `%s` requires a selector as left operand", ErrInvalidNode, op)
+	}
+	arg, ok := right.(*constantExpression)
+	if !ok || arg.selector {
+		return nil, fmt.Errorf("%w: `%s` requires a argument as right operand", ErrInvalidNode, op)
+	}
+	if msg := comparisonRequirement(Comparison(op), arg); msg != "" {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidNode, msg)
+	}
+	if arg.tuple != nil && op == string(ComparisonBetween) {
+		c := *arg
+		c.recommended = ValueRecommendationRange
+		arg = &c
+	}
+	return newBinary(op, sel, arg), nil
+}
+
+// NewNegation negates the operand, which is enclosed in braces as by the parser.
+// The parser has to be configured WithNegation to parse the FIQL of the result again.
+func NewNegation(operand Node) (Node, error) {
+	n, err := operandNode(string(OperatorNOT), operand)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := n.(*Expression); !ok {
+		n = newSubExpression(n)
+	}
+	return &notExpression{node: n}, nil
+}
+
+// NewExpression creates a root expression from a constructed node, so it can be used like a parsed one
+func NewExpression(n Node) (Expression, error) {
+	node, err := operandNode("expression", n)
+	if err != nil {
+		return Expression{}, err
+	}
+	if e, ok := node.(*Expression); ok && e.label == "" {
+		node = e.node
+	}
+	return Expression{root: true, node: node}, nil
+}
+
+// operandNode checks a operand of a logical operation, root expressions become sub expressions
+func operandNode(op string, n Node) (Node, error) {
+	switch node := n.(type) {
+	case nil:
+		return nil, fmt.Errorf("%w: `%s` with missing operand", ErrInvalidNode, op)
+	case *Expression:
+		if node == nil || node.node == nil {
+			return nil, fmt.Errorf("%w: `%s` with empty operand", ErrInvalidNode, op)
+		}
+		if node.root {
+			return newSubExpression(node.node), nil
+		}
+	case *binaryExpression:
+		if node == nil {
+			return nil, fmt.Errorf("%w: `%s` with missing operand", ErrInvalidNode, op)
+		}
+	case *constantExpression:
+		if node == nil {
+			return nil, fmt.Errorf("%w: `%s` with missing operand", ErrInvalidNode, op)
+		}
+		if !node.selector || !node.unary {
+			return nil, fmt.Errorf("%w: `%s` requires a comparison or unary selector instead of `%s`", ErrInvalidNode, op, node.value)
+		}
+	}
+	return n, nil
+}
+
+// comparisonRequirement checks the argument as the parser would for the comparison,
+// it returns the violated requirement or the empty string
+func comparisonRequirement(cmp Comparison, arg *constantExpression) string {
+	switch cmp {
+	case ComparisonIn:
+		if arg.tuple == nil {
+			return fmt.Sprintf("`%s` requires a tuple", cmp)
+		}
+	case ComparisonBetween:
+		if arg.recommended != ValueRecommendationRange && (arg.tuple == nil || len(arg.tuple.elements) != 2) {
+			return fmt.Sprintf("`%s` requires a range or a tuple of two elements", cmp)
+		}
+	case ComparisonGt, ComparisonLt, ComparisonGte, ComparisonLte:
+		if ok, _, _ := layoutValidator(numberOrDateExpressionValidator, arg.layout)(arg.value); !ok || arg.tuple != nil {
+			return fmt.Sprintf("`%s` requires a number, date or duration instead of `%s`", cmp, arg.value)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,112 @@
+package fiqlparser
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// mustNode returns a function failing the test if a node could not be constructed
+func mustNode(t *testing.T) func(Node, error) Node {
+	return func(n Node, err error) Node {
+		t.Helper()
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		return n
+	}
+}
+
+func TestNewBinary(t *testing.T) {
+	must := mustNode(t)
+	name := must(NewSelector("name"))
+	age := must(NewSelector("age"))
+	deleted := must(NewUnary("deleted"))
+	eq := must(NewBinary(string(ComparisonEq), name, NewArgument("John Doe")))
+	gt := must(NewBinary(string(ComparisonGt), age, NewArgument("18")))
+	or := must(NewBinary(string(OperatorOR), eq, deleted))
+	and := must(NewBinary(string(OperatorAND), or, gt))
+
+	e, err := NewExpression(and)
+	if assert.NoError(t, err) {
+		assert.Equal(t, `(name=="John Doe",deleted);age=gt=18`, e.ToFIQL())
+		parsed, err := New().Parse(context.Background(), e.ToFIQL())
+		if assert.NoError(t, err) {
+			assert.Equal(t, parsed.ToFIQL(), e.ToFIQL())
+		}
+	}
+
+	in := must(NewBinary(string(ComparisonIn), name, must(NewTuple("a", "b c"))))
+	bt := must(NewBinary(string(ComparisonBetween), age, must(NewTuple("1", "5"))))
+	e, err = NewExpression(must(NewBinary(string(OperatorAND), in, bt)))
+	if assert.NoError(t, err) {
+		assert.Equal(t, `name=in=[a+"b c"];age=bt=[1+5]`, e.ToFIQL())
+	}
+}
+
+func TestNewBinaryCombinesParsedExpressions(t *testing.T) {
+	user := mustParse(t, "a==1,b==2")
+	tenant := mustParse(t, "tenant==x")
+	n, err := NewBinary(string(OperatorAND), &user, &tenant)
+	if assert.NoError(t, err) {
+		e, err := NewExpression(n)
+		assert.NoError(t, err)
+		assert.Equal(t, "(a==1,b==2);(tenant==x)", e.ToFIQL())
+	}
+	// the operands are not modified
+	assert.Equal(t, "a==1,b==2", user.ToFIQL())
+}
+
+func TestNewBinaryErrors(t *testing.T) {
+	must := mustNode(t)
+	name := must(NewSelector("name"))
+	deleted := must(NewUnary("deleted"))
+	arg := NewArgument("x")
+	cmp := must(NewBinary(string(ComparisonEq), name, arg))
+	var values = []struct {
+		op    string
+		left  Node
+		right Node
+	}{
+		{op: "XOR", left: cmp, right: cmp},
+		{op: string(OperatorAND), left: cmp, right: nil},
+		{op: string(OperatorAND), left: nil, right: cmp},
+		{op: string(OperatorAND), left: cmp, right: arg},
+		{op: string(OperatorOR), left: name, right: cmp},
+		{op: string(OperatorAND), left: cmp, right: &Expression{root: true}},
+		{op: string(ComparisonEq), left: arg, right: arg},
+		{op: string(ComparisonEq), left: deleted, right: arg},
+		{op: string(ComparisonEq), left: name, right: name},
+		{op: string(ComparisonEq), left: name, right: cmp},
+		{op: string(ComparisonGt), left: name, right: arg},
+		{op: string(ComparisonIn), left: name, right: arg},
+		{op: string(ComparisonBetween), left: name, right: must(NewTuple("1"))},
+	}
+	for i, v := range values {
+		n, err := NewBinary(v.op, v.left, v.right)
+		assert.ErrorIs(t, err, ErrInvalidNode, i)
+		assert.Nil(t, n, i)
+	}
+
+	_, err := NewSelector("")
+	assert.ErrorIs(t, err, ErrInvalidNode)
+	_, err = NewTuple()
+	assert.ErrorIs(t, err, ErrInvalidNode)
+	_, err = NewNegation(nil)
+	assert.ErrorIs(t, err, ErrInvalidNode)
+	_, err = NewExpression(arg)
+	assert.ErrorIs(t, err, ErrInvalidNode)
+}
+
+func TestNewNegation(t *testing.T) {
+	must := mustNode(t)
+	cmp := must(NewBinary(string(ComparisonEq), must(NewSelector("a")), NewArgument("1")))
+	not := must(NewNegation(must(NewBinary(string(OperatorAND), cmp, must(NewUnary("b"))))))
+	e, err := NewExpression(not)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "!(a==1;b)", e.ToFIQL())
+		_, err := New(WithNegation()).Parse(context.Background(), e.ToFIQL())
+		assert.NoError(t, err)
+	}
+}
@@ -0,0 +1,49 @@
+package fiqlparser
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseContext(t *testing.T) {
+	e, err := Parse(context.Background(), "a==1;b=gt=2", WithLogicalNodes())
+	assert.NoError(t, err)
+	assert.Equal(t, "(a == 1 AND b > 2)", e.String())
+
+	_, err = Parse(context.Background(), "a==")
+	var perr *ParseError
+	assert.ErrorAs(t, err, &perr)
+}
+
+func TestParseCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := New().Parse(ctx, "a==1")
+	assert.ErrorIs(t, err, context.Canceled)
+	_, err = New().ParseReader(ctx, strings.NewReader("a==1"))
+	assert.ErrorIs(t, err, context.Canceled)
+	_, err = New().ParseMulti(ctx, "a==1\nb==2")
+	assert.ErrorIs(t, err, context.Canceled)
+	_, err = New().ParseAll(ctx, "a==1;b==")
+	assert.ErrorIs(t, err, context.Canceled)
+	_, _, err = New().ParseWithMetrics(ctx, "a==1")
+	assert.ErrorIs(t, err, context.Canceled)
+
+	e, err := New().ParseReader(context.Background(), strings.NewReader("a==1"))
+	assert.NoError(t, err)
+	assert.Equal(t, "(a == 1)", e.String())
+}
+
+func TestParseCanceledWhileParsing(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	lex := acquireLexer(strings.Repeat("a==1;", 10000) + "a==1")
+	defer releaseLexer(lex)
+	// canceled after the initial check of Parse, so only the check while parsing can notice it
+	cancel()
+	_, err := New().withInput(ctx, lex).parse()
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, contextCheckInterval, lex.metrics.Tokens)
+}
@@ -0,0 +1,153 @@
+package fiqlparser
+
+import (
+	"errors"
+	"fmt"
+)
+
+// SelectorStatistics describes the storage backing a selector, it is used for cost estimation
+type SelectorStatistics struct {
+	// Rows is the number of rows (or documents) the selector has to be looked up in
+	Rows int64
+	// Indexed indicates whether or not a index exists for the selector
+	Indexed bool
+}
+
+// StatisticsProvider returns the statistics for a selector, ok is false for unknown selectors
+type StatisticsProvider func(selector string) (stats SelectorStatistics, ok bool)
+
+// CostWarning describes a predicate which will most likely cause a full scan
+type CostWarning struct {
+	// Selector is the selector of the predicate
+	Selector string
+	// Reason describes why the index can not be used
+	Reason string
+	// Rows is the number of rows which have to be scanned
+	Rows int64
+}
+
+// String returns the warning in a human readable form
+func (w CostWarning) String() string {
+	return fmt.Sprintf("full scan of %d rows on `%s` (%s)", w.Rows, w.Selector, w.Reason)
+}
+
+// CostEstimate is the result of a cost estimation
+type CostEstimate struct {
+	// Rows is the estimated number of rows which have to be scanned,
+	// index lookups are considered free
+	Rows int64
+	// Warnings contains the predicates causing full scans
+	Warnings []CostWarning
+}
+
+// ErrTooExpensive is generated if the estimated cost exceeds the allowed maximum
+var ErrTooExpensive = errors.New("expression too expensive")
+
+// EstimateCost estimates the number of rows which have to be scanned to evaluate the expression.
+// It is a naive planner: a indexed predicate is considered free unless it can not use the index
+// (leading wildcard, negation), a AND can be resolved by its cheapest operand while
+// every operand of a OR has to be resolved. Unknown selectors are ignored.
+func EstimateCost(e Expression, stats StatisticsProvider) CostEstimate {
+	rows, warnings := estimateNodeCost(&e, stats)
+	return CostEstimate{Rows: rows, Warnings: warnings}
+}
+
+// CheckCost estimates the cost of the expression and returns ErrTooExpensive if more than maxRows have to be scanned
+func CheckCost(e Expression, stats StatisticsProvider, maxRows int64) (CostEstimate, error) {
+	estimate := EstimateCost(e, stats)
+	if estimate.Rows > maxRows {
+		return estimate, fmt.Errorf("%w (estimated %d scanned rows, allowed %d)", ErrTooExpensive, estimate.Rows, maxRows)
+	}
+	return estimate, nil
+}
+
+func estimateNodeCost(n Node, stats StatisticsProvider) (int64, []CostWarning) {
+	switch node := n.(type) {
+	case *Expression:
+		if node.node == nil {
+			return 0, nil
+		}
+		return estimateNodeCost(node.node, stats)
+	case *binaryExpression:
+		switch node.operator {
+		case string(OperatorAND):
+			lhs, lw := estimateNodeCost(node.nodes[0], stats)
+			rhs, rw := estimateNodeCost(node.nodes[1], stats)
+			if lhs <= rhs {
+				return lhs, lw
+			}
+			return rhs, rw
+		case string(OperatorOR):
+			lhs, lw := estimateNodeCost(node.nodes[0], stats)
+			rhs, rw := estimateNodeCost(node.nodes[1], stats)
+			return lhs + rhs, append(lw, rw...)
+		}
+		return estimatePredicateCost(node, stats)
+	case *logicalExpression:
+		var total int64
+		var warnings []CostWarning
+		for i, c := range node.nodes {
+			rows, w := estimateNodeCost(c, stats)
+			if node.operator == string(OperatorOR) {
+				total += rows
+				warnings = append(warnings, w...)
+			} else if i == 0 || rows < total {
+				total, warnings = rows, w
+			}
+		}
+		return total, warnings
+	case *notExpression:
+		return estimateNegationCost(node, stats)
+	case *constantExpression:
+		if node.selector {
+			return estimateSelectorCost(node.value, "", stats)
+		}
+	}
+	return 0, nil
+}
+
+// estimateNegationCost assumes a scan for every selector within the negated sub expression
+func estimateNegationCost(node *notExpression, stats StatisticsProvider) (int64, []CostWarning) {
+	var total int64
+	var warnings []CostWarning
+	Walk(node.node, func(n Node) bool {
+		if c, ok := n.(*constantExpression); ok && c.selector {
+			rows, w := estimateSelectorCost(c.value, "negation prevents index usage", stats)
+			if rows > total {
+				total = rows
+			}
+			warnings = append(warnings, w...)
+		}
+		return true
+	})
+	return total, warnings
+}
+
+func estimatePredicateCost(node *binaryExpression, stats StatisticsProvider) (int64, []CostWarning) {
+	selector, ok := node.nodes[0].(*constantExpression)
+	if !ok {
+		return 0, nil
+	}
+	reason := ""
+	if arg, ok := node.nodes[1].(*constantExpression); ok && arg.prefixWildcard {
+		reason = "leading wildcard prevents index usage"
+	}
+	if node.operator == string(ComparisonNeq) {
+		reason = "negation prevents index usage"
+	}
+	return estimateSelectorCost(selector.value, reason, stats)
+}
+
+func estimateSelectorCost(selector string, reason string, stats StatisticsProvider) (int64, []CostWarning) {
+	s, ok := stats(selector)
+	if !ok {
+		return 0, nil
+	}
+	if s.Indexed && reason == "" {
+		return 0, nil
+	}
+	if reason == "" {
+		reason = "no index"
+	}
+	return s.Rows, []CostWarning{{Selector: selector, Reason: reason, Rows: s.Rows}}
+}
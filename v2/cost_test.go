@@ -0,0 +1,64 @@
+package fiqlparser
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testStatistics(selector string) (SelectorStatistics, bool) {
+	switch selector {
+	case "id", "email":
+		return SelectorStatistics{Rows: 1000, Indexed: true}, true
+	case "name", "bio":
+		return SelectorStatistics{Rows: 1000, Indexed: false}, true
+	}
+	return SelectorStatistics{}, false
+}
+
+func TestEstimateCost(t *testing.T) {
+	var values = []struct {
+		fiql     string
+		rows     int64
+		warnings []string
+	}{
+		{fiql: "id==1", rows: 0, warnings: nil},
+		{fiql: "unknown==1", rows: 0, warnings: nil},
+		{fiql: "name==foo", rows: 1000, warnings: []string{"full scan of 1000 rows on `name` (no index)"}},
+		{fiql: "email==*@example.com", rows: 1000, warnings: []string{"full scan of 1000 rows on `email` (leading wildcard prevents index usage)"}},
+		{fiql: "email==foo*", rows: 0, warnings: nil},
+		{fiql: "id!=1", rows: 1000, warnings: []string{"full scan of 1000 rows on `id` (negation prevents index usage)"}},
+		{fiql: "id==1;name==foo", rows: 0, warnings: nil},
+		{fiql: "name==foo;id==1", rows: 0, warnings: nil},
+		{fiql: "id==1,name==foo", rows: 1000, warnings: []string{"full scan of 1000 rows on `name` (no index)"}},
+		{fiql: "name==foo,(bio==bar;email==*x)", rows: 2000, warnings: []string{"full scan of 1000 rows on `name` (no index)", "full scan of 1000 rows on `bio` (no index)"}},
+		{fiql: "bio", rows: 1000, warnings: []string{"full scan of 1000 rows on `bio` (no index)"}},
+		{fiql: "id", rows: 0, warnings: nil},
+	}
+	for _, v := range values {
+		res, err := Parse(context.Background(), v.fiql)
+		if !assert.NoError(t, err) {
+			continue
+		}
+		estimate := EstimateCost(res, testStatistics)
+		assert.Equal(t, v.rows, estimate.Rows, v.fiql)
+		var warnings []string
+		for _, w := range estimate.Warnings {
+			warnings = append(warnings, w.String())
+		}
+		assert.Equal(t, v.warnings, warnings, v.fiql)
+	}
+}
+
+func TestCheckCost(t *testing.T) {
+	res, err := Parse(context.Background(), "name==foo,bio==bar")
+	assert.NoError(t, err)
+	_, err = CheckCost(res, testStatistics, 1500)
+	assert.ErrorIs(t, err, ErrTooExpensive)
+	assert.EqualError(t, err, "expression too expensive (estimated 2000 scanned rows, allowed 1500)")
+
+	estimate, err := CheckCost(res, testStatistics, 2000)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2000), estimate.Rows)
+}
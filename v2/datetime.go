@@ -0,0 +1,144 @@
+package fiqlparser
+
+import (
+	"strconv"
+	"time"
+)
+
+// DateTimeLayout parses datetime arguments in a additional format, see WithDateTimeLayouts.
+// Values without offset are in loc, ok is false if the value is not in the format.
+type DateTimeLayout func(value string, loc *time.Location) (t time.Time, ok bool)
+
+// DateTimeLayoutOf returns a DateTimeLayout for a time.Parse layout, e.g. time.RFC3339Nano or `02.01.2006`
+func DateTimeLayoutOf(layout string) DateTimeLayout {
+	return func(value string, loc *time.Location) (time.Time, bool) {
+		t, err := time.ParseInLocation(layout, value, loc)
+		return t, err == nil
+	}
+}
+
+// DateTimeLayoutEpochSeconds accepts unix timestamps in seconds with 10 digits (2001-09-09 until 2286-11-20),
+// shorter numbers are kept as numbers
+var DateTimeLayoutEpochSeconds DateTimeLayout = epochLayout(10, time.Second)
+
+// DateTimeLayoutEpochMillis accepts unix timestamps in milliseconds with 13 digits (2001-09-09 until 2286-11-20),
+// shorter numbers are kept as numbers
+var DateTimeLayoutEpochMillis DateTimeLayout = epochLayout(13, time.Millisecond)
+
+func epochLayout(digits int, unit time.Duration) DateTimeLayout {
+	return func(value string, _ *time.Location) (time.Time, bool) {
+		if len(value) != digits {
+			return time.Time{}, false
+		}
+		for _, r := range value {
+			if r < '0' || r > '9' {
+				return time.Time{}, false
+			}
+		}
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return time.Time{}, false
+		}
+		return time.Unix(0, 0).Add(time.Duration(n) * unit).UTC(), true
+	}
+}
+
+// WithDateTimeLayouts registers additional datetime layouts, arguments in one of the layouts are
+// recommended as ValueRecommendationDateTime and converted by AsTime.
+// RFC3339 timestamps and coarse dates (e.g. `2023-01-15`) are always accepted, the layouts are tried in order.
+func WithDateTimeLayouts(layouts ...DateTimeLayout) Option {
+	return func(p *Parser) {
+		p.dateTimeLayouts = append(p.dateTimeLayouts, layouts...)
+		p.anyDateTimeLayout = anyDateTimeLayout(p.dateTimeLayouts)
+	}
+}
+
+// anyDateTimeLayout returns a layout accepting values in any of the layouts, they are tried in order.
+// Named parameters keep it, so values bound later are typed like parsed arguments (see Expression.Bind).
+func anyDateTimeLayout(layouts []DateTimeLayout) DateTimeLayout {
+	layouts = append([]DateTimeLayout(nil), layouts...)
+	return func(value string, loc *time.Location) (time.Time, bool) {
+		for _, l := range layouts {
+			if t, ok := l(value, loc); ok {
+				return t, true
+			}
+		}
+		return time.Time{}, false
+	}
+}
+
+// WithDefaultLocation sets the location of datetimes without offset, coarse dates (e.g. `2023-01-15`)
+// and values of registered layouts without time zone are interpreted in loc instead of UTC by AsTime
+func WithDefaultLocation(loc *time.Location) Option {
+	return func(p *Parser) {
+		p.location = loc
+	}
+}
+
+// AsTimeIn converts the argument like AsTime, but datetimes without offset are interpreted in loc
+// and the result is in loc
+func (c ArgumentContext) AsTimeIn(loc *time.Location) (time.Time, error) {
+	c.loc = loc
+	t, err := c.AsTime()
+	if err != nil {
+		return t, err
+	}
+	return t.In(loc), nil
+}
+
+// location returns the location of datetimes without offset
+func (c ArgumentContext) location() *time.Location {
+	if c.loc == nil {
+		return time.UTC
+	}
+	return c.loc
+}
+
+// dateTimeLayout returns the first registered layout accepting the value, nil if there is none
+func (p *Parser) dateTimeLayout(value string) DateTimeLayout {
+	for _, l := range p.dateTimeLayouts {
+		if _, ok := l(value, time.UTC); ok {
+			return l
+		}
+	}
+	return nil
+}
+
+// dateTimeValidator extends the validator by the registered layouts,
+// strings and numbers in one of the layouts are recommended as datetime
+func (p *Parser) dateTimeValidator(validator argumentValidator) argumentValidator {
+	return layoutValidator(validator, p.anyDateTimeLayout)
+}
+
+// layoutValidator extends the validator by the layout, strings and numbers in the layout are recommended as datetime
+func layoutValidator(validator argumentValidator, layout DateTimeLayout) argumentValidator {
+	if layout == nil {
+		return validator
+	}
+	return func(i string) (bool, ValueRecommendation, []string) {
+		ok, rec, expected := validator(i)
+		if ok && rec != ValueRecommendationString && rec != ValueRecommendationNumber {
+			return ok, rec, expected
+		}
+		if _, valid := layout(i, time.UTC); valid {
+			return true, ValueRecommendationDateTime, nil
+		}
+		return ok, rec, expected
+	}
+}
+
+// recommendDateTime remembers the layout and the default location of datetime constants, which are used by AsTime.
+// Dynamic arguments keep all layouts, so their resolved value is typed like a parsed argument (see DynamicArguments.Resolve).
+func (p *Parser) recommendDateTime(con *constantExpression) {
+	if _, ok := dynamicArgument(con); ok {
+		con.layout, con.loc = p.anyDateTimeLayout, p.location
+		return
+	}
+	if con.recommended != ValueRecommendationDateTime {
+		return
+	}
+	if len(p.dateTimeLayouts) > 0 {
+		con.layout = p.dateTimeLayout(con.value)
+	}
+	con.loc = p.location
+}
@@ -0,0 +1,164 @@
+package fiqlparser
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDateTimeLayouts(t *testing.T) {
+	p := New(WithDateTimeLayouts(DateTimeLayoutOf("02.01.2006"), DateTimeLayoutOf(time.RFC3339Nano),
+		DateTimeLayoutEpochSeconds, DateTimeLayoutEpochMillis))
+	var values = []struct {
+		input       string
+		recommended ValueRecommendation
+		expected    time.Time
+	}{
+		{input: "created=gt=2023-01-15", recommended: ValueRecommendationDateTime, expected: time.Date(2023, 1, 15, 0, 0, 0, 0, time.UTC)},
+		{input: "created=gt=15.01.2023", recommended: ValueRecommendationDateTime, expected: time.Date(2023, 1, 15, 0, 0, 0, 0, time.UTC)},
+		{input: "created==15.01.2023", recommended: ValueRecommendationDateTime, expected: time.Date(2023, 1, 15, 0, 0, 0, 0, time.UTC)},
+		{input: "created=gt=2023-01-15T10:00:00.123456789Z", recommended: ValueRecommendationDateTime, expected: time.Date(2023, 1, 15, 10, 0, 0, 123456789, time.UTC)},
+		{input: "created=gt=1673776800", recommended: ValueRecommendationDateTime, expected: time.Date(2023, 1, 15, 10, 0, 0, 0, time.UTC)},
+		{input: "created=gt=1673776800123", recommended: ValueRecommendationDateTime, expected: time.Date(2023, 1, 15, 10, 0, 0, 123000000, time.UTC)},
+		// numbers which are no plausible timestamps stay numbers
+		{input: "age=gt=30", recommended: ValueRecommendationNumber},
+		{input: "name==John", recommended: ValueRecommendationString},
+	}
+	for _, v := range values {
+		res, err := p.Parse(context.Background(), v.input)
+		if !assert.NoError(t, err, v.input) {
+			continue
+		}
+		var arg ArgumentContext
+		Walk(&res, func(n Node) bool {
+			if pr, ok := predicateOf(n); ok {
+				arg = pr.Argument
+			}
+			return true
+		})
+		assert.Equal(t, v.recommended, arg.ValueRecommendation(), v.input)
+		if v.recommended != ValueRecommendationDateTime {
+			continue
+		}
+		tm, err := arg.AsTime()
+		if assert.NoError(t, err, v.input) {
+			assert.True(t, v.expected.Equal(tm), "%s: expected %s got %s", v.input, v.expected, tm)
+		}
+	}
+}
+
+func TestDateTimeLayoutsDefault(t *testing.T) {
+	res, err := Parse(context.Background(), "created=gt=1673776800")
+	if assert.NoError(t, err) {
+		Walk(&res, func(n Node) bool {
+			if pr, ok := predicateOf(n); ok {
+				assert.Equal(t, ValueRecommendationNumber, pr.Argument.ValueRecommendation())
+			}
+			return true
+		})
+	}
+}
+
+func TestDateTimeLayoutsTupleAndSQL(t *testing.T) {
+	p := New(WithDateTimeLayouts(DateTimeLayoutOf("02.01.2006")))
+	res, err := p.Parse(context.Background(), "created=in=[15.01.2023+16.01.2023];updated=lt=17.01.2023")
+	if !assert.NoError(t, err) {
+		return
+	}
+	translator := &SQLTranslator{Columns: map[string]string{"created": "created", "updated": "updated"}}
+	cond, args, err := translator.Translate(res)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "created IN (?, ?) AND updated < ?", cond)
+		assert.Equal(t, []interface{}{
+			time.Date(2023, 1, 15, 0, 0, 0, 0, time.UTC),
+			time.Date(2023, 1, 16, 0, 0, 0, 0, time.UTC),
+			time.Date(2023, 1, 17, 0, 0, 0, 0, time.UTC),
+		}, args)
+	}
+}
+
+func TestDefaultLocation(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip("time zone data not available")
+	}
+	p := New(WithDefaultLocation(berlin), WithDateTimeLayouts(DateTimeLayoutOf("2006-01-02 15:04")))
+	var values = []struct {
+		input    string
+		expected time.Time
+	}{
+		{input: "created=gt=2023-07-15", expected: time.Date(2023, 7, 15, 0, 0, 0, 0, berlin)},
+		{input: "created=gt='2023-07-15 10:30'", expected: time.Date(2023, 7, 15, 10, 30, 0, 0, berlin)},
+		// datetimes with offset are not affected
+		{input: "created=gt=2023-07-15T10:30:00Z", expected: time.Date(2023, 7, 15, 10, 30, 0, 0, time.UTC)},
+	}
+	for _, v := range values {
+		res, err := p.Parse(context.Background(), v.input)
+		if !assert.NoError(t, err, v.input) {
+			continue
+		}
+		arg := firstArgument(&res)
+		tm, err := arg.AsTime()
+		if assert.NoError(t, err, v.input) {
+			assert.True(t, v.expected.Equal(tm), "%s: expected %s got %s", v.input, v.expected, tm)
+		}
+	}
+
+	res, err := p.Parse(context.Background(), "created=ge=2023-07")
+	if assert.NoError(t, err) {
+		start, end, err := firstArgument(&res).AsTimeRange()
+		if assert.NoError(t, err) {
+			assert.True(t, time.Date(2023, 7, 1, 0, 0, 0, 0, berlin).Equal(start))
+			assert.True(t, time.Date(2023, 8, 1, 0, 0, 0, 0, berlin).Equal(end))
+		}
+	}
+}
+
+func TestAsTimeIn(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Skip("time zone data not available")
+	}
+	res, err := Parse(context.Background(), "created=gt=2023-07-15;updated=gt=2023-07-15T00:00:00Z")
+	if !assert.NoError(t, err) {
+		return
+	}
+	var args []ArgumentContext
+	Walk(&res, func(n Node) bool {
+		if pr, ok := predicateOf(n); ok {
+			args = append(args, pr.Argument)
+		}
+		return true
+	})
+	if !assert.Len(t, args, 2) {
+		return
+	}
+	local, err := args[0].AsTimeIn(tokyo)
+	if assert.NoError(t, err) {
+		assert.True(t, time.Date(2023, 7, 15, 0, 0, 0, 0, tokyo).Equal(local))
+		assert.Equal(t, tokyo, local.Location())
+	}
+	utc, err := args[0].AsTime()
+	if assert.NoError(t, err) {
+		assert.True(t, time.Date(2023, 7, 15, 0, 0, 0, 0, time.UTC).Equal(utc))
+	}
+	withOffset, err := args[1].AsTimeIn(tokyo)
+	if assert.NoError(t, err) {
+		assert.True(t, time.Date(2023, 7, 15, 9, 0, 0, 0, tokyo).Equal(withOffset))
+		assert.Equal(t, tokyo, withOffset.Location())
+	}
+}
+
+func firstArgument(e *Expression) ArgumentContext {
+	var arg ArgumentContext
+	Walk(e, func(n Node) bool {
+		if pr, ok := predicateOf(n); ok {
+			arg = pr.Argument
+			return false
+		}
+		return true
+	})
+	return arg
+}
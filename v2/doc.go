@@ -0,0 +1,15 @@
+// Package fiqlparser provides a simple fiql parser, it is the version 2 of github.com/eisenwinter/fiql-parser.
+//
+// The parser does not adhere 100% to the fiql spec
+// which can be found https://datatracker.ietf.org/doc/html/draft-nottingham-atompub-fiql-00.
+//
+// The parser produces a walkable AST which can be walked by using a visitor.
+//
+// The translators (e.g. SQLTranslator or ElasticsearchTranslator) are configured by their fields
+// and keep no state between translations, they are safe for concurrent use as long as their fields are not modified.
+//
+// Compared to version 1 the parse functions take a context, which is checked while parsing,
+// the parser is created by New and the operator and comparison types are named Operator and Comparison.
+// The version 1 package is a shim delegating to this package, its types are aliases of the types of this package,
+// so values can be passed between both versions and code can be migrated package by package.
+package fiqlparser
@@ -0,0 +1,270 @@
+package fiqlparser
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ISO8601Duration represents a ISO 8601-2 duration
+// the extension 2 allows a sign character (+/-) at
+// the beginning of the duration
+type ISO8601Duration struct {
+	Negative bool
+	Years    float64
+	Months   float64
+	Weeks    float64
+	Days     float64
+	Hours    float64
+	Minutes  float64
+	Seconds  float64
+	_string  string
+}
+
+// String returns the string representation as supplied,
+// durations which were not parsed (e.g. built in code) are formatted by Canonical
+func (i *ISO8601Duration) String() string {
+	if i._string == "" {
+		return i.Canonical()
+	}
+	return i._string
+}
+
+// Canonical returns the duration formatted as ISO 8601 duration without zero components,
+// e.g. `-P1Y2DT3H`, a zero duration is formatted as `PT0S`
+func (i ISO8601Duration) Canonical() string {
+	var b strings.Builder
+	if i.Negative {
+		b.WriteRune('-')
+	}
+	b.WriteByte(durationPeriod)
+	zero := true
+	writeComponent := func(v float64, designator byte) {
+		if v != 0 {
+			zero = false
+			b.WriteString(strconv.FormatFloat(v, 'f', -1, 64))
+			b.WriteByte(designator)
+		}
+	}
+	writeComponent(i.Years, durationYear)
+	writeComponent(i.Months, durationMonthOrMinute)
+	writeComponent(i.Weeks, durationWeek)
+	writeComponent(i.Days, durationDay)
+	if i.Hours != 0 || i.Minutes != 0 || i.Seconds != 0 {
+		b.WriteByte(durationTime)
+		writeComponent(i.Hours, durationHour)
+		writeComponent(i.Minutes, durationMonthOrMinute)
+		writeComponent(i.Seconds, durationSecond)
+	} else if zero {
+		b.WriteString("T0S")
+	}
+	return b.String()
+}
+
+// MarshalJSON marshals the duration as canonical string (see Canonical)
+func (i ISO8601Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(i.Canonical())
+}
+
+// UnmarshalJSON parses a duration string, e.g. `"P1DT2H"`
+func (i *ISO8601Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	d, err := durationConverter.tryParseISO8601Duration(s)
+	if err != nil {
+		return fmt.Errorf("invalid duration `%s`: %w", s, err)
+	}
+	*i = d
+	return nil
+}
+
+// AsMilliseconds returns a approximation of the duration in miliseconds, its a naive implemntation
+func (i *ISO8601Duration) AsMilliseconds() int64 {
+	return int64(math.Round(i.Seconds*1000 + i.Minutes*60000 + i.Hours*60000*60 + i.Days*60000*60*24 + i.Weeks*60000*60*24*7 + i.Months*2629800000 + i.Years*2629800000*12))
+}
+
+// AsSeconds returns the seconds of the duration, approximated
+func (i *ISO8601Duration) AsSeconds() int64 {
+	return i.AsMilliseconds() / 1000
+}
+
+// averageMonth is the average length of a month (365.25 days / 12) used for fractional months
+const averageMonth = 2629800 * time.Second
+
+// ErrNoFixedLength is returned by ToTimeDuration for durations with years or months
+var ErrNoFixedLength = errors.New("duration contains years or months which have no fixed length")
+
+// ToTimeDuration returns the duration as time.Duration, weeks are 7 days and days are 24 hours.
+// Durations with years or months have no fixed length and return ErrNoFixedLength, use AddTo instead.
+func (i *ISO8601Duration) ToTimeDuration() (time.Duration, error) {
+	if i.Years != 0 || i.Months != 0 {
+		return 0, ErrNoFixedLength
+	}
+	ns := (i.Weeks*7+i.Days)*float64(24*time.Hour) + i.Hours*float64(time.Hour) +
+		i.Minutes*float64(time.Minute) + i.Seconds*float64(time.Second)
+	if ns >= math.MaxInt64 {
+		return 0, fmt.Errorf("duration `%s` exceeds the range of time.Duration", i.String())
+	}
+	d := time.Duration(math.Round(ns))
+	if i.Negative {
+		d = -d
+	}
+	return d, nil
+}
+
+// AddTo returns t plus the duration. Years, months, weeks and days are calendar units applied
+// with time.AddDate (e.g. P1M added to January 31st results in March 2nd or 3rd, P1D keeps the
+// wall clock across daylight saving time changes), fractional months are added as 1/12 of 365.25 days.
+func (i *ISO8601Duration) AddTo(t time.Time) time.Time {
+	return i.apply(t, i.Negative)
+}
+
+// SubtractFrom returns t minus the duration, see AddTo
+func (i *ISO8601Duration) SubtractFrom(t time.Time) time.Time {
+	return i.apply(t, !i.Negative)
+}
+
+func (i *ISO8601Duration) apply(t time.Time, negative bool) time.Time {
+	months, monthFraction := math.Modf(i.Years*12 + i.Months)
+	days, dayFraction := math.Modf(i.Weeks*7 + i.Days)
+	rest := time.Duration(math.Round(monthFraction*float64(averageMonth) + dayFraction*float64(24*time.Hour) +
+		i.Hours*float64(time.Hour) + i.Minutes*float64(time.Minute) + i.Seconds*float64(time.Second)))
+	if negative {
+		return t.AddDate(0, -int(months), -int(days)).Add(-rest)
+	}
+	return t.AddDate(0, int(months), int(days)).Add(rest)
+}
+
+// AsTimeRelative resolves the argument to an absolute time, durations are applied to now
+// (e.g. `updated=gt=-P1D` means after now minus one day) using AddTo,
+// datetime arguments are returned as with AsTime
+func (c ArgumentContext) AsTimeRelative(now time.Time) (time.Time, error) {
+	if c.r == ValueRecommendationDateTime {
+		return c.AsTime()
+	}
+	d, err := c.AsDuration()
+	if err != nil {
+		return time.Time{}, err
+	}
+	return d.AddTo(now), nil
+}
+
+type iSO8601DurationConverter struct{}
+
+var durationConverter = &iSO8601DurationConverter{}
+
+// readNr reads the number of a component, digits with a optional fraction (e.g. `1` or `1.5`)
+func (*iSO8601DurationConverter) readNr(input string, pos int) (int, float64, error) {
+	start := pos
+	for pos < len(input) && (input[pos] == '.' || (input[pos] >= '0' && input[pos] <= '9')) {
+		pos++
+	}
+	nr := input[start:pos]
+	if nr == "" || nr[0] == '.' || nr[len(nr)-1] == '.' {
+		return pos, 0, fmt.Errorf("expected number at position %d", start)
+	}
+	r, err := strconv.ParseFloat(nr, 64)
+	return pos, r, err
+}
+
+// durationDesignators are the designators of the date and the time components in the required order
+var durationDesignators = [2]string{
+	string([]byte{durationYear, durationMonthOrMinute, durationWeek, durationDay}),
+	string([]byte{durationHour, durationMonthOrMinute, durationSecond}),
+}
+
+func (i *iSO8601DurationConverter) tryParseISO8601Duration(input string) (ISO8601Duration, error) {
+	d := ISO8601Duration{}
+	if len(input) == 0 {
+		return d, nil
+	}
+	d._string = input
+	pos := 0
+
+	if input[0] == '-' {
+		pos++
+		d.Negative = true
+	} else if input[0] == '+' {
+		pos++
+	}
+	if pos >= len(input) {
+		return d, fmt.Errorf("expected P but got end of input")
+	}
+	if input[pos] != durationPeriod {
+		return d, fmt.Errorf("expected P but got `%c`", input[pos])
+	}
+	pos++
+	isTime := false
+	// next is the index of the next allowed designator, so every designator occurs at most once and in order
+	next := 0
+	components := 0
+	for pos < len(input) {
+		if input[pos] == durationTime {
+			if isTime {
+				return d, fmt.Errorf("unexpected token `%c`", durationTime)
+			}
+			isTime = true
+			next = 0
+			components = 0
+			pos++
+		}
+		newPos, nr, err := i.readNr(input, pos)
+		pos = newPos
+		if err != nil {
+			return d, err
+		}
+		if pos >= len(input) {
+			return d, fmt.Errorf("missing designator after `%s`", input[:pos])
+		}
+		mark := input[pos]
+		pos++
+		designators := durationDesignators[0]
+		if isTime {
+			designators = durationDesignators[1]
+		}
+		idx := strings.IndexByte(designators[next:], mark)
+		if idx < 0 {
+			return d, fmt.Errorf("unexpected token `%c`", mark)
+		}
+		next += idx + 1
+		components++
+		switch mark {
+		case durationYear:
+			d.Years = nr
+		case durationMonthOrMinute:
+			if isTime {
+				d.Minutes = nr
+			} else {
+				d.Months = nr
+			}
+
+		case durationWeek:
+			d.Weeks = nr
+		case durationDay:
+			d.Days = nr
+		case durationHour:
+			d.Hours = nr
+		case durationSecond:
+			d.Seconds = nr
+		}
+	}
+	if components == 0 {
+		return d, fmt.Errorf("missing component after `%s`", input)
+	}
+	return d, nil
+}
+
+const durationPeriod byte = 'P'
+const durationTime byte = 'T'
+const durationYear byte = 'Y'
+const durationMonthOrMinute byte = 'M'
+const durationWeek byte = 'W'
+const durationDay byte = 'D'
+const durationHour byte = 'H'
+const durationSecond byte = 'S'
@@ -0,0 +1,271 @@
+package fiqlparser
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTryParseISO8601Duration(t *testing.T) {
+	var values = []struct {
+		input       string
+		duration    ISO8601Duration
+		errorOutput bool
+	}{
+		//One year, one month, one day, one hour, one minute, one second, and 100 milliseconds
+		{input: "P1Y1M1DT1H1M1.1S", duration: ISO8601Duration{Negative: false, Years: 1, Months: 1, Weeks: 0, Days: 1, Hours: 1, Minutes: 1, Seconds: 1.1}, errorOutput: false},
+		{input: "-P1Y1M1DT1H1M1.1S", duration: ISO8601Duration{Negative: true, Years: 1, Months: 1, Weeks: 0, Days: 1, Hours: 1, Minutes: 1, Seconds: 1.1}, errorOutput: false},
+		{input: "+P1Y1M1DT1H1M1.1S", duration: ISO8601Duration{Negative: false, Years: 1, Months: 1, Weeks: 0, Days: 1, Hours: 1, Minutes: 1, Seconds: 1.1}, errorOutput: false},
+		//Forty days
+		{input: "P40D", duration: ISO8601Duration{Days: 40}, errorOutput: false},
+		//A year and a day
+		{input: "P1Y1D", duration: ISO8601Duration{Years: 1, Days: 1}, errorOutput: false},
+		//Three days, four hours and 59 minutes
+		{input: "P3DT4H59M", duration: ISO8601Duration{Days: 3, Hours: 4, Minutes: 59}, errorOutput: false},
+		//Two and a half hours
+		{input: "PT2H30M", duration: ISO8601Duration{Hours: 2, Minutes: 30}, errorOutput: false},
+		//One month
+		{input: "P1M", duration: ISO8601Duration{Months: 1}, errorOutput: false},
+		//One minute
+		{input: "PT1M", duration: ISO8601Duration{Minutes: 1}, errorOutput: false},
+		//2.1 milliseconds
+		{input: "PT0.0021S", duration: ISO8601Duration{Seconds: 0.0021}, errorOutput: false},
+		// one week
+		{input: "P1W", duration: ISO8601Duration{Weeks: 1}, errorOutput: false},
+		// zeros
+		{input: "PT0S", duration: ISO8601Duration{}, errorOutput: false},
+		{input: "P0D", duration: ISO8601Duration{}, errorOutput: false},
+		{input: "", duration: ISO8601Duration{}, errorOutput: false},
+		//errors
+		{input: "PD", duration: ISO8601Duration{}, errorOutput: true},
+		{input: "+1Y1D", duration: ISO8601Duration{}, errorOutput: true},
+		{input: "1Y1D", duration: ISO8601Duration{}, errorOutput: true},
+		{input: "P1X", duration: ISO8601Duration{}, errorOutput: true},
+		{input: "P", duration: ISO8601Duration{}, errorOutput: true},
+		{input: "-P", duration: ISO8601Duration{Negative: true}, errorOutput: true},
+		{input: "PT", duration: ISO8601Duration{}, errorOutput: true},
+		{input: "P1DT", duration: ISO8601Duration{Days: 1}, errorOutput: true},
+		{input: "PT1D", duration: ISO8601Duration{}, errorOutput: true},
+		{input: "P1D1Y", duration: ISO8601Duration{Days: 1}, errorOutput: true},
+		{input: "P1Y1Y", duration: ISO8601Duration{Years: 1}, errorOutput: true},
+		{input: "P1DT1HT1S", duration: ISO8601Duration{Days: 1, Hours: 1}, errorOutput: true},
+		{input: "P-1D", duration: ISO8601Duration{}, errorOutput: true},
+		{input: "P1.D", duration: ISO8601Duration{}, errorOutput: true},
+		{input: "P.5D", duration: ISO8601Duration{}, errorOutput: true},
+	}
+
+	for _, v := range values {
+		d, err := durationConverter.tryParseISO8601Duration(v.input)
+		if !v.errorOutput {
+			assert.NoError(t, err)
+			if err != nil {
+				return
+			}
+		} else {
+			assert.Error(t, err)
+		}
+		assert.Equal(t, v.duration.Negative, d.Negative)
+		assert.Equal(t, v.duration.Days, d.Days)
+		assert.Equal(t, v.duration.Hours, d.Hours)
+		assert.Equal(t, v.duration.Minutes, d.Minutes)
+		assert.Equal(t, v.duration.Months, d.Months)
+		assert.Equal(t, v.duration.Seconds, d.Seconds)
+		assert.Equal(t, v.duration.Weeks, d.Weeks)
+		assert.Equal(t, v.duration.Years, d.Years)
+	}
+}
+
+func TestAsMiliseconds(t *testing.T) {
+	var values = []struct {
+		input string
+		ms    int64
+	}{
+		//One year, one month, one day, one hour, one minute, one second, and 100 milliseconds
+		{input: "P1Y1M1DT1H1M1.1S", ms: 34277461100},
+		//Forty days
+		{input: "P40D", ms: 3456000000},
+		//A year and a day
+		{input: "P1Y1D", ms: 31644000000},
+		//Three days, four hours and 59 minutes
+		{input: "P3DT4H59M", ms: 277140000},
+		//Two and a half hours
+		{input: "PT2H30M", ms: 9000000},
+		//One month
+		{input: "P1M", ms: 2629800000},
+		//One minute
+		{input: "PT1M", ms: 60000},
+		//2.1 milliseconds - rounded
+		{input: "PT0.0021S", ms: 2},
+		//4 milliseconds
+		{input: "PT0.004S", ms: 4},
+		// one week
+		{input: "P1W", ms: 604800000},
+		{input: "P2W", ms: 1209600000},
+		// zeros
+		{input: "PT0S", ms: 0},
+		{input: "P0D", ms: 0},
+		{input: "", ms: 0},
+	}
+
+	for _, v := range values {
+		d, err := durationConverter.tryParseISO8601Duration(v.input)
+		if err != nil {
+			assert.NoError(t, err)
+		}
+		assert.Equal(t, v.ms, d.AsMilliseconds(), "failed %s", v.input)
+	}
+}
+
+func TestToTimeDuration(t *testing.T) {
+	var values = []struct {
+		input    string
+		duration time.Duration
+		err      bool
+	}{
+		{input: "PT2H30M", duration: 2*time.Hour + 30*time.Minute},
+		{input: "-PT1.5S", duration: -1500 * time.Millisecond},
+		{input: "P1W2DT1H", duration: 9*24*time.Hour + time.Hour},
+		{input: "PT0.0021S", duration: 2100 * time.Microsecond},
+		{input: "P0D", duration: 0},
+		{input: "P1M", err: true},
+		{input: "P1Y1D", err: true},
+		{input: "P300000D", err: true},
+	}
+	for _, v := range values {
+		d, err := durationConverter.tryParseISO8601Duration(v.input)
+		if !assert.NoError(t, err, v.input) {
+			continue
+		}
+		res, err := d.ToTimeDuration()
+		if v.err {
+			assert.Error(t, err, v.input)
+			continue
+		}
+		if assert.NoError(t, err, v.input) {
+			assert.Equal(t, v.duration, res, v.input)
+		}
+	}
+	d, _ := durationConverter.tryParseISO8601Duration("P1Y")
+	_, err := d.ToTimeDuration()
+	assert.ErrorIs(t, err, ErrNoFixedLength)
+}
+
+func TestDurationAddTo(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip("time zone data not available")
+	}
+	base := time.Date(2024, 1, 31, 10, 0, 0, 0, time.UTC)
+	var values = []struct {
+		input      string
+		t          time.Time
+		added      time.Time
+		subtracted time.Time
+	}{
+		{input: "P1M", t: base, added: time.Date(2024, 3, 2, 10, 0, 0, 0, time.UTC), subtracted: time.Date(2023, 12, 31, 10, 0, 0, 0, time.UTC)},
+		{input: "P1Y", t: time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC), added: time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), subtracted: time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{input: "P1Y2M3DT4H5M6S", t: base, added: time.Date(2025, 4, 3, 14, 5, 6, 0, time.UTC), subtracted: time.Date(2022, 11, 28, 5, 54, 54, 0, time.UTC)},
+		{input: "-P1W", t: base, added: time.Date(2024, 1, 24, 10, 0, 0, 0, time.UTC), subtracted: time.Date(2024, 2, 7, 10, 0, 0, 0, time.UTC)},
+		{input: "P1.5D", t: base, added: time.Date(2024, 2, 1, 22, 0, 0, 0, time.UTC), subtracted: time.Date(2024, 1, 29, 22, 0, 0, 0, time.UTC)},
+		// the wall clock is kept across the daylight saving time change
+		{input: "P1D", t: time.Date(2024, 3, 30, 12, 0, 0, 0, berlin), added: time.Date(2024, 3, 31, 12, 0, 0, 0, berlin), subtracted: time.Date(2024, 3, 29, 12, 0, 0, 0, berlin)},
+	}
+	for _, v := range values {
+		d, err := durationConverter.tryParseISO8601Duration(v.input)
+		if !assert.NoError(t, err, v.input) {
+			continue
+		}
+		assert.True(t, v.added.Equal(d.AddTo(v.t)), "%s: expected %s got %s", v.input, v.added, d.AddTo(v.t))
+		assert.True(t, v.subtracted.Equal(d.SubtractFrom(v.t)), "%s: expected %s got %s", v.input, v.subtracted, d.SubtractFrom(v.t))
+	}
+}
+
+func TestDurationCanonical(t *testing.T) {
+	var values = []struct {
+		input     string
+		canonical string
+	}{
+		{input: "P1Y1M1DT1H1M1.1S", canonical: "P1Y1M1DT1H1M1.1S"},
+		{input: "+P1Y", canonical: "P1Y"},
+		{input: "-P3DT4H59M", canonical: "-P3DT4H59M"},
+		{input: "P0Y2D", canonical: "P2D"},
+		{input: "PT0.0021S", canonical: "PT0.0021S"},
+		{input: "P1W", canonical: "P1W"},
+		{input: "P0D", canonical: "PT0S"},
+		{input: "-PT0S", canonical: "-PT0S"},
+	}
+	for _, v := range values {
+		d, err := durationConverter.tryParseISO8601Duration(v.input)
+		if !assert.NoError(t, err, v.input) {
+			continue
+		}
+		assert.Equal(t, v.input, d.String())
+		assert.Equal(t, v.canonical, d.Canonical())
+		again, err := durationConverter.tryParseISO8601Duration(d.Canonical())
+		if assert.NoError(t, err, v.input) {
+			assert.Equal(t, d.Canonical(), again.Canonical())
+		}
+	}
+	built := ISO8601Duration{Days: 2, Minutes: 30}
+	assert.Equal(t, "P2DT30M", built.String())
+	assert.Equal(t, "PT0S", (&ISO8601Duration{}).String())
+}
+
+func TestDurationJSON(t *testing.T) {
+	type filter struct {
+		Window ISO8601Duration
+		Grace  *ISO8601Duration
+	}
+	b, err := json.Marshal(filter{Window: ISO8601Duration{Hours: 2}, Grace: &ISO8601Duration{Negative: true, Days: 1}})
+	assert.NoError(t, err)
+	assert.Equal(t, `{"Window":"PT2H","Grace":"-P1D"}`, string(b))
+
+	var f filter
+	if assert.NoError(t, json.Unmarshal(b, &f)) {
+		assert.Equal(t, float64(2), f.Window.Hours)
+		assert.True(t, f.Grace.Negative)
+		assert.Equal(t, float64(1), f.Grace.Days)
+	}
+	for _, invalid := range []string{`{"Window":"2h"}`, `{"Window":"P1"}`, `{"Window":"-"}`, `{"Window":1}`} {
+		assert.Error(t, json.Unmarshal([]byte(invalid), &f), invalid)
+	}
+}
+
+func TestAsTimeRelative(t *testing.T) {
+	now := time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC)
+	var values = []struct {
+		input    string
+		expected time.Time
+		err      bool
+	}{
+		{input: "updated=gt=-P1D", expected: time.Date(2024, 3, 14, 12, 0, 0, 0, time.UTC)},
+		{input: "updated=lt=PT2H", expected: time.Date(2024, 3, 15, 14, 0, 0, 0, time.UTC)},
+		{input: "updated=gt=-P1M", expected: time.Date(2024, 2, 15, 12, 0, 0, 0, time.UTC)},
+		{input: "updated=gt=2024-01-01T00:00:00Z", expected: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{input: "updated==yesterday", err: true},
+	}
+	for _, v := range values {
+		res, err := Parse(context.Background(), v.input)
+		if !assert.NoError(t, err, v.input) {
+			continue
+		}
+		var arg ArgumentContext
+		Walk(&res, func(n Node) bool {
+			if p, ok := predicateOf(n); ok {
+				arg = p.Argument
+			}
+			return true
+		})
+		resolved, err := arg.AsTimeRelative(now)
+		if v.err {
+			assert.Error(t, err, v.input)
+			continue
+		}
+		if assert.NoError(t, err, v.input) {
+			assert.True(t, v.expected.Equal(resolved), "%s: expected %s got %s", v.input, v.expected, resolved)
+		}
+	}
+}
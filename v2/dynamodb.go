@@ -0,0 +1,289 @@
+package fiqlparser
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DynamoDBTranslator translates a expression to a DynamoDB filter expression,
+// e.g. `name==Jo*;age=gt=18` becomes `begins_with(#n0, :v0) AND #n1 > :v1`.
+// Attribute names and values are always passed as placeholders,
+// so reserved words and user input never end up in the expression.
+// The zero value maps every selector to the attribute of the same name, dots separate nested attributes.
+type DynamoDBTranslator struct {
+	// Attributes maps selectors to attribute paths (e.g. `address.city`), dots separate nested attributes
+	Attributes map[string]string
+	// Strict rejects selectors which are not mapped in Attributes
+	Strict bool
+	// Resolver resolves the path of selectors which are not mapped in Attributes
+	Resolver SelectorResolver
+}
+
+// DynamoDBFilter is a translated filter, the fields are passed as
+// FilterExpression, ExpressionAttributeNames and ExpressionAttributeValues.
+// The values are plain Go values (string, int64, float64 or bool) to be converted
+// by the attributevalue package of the AWS SDK, datetimes are RFC 3339 strings in UTC.
+type DynamoDBFilter struct {
+	Expression string
+	Names      map[string]string
+	Values     map[string]interface{}
+}
+
+// dynamoDBBuilder holds the state of a single translation
+type dynamoDBBuilder struct {
+	t *DynamoDBTranslator
+	b strings.Builder
+	// placeholders maps the attribute names to their placeholders
+	placeholders map[string]string
+	f            DynamoDBFilter
+}
+
+var dynamoDBComparisons = map[string]string{
+	string(ComparisonEq):  "=",
+	string(ComparisonNeq): "<>",
+	string(ComparisonGt):  ">",
+	string(ComparisonGte): ">=",
+	string(ComparisonLt):  "<",
+	string(ComparisonLte): "<=",
+}
+
+// Translate translates the expression to a filter, the empty expression is translated to a empty filter
+// which has to be omitted from the request
+func (t *DynamoDBTranslator) Translate(e Expression) (DynamoDBFilter, error) {
+	if e.node == nil {
+		return DynamoDBFilter{}, nil
+	}
+	s := &dynamoDBBuilder{t: t, placeholders: map[string]string{}, f: DynamoDBFilter{
+		Names:  map[string]string{},
+		Values: map[string]interface{}{},
+	}}
+	if err := s.write(&e); err != nil {
+		return DynamoDBFilter{}, err
+	}
+	s.f.Expression = s.b.String()
+	return s.f, nil
+}
+
+func (t *DynamoDBTranslator) path(selector string) (string, error) {
+	if p, ok := t.Attributes[selector]; ok && p != "" {
+		return p, nil
+	}
+	if t.Resolver != nil {
+		return t.Resolver(selector)
+	}
+	if t.Strict || selector == "" {
+		return "", fmt.Errorf("%w `%s`", ErrUnknownSelector, selector)
+	}
+	return selector, nil
+}
+
+// attribute returns the placeholder path of the selector, e.g. `#n0.#n1` for `address.city`
+func (s *dynamoDBBuilder) attribute(selector string) (string, error) {
+	p, err := s.t.path(selector)
+	if err != nil {
+		return "", err
+	}
+	parts := strings.Split(p, ".")
+	for i, name := range parts {
+		if name == "" {
+			return "", fmt.Errorf("%w `%s`", ErrUnknownSelector, selector)
+		}
+		placeholder, ok := s.placeholders[name]
+		if !ok {
+			placeholder = "#n" + strconv.Itoa(len(s.placeholders))
+			s.placeholders[name] = placeholder
+			s.f.Names[placeholder] = name
+		}
+		parts[i] = placeholder
+	}
+	return strings.Join(parts, "."), nil
+}
+
+// value adds a value placeholder
+func (s *dynamoDBBuilder) value(v interface{}) string {
+	placeholder := ":v" + strconv.Itoa(len(s.f.Values))
+	s.f.Values[placeholder] = v
+	return placeholder
+}
+
+func (s *dynamoDBBuilder) write(n Node) error {
+	n = unwrapExpression(n)
+	if op, operands, ok := logicalOperands(n); ok {
+		for i, o := range operands {
+			if i > 0 {
+				s.b.WriteRune(' ')
+				s.b.WriteString(op)
+				s.b.WriteRune(' ')
+			}
+			_, _, nested := logicalOperands(unwrapExpression(o))
+			if nested {
+				s.b.WriteRune('(')
+			}
+			if err := s.write(o); err != nil {
+				return err
+			}
+			if nested {
+				s.b.WriteRune(')')
+			}
+		}
+		return nil
+	}
+	switch node := n.(type) {
+	case *binaryExpression:
+		return s.writePredicate(node)
+	case *notExpression:
+		s.b.WriteString("NOT (")
+		if err := s.write(node.node); err != nil {
+			return err
+		}
+		s.b.WriteRune(')')
+		return nil
+	case *constantExpression:
+		a, err := s.attribute(node.value)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(&s.b, "attribute_exists(%s)", a)
+		return nil
+	}
+	return fmt.Errorf("unsupported node `%v`", n)
+}
+
+func (s *dynamoDBBuilder) writePredicate(node *binaryExpression) error {
+	sel, arg, ok := predicateOperands(node)
+	if !ok {
+		return fmt.Errorf("incomplete comparison `%s`", node.String())
+	}
+	a, err := s.attribute(sel.value)
+	if err != nil {
+		return err
+	}
+	if arg.recommended == ValueRecommendationRange {
+		return s.writeBetween(a, node.operator, arg)
+	}
+	if arg.tuple != nil {
+		if node.operator != string(ComparisonIn) {
+			return fmt.Errorf("unsupported tuple comparison `%s`", node.operator)
+		}
+		s.b.WriteString(a)
+		s.b.WriteString(" IN (")
+		for i, el := range arg.tuple.elements {
+			if el.prefixWildcard || el.suffixWildcard {
+				return fmt.Errorf("wildcards are not supported within tuples `%s`", el.value)
+			}
+			if i > 0 {
+				s.b.WriteString(", ")
+			}
+			v, err := dynamoDBValue(el)
+			if err != nil {
+				return err
+			}
+			s.b.WriteString(s.value(v))
+		}
+		s.b.WriteRune(')')
+		return nil
+	}
+	cmp, ok := dynamoDBComparisons[node.operator]
+	if !ok {
+		return fmt.Errorf("unsupported comparison `%s`", node.operator)
+	}
+	if ref, ok := arg.Argument().Field(); ok {
+		other, err := s.attribute(ref)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(&s.b, "%s %s %s", a, cmp, other)
+		return nil
+	}
+	if node.operator == string(ComparisonEq) || node.operator == string(ComparisonNeq) {
+		if arg.recommended == ValueRecommendationNull && arg.quote == 0 {
+			if node.operator == string(ComparisonEq) {
+				fmt.Fprintf(&s.b, "attribute_not_exists(%s)", a)
+			} else {
+				fmt.Fprintf(&s.b, "attribute_exists(%s)", a)
+			}
+			return nil
+		}
+		if arg.prefixWildcard || arg.suffixWildcard {
+			return s.writeMatch(a, node.operator == string(ComparisonNeq), arg)
+		}
+	}
+	if arg.prefixWildcard || arg.suffixWildcard {
+		return fmt.Errorf("wildcards are not supported by `%s`", fiqlOperators[node.operator])
+	}
+	v, err := dynamoDBValue(arg)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(&s.b, "%s %s %s", a, cmp, s.value(v))
+	return nil
+}
+
+// writeMatch writes a wildcard comparison, `Jo*` is translated to begins_with and `*oh*` to contains,
+// there is no function matching the end of a value
+func (s *dynamoDBBuilder) writeMatch(a string, negate bool, arg *constantExpression) error {
+	fn := "begins_with"
+	if arg.prefixWildcard {
+		if !arg.suffixWildcard {
+			return fmt.Errorf("leading wildcards are only supported together with trailing ones `*%s`", arg.value)
+		}
+		fn = "contains"
+	}
+	if negate {
+		s.b.WriteString("NOT ")
+	}
+	fmt.Fprintf(&s.b, "%s(%s, %s)", fn, a, s.value(arg.value))
+	return nil
+}
+
+// writeBetween writes a range comparison, `==` and `=bt=` match the range and `!=` excludes it
+func (s *dynamoDBBuilder) writeBetween(a string, operator string, arg *constantExpression) error {
+	low, high, ok := argumentRangeBounds(arg.value, arg.tuple)
+	if !ok {
+		return fmt.Errorf("%w `%s`", ErrNoRange, arg.value)
+	}
+	negate := false
+	switch operator {
+	case string(ComparisonEq), string(ComparisonBetween):
+	case string(ComparisonNeq):
+		negate = true
+	default:
+		return fmt.Errorf("unsupported range comparison `%s`", operator)
+	}
+	lv, err := dynamoDBValue(low)
+	if err != nil {
+		return err
+	}
+	hv, err := dynamoDBValue(high)
+	if err != nil {
+		return err
+	}
+	if negate {
+		s.b.WriteString("NOT (")
+	}
+	fmt.Fprintf(&s.b, "%s BETWEEN %s AND %s", a, s.value(lv), s.value(hv))
+	if negate {
+		s.b.WriteRune(')')
+	}
+	return nil
+}
+
+// dynamoDBValue converts the argument to a typed value according to its recommendation
+func dynamoDBValue(arg *constantExpression) (interface{}, error) {
+	if arg.quote != 0 {
+		return arg.value, nil
+	}
+	switch arg.recommended {
+	case ValueRecommendationDuration:
+		return nil, fmt.Errorf("duration `%s` is not supported by DynamoDB filters", arg.value)
+	case ValueRecommendationDateTime:
+		tm, err := arg.Argument().AsTime()
+		if err != nil {
+			return nil, err
+		}
+		return tm.UTC().Format(time.RFC3339Nano), nil
+	}
+	return sqlArgument(arg), nil
+}
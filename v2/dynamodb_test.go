@@ -0,0 +1,106 @@
+package fiqlparser
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDynamoDBTranslator(t *testing.T) {
+	var values = []struct {
+		fiql   string
+		expr   string
+		names  map[string]string
+		values map[string]interface{}
+		err    string
+	}{
+		{
+			fiql:   "name==Jo*;age=gt=18",
+			expr:   "begins_with(#n0, :v0) AND #n1 > :v1",
+			names:  map[string]string{"#n0": "name", "#n1": "age"},
+			values: map[string]interface{}{":v0": "Jo", ":v1": int64(18)},
+		},
+		{
+			fiql:   "name==*oh*,name!=x",
+			expr:   "contains(#n0, :v0) OR #n0 <> :v1",
+			names:  map[string]string{"#n0": "name"},
+			values: map[string]interface{}{":v0": "oh", ":v1": "x"},
+		},
+		{
+			fiql:   "a==1;(b==true,c==2.5)",
+			expr:   "#n0 = :v0 AND (#n1 = :v1 OR #n2 = :v2)",
+			names:  map[string]string{"#n0": "a", "#n1": "b", "#n2": "c"},
+			values: map[string]interface{}{":v0": int64(1), ":v1": true, ":v2": 2.5},
+		},
+		{
+			fiql:   "address.city==Vienna",
+			expr:   "#n0.#n1 = :v0",
+			names:  map[string]string{"#n0": "address", "#n1": "city"},
+			values: map[string]interface{}{":v0": "Vienna"},
+		},
+		{
+			fiql:   "age!=1..5",
+			expr:   "NOT (#n0 BETWEEN :v0 AND :v1)",
+			names:  map[string]string{"#n0": "age"},
+			values: map[string]interface{}{":v0": int64(1), ":v1": int64(5)},
+		},
+		{
+			fiql:   "status=in=[open+\"closed\"];created=ge=2024-05-01T10:30:00+02:00",
+			expr:   "#n0 IN (:v0, :v1) AND #n1 >= :v2",
+			names:  map[string]string{"#n0": "status", "#n1": "created"},
+			values: map[string]interface{}{":v0": "open", ":v1": "closed", ":v2": "2024-05-01T08:30:00Z"},
+		},
+		{
+			fiql:   "mail==null,phone!=null,deleted",
+			expr:   "attribute_not_exists(#n0) OR attribute_exists(#n1) OR attribute_exists(#n2)",
+			names:  map[string]string{"#n0": "mail", "#n1": "phone", "#n2": "deleted"},
+			values: map[string]interface{}{},
+		},
+		{fiql: "name==*x", err: "leading wildcards are only supported together with trailing ones `*x`"},
+		{fiql: "age=ge=1*", err: "wildcards are not supported by `=ge=`"},
+		{fiql: "created=gt=-P1D", err: "duration `-P1D` is not supported by DynamoDB filters"},
+		{fiql: "name=q=john", err: "unsupported comparison `QUERY`"},
+	}
+	translator := &DynamoDBTranslator{}
+	for _, v := range values {
+		res, err := Parse(context.Background(), v.fiql)
+		if !assert.NoError(t, err, v.fiql) {
+			continue
+		}
+		f, err := translator.Translate(res)
+		if v.err != "" {
+			assert.EqualError(t, err, v.err, v.fiql)
+			continue
+		}
+		if assert.NoError(t, err, v.fiql) {
+			assert.Equal(t, v.expr, f.Expression, v.fiql)
+			assert.Equal(t, v.names, f.Names, v.fiql)
+			assert.Equal(t, v.values, f.Values, v.fiql)
+		}
+	}
+}
+
+func TestDynamoDBTranslatorOptions(t *testing.T) {
+	translator := &DynamoDBTranslator{
+		Attributes: map[string]string{"city": "address.city"},
+		Strict:     true,
+	}
+	res, err := New(WithNegation()).Parse(context.Background(), "city==x;!(city==y)")
+	if !assert.NoError(t, err) {
+		return
+	}
+	f, err := translator.Translate(res)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "#n0.#n1 = :v0 AND NOT (#n0.#n1 = :v1)", f.Expression)
+	}
+
+	res, err = Parse(context.Background(), "name==x")
+	assert.NoError(t, err)
+	_, err = translator.Translate(res)
+	assert.ErrorIs(t, err, ErrUnknownSelector)
+
+	f, err = translator.Translate(Expression{})
+	assert.NoError(t, err)
+	assert.Equal(t, DynamoDBFilter{}, f)
+}
@@ -0,0 +1,382 @@
+package fiqlparser
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ErrUnknownSelector is generated by translators if a selector is not mapped in strict mode
+var ErrUnknownSelector = errors.New("unknown selector")
+
+// SelectorResolver resolves a selector to a identifier of the target (e.g. a column or field),
+// resolvers should return a error wrapping ErrUnknownSelector for selectors they do not know
+type SelectorResolver func(selector string) (identifier string, err error)
+
+// ElasticsearchField describes how a selector is mapped to a elasticsearch field
+type ElasticsearchField struct {
+	// Name is the name of the field in the index
+	Name string
+	// Analyzed marks text fields, which are tokenized by a analyzer
+	// equality is translated to match_phrase and wildcards are matched case insensitive
+	// unless a Keyword sub field is supplied
+	Analyzed bool
+	// Keyword is the name of a not analyzed sub field (e.g. `name.keyword`)
+	// which is used for equality and wildcard queries on analyzed fields
+	Keyword string
+}
+
+// ElasticsearchTranslator translates a expression to a elasticsearch bool query,
+// the zero value maps every selector to the field of the same name.
+type ElasticsearchTranslator struct {
+	// Fields maps selectors to fields, unmapped selectors are used as field name
+	Fields map[string]ElasticsearchField
+	// Strict rejects selectors which are not mapped in Fields
+	Strict bool
+	// Resolver resolves the field name of selectors which are not mapped in Fields
+	Resolver SelectorResolver
+	// Nulls defines whether `!=` matches documents missing the field, with NullSemanticsExclude
+	// the field is required to exist
+	Nulls NullSemantics
+}
+
+// Translate translates the expression to the elasticsearch query DSL
+func (t *ElasticsearchTranslator) Translate(e Expression) (map[string]interface{}, error) {
+	if e.node == nil {
+		return map[string]interface{}{"match_all": map[string]interface{}{}}, nil
+	}
+	return t.translate(&e)
+}
+
+// TranslateJSON translates the expression to the elasticsearch query DSL as JSON
+func (t *ElasticsearchTranslator) TranslateJSON(e Expression) ([]byte, error) {
+	q, err := t.Translate(e)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(q)
+}
+
+func (t *ElasticsearchTranslator) field(selector string) (ElasticsearchField, error) {
+	if f, ok := t.Fields[selector]; ok {
+		if f.Name == "" {
+			f.Name = selector
+		}
+		return f, nil
+	}
+	if t.Resolver != nil {
+		name, err := t.Resolver(selector)
+		if err != nil {
+			return ElasticsearchField{}, err
+		}
+		return ElasticsearchField{Name: name}, nil
+	}
+	if t.Strict {
+		return ElasticsearchField{}, fmt.Errorf("%w `%s`", ErrUnknownSelector, selector)
+	}
+	return ElasticsearchField{Name: selector}, nil
+}
+
+func (t *ElasticsearchTranslator) translate(n Node) (map[string]interface{}, error) {
+	switch node := n.(type) {
+	case *Expression:
+		q, err := t.translate(node.node)
+		if err != nil || node.label == "" {
+			return q, err
+		}
+		return map[string]interface{}{"bool": map[string]interface{}{
+			"filter": []interface{}{q},
+			"_name":  node.label,
+		}}, nil
+	case *binaryExpression:
+		if node.operator == string(OperatorAND) || node.operator == string(OperatorOR) {
+			return t.translateLogical(node)
+		}
+		return t.translatePredicate(node)
+	case *logicalExpression:
+		return t.translateLogical(node)
+	case *notExpression:
+		q, err := t.translate(node.node)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"bool": map[string]interface{}{"must_not": []interface{}{q}}}, nil
+	case *constantExpression:
+		f, err := t.field(node.value)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"exists": map[string]interface{}{"field": f.Name}}, nil
+	}
+	return nil, fmt.Errorf("unsupported node `%v`", n)
+}
+
+func (t *ElasticsearchTranslator) translateLogical(node Node) (map[string]interface{}, error) {
+	operator, operands, _ := logicalOperands(node)
+	clauses := make([]interface{}, 0, len(operands))
+	// equality on the same field within a OR is collected into a terms query
+	terms := make(map[string][]interface{})
+	termsOrder := make([]string, 0)
+	for _, o := range operands {
+		if operator == string(OperatorOR) {
+			if field, value, ok := t.termOperand(o); ok {
+				if _, exists := terms[field]; !exists {
+					termsOrder = append(termsOrder, field)
+				}
+				terms[field] = append(terms[field], value)
+				continue
+			}
+		}
+		q, err := t.translate(o)
+		if err != nil {
+			return nil, err
+		}
+		clauses = append(clauses, q)
+	}
+	for _, field := range termsOrder {
+		values := terms[field]
+		if len(values) == 1 {
+			clauses = append(clauses, map[string]interface{}{"term": map[string]interface{}{field: values[0]}})
+			continue
+		}
+		clauses = append(clauses, map[string]interface{}{"terms": map[string]interface{}{field: values}})
+	}
+	if len(clauses) == 1 {
+		return clauses[0].(map[string]interface{}), nil
+	}
+	if operator == string(OperatorAND) {
+		return map[string]interface{}{"bool": map[string]interface{}{"filter": clauses}}, nil
+	}
+	return map[string]interface{}{"bool": map[string]interface{}{"should": clauses, "minimum_should_match": 1}}, nil
+}
+
+// termOperand returns the field and value if the node is a equality translated to a term query
+func (t *ElasticsearchTranslator) termOperand(n Node) (string, interface{}, bool) {
+	bin, ok := n.(*binaryExpression)
+	if !ok || bin.operator != string(ComparisonEq) {
+		return "", nil, false
+	}
+	sel, arg, ok := predicateOperands(bin)
+	if !ok || arg.prefixWildcard || arg.suffixWildcard || arg.recommended == ValueRecommendationNull || arg.recommended == ValueRecommendationRange {
+		return "", nil, false
+	}
+	f, err := t.field(sel.value)
+	if err != nil {
+		return "", nil, false
+	}
+	if f.Analyzed {
+		if f.Keyword == "" {
+			return "", nil, false
+		}
+		return f.Keyword, arg.value, true
+	}
+	return f.Name, arg.value, true
+}
+
+// predicateOperands returns the selector and argument of a comparison
+func predicateOperands(bin *binaryExpression) (*constantExpression, *constantExpression, bool) {
+	sel, ok := bin.nodes[0].(*constantExpression)
+	if !ok {
+		return nil, nil, false
+	}
+	arg, ok := bin.nodes[1].(*constantExpression)
+	if !ok {
+		return nil, nil, false
+	}
+	return sel, arg, true
+}
+
+var elasticRangeOperators = map[string]string{
+	string(ComparisonGt):  "gt",
+	string(ComparisonGte): "gte",
+	string(ComparisonLt):  "lt",
+	string(ComparisonLte): "lte",
+}
+
+func (t *ElasticsearchTranslator) translatePredicate(node *binaryExpression) (map[string]interface{}, error) {
+	sel, arg, ok := predicateOperands(node)
+	if !ok {
+		return nil, fmt.Errorf("incomplete comparison `%s`", node.String())
+	}
+	f, err := t.field(sel.value)
+	if err != nil {
+		return nil, err
+	}
+	if arg.recommended == ValueRecommendationField {
+		return nil, fmt.Errorf("field references are not supported by Elasticsearch queries `@%s`", arg.value)
+	}
+	if arg.recommended == ValueRecommendationRange {
+		return t.between(f, node.operator, arg)
+	}
+	if arg.tuple != nil {
+		return t.in(f, arg.tuple), nil
+	}
+	switch node.operator {
+	case string(ComparisonQuery):
+		return map[string]interface{}{"match": map[string]interface{}{f.Name: arg.value}}, nil
+	case string(ComparisonEq):
+		return t.equality(f, arg), nil
+	case string(ComparisonNeq):
+		if arg.recommended == ValueRecommendationNull {
+			return map[string]interface{}{"exists": map[string]interface{}{"field": f.Name}}, nil
+		}
+		return t.negate(f, t.equality(f, arg)), nil
+	}
+	op, ok := elasticRangeOperators[node.operator]
+	if !ok {
+		return nil, fmt.Errorf("unsupported comparison `%s`", node.operator)
+	}
+	value, err := elasticRangeValue(arg)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"range": map[string]interface{}{f.Name: map[string]interface{}{op: value}}}, nil
+}
+
+// between translates a range comparison, `==` and `=bt=` match the range and `!=` excludes it
+func (t *ElasticsearchTranslator) between(f ElasticsearchField, operator string, arg *constantExpression) (map[string]interface{}, error) {
+	low, high, ok := argumentRangeBounds(arg.value, arg.tuple)
+	if !ok {
+		return nil, fmt.Errorf("%w `%s`", ErrNoRange, arg.value)
+	}
+	gte, err := elasticRangeValue(low)
+	if err != nil {
+		return nil, err
+	}
+	lte, err := elasticRangeValue(high)
+	if err != nil {
+		return nil, err
+	}
+	q := map[string]interface{}{"range": map[string]interface{}{f.Name: map[string]interface{}{"gte": gte, "lte": lte}}}
+	switch operator {
+	case string(ComparisonEq), string(ComparisonBetween):
+		return q, nil
+	case string(ComparisonNeq):
+		return t.negate(f, q), nil
+	}
+	return nil, fmt.Errorf("unsupported range comparison `%s`", operator)
+}
+
+// in translates a membership comparison to a terms query, elements with wildcards
+// or analyzed fields without keyword are matched by a should clause
+func (t *ElasticsearchTranslator) in(f ElasticsearchField, tuple *tupleArgument) map[string]interface{} {
+	plain := !f.Analyzed || f.Keyword != ""
+	for _, el := range tuple.elements {
+		plain = plain && !el.prefixWildcard && !el.suffixWildcard
+	}
+	if plain {
+		name := f.Name
+		if f.Analyzed {
+			name = f.Keyword
+		}
+		values := make([]interface{}, 0, len(tuple.elements))
+		for _, el := range tuple.elements {
+			values = append(values, el.value)
+		}
+		return map[string]interface{}{"terms": map[string]interface{}{name: values}}
+	}
+	clauses := make([]interface{}, 0, len(tuple.elements))
+	for _, el := range tuple.elements {
+		clauses = append(clauses, t.equality(f, el))
+	}
+	return map[string]interface{}{"bool": map[string]interface{}{"should": clauses, "minimum_should_match": 1}}
+}
+
+// negate wraps the query in a must_not, honoring the null semantics
+func (t *ElasticsearchTranslator) negate(f ElasticsearchField, q map[string]interface{}) map[string]interface{} {
+	clause := map[string]interface{}{"must_not": []interface{}{q}}
+	if t.Nulls == NullSemanticsExclude {
+		clause["filter"] = []interface{}{map[string]interface{}{"exists": map[string]interface{}{"field": f.Name}}}
+	}
+	return map[string]interface{}{"bool": clause}
+}
+
+func (t *ElasticsearchTranslator) equality(f ElasticsearchField, arg *constantExpression) map[string]interface{} {
+	if arg.recommended == ValueRecommendationNull {
+		return map[string]interface{}{"bool": map[string]interface{}{
+			"must_not": []interface{}{map[string]interface{}{"exists": map[string]interface{}{"field": f.Name}}},
+		}}
+	}
+	if arg.prefixWildcard || arg.suffixWildcard {
+		var b strings.Builder
+		if arg.prefixWildcard {
+			b.WriteRune('*')
+		}
+		b.WriteString(escapeElasticWildcard(arg.value))
+		if arg.suffixWildcard {
+			b.WriteRune('*')
+		}
+		if f.Analyzed && f.Keyword == "" {
+			return map[string]interface{}{"wildcard": map[string]interface{}{
+				f.Name: map[string]interface{}{"value": strings.ToLower(b.String()), "case_insensitive": true},
+			}}
+		}
+		name := f.Name
+		if f.Analyzed {
+			name = f.Keyword
+		}
+		return map[string]interface{}{"wildcard": map[string]interface{}{name: map[string]interface{}{"value": b.String()}}}
+	}
+	if f.Analyzed {
+		if f.Keyword != "" {
+			return map[string]interface{}{"term": map[string]interface{}{f.Keyword: arg.value}}
+		}
+		return map[string]interface{}{"match_phrase": map[string]interface{}{f.Name: arg.value}}
+	}
+	return map[string]interface{}{"term": map[string]interface{}{f.Name: arg.value}}
+}
+
+func escapeElasticWildcard(v string) string {
+	var b strings.Builder
+	for _, r := range v {
+		if r == '*' || r == '?' || r == '\\' {
+			b.WriteRune('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// elasticRangeValue converts the argument to a number, a date or date math for durations
+func elasticRangeValue(arg *constantExpression) (interface{}, error) {
+	switch arg.recommended {
+	case ValueRecommendationNumber:
+		return strconv.ParseFloat(arg.value, 64)
+	case ValueRecommendationDuration:
+		d, err := durationConverter.tryParseISO8601Duration(arg.value)
+		if err != nil {
+			return nil, err
+		}
+		return elasticDateMath(d)
+	}
+	return arg.value, nil
+}
+
+// elasticDateMath converts a duration to date math relative to now, e.g. -P1D is `now-1d`
+func elasticDateMath(d ISO8601Duration) (string, error) {
+	var b strings.Builder
+	b.WriteString("now")
+	sign := "+"
+	if d.Negative {
+		sign = "-"
+	}
+	units := []struct {
+		v    float64
+		unit string
+	}{{d.Years, "y"}, {d.Months, "M"}, {d.Weeks, "w"}, {d.Days, "d"}, {d.Hours, "h"}, {d.Minutes, "m"}, {d.Seconds, "s"}}
+	for _, u := range units {
+		if u.v == 0 {
+			continue
+		}
+		if u.v != math.Trunc(u.v) {
+			return "", fmt.Errorf("fractional duration `%s` is not supported by date math", d.String())
+		}
+		b.WriteString(sign)
+		b.WriteString(strconv.FormatFloat(u.v, 'f', -1, 64))
+		b.WriteString(u.unit)
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,120 @@
+package fiqlparser
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestElasticsearchTranslator(t *testing.T) {
+	translator := &ElasticsearchTranslator{
+		Fields: map[string]ElasticsearchField{
+			"user":  {Name: "user_id"},
+			"title": {Analyzed: true},
+			"name":  {Name: "full_name", Analyzed: true, Keyword: "full_name.keyword"},
+		},
+	}
+	var values = []struct {
+		fiql  string
+		query string
+	}{
+		{fiql: "user==1", query: `{"term":{"user_id":"1"}}`},
+		{fiql: "status!=open", query: `{"bool":{"must_not":[{"term":{"status":"open"}}]}}`},
+		{fiql: "age=gt=18", query: `{"range":{"age":{"gt":18}}}`},
+		{fiql: "age=le=18.5", query: `{"range":{"age":{"lte":18.5}}}`},
+		{fiql: "updated=ge=2003-12-13T00:00:00Z", query: `{"range":{"updated":{"gte":"2003-12-13T00:00:00Z"}}}`},
+		{fiql: "updated=lt=-P1DT2H", query: `{"range":{"updated":{"lt":"now-1d-2h"}}}`},
+		{fiql: "status==op*", query: `{"wildcard":{"status":{"value":"op*"}}}`},
+		{fiql: "status==*a\\*b?", query: `{"wildcard":{"status":{"value":"*a\\*b\\?"}}}`},
+		{fiql: "title==Hello", query: `{"match_phrase":{"title":"Hello"}}`},
+		{fiql: "title==Hel*", query: `{"wildcard":{"title":{"value":"hel*","case_insensitive":true}}}`},
+		{fiql: "name==Jo*", query: `{"wildcard":{"full_name.keyword":{"value":"Jo*"}}}`},
+		{fiql: "name==John", query: `{"term":{"full_name.keyword":"John"}}`},
+		{fiql: "deleted", query: `{"exists":{"field":"deleted"}}`},
+		{fiql: `title=q="quick fox"`, query: `{"match":{"title":"quick fox"}}`},
+		{fiql: "status=in=[open+closed]", query: `{"terms":{"status":["open","closed"]}}`},
+		{fiql: "name=in=[John+Jane]", query: `{"terms":{"full_name.keyword":["John","Jane"]}}`},
+		{fiql: "title=in=[Hello+Wor*]", query: `{"bool":{"should":[{"match_phrase":{"title":"Hello"}},{"wildcard":{"title":{"value":"wor*","case_insensitive":true}}}],"minimum_should_match":1}}`},
+		{fiql: "age=bt=18..65", query: `{"range":{"age":{"gte":18,"lte":65}}}`},
+		{fiql: "updated!=-P2D..-P1D", query: `{"bool":{"must_not":[{"range":{"updated":{"gte":"now-2d","lte":"now-1d"}}}]}}`},
+		{fiql: "deleted==null", query: `{"bool":{"must_not":[{"exists":{"field":"deleted"}}]}}`},
+		{fiql: "deleted!=null", query: `{"exists":{"field":"deleted"}}`},
+		{fiql: "a==null,a==1", query: `{"bool":{"should":[{"bool":{"must_not":[{"exists":{"field":"a"}}]}},{"term":{"a":"1"}}],"minimum_should_match":1}}`},
+		{fiql: "a==1;b==2;c==3", query: `{"bool":{"filter":[{"term":{"a":"1"}},{"term":{"b":"2"}},{"term":{"c":"3"}}]}}`},
+		{fiql: "a==1;(b==2;c==3)", query: `{"bool":{"filter":[{"term":{"a":"1"}},{"term":{"b":"2"}},{"term":{"c":"3"}}]}}`},
+		{fiql: "a==1,a==2,a==3", query: `{"terms":{"a":["1","2","3"]}}`},
+		{fiql: "a==1,b==2,a==3,c=gt=1", query: `{"bool":{"should":[{"range":{"c":{"gt":1}}},{"terms":{"a":["1","3"]}},{"term":{"b":"2"}}],"minimum_should_match":1}}`},
+		{fiql: "a==1;(b==2,c==3)", query: `{"bool":{"filter":[{"term":{"a":"1"}},{"bool":{"should":[{"term":{"b":"2"}},{"term":{"c":"3"}}],"minimum_should_match":1}}]}}`},
+		{fiql: "a==1;urgent:(b==2)", query: `{"bool":{"filter":[{"term":{"a":"1"}},{"bool":{"filter":[{"term":{"b":"2"}}],"_name":"urgent"}}]}}`},
+	}
+	for _, v := range values {
+		res, err := Parse(context.Background(), v.fiql)
+		if !assert.NoError(t, err, v.fiql) {
+			continue
+		}
+		j, err := translator.TranslateJSON(res)
+		if assert.NoError(t, err, v.fiql) {
+			assert.JSONEq(t, v.query, string(j), v.fiql)
+		}
+	}
+}
+
+func TestElasticsearchTranslatorNullSemantics(t *testing.T) {
+	translator := &ElasticsearchTranslator{Nulls: NullSemanticsExclude}
+	var values = []struct {
+		fiql  string
+		query string
+	}{
+		{fiql: "status!=open", query: `{"bool":{"must_not":[{"term":{"status":"open"}}],"filter":[{"exists":{"field":"status"}}]}}`},
+		{fiql: "age!=1..2", query: `{"bool":{"must_not":[{"range":{"age":{"gte":1,"lte":2}}}],"filter":[{"exists":{"field":"age"}}]}}`},
+		{fiql: "status!=null", query: `{"exists":{"field":"status"}}`},
+	}
+	for _, v := range values {
+		res, err := Parse(context.Background(), v.fiql)
+		if !assert.NoError(t, err, v.fiql) {
+			continue
+		}
+		j, err := translator.TranslateJSON(res)
+		if assert.NoError(t, err, v.fiql) {
+			assert.JSONEq(t, v.query, string(j), v.fiql)
+		}
+	}
+}
+
+func TestElasticsearchTranslatorResolver(t *testing.T) {
+	translator := &ElasticsearchTranslator{
+		Fields: map[string]ElasticsearchField{"name": {Analyzed: true}},
+		Resolver: func(selector string) (string, error) {
+			if selector == "secret" {
+				return "", fmt.Errorf("%w `%s`", ErrUnknownSelector, selector)
+			}
+			return "attributes." + selector, nil
+		},
+	}
+	res, err := Parse(context.Background(), "name==John;color==red")
+	assert.NoError(t, err)
+	j, err := translator.TranslateJSON(res)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"bool":{"filter":[{"match_phrase":{"name":"John"}},{"term":{"attributes.color":"red"}}]}}`, string(j))
+
+	res, err = Parse(context.Background(), "secret==x")
+	assert.NoError(t, err)
+	_, err = translator.Translate(res)
+	assert.ErrorIs(t, err, ErrUnknownSelector)
+}
+
+func TestElasticsearchTranslatorErrors(t *testing.T) {
+	translator := &ElasticsearchTranslator{Strict: true, Fields: map[string]ElasticsearchField{"a": {}}}
+	res, err := Parse(context.Background(), "a==1;b==2")
+	assert.NoError(t, err)
+	_, err = translator.Translate(res)
+	assert.ErrorIs(t, err, ErrUnknownSelector)
+	assert.EqualError(t, err, "unknown selector `b`")
+
+	res, err = Parse(context.Background(), "a=gt=P1.5D")
+	assert.NoError(t, err)
+	_, err = translator.Translate(res)
+	assert.EqualError(t, err, "fractional duration `P1.5D` is not supported by date math")
+}
@@ -0,0 +1,258 @@
+package fiqlparser
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrorCode classifies the reason a ParseError was generated
+type ErrorCode string
+
+// ErrorCodeUnexpectedInput is used for input that can not be tokenized, e.g. an invalid comparison
+const ErrorCodeUnexpectedInput ErrorCode = "UnexpectedInput"
+
+// ErrorCodeUnexpectedEOF is used if the input ends in the middle of a token
+const ErrorCodeUnexpectedEOF ErrorCode = "UnexpectedEOF"
+
+// ErrorCodeUnexpectedToken is used if a valid token is found where another one was expected
+const ErrorCodeUnexpectedToken ErrorCode = "UnexpectedToken"
+
+// ErrorCodeInvalidValue is used if a argument does not satisfy the comparison (e.g. a string for =gt=)
+const ErrorCodeInvalidValue ErrorCode = "InvalidValue"
+
+// ErrorCodeUnclosedBrace is used if a sub expression is not closed
+const ErrorCodeUnclosedBrace ErrorCode = "UnclosedBrace"
+
+// ErrorCodeInvalidClosingBrace is used if a closing brace has no matching opening brace
+const ErrorCodeInvalidClosingBrace ErrorCode = "InvalidClosingBrace"
+
+// ErrorCodeDanglingOperator is used if a logical operator is missing an operand
+const ErrorCodeDanglingOperator ErrorCode = "DanglingOperator"
+
+// ErrorCodeDanglingComparator is used if a comparison is missing its selector
+const ErrorCodeDanglingComparator ErrorCode = "DanglingComparator"
+
+// ErrorCodeTrailingInput is used if input remains after a complete expression (e.g. `name==John Doe`)
+const ErrorCodeTrailingInput ErrorCode = "TrailingInput"
+
+// ErrorCodeInvalidSelector is used if a selector does not match the configured selector pattern
+const ErrorCodeInvalidSelector ErrorCode = "InvalidSelector"
+
+// ErrorCodeUnknownSelector is used if a selector is not mapped by the strict aliases configured by WithSelectorAliases
+const ErrorCodeUnknownSelector ErrorCode = "UnknownSelector"
+
+// ErrorCodeSpecViolation is used if the input deviates from the FIQL specification in strict mode
+const ErrorCodeSpecViolation ErrorCode = "SpecViolation"
+
+// ErrorCodeInputTooLong is used if the input exceeds the length configured by WithMaxLength
+const ErrorCodeInputTooLong ErrorCode = "InputTooLong"
+
+// ErrorCodeInvalidDirective is used for malformed directives in filter files
+const ErrorCodeInvalidDirective ErrorCode = "InvalidDirective"
+
+// ErrorCodeUnresolvedInclude is used if a included file can not be resolved
+const ErrorCodeUnresolvedInclude ErrorCode = "UnresolvedInclude"
+
+// ErrorCodeIncludeCycle is used if files include each other
+const ErrorCodeIncludeCycle ErrorCode = "IncludeCycle"
+
+// ErrUnexpectedInput is generated once unexpected input is met
+var ErrUnexpectedInput = errors.New("unexpected input")
+
+// ErrUnexpectedEOF is generated if the input is suspected to be incomplete
+var ErrUnexpectedEOF = errors.New("unexpected end of file")
+
+// ParseError is returned for any syntax error found while parsing,
+// use errors.As to retrieve it.
+//
+// Offset and ByteOffset point at the start of the offending token for every error
+// (at the opening quote of a quoted value, at the end of the input for a unexpected end),
+// so input[ByteOffset:] starts with Token. Position returns that start with its line and column.
+// Line and Column are where Error reports the error, they are kept as they were
+// and do not necessarily match the start of the token.
+type ParseError struct {
+	// Line is the line the error is reported on, starting at 1
+	Line int
+	// Column is the column the error is reported at (in runes, starting at 1 as in Error): the last rune read when the error was detected,
+	// errors about a selector, a value rejected by a ValueValidator, trailing input or specification violations are reported at the first rune
+	// of the offending input instead
+	Column int
+	// Offset is the start of the offending token within the whole input (in runes, starting at 0)
+	Offset int
+	// ByteOffset is the start of the offending token within the whole input (in bytes, starting at 0)
+	ByteOffset int
+	// Code classifies the error
+	Code ErrorCode
+	// Token is the offending token or input
+	Token string
+	// Expected contains what would have been valid instead of Token, if known
+	Expected []string
+	// Tokens are the tokens consumed before the error occurred, only set if WithTokenTrace is used
+	Tokens []Token
+
+	msg   string
+	err   error
+	start Position
+}
+
+// Error returns the error in the form `ln:<line>:<column> <message>`
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("ln:%d:%d %s", e.Line, e.Column, e.msg)
+}
+
+// Position returns the start of the offending token,
+// its column starts at 0 like the positions of nodes and validation issues
+func (e *ParseError) Position() Position {
+	return e.start
+}
+
+// Unwrap returns the cause of the error (e.g. ErrUnexpectedInput or ErrUnexpectedEOF) if any
+func (e *ParseError) Unwrap() error {
+	return e.err
+}
+
+// newParseError creates a error reported at the current lexer position,
+// its offsets point at the start of the last token read
+func (p *lexer) newParseError(code ErrorCode, token string, expected []string, msg string) *ParseError {
+	return &ParseError{
+		Line:       p.ln,
+		Column:     p.posInLine,
+		Offset:     p.start.Offset,
+		ByteOffset: p.start.ByteOffset,
+		start:      p.start,
+		Code:       code,
+		Token:      token,
+		Expected:   expected,
+		msg:        msg,
+	}
+}
+
+func (p *lexer) errUnexpectedComparator(cmp string) *ParseError {
+	err := p.newParseError(ErrorCodeUnexpectedInput, cmp, comparators,
+		fmt.Sprintf("%s (got `%s` but expected one of %s)", ErrUnexpectedInput, cmp, strings.Join(comparators, ",")))
+	err.err = ErrUnexpectedInput
+	return err
+}
+
+func (p *lexer) errUnexpectedEOF(token string, expected []string) *ParseError {
+	err := p.newParseError(ErrorCodeUnexpectedEOF, token, expected, ErrUnexpectedEOF.Error())
+	err.err = ErrUnexpectedEOF
+	return err
+}
+
+func (p *lexer) errUnterminatedQuote(quote rune, value string) *ParseError {
+	err := p.newParseError(ErrorCodeUnexpectedEOF, string(quote)+value, []string{string(quote)},
+		fmt.Sprintf("%s (unterminated quote `%c`)", ErrUnexpectedEOF, quote))
+	err.err = ErrUnexpectedEOF
+	return err
+}
+
+func (p *lexer) errInvalidValue(value string, expected []string) *ParseError {
+	return p.newParseError(ErrorCodeInvalidValue, value, expected,
+		fmt.Sprintf("syntax error (got `%s` but expected %s)", value, strings.Join(expected, " or ")))
+}
+
+func (p *lexer) errExpectedValue(t tokenType) *ParseError {
+	return p.newParseError(ErrorCodeUnexpectedToken, p.literal(t), []string{"value"},
+		fmt.Sprintf("syntax error (got `%s` but expected a value)", t.String()))
+}
+
+func (p *lexer) errExpectedSelector(t tokenType) *ParseError {
+	return p.newParseError(ErrorCodeUnexpectedToken, p.literal(t), []string{"selector", "("},
+		fmt.Sprintf("syntax error (got `%s` but expected a selector)", t.String()))
+}
+
+func (p *lexer) errDanglingComparator(t tokenType) *ParseError {
+	return p.newParseError(ErrorCodeDanglingComparator, p.literal(t), []string{"selector"}, "dangling comparator")
+}
+
+func (p *lexer) errDanglingOperator(t tokenType) *ParseError {
+	return p.newParseError(ErrorCodeDanglingOperator, p.literal(t), []string{"selector", "("}, "dangling operator")
+}
+
+func (p *lexer) errInvalidClosingBrace() *ParseError {
+	return p.newParseError(ErrorCodeInvalidClosingBrace, p.literal(tokenBraceClose), nil, "syntax error (invalid closing brace `)` )")
+}
+
+func (p *lexer) errUnclosedBrace(t tokenType) *ParseError {
+	return p.newParseError(ErrorCodeUnclosedBrace, p.literal(t), []string{p.literal(tokenBraceClose)}, "syntax error (unclosed brace `)` )")
+}
+
+// at positions the error at the start of a token
+func (e *ParseError) at(pos Position) *ParseError {
+	e.Line, e.Column, e.Offset, e.ByteOffset, e.start = pos.Line, pos.Column+1, pos.Offset, pos.ByteOffset, pos
+	return e
+}
+
+// startingAt points the offsets of the error at the start of the offending token, the reported line and column are kept
+func (e *ParseError) startingAt(pos Position) *ParseError {
+	e.Offset, e.ByteOffset, e.start = pos.Offset, pos.ByteOffset, pos
+	return e
+}
+
+// errInvalidSelector is positioned at the start of the selector instead of the current position
+func (p *lexer) errInvalidSelector(selector string, pos Position, pattern string) *ParseError {
+	return p.newParseError(ErrorCodeInvalidSelector, selector, []string{pattern},
+		fmt.Sprintf("invalid selector (`%s` does not match `%s`)", selector, pattern)).at(pos)
+}
+
+// errUnknownSelector is positioned at the start of the selector, it unwraps to ErrUnknownSelector
+func (p *lexer) errUnknownSelector(selector string, pos Position, known []string) *ParseError {
+	err := p.newParseError(ErrorCodeUnknownSelector, selector, known, fmt.Sprintf("%s `%s`", ErrUnknownSelector, selector)).at(pos)
+	err.err = ErrUnknownSelector
+	return err
+}
+
+// errRejectedValue is positioned at the start of the argument rejected by a ValueValidator, it unwraps to cause
+func (p *lexer) errRejectedValue(arg Node, cause error) *ParseError {
+	var b strings.Builder
+	writeFIQL(&b, arg)
+	err := p.newParseError(ErrorCodeInvalidValue, b.String(), nil, fmt.Sprintf("invalid value `%s` (%s)", b.String(), cause))
+	if c, ok := arg.(*constantExpression); ok {
+		err.at(c.pos)
+	}
+	err.err = cause
+	return err
+}
+
+// errTrailingInput is positioned at the start of the remaining input
+func (p *lexer) errTrailingInput(t tokenType) *ParseError {
+	msg := fmt.Sprintf("syntax error (unexpected `%s` after complete expression)", p.literal(t))
+	if t == tokenValue {
+		msg = fmt.Sprintf("syntax error (unexpected `%s` after complete expression, quote values containing whitespace)", p.literal(t))
+	}
+	return p.newParseError(ErrorCodeTrailingInput, p.literal(t), []string{";", ","}, msg).at(p.start)
+}
+
+// errInputTooLong is positioned at the start of the input, nothing has been parsed yet
+func (p *lexer) errInputTooLong(length int, max int) *ParseError {
+	return p.newParseError(ErrorCodeInputTooLong, "", nil, fmt.Sprintf("input too long (%d bytes, at most %d allowed)", length, max))
+}
+
+// errReaderTooLong is returned by ParseReader once more than max bytes have been read,
+// the input is not read any further so its length is unknown
+func (p *lexer) errReaderTooLong(max int) *ParseError {
+	return p.newParseError(ErrorCodeInputTooLong, "", nil, fmt.Sprintf("input too long (more than %d bytes allowed)", max))
+}
+
+// ParseErrors are all syntax errors of a input ordered by position, see Parser.ParseAll.
+// It unwraps to the first error, so errors.As and errors.Is behave as for Parse.
+type ParseErrors []*ParseError
+
+// Error returns the errors separated by `; `
+func (e ParseErrors) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, err := range e {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Unwrap returns the first error
+func (e ParseErrors) Unwrap() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e[0]
+}
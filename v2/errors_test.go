@@ -0,0 +1,118 @@
+package fiqlparser
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseError(t *testing.T) {
+	var values = []struct {
+		fiql     string
+		line     int
+		column   int
+		offset   int
+		code     ErrorCode
+		token    string
+		expected []string
+	}{
+		{fiql: "a==b;", line: 1, column: 5, offset: 5, code: ErrorCodeDanglingOperator, token: "", expected: []string{"selector", "("}},
+		{fiql: ",a==b", line: 1, column: 1, offset: 0, code: ErrorCodeDanglingOperator, token: ",", expected: []string{"selector", "("}},
+		{fiql: "a==b!=", line: 1, column: 4, offset: 4, code: ErrorCodeDanglingComparator, token: "!=", expected: []string{"selector"}},
+		{fiql: "(a==b", line: 1, column: 5, offset: 5, code: ErrorCodeUnclosedBrace, token: "", expected: []string{")"}},
+		{fiql: "a==b)", line: 1, column: 4, offset: 4, code: ErrorCodeInvalidClosingBrace, token: ")", expected: nil},
+		{fiql: "a==", line: 1, column: 3, offset: 3, code: ErrorCodeUnexpectedToken, token: "", expected: []string{"value"}},
+		{fiql: "a=ge=invalid", line: 1, column: 12, offset: 5, code: ErrorCodeInvalidValue, token: "invalid", expected: []string{"number", "date", "duration"}},
+		{fiql: "a=gt=P", line: 1, column: 6, offset: 5, code: ErrorCodeInvalidValue, token: "P", expected: []string{"number", "date", "duration"}},
+		{fiql: "a=f", line: 1, column: 2, offset: 1, code: ErrorCodeUnexpectedInput, token: "=f", expected: comparators},
+		{fiql: "a=g", line: 1, column: 3, offset: 1, code: ErrorCodeUnexpectedEOF, token: "=g", expected: comparators},
+		{fiql: "a==b;\n c==", line: 2, column: 4, offset: 10, code: ErrorCodeUnexpectedToken, token: "", expected: []string{"value"}},
+		{fiql: "näme==b;", line: 1, column: 8, offset: 8, code: ErrorCodeDanglingOperator, token: "", expected: []string{"selector", "("}},
+		{fiql: "a==1,*", line: 1, column: 6, offset: 5, code: ErrorCodeUnexpectedToken, token: "*", expected: []string{"selector", "("}},
+		{fiql: "*", line: 1, column: 1, offset: 0, code: ErrorCodeUnexpectedToken, token: "*", expected: []string{"selector", "("}},
+		{fiql: "(*)", line: 1, column: 2, offset: 1, code: ErrorCodeUnexpectedToken, token: "*", expected: []string{"selector", "("}},
+	}
+	for _, v := range values {
+		_, err := Parse(context.Background(), v.fiql)
+		var perr *ParseError
+		if !assert.True(t, errors.As(err, &perr), "expected ParseError for `%s`", v.fiql) {
+			continue
+		}
+		assert.Equal(t, v.line, perr.Line, v.fiql)
+		assert.Equal(t, v.column, perr.Column, v.fiql)
+		assert.Equal(t, v.offset, perr.Offset, v.fiql)
+		assert.Equal(t, v.code, perr.Code, v.fiql)
+		assert.Equal(t, v.token, perr.Token, v.fiql)
+		assert.Equal(t, v.expected, perr.Expected, v.fiql)
+	}
+}
+
+func TestParseErrorUnwrap(t *testing.T) {
+	_, err := Parse(context.Background(), "title=ffoo*")
+	assert.ErrorIs(t, err, ErrUnexpectedInput)
+	assert.EqualError(t, err, "ln:1:6 unexpected input (got `=f` but expected one of ==,!=,=gt=,=ge=,=lt=,=le=,=bt=,=between=,=in=,=q=)")
+
+	_, err = Parse(context.Background(), "title=g")
+	assert.ErrorIs(t, err, ErrUnexpectedEOF)
+	assert.EqualError(t, err, "ln:1:7 unexpected end of file")
+
+	_, err = Parse(context.Background(), "title==")
+	assert.False(t, errors.Is(err, ErrUnexpectedEOF))
+}
+
+func TestParseErrorByteOffset(t *testing.T) {
+	_, err := Parse(context.Background(), "näme==b;")
+	var perr *ParseError
+	if assert.True(t, errors.As(err, &perr)) {
+		assert.Equal(t, 8, perr.Column)
+		assert.Equal(t, 8, perr.Offset)
+		assert.Equal(t, 9, perr.ByteOffset)
+	}
+
+	input := "ä==1\nö=="
+	_, err = ParseMulti(context.Background(), input)
+	if assert.True(t, errors.As(err, &perr)) {
+		assert.Equal(t, 2, perr.Line)
+		assert.Equal(t, 3, perr.Column)
+		assert.Equal(t, 8, perr.Offset)
+		assert.Equal(t, 10, perr.ByteOffset)
+		assert.Equal(t, "ö==", input[perr.ByteOffset-4:perr.ByteOffset])
+	}
+}
+
+func TestParseErrorOffsets(t *testing.T) {
+	var values = []struct {
+		fiql   string
+		opts   []Option
+		offset int
+		token  string
+		pos    Position
+	}{
+		{fiql: "ü==ä)", offset: 4, token: ")", pos: Position{Line: 1, Column: 4, Offset: 4, ByteOffset: 6}},
+		{fiql: "a=gt=xyz;b==1", offset: 5, token: "xyz", pos: Position{Line: 1, Column: 5, Offset: 5, ByteOffset: 5}},
+		{fiql: "a==ü ü", offset: 5, token: "ü", pos: Position{Line: 1, Column: 5, Offset: 5, ByteOffset: 6}},
+		{fiql: "a=gt=\"xyz\"", offset: 5, token: "xyz", pos: Position{Line: 1, Column: 5, Offset: 5, ByteOffset: 5}},
+		{fiql: "a==\"xyz", offset: 3, token: "\"xyz", pos: Position{Line: 1, Column: 3, Offset: 3, ByteOffset: 3}},
+		{fiql: "a==b!=", offset: 4, token: "!=", pos: Position{Line: 1, Column: 4, Offset: 4, ByteOffset: 4}},
+		{fiql: "a=x=1", offset: 1, token: "=x", pos: Position{Line: 1, Column: 1, Offset: 1, ByteOffset: 1}},
+		{fiql: "a==b;\n c==", offset: 10, token: "", pos: Position{Line: 2, Column: 4, Offset: 10, ByteOffset: 10}},
+		{fiql: "a==1", opts: []Option{WithMaxLength(3)}, offset: 0, token: "", pos: Position{Line: 1}},
+		{fiql: "a=b", opts: []Option{WithStrictSpec()}, offset: 1, token: "=b", pos: Position{Line: 1, Column: 1, Offset: 1, ByteOffset: 1}},
+	}
+	for _, v := range values {
+		_, err := New(v.opts...).Parse(context.Background(), v.fiql)
+		var perr *ParseError
+		if !assert.True(t, errors.As(err, &perr), "expected ParseError for `%s`", v.fiql) {
+			continue
+		}
+		assert.Equal(t, v.offset, perr.Offset, v.fiql)
+		assert.Equal(t, v.token, perr.Token, v.fiql)
+		assert.Equal(t, v.pos, perr.Position(), v.fiql)
+		assert.Equal(t, v.pos.ByteOffset, perr.ByteOffset, v.fiql)
+		rest := v.fiql[perr.ByteOffset:]
+		assert.True(t, strings.HasPrefix(rest, perr.Token) || strings.HasPrefix(rest, "\""+perr.Token+"\""), v.fiql)
+	}
+}
@@ -0,0 +1,52 @@
+package fiqlparser
+
+import "strings"
+
+// ValueRecommendationField suggests a reference to another selector (`@start_date`), see WithFieldReferences
+const ValueRecommendationField ValueRecommendation = "field"
+
+// WithFieldReferences enables arguments referencing another selector prefixed by `@`,
+// e.g. `end_date=gt=@start_date`. The argument is recommended as ValueRecommendationField,
+// its value is the referenced selector (see ArgumentContext.Field), which is subject to the same
+// case policy, pattern and mapper as the selector of the comparison.
+// Quoted values (`"@start_date"`) remain literals.
+func WithFieldReferences() Option {
+	return func(p *Parser) {
+		p.fieldReferences = true
+	}
+}
+
+// Field returns the referenced selector if the argument is a field reference (see WithFieldReferences)
+func (c ArgumentContext) Field() (string, bool) {
+	if c.r != ValueRecommendationField {
+		return "", false
+	}
+	return c.val, true
+}
+
+// isFieldReference reports whether the consumed value is a field reference
+func (p *Parser) isFieldReference(prefixWildcard bool) bool {
+	return p.fieldReferences && !prefixWildcard && p.lex.lastQuote() == 0 && strings.HasPrefix(p.lex.lastValue(), "@")
+}
+
+// handleFieldReference creates the argument for the consumed field reference
+func (p *Parser) handleFieldReference(pos Position) (Node, error) {
+	raw := p.lex.lastValue()
+	if len(raw) == 1 {
+		return nil, p.lex.errInvalidValue(raw, []string{"selector"})
+	}
+	ref := &constantExpression{value: raw[1:], recommended: ValueRecommendationField, pos: pos}
+	p.lex.countNode(constantSize, ref.value)
+	n, _, err := p.lex.PeekNextToken()
+	if err != nil {
+		return nil, err
+	}
+	if n == tokenWildcard {
+		return nil, p.lex.errInvalidValue(raw+"*", []string{"selector"})
+	}
+	if err := p.prepareSelector(ref); err != nil {
+		return nil, err
+	}
+	p.recommendDateTime(ref)
+	return ref, nil
+}
@@ -0,0 +1,170 @@
+package fiqlparser
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFieldReferences(t *testing.T) {
+	var values = []struct {
+		input    string
+		expected string
+		fiql     string
+		err      bool
+	}{
+		{input: "end=gt=@start", expected: "(end > @start)", fiql: "end=gt=@start"},
+		{input: "a==@b;c!=@d", expected: "(a == @b AND c <> @d)", fiql: "a==@b;c!=@d"},
+		{input: `a=="@b"`, expected: `(a == "@b")`, fiql: `a=="@b"`},
+		{input: "a==*@b", expected: "(a == *@b)", fiql: `a==*"@b"`},
+		{input: "a==@", err: true},
+		{input: "a==@b*", err: true},
+	}
+	p := New(WithFieldReferences())
+	for _, v := range values {
+		res, err := p.Parse(context.Background(), v.input)
+		if v.err {
+			assert.Error(t, err, v.input)
+			continue
+		}
+		if !assert.NoError(t, err, v.input) {
+			continue
+		}
+		assert.Equal(t, v.expected, res.String(), v.input)
+		assert.Equal(t, v.fiql, res.ToFIQL(), v.input)
+	}
+
+	res, err := p.Parse(context.Background(), "end=gt=@start")
+	if assert.NoError(t, err) {
+		pred, ok := predicateOf(res.node)
+		if assert.True(t, ok) {
+			ref, ok := pred.Argument.Field()
+			assert.True(t, ok)
+			assert.Equal(t, "start", ref)
+			assert.Equal(t, ValueRecommendationField, pred.Argument.ValueRecommendation())
+		}
+		assert.Equal(t, []string{"end", "start"}, res.Selectors())
+	}
+
+	// without the option `@` is part of a literal, which can not be compared relationally
+	res, err = Parse(context.Background(), "a==@b")
+	if assert.NoError(t, err) {
+		pred, _ := predicateOf(res.node)
+		_, ok := pred.Argument.Field()
+		assert.False(t, ok)
+		assert.Equal(t, `a=="@b"`, res.ToFIQL())
+	}
+	_, err = Parse(context.Background(), "end=gt=@start")
+	assert.Error(t, err)
+}
+
+func TestFieldReferencesSelectorPolicy(t *testing.T) {
+	p := New(WithFieldReferences(), WithSelectorCase(SelectorCaseLower), WithSelectorMapper(func(s string) string { return "x." + s }))
+	res, err := p.Parse(context.Background(), "End=gt=@Start")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "(x.end > @x.start)", res.String())
+	}
+
+	_, err = New(WithFieldReferences(), WithSelectorPattern(regexp.MustCompile(`^[a-z]+$`))).Parse(context.Background(), "end=gt=@start_date")
+	var perr *ParseError
+	if assert.True(t, errors.As(err, &perr)) {
+		assert.Equal(t, ErrorCodeInvalidSelector, perr.Code)
+	}
+}
+
+func TestFieldReferencesRoundTrip(t *testing.T) {
+	res, err := New(WithFieldReferences()).Parse(context.Background(), "end=gt=@start")
+	if !assert.NoError(t, err) {
+		return
+	}
+	thawed := res.Freeze().Thaw()
+	assert.Equal(t, res.String(), thawed.String())
+
+	b, err := res.MarshalDetailedJSON()
+	if !assert.NoError(t, err) {
+		return
+	}
+	var restored Expression
+	if assert.NoError(t, json.Unmarshal(b, &restored)) {
+		pred, _ := predicateOf(restored.node)
+		ref, ok := pred.Argument.Field()
+		assert.True(t, ok)
+		assert.Equal(t, "start", ref)
+	}
+}
+
+func TestFieldReferencesSchema(t *testing.T) {
+	schema := Schema{Selectors: []SelectorSchema{
+		{Name: "start", Type: ValueRecommendationDateTime},
+		{Name: "end", Type: ValueRecommendationDateTime},
+		{Name: "name"},
+		{Name: "age", Type: ValueRecommendationNumber},
+	}}
+	var values = []struct {
+		input string
+		err   error
+	}{
+		{input: "end=gt=@start"},
+		{input: "name==@age"},
+		{input: "end=gt=@age", err: ErrInvalidArgumentType},
+		{input: "end=gt=@hidden", err: ErrUnknownSelector},
+	}
+	p := New(WithFieldReferences())
+	for _, v := range values {
+		res, err := p.Parse(context.Background(), v.input)
+		if !assert.NoError(t, err, v.input) {
+			continue
+		}
+		err = schema.Validate(res)
+		if v.err == nil {
+			assert.NoError(t, err, v.input)
+			continue
+		}
+		assert.ErrorIs(t, err, v.err, v.input)
+	}
+}
+
+func TestFieldReferencesTranslators(t *testing.T) {
+	p := New(WithFieldReferences())
+	res, err := p.Parse(context.Background(), "end=gt=@start;name!=@alias")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	sql, args, err := (&SQLTranslator{Columns: map[string]string{"end": "t.end_at", "start": "t.start_at", "name": "t.name", "alias": "t.alias"}}).Translate(res)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "t.end_at > t.start_at AND t.name <> t.alias", sql)
+		assert.Empty(t, args)
+	}
+	_, _, err = (&SQLTranslator{Columns: map[string]string{"end": "t.end_at", "name": "t.name"}}).Translate(res)
+	assert.ErrorIs(t, err, ErrUnknownSelector)
+
+	cel, err := (&CELTranslator{}).Translate(res)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "end > start && name != alias", cel)
+	}
+
+	f, err := (&DynamoDBTranslator{}).Translate(res)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "#n0 > #n1 AND #n2 <> #n3", f.Expression)
+		assert.Equal(t, map[string]string{"#n0": "end", "#n1": "start", "#n2": "name", "#n3": "alias"}, f.Names)
+		assert.Empty(t, f.Values)
+	}
+
+	_, err = (&ElasticsearchTranslator{}).Translate(res)
+	assert.EqualError(t, err, "field references are not supported by Elasticsearch queries `@start`")
+	_, err = (&LDAPTranslator{}).Translate(res)
+	assert.EqualError(t, err, "field references are not supported by LDAP filters `@start`")
+	_, err = (&LuceneTranslator{}).Translate(res)
+	assert.EqualError(t, err, "field references are not supported by Lucene queries `@start`")
+
+	res, err = p.Parse(context.Background(), "name=q=@alias")
+	if assert.NoError(t, err) {
+		_, _, err = (&SQLTranslator{Columns: map[string]string{"alias": "t.alias", "name": "t.name"}}).Translate(res)
+		assert.EqualError(t, err, "unsupported field comparison `QUERY`")
+	}
+}
@@ -0,0 +1,361 @@
+package fiqlparser
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Filter returns the items matching the expression in their original order,
+// binder returns the value of a selector for a item (nil if the item has no such value).
+//
+// Values are compared according to their Go type: strings (wildcards match prefixes and suffixes),
+// signed and unsigned integers, floats, bools, time.Time (durations are relative to the start of the call,
+// see ArgumentContext.AsTimeRelative) and time.Duration. Pointers are dereferenced, nil matches `null`.
+// Slices match if any element matches, `!=` if no element equals the argument.
+// Unary selectors match non-nil values, `=q=` matches case insensitive substrings
+// and field references (see WithFieldReferences) compare with the value bound to the referenced selector.
+// Values which can not be compared with the argument (e.g. `age==abc` for a int) do not match.
+// Like SQLTranslator `!=` does not match nil values, see FilterWithOptions to include them.
+func Filter[T any](expr Expression, items []T, binder func(T, string) any) ([]T, error) {
+	return FilterWithOptions(expr, items, binder, FilterOptions{})
+}
+
+// FilterOptions configures FilterWithOptions
+type FilterOptions struct {
+	// Nulls defines whether `!=` matches items where the selector is nil, with NullSemanticsInclude
+	// `status!=open` matches them as `(status <> ? OR status IS NULL)` does in SQL.
+	// By default they do not match, like the default of SQLTranslator.
+	Nulls NullSemantics
+}
+
+// FilterWithOptions returns the items matching the expression like Filter, configured by opts
+func FilterWithOptions[T any](expr Expression, items []T, binder func(T, string) any, opts FilterOptions) ([]T, error) {
+	res := make([]T, 0, len(items))
+	ev := evaluator{now: time.Now(), nulls: opts.Nulls}
+	for _, item := range items {
+		item := item
+		ev.bind = func(selector string) any { return binder(item, selector) }
+		ok, err := ev.eval(&expr)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			res = append(res, item)
+		}
+	}
+	return res, nil
+}
+
+// evaluator evaluates a expression against the values of a single item
+type evaluator struct {
+	bind  func(selector string) any
+	now   time.Time
+	nulls NullSemantics
+}
+
+func (ev *evaluator) eval(n Node) (bool, error) {
+	n = unwrapExpression(n)
+	if e, ok := n.(*Expression); n == nil || (ok && e.node == nil) {
+		return true, nil
+	}
+	if op, operands, ok := logicalOperands(n); ok {
+		for _, o := range operands {
+			res, err := ev.eval(o)
+			if err != nil {
+				return false, err
+			}
+			if res != (op == string(OperatorAND)) {
+				return res, nil
+			}
+		}
+		return op == string(OperatorAND), nil
+	}
+	if not, ok := n.(*notExpression); ok {
+		res, err := ev.eval(not.node)
+		return !res, err
+	}
+	p, ok := predicateOf(n)
+	if !ok {
+		return false, fmt.Errorf("unsupported node `%v`", n)
+	}
+	v := filterValue(ev.bind(p.Selector))
+	if p.IsUnary() {
+		return v != nil, nil
+	}
+	if v == nil && p.Comparison == ComparisonNeq && !p.Argument.IsNull() {
+		return ev.nulls == NullSemanticsInclude, nil
+	}
+	return ev.matchValue(v, p.Comparison, p.Argument)
+}
+
+// matchValue matches a value or, for slices, its elements
+func (ev *evaluator) matchValue(v any, c Comparison, arg ArgumentContext) (bool, error) {
+	rv := reflect.ValueOf(v)
+	if v == nil || (rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array) {
+		return ev.match(v, c, arg)
+	}
+	negated := c == ComparisonNeq && arg.ValueRecommendation() != ValueRecommendationRange
+	if negated {
+		c = ComparisonEq
+	}
+	for i := 0; i < rv.Len(); i++ {
+		ok, err := ev.match(filterValue(rv.Index(i).Interface()), c, arg)
+		if err != nil {
+			return false, err
+		}
+		if ok {
+			return !negated, nil
+		}
+	}
+	return negated, nil
+}
+
+func (ev *evaluator) match(v any, c Comparison, arg ArgumentContext) (bool, error) {
+	if ref, ok := arg.Field(); ok {
+		other := filterValue(ev.bind(ref))
+		if other == nil && c == ComparisonNeq {
+			// like `a <> b` in SQL a nil reference never matches
+			return false, nil
+		}
+		cmp, comparable := compareValues(v, other)
+		return compareResult(c, cmp, comparable, v == nil && other == nil)
+	}
+	switch arg.ValueRecommendation() {
+	case ValueRecommendationRange:
+		return ev.between(v, c, arg)
+	case ValueRecommendationTuple:
+		if c != ComparisonIn {
+			return false, fmt.Errorf("unsupported tuple comparison `%s`", c)
+		}
+		elements, err := arg.AsTuple()
+		if err != nil {
+			return false, err
+		}
+		for _, el := range elements {
+			if ok, _ := ev.match(v, ComparisonEq, el); ok {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+	if arg.IsNull() && (c == ComparisonEq || c == ComparisonNeq) {
+		return (v == nil) == (c == ComparisonEq), nil
+	}
+	if c == ComparisonQuery {
+		return v != nil && strings.Contains(strings.ToLower(filterString(v)), strings.ToLower(arg.AsString())), nil
+	}
+	if arg.StartsWithWildcard() || arg.EndsWithWildcard() {
+		if c != ComparisonEq && c != ComparisonNeq {
+			return false, fmt.Errorf("wildcards are not supported by `%s`", fiqlOperators[string(c)])
+		}
+		return (v != nil && matchWildcard(filterString(v), arg)) == (c == ComparisonEq), nil
+	}
+	cmp, comparable := ev.compareArgument(v, arg)
+	return compareResult(c, cmp, comparable, false)
+}
+
+// between matches inclusive ranges, `==` and `=bt=` match the range and `!=` excludes it
+func (ev *evaluator) between(v any, c Comparison, arg ArgumentContext) (bool, error) {
+	low, high, err := arg.AsRange()
+	if err != nil {
+		return false, err
+	}
+	lc, lok := ev.compareArgument(v, low)
+	hc, hok := ev.compareArgument(v, high)
+	in := lok && hok && lc >= 0 && hc <= 0
+	switch c {
+	case ComparisonEq, ComparisonBetween:
+		return in, nil
+	case ComparisonNeq:
+		return !in, nil
+	}
+	return false, fmt.Errorf("unsupported range comparison `%s`", c)
+}
+
+// compareResult applies the comparison to the result of a comparison,
+// values which are not comparable are only unequal
+func compareResult(c Comparison, cmp int, comparable bool, equal bool) (bool, error) {
+	switch c {
+	case ComparisonEq:
+		return equal || (comparable && cmp == 0), nil
+	case ComparisonNeq:
+		return !equal && (!comparable || cmp != 0), nil
+	case ComparisonGt:
+		return comparable && cmp > 0, nil
+	case ComparisonGte:
+		return comparable && cmp >= 0, nil
+	case ComparisonLt:
+		return comparable && cmp < 0, nil
+	case ComparisonLte:
+		return comparable && cmp <= 0, nil
+	}
+	return false, fmt.Errorf("unsupported comparison `%s`", c)
+}
+
+// compareArgument compares the value with the argument converted to the type of the value
+func (ev *evaluator) compareArgument(v any, arg ArgumentContext) (int, bool) {
+	switch value := v.(type) {
+	case string:
+		return strings.Compare(value, arg.AsString()), true
+	case bool:
+		b, err := arg.AsBool()
+		if err != nil || value != b {
+			return 1, err == nil
+		}
+		return 0, true
+	case int64:
+		if i, err := strconv.ParseInt(arg.AsString(), 10, 64); err == nil {
+			return compareOrdered(value, i), true
+		}
+		f, err := arg.AsFloat64()
+		return compareOrdered(float64(value), f), err == nil
+	case uint64:
+		if u, err := strconv.ParseUint(arg.AsString(), 10, 64); err == nil {
+			return compareOrdered(value, u), true
+		}
+		f, err := arg.AsFloat64()
+		return compareOrdered(float64(value), f), err == nil
+	case float64:
+		f, err := arg.AsFloat64()
+		return compareOrdered(value, f), err == nil
+	case time.Time:
+		t, err := arg.AsTimeRelative(ev.now)
+		if err != nil {
+			return 0, false
+		}
+		return compareTimes(value, t), true
+	case time.Duration:
+		d, err := arg.AsDuration()
+		if err != nil {
+			return 0, false
+		}
+		td, err := d.ToTimeDuration()
+		return compareOrdered(value, td), err == nil
+	}
+	return 0, false
+}
+
+// compareValues compares two bound values, numbers of different types are compared as float64
+func compareValues(a any, b any) (int, bool) {
+	switch x := a.(type) {
+	case string:
+		if y, ok := b.(string); ok {
+			return strings.Compare(x, y), true
+		}
+	case bool:
+		if y, ok := b.(bool); ok {
+			if x == y {
+				return 0, true
+			}
+			return 1, true
+		}
+	case time.Time:
+		if y, ok := b.(time.Time); ok {
+			return compareTimes(x, y), true
+		}
+	case time.Duration:
+		if y, ok := b.(time.Duration); ok {
+			return compareOrdered(x, y), true
+		}
+	}
+	if x, ok := filterNumber(a); ok {
+		if y, ok := filterNumber(b); ok {
+			return compareOrdered(x, y), true
+		}
+	}
+	return 0, false
+}
+
+func compareOrdered[N int64 | uint64 | float64 | time.Duration](a N, b N) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func compareTimes(a time.Time, b time.Time) int {
+	switch {
+	case a.Before(b):
+		return -1
+	case a.After(b):
+		return 1
+	}
+	return 0
+}
+
+// filterValue normalizes a bound value, pointers are dereferenced and numbers converted
+// to int64, uint64 or float64, byte slices to strings
+func filterValue(v any) any {
+	switch value := v.(type) {
+	case nil, string, bool, int64, uint64, float64, time.Time, time.Duration:
+		return v
+	case []byte:
+		return string(value)
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if rv.IsNil() {
+			return nil
+		}
+		return filterValue(rv.Elem().Interface())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return rv.Uint()
+	case reflect.Float32, reflect.Float64:
+		return rv.Float()
+	case reflect.String:
+		return rv.String()
+	case reflect.Bool:
+		return rv.Bool()
+	case reflect.Slice, reflect.Array, reflect.Map:
+		if rv.Kind() != reflect.Array && rv.IsNil() {
+			return nil
+		}
+		return v
+	}
+	if s, ok := v.(fmt.Stringer); ok {
+		return s.String()
+	}
+	return v
+}
+
+// filterNumber converts normalized numbers to float64
+func filterNumber(v any) (float64, bool) {
+	switch n := v.(type) {
+	case int64:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
+
+// filterString returns the text of a normalized value
+func filterString(v any) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	return fmt.Sprint(v)
+}
+
+// matchWildcard matches the value against a argument with leading or trailing wildcards
+func matchWildcard(v string, arg ArgumentContext) bool {
+	s := arg.AsString()
+	switch {
+	case arg.StartsWithWildcard() && arg.EndsWithWildcard():
+		return strings.Contains(v, s)
+	case arg.StartsWithWildcard():
+		return strings.HasSuffix(v, s)
+	}
+	return strings.HasPrefix(v, s)
+}
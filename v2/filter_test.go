@@ -0,0 +1,172 @@
+package fiqlparser
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type filterTestItem struct {
+	Name     string
+	Age      int
+	Score    float64
+	Active   bool
+	Created  time.Time
+	Timeout  time.Duration
+	Deleted  *time.Time
+	Tags     []string
+	Nickname *string
+}
+
+func filterTestBinder(item filterTestItem, selector string) any {
+	switch selector {
+	case "name":
+		return item.Name
+	case "age":
+		return item.Age
+	case "score":
+		return item.Score
+	case "active":
+		return item.Active
+	case "created":
+		return item.Created
+	case "timeout":
+		return item.Timeout
+	case "deleted":
+		return item.Deleted
+	case "tags":
+		return item.Tags
+	case "nickname":
+		return item.Nickname
+	}
+	return nil
+}
+
+func TestFilter(t *testing.T) {
+	deleted := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	nick := "jo"
+	items := []filterTestItem{
+		{Name: "John", Age: 30, Score: 1.5, Active: true, Created: time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC), Timeout: time.Minute, Tags: []string{"admin", "dev"}, Nickname: &nick},
+		{Name: "Jane", Age: 25, Score: 2.5, Created: time.Now().Add(-time.Hour), Timeout: time.Hour, Deleted: &deleted, Tags: []string{"dev"}},
+		{Name: "Max Mustermann", Age: 40, Score: -1},
+	}
+	var values = []struct {
+		fiql     string
+		expected []string
+	}{
+		{fiql: "", expected: []string{"John", "Jane", "Max Mustermann"}},
+		{fiql: "name==John", expected: []string{"John"}},
+		{fiql: "name==J*", expected: []string{"John", "Jane"}},
+		{fiql: "name==*ann", expected: []string{"Max Mustermann"}},
+		{fiql: "name!=*o*", expected: []string{"Jane", "Max Mustermann"}},
+		{fiql: "name=q=MUSTER", expected: []string{"Max Mustermann"}},
+		{fiql: "age=gt=25", expected: []string{"John", "Max Mustermann"}},
+		{fiql: "age=le=30;score=ge=1.5", expected: []string{"John", "Jane"}},
+		{fiql: "age==25,age==40", expected: []string{"Jane", "Max Mustermann"}},
+		{fiql: "age=bt=26..40", expected: []string{"John", "Max Mustermann"}},
+		{fiql: "age!=26..40", expected: []string{"Jane"}},
+		{fiql: "age=in=[25+30]", expected: []string{"John", "Jane"}},
+		{fiql: "age==abc", expected: []string{}},
+		{fiql: "score=lt=0", expected: []string{"Max Mustermann"}},
+		{fiql: "active==true", expected: []string{"John"}},
+		{fiql: "created=lt=2024-01-01", expected: []string{"John", "Max Mustermann"}},
+		{fiql: "created=gt=-P1D", expected: []string{"Jane"}},
+		{fiql: "timeout=ge=PT1H", expected: []string{"Jane"}},
+		{fiql: "deleted==null", expected: []string{"John", "Max Mustermann"}},
+		{fiql: "deleted!=null", expected: []string{"Jane"}},
+		{fiql: "deleted", expected: []string{"Jane"}},
+		{fiql: "deleted=ge=2024-01-01", expected: []string{"Jane"}},
+		{fiql: "nickname==jo", expected: []string{"John"}},
+		{fiql: "tags==admin", expected: []string{"John"}},
+		{fiql: "tags!=admin", expected: []string{"Jane"}},
+		{fiql: "nickname!=jo", expected: []string{}},
+		{fiql: "tags=in=[dev+ops]", expected: []string{"John", "Jane"}},
+		{fiql: "tags==null", expected: []string{"Max Mustermann"}},
+		{fiql: "name==John,(age=lt=30;active==false)", expected: []string{"John", "Jane"}},
+		{fiql: "unknown==x", expected: []string{}},
+	}
+	for _, v := range values {
+		res, err := Parse(context.Background(), v.fiql)
+		if !assert.NoError(t, err, v.fiql) {
+			continue
+		}
+		matched, err := Filter(res, items, filterTestBinder)
+		if !assert.NoError(t, err, v.fiql) {
+			continue
+		}
+		names := make([]string, 0, len(matched))
+		for _, m := range matched {
+			names = append(names, m.Name)
+		}
+		assert.Equal(t, v.expected, names, v.fiql)
+	}
+}
+
+func TestFilterNulls(t *testing.T) {
+	nick := "jo"
+	items := []filterTestItem{{Name: "John", Nickname: &nick}, {Name: "Jane"}}
+	var values = []struct {
+		fiql     string
+		nulls    NullSemantics
+		expected []string
+	}{
+		{fiql: "nickname!=max", expected: []string{"John"}},
+		{fiql: "nickname!=max", nulls: NullSemanticsExclude, expected: []string{"John"}},
+		{fiql: "nickname!=max", nulls: NullSemanticsInclude, expected: []string{"John", "Jane"}},
+		{fiql: "nickname!=j*", nulls: NullSemanticsInclude, expected: []string{"Jane"}},
+		{fiql: "nickname!=null", nulls: NullSemanticsInclude, expected: []string{"John"}},
+		{fiql: "nickname=ge=1", nulls: NullSemanticsInclude, expected: []string{"John"}},
+		{fiql: "name!=@nickname", nulls: NullSemanticsInclude, expected: []string{"John"}},
+	}
+	for _, v := range values {
+		res, err := New(WithFieldReferences()).Parse(context.Background(), v.fiql)
+		if !assert.NoError(t, err, v.fiql) {
+			continue
+		}
+		matched, err := FilterWithOptions(res, items, filterTestBinder, FilterOptions{Nulls: v.nulls})
+		if !assert.NoError(t, err, v.fiql) {
+			continue
+		}
+		names := make([]string, 0, len(matched))
+		for _, m := range matched {
+			names = append(names, m.Name)
+		}
+		assert.Equal(t, v.expected, names, v.fiql)
+	}
+}
+
+func TestFilterNegationAndFieldReferences(t *testing.T) {
+	type span struct {
+		ID    int
+		Start int64
+		End   uint
+	}
+	items := []span{{ID: 1, Start: 1, End: 5}, {ID: 2, Start: 7, End: 3}}
+	binder := func(s span, selector string) any {
+		switch selector {
+		case "id":
+			return s.ID
+		case "start":
+			return s.Start
+		case "end":
+			return s.End
+		}
+		return nil
+	}
+	res, err := New(WithFieldReferences(), WithNegation()).Parse(context.Background(), "end=gt=@start;!(id==3)")
+	if assert.NoError(t, err) {
+		matched, err := Filter(res, items, binder)
+		assert.NoError(t, err)
+		assert.Equal(t, []span{{ID: 1, Start: 1, End: 5}}, matched)
+	}
+}
+
+func TestFilterErrors(t *testing.T) {
+	res, err := Parse(context.Background(), "name=gt=1*")
+	if assert.NoError(t, err) {
+		_, err = Filter(res, []filterTestItem{{}}, filterTestBinder)
+		assert.EqualError(t, err, "wildcards are not supported by `=gt=`")
+	}
+}
@@ -0,0 +1,139 @@
+package fiqlparser
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// ErrUndeclaredParameter is generated if a template uses or is bound to a parameter it does not declare
+var ErrUndeclaredParameter = errors.New("undeclared parameter")
+
+// TemplateParameter declares a named parameter of a Template
+type TemplateParameter struct {
+	// Name is the name of the parameter (without `:`)
+	Name string
+	// Type is the required value recommendation of the bound value (e.g. ValueRecommendationDateTime),
+	// empty or ValueRecommendationString accept every value, `null` is always accepted
+	Type ValueRecommendation
+	// Default is bound if no value is supplied, parameters without default are required
+	Default string
+}
+
+// Template is a saved filter containing named parameters (see WithParameters), e.g. for saved searches
+// or scheduled reports: `created=gt=:since;owner==:user`. The bindings are validated against the
+// declared parameters before the bound expression or FIQL is rendered.
+// It is safe for concurrent use.
+type Template struct {
+	expr   Expression
+	params []TemplateParameter
+	// layout accepts the datetime layouts of the parser, see WithDateTimeLayouts
+	layout DateTimeLayout
+}
+
+// NewTemplate parses the supplied fiql with named parameters enabled and checks that every used parameter
+// is declared and vice versa, and that the defaults match their type. opts configure the parser.
+func NewTemplate(input string, params []TemplateParameter, opts ...Option) (*Template, error) {
+	parser := New(append(opts, WithParameters())...)
+	e, err := parser.Parse(context.Background(), input)
+	if err != nil {
+		return nil, err
+	}
+	t := &Template{expr: e, params: append([]TemplateParameter{}, params...), layout: parser.anyDateTimeLayout}
+	declared := make(map[string]bool)
+	for _, p := range t.params {
+		if declared[p.Name] {
+			return nil, fmt.Errorf("parameter `:%s` declared twice", p.Name)
+		}
+		declared[p.Name] = true
+		if p.Default != "" {
+			if err := p.check(p.Default, t.layout); err != nil {
+				return nil, fmt.Errorf("invalid default: %w", err)
+			}
+		}
+	}
+	used := make(map[string]bool)
+	for _, name := range e.Parameters() {
+		if !declared[name] {
+			return nil, fmt.Errorf("%w `:%s`", ErrUndeclaredParameter, name)
+		}
+		used[name] = true
+	}
+	for _, p := range t.params {
+		if !used[p.Name] {
+			return nil, fmt.Errorf("parameter `:%s` is declared but not used", p.Name)
+		}
+	}
+	return t, nil
+}
+
+// Parameters returns the declared parameters
+func (t *Template) Parameters() []TemplateParameter {
+	return append([]TemplateParameter{}, t.params...)
+}
+
+// Expression returns the unbound expression
+func (t *Template) Expression() Expression {
+	return t.expr
+}
+
+// String returns the unbound template as FIQL
+func (t *Template) String() string {
+	return t.expr.ToFIQL()
+}
+
+// Bind validates the values against the declared parameters and returns the bound expression (see Expression.Bind),
+// missing values are replaced by their default. The error wraps ErrUndeclaredParameter for values of
+// undeclared parameters, ErrUnboundParameter for missing values without default and
+// ErrInvalidArgumentType for values not matching the type of their parameter or the comparison.
+func (t *Template) Bind(values map[string]string) (Expression, error) {
+	declared := make(map[string]bool, len(t.params))
+	for _, p := range t.params {
+		declared[p.Name] = true
+	}
+	undeclared := make([]string, 0)
+	for name := range values {
+		if !declared[name] {
+			undeclared = append(undeclared, name)
+		}
+	}
+	if len(undeclared) > 0 {
+		sort.Strings(undeclared)
+		return Expression{}, fmt.Errorf("%w `:%s`", ErrUndeclaredParameter, undeclared[0])
+	}
+	bindings := make(map[string]string, len(t.params))
+	for _, p := range t.params {
+		v, ok := values[p.Name]
+		if !ok {
+			if p.Default == "" {
+				return Expression{}, fmt.Errorf("%w `:%s`", ErrUnboundParameter, p.Name)
+			}
+			v = p.Default
+		}
+		if err := p.check(v, t.layout); err != nil {
+			return Expression{}, err
+		}
+		bindings[p.Name] = v
+	}
+	return t.expr.Bind(bindings)
+}
+
+// Render binds the values (see Bind) and returns the bound expression as FIQL
+func (t *Template) Render(values map[string]string) (string, error) {
+	e, err := t.Bind(values)
+	if err != nil {
+		return "", err
+	}
+	return e.ToFIQL(), nil
+}
+
+// check validates a value against the type of the parameter, values in layout are datetimes
+func (p TemplateParameter) check(value string, layout DateTimeLayout) error {
+	_, rec, _ := layoutValidator(defaultValidator, layout)(value)
+	arg := &constantExpression{value: value, recommended: rec}
+	if !(SelectorSchema{Type: p.Type}).accepts(arg.Argument()) {
+		return fmt.Errorf("%w: `:%s` expects %s", ErrInvalidArgumentType, p.Name, p.Type)
+	}
+	return nil
+}
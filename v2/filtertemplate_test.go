@@ -0,0 +1,109 @@
+package fiqlparser
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var reportParameters = []TemplateParameter{
+	{Name: "since", Type: ValueRecommendationDateTime},
+	{Name: "owner"},
+	{Name: "limit", Type: ValueRecommendationNumber, Default: "10"},
+}
+
+func TestTemplate(t *testing.T) {
+	tpl, err := NewTemplate("created=gt=:since;owner==:owner;size=lt=:limit", reportParameters)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, "created=gt=:since;owner==:owner;size=lt=:limit", tpl.String())
+	assert.Equal(t, reportParameters, tpl.Parameters())
+
+	var values = []struct {
+		values   map[string]string
+		expected string
+		err      error
+	}{
+		{values: map[string]string{"since": "2024-05-01", "owner": "John Doe", "limit": "5"}, expected: `created=gt=2024-05-01;owner=="John Doe";size=lt=5`},
+		{values: map[string]string{"since": "2024-05-01", "owner": "x"}, expected: "created=gt=2024-05-01;owner==x;size=lt=10"},
+		{values: map[string]string{"owner": "x"}, err: ErrUnboundParameter},
+		{values: map[string]string{"since": "yesterday", "owner": "x"}, err: ErrInvalidArgumentType},
+		{values: map[string]string{"since": "2024-05-01", "owner": "x", "limit": "many"}, err: ErrInvalidArgumentType},
+		{values: map[string]string{"since": "2024-05-01", "owner": "x", "other": "1"}, err: ErrUndeclaredParameter},
+	}
+	for _, v := range values {
+		res, err := tpl.Render(v.values)
+		if v.err != nil {
+			assert.ErrorIs(t, err, v.err, v.values)
+			continue
+		}
+		if assert.NoError(t, err, v.values) {
+			assert.Equal(t, v.expected, res, v.values)
+		}
+	}
+
+	e, err := tpl.Bind(map[string]string{"since": "2024-05-01", "owner": "x"})
+	if assert.NoError(t, err) {
+		assert.Empty(t, e.Parameters())
+	}
+	// the template is not modified by binding
+	unbound := tpl.Expression()
+	assert.Equal(t, []string{"since", "owner", "limit"}, unbound.Parameters())
+}
+
+func TestNewTemplateErrors(t *testing.T) {
+	var values = []struct {
+		input  string
+		params []TemplateParameter
+		err    error
+	}{
+		{input: "a==:x;b==:y", params: []TemplateParameter{{Name: "x"}}, err: ErrUndeclaredParameter},
+		{input: "a==:x", params: []TemplateParameter{{Name: "x"}, {Name: "y"}}},
+		{input: "a==:x", params: []TemplateParameter{{Name: "x"}, {Name: "x"}}},
+		{input: "a==:x", params: []TemplateParameter{{Name: "x", Type: ValueRecommendationNumber, Default: "abc"}}, err: ErrInvalidArgumentType},
+		{input: "a==:x;", params: []TemplateParameter{{Name: "x"}}},
+	}
+	for _, v := range values {
+		tpl, err := NewTemplate(v.input, v.params)
+		assert.Error(t, err, v.input)
+		assert.Nil(t, tpl, v.input)
+		if v.err != nil {
+			assert.ErrorIs(t, err, v.err, v.input)
+		}
+	}
+}
+
+func TestTemplateSchema(t *testing.T) {
+	tpl, err := NewTemplate("age=gt=:age", []TemplateParameter{{Name: "age", Type: ValueRecommendationNumber}})
+	if !assert.NoError(t, err) {
+		return
+	}
+	schema := Schema{Selectors: []SelectorSchema{{Name: "age", Type: ValueRecommendationNumber}}}
+	assert.NoError(t, schema.Validate(tpl.Expression()))
+	e, err := tpl.Bind(map[string]string{"age": "18"})
+	if assert.NoError(t, err) {
+		assert.NoError(t, schema.Validate(e))
+	}
+}
+
+func TestTemplateDateTimeLayouts(t *testing.T) {
+	tpl, err := NewTemplate("created=gt=:since", []TemplateParameter{{Name: "since", Type: ValueRecommendationDateTime, Default: "01.05.2024"}},
+		WithDateTimeLayouts(DateTimeLayoutOf("02.01.2006")))
+	if !assert.NoError(t, err) {
+		return
+	}
+	res, err := tpl.Render(map[string]string{"since": "15.01.2024"})
+	if assert.NoError(t, err) {
+		assert.Equal(t, "created=gt=15.01.2024", res)
+	}
+	e, err := tpl.Bind(nil)
+	if assert.NoError(t, err) {
+		tm, err := firstArgument(&e).AsTime()
+		assert.NoError(t, err)
+		assert.Equal(t, time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), tm)
+	}
+	_, err = tpl.Render(map[string]string{"since": "15/01/2024"})
+	assert.ErrorIs(t, err, ErrInvalidArgumentType)
+}
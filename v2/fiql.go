@@ -0,0 +1,147 @@
+package fiqlparser
+
+import (
+	"io"
+	"strings"
+	"unicode"
+)
+
+// fiqlOperators maps the AST operators back to their FIQL representation
+var fiqlOperators = map[string]string{
+	string(OperatorAND):       ";",
+	string(OperatorOR):        ",",
+	string(ComparisonEq):      "==",
+	string(ComparisonNeq):     "!=",
+	string(ComparisonGt):      "=gt=",
+	string(ComparisonLt):      "=lt=",
+	string(ComparisonGte):     "=ge=",
+	string(ComparisonLte):     "=le=",
+	string(ComparisonBetween): "=bt=",
+	string(ComparisonIn):      "=in=",
+	string(ComparisonQuery):   "=q=",
+}
+
+// ToFIQL returns the expression as FIQL which can be parsed again.
+// Reserved characters within values are escaped using a backslash,
+// values containing whitespace (or originally quoted values) are enclosed in quotes.
+func (e *Expression) ToFIQL() string {
+	var b strings.Builder
+	writeFIQL(&b, e)
+	return b.String()
+}
+
+// WriteFIQLTo writes the expression as FIQL (see ToFIQL) to w
+func (e *Expression) WriteFIQLTo(w io.Writer) (int64, error) {
+	return writeText(w, func(w textWriter) { writeFIQL(w, e) })
+}
+
+// AppendFIQL appends the expression as FIQL (see ToFIQL) to dst and returns the extended buffer
+func (e *Expression) AppendFIQL(dst []byte) []byte {
+	return appendText(dst, func(w textWriter) { writeFIQL(w, e) })
+}
+
+func writeFIQL(b textWriter, n Node) {
+	switch node := n.(type) {
+	case *Expression:
+		if !node.root {
+			if node.label != "" {
+				writeFIQLValue(b, node.label, 0)
+				b.WriteRune(':')
+			}
+			b.WriteRune('(')
+		}
+		if node.node != nil {
+			writeFIQL(b, node.node)
+		}
+		if !node.root {
+			b.WriteRune(')')
+		}
+	case *binaryExpression:
+		if node.nodes[0] != nil {
+			writeFIQLOperand(b, node.operator, node.nodes[0])
+		}
+		b.WriteString(fiqlOperators[node.operator])
+		if node.nodes[1] != nil {
+			writeFIQLOperand(b, node.operator, node.nodes[1])
+		}
+	case *logicalExpression:
+		for i, c := range node.nodes {
+			if i > 0 {
+				b.WriteString(fiqlOperators[node.operator])
+			}
+			writeFIQLOperand(b, node.operator, c)
+		}
+	case *notExpression:
+		b.WriteRune('!')
+		writeFIQL(b, node.node)
+	case *constantExpression:
+		if node.tuple != nil {
+			writeTuple(b, node.tuple)
+			return
+		}
+		if node.recommended == ValueRecommendationParameter {
+			b.WriteRune(':')
+			b.WriteString(node.value)
+			return
+		}
+		if node.recommended == ValueRecommendationField {
+			b.WriteRune('@')
+		}
+		if node.prefixWildcard {
+			b.WriteRune('*')
+		}
+		writeFIQLValue(b, node.value, node.quote)
+		if node.suffixWildcard {
+			b.WriteRune('*')
+		}
+	}
+}
+
+// writeFIQLOperand writes a operand of a operation, OR operations within AND operations
+// are enclosed in braces as AND binds tighter
+func writeFIQLOperand(b textWriter, operator string, n Node) {
+	if op, ok := logicalOperator(n); ok && op == string(OperatorOR) && operator == string(OperatorAND) {
+		b.WriteRune('(')
+		writeFIQL(b, n)
+		b.WriteRune(')')
+		return
+	}
+	writeFIQL(b, n)
+}
+
+func isReservedFIQLRune(r rune) bool {
+	switch r {
+	case ';', ',', '!', '=', '(', ')', '*', '\\', '"', '\'':
+		return true
+	}
+	return false
+}
+
+// writeFIQLValue writes a escaped value, quote is the preferred quote character (0 for unquoted)
+func writeFIQLValue(b textWriter, value string, quote rune) {
+	if quote == 0 && (value == "" || value[0] == '@' || value[0] == ':' || strings.IndexFunc(value, unicode.IsSpace) >= 0) {
+		quote = '"'
+	}
+	if quote != 0 {
+		writeQuotedValue(b, value, quote)
+		return
+	}
+	for _, r := range value {
+		if isReservedFIQLRune(r) {
+			b.WriteRune('\\')
+		}
+		b.WriteRune(r)
+	}
+}
+
+// writeQuotedValue writes the value enclosed in quote, escaping the quote itself and backslashes
+func writeQuotedValue(b textWriter, value string, quote rune) {
+	b.WriteRune(quote)
+	for _, r := range value {
+		if r == quote || r == '\\' {
+			b.WriteRune('\\')
+		}
+		b.WriteRune(r)
+	}
+	b.WriteRune(quote)
+}
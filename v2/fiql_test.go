@@ -0,0 +1,48 @@
+package fiqlparser
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToFIQL(t *testing.T) {
+	var values = []struct {
+		fiql   string
+		output string
+	}{
+		{fiql: "column==value", output: "column==value"},
+		{fiql: "(column==value)", output: "(column==value)"},
+		{fiql: "column!=value", output: "column!=value"},
+		{fiql: "column=ge=1", output: "column=ge=1"},
+		{fiql: "column=le=1;column=lt=2,column=gt=-1.5", output: "column=le=1;column=lt=2,column=gt=-1.5"},
+		{fiql: "  column   ==  value ,   b==a  ", output: "column==value,b==a"},
+		{fiql: "updated=gt=2003-12-13T00:00:00Z", output: "updated=gt=2003-12-13T00:00:00Z"},
+		{fiql: "column==va\\,lue,b==a", output: "column==va\\,lue,b==a"},
+		{fiql: `column==a\;b\=c\!d\)e\*f\\g\(`, output: `column==a\;b\=c\!d\)e\*f\\g\(`},
+		{fiql: "title==foo*;(updated=lt=-P1D,title==*bar)", output: "title==foo*;(updated=lt=-P1D,title==*bar)"},
+		{fiql: "(title==foo*);(fml==x,(xfs==a;f==fx))", output: "(title==foo*);(fml==x,(xfs==a;f==fx))"},
+		{fiql: "columnA,(columnB==c;columnC)", output: "columnA,(columnB==c;columnC)"},
+		{fiql: `name=="John Doe"`, output: `name=="John Doe"`},
+		{fiql: `name=='it\'s'*`, output: `name=='it\'s'*`},
+		{fiql: `name==""`, output: `name==""`},
+		{fiql: "urgent:(status==open;priority==high)", output: "urgent:(status==open;priority==high)"},
+		{fiql: `title=q="quick fox"`, output: `title=q="quick fox"`},
+	}
+	for _, v := range values {
+		res, err := Parse(context.Background(), v.fiql)
+		if !assert.NoError(t, err, v.fiql) {
+			continue
+		}
+		out := res.ToFIQL()
+		assert.Equal(t, v.output, out)
+
+		// round trip
+		again, err := Parse(context.Background(), out)
+		if assert.NoError(t, err, out) {
+			assert.Equal(t, res.String(), again.String())
+			assert.Equal(t, out, again.ToFIQL())
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package fiqlparser
+
+import (
+	"context"
+	"strings"
+)
+
+// FormatOptions configures Format
+type FormatOptions struct {
+	// Parser parses the input, a parser with the default configuration is used if nil
+	Parser *Parser
+	// Spaced surrounds the logical operators with a space in single line output, e.g. `a==1 ; b==2`
+	Spaced bool
+	// Indent prints every operand of a logical operation on its own line, the operands within braces
+	// are indented by Indent per level (e.g. two spaces or a tab), single line output if empty
+	Indent string
+}
+
+// Format reprints the supplied fiql in a canonical form, e.g. for linters or before storing saved filters:
+// whitespace between tokens is removed, comparisons are written in their short form (`=between=` becomes `=bt=`),
+// values are escaped consistently (see ToFIQL) and braces without effect around operands of the same logical operation are removed.
+// The order of the operands is kept, see Fingerprint to compare expressions regardless of it.
+func Format(input string, opts FormatOptions) (string, error) {
+	p := opts.Parser
+	if p == nil {
+		p = &Parser{}
+	}
+	e, err := p.Parse(context.Background(), input)
+	if err != nil {
+		return "", err
+	}
+	f := formatter{opts: opts}
+	f.node(&e, 0)
+	return f.b.String(), nil
+}
+
+// formatter writes the output of Format
+type formatter struct {
+	b    strings.Builder
+	opts FormatOptions
+}
+
+func (f *formatter) node(n Node, depth int) {
+	switch node := n.(type) {
+	case *Expression:
+		if node.root {
+			if node.node != nil {
+				f.node(node.node, depth)
+			}
+			return
+		}
+		if node.label != "" {
+			writeFIQLValue(&f.b, node.label, 0)
+			f.b.WriteRune(':')
+		}
+		f.b.WriteRune('(')
+		if _, ok := logicalOperator(unwrapExpression(node.node)); ok && f.opts.Indent != "" {
+			f.newline(depth + 1)
+			f.node(node.node, depth+1)
+			f.newline(depth)
+		} else if node.node != nil {
+			f.node(node.node, depth)
+		}
+		f.b.WriteRune(')')
+	case *binaryExpression, *logicalExpression:
+		op, ok := logicalOperator(n)
+		if !ok {
+			writeFIQL(&f.b, n)
+			return
+		}
+		for i, c := range flattenLogical(n, op, nil) {
+			if i > 0 {
+				f.operator(op, depth)
+			}
+			f.operand(op, c, depth)
+		}
+	case *notExpression:
+		f.b.WriteRune('!')
+		f.node(node.node, depth)
+	default:
+		writeFIQL(&f.b, n)
+	}
+}
+
+// operand writes a operand of a logical operation, OR operations within AND operations
+// are enclosed in braces as AND binds tighter (see writeFIQLOperand)
+func (f *formatter) operand(operator string, n Node, depth int) {
+	if op, ok := logicalOperator(n); ok && op == string(OperatorOR) && operator == string(OperatorAND) {
+		f.node(&Expression{node: n}, depth)
+		return
+	}
+	f.node(n, depth)
+}
+
+// operator writes the logical operator between two operands
+func (f *formatter) operator(op string, depth int) {
+	switch {
+	case f.opts.Indent != "":
+		f.b.WriteString(fiqlOperators[op])
+		f.newline(depth)
+	case f.opts.Spaced:
+		f.b.WriteString(" " + fiqlOperators[op] + " ")
+	default:
+		f.b.WriteString(fiqlOperators[op])
+	}
+}
+
+// newline starts a new line indented to depth
+func (f *formatter) newline(depth int) {
+	f.b.WriteRune('\n')
+	for i := 0; i < depth; i++ {
+		f.b.WriteString(f.opts.Indent)
+	}
+}
@@ -0,0 +1,47 @@
+package fiqlparser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormat(t *testing.T) {
+	var values = []struct {
+		input    string
+		opts     FormatOptions
+		expected string
+	}{
+		{input: " a==1 ; b=GT=2 ", expected: "a==1;b=gt=2"},
+		{input: "a=between=1..2", expected: "a=bt=1..2"},
+		{input: "(a==1;b==2);c==3", expected: "a==1;b==2;c==3"},
+		{input: "a==1;(b==2,c==3)", expected: "a==1;(b==2,c==3)"},
+		{input: "a==1,b==2;c==3", expected: "a==1,b==2;c==3"},
+		{input: `name=="John Doe";x==a\,b`, expected: `name=="John Doe";x==a\,b`},
+		{input: "f:(a==1;b==2)", expected: "f:(a==1;b==2)"},
+		{input: "a==1;(b==2,c==3)", opts: FormatOptions{Spaced: true}, expected: "a==1 ; (b==2 , c==3)"},
+		{input: "a==1;(b==2,(c==3;d==4));(e==5)", opts: FormatOptions{Indent: "  "},
+			expected: "a==1;\n(\n  b==2,\n  (\n    c==3;\n    d==4\n  )\n);\n(e==5)"},
+		{input: "a==1", opts: FormatOptions{Indent: "\t"}, expected: "a==1"},
+		{input: "!(a==1;b==2)", opts: FormatOptions{Parser: New(WithNegation()), Indent: "\t"}, expected: "!(\n\ta==1;\n\tb==2\n)"},
+	}
+	for _, v := range values {
+		res, err := Format(v.input, v.opts)
+		if assert.NoError(t, err, v.input) {
+			assert.Equal(t, v.expected, res, v.input)
+			// the output is stable
+			again, err := Format(res, v.opts)
+			assert.NoError(t, err, res)
+			assert.Equal(t, res, again, res)
+		}
+	}
+}
+
+func TestFormatInvalid(t *testing.T) {
+	res, err := Format("a==1;", FormatOptions{})
+	assert.Equal(t, "", res)
+	var perr *ParseError
+	if assert.ErrorAs(t, err, &perr) {
+		assert.Equal(t, ErrorCodeDanglingOperator, perr.Code)
+	}
+}
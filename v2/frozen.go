@@ -0,0 +1,309 @@
+package fiqlparser
+
+import "time"
+
+// frozenKind is the kind of a frozen node
+type frozenKind uint8
+
+const (
+	frozenExpression frozenKind = iota
+	frozenBinary
+	frozenLogical
+	frozenConstant
+	frozenNot
+)
+
+// frozen node flags
+const (
+	frozenRoot uint8 = 1 << iota
+	frozenSelector
+	frozenUnary
+	frozenPrefixWildcard
+	frozenSuffixWildcard
+	frozenTuple
+)
+
+// frozenOperators are the operators which can be stored in a frozen node
+var frozenOperators = []string{
+	string(OperatorAND), string(OperatorOR),
+	string(ComparisonEq), string(ComparisonNeq), string(ComparisonGt), string(ComparisonLt),
+	string(ComparisonGte), string(ComparisonLte), string(ComparisonBetween), string(ComparisonIn), string(ComparisonQuery),
+}
+
+// frozenRecommendations are the recommendations which can be stored in a frozen node
+var frozenRecommendations = []ValueRecommendation{
+	ValueRecommendationString, ValueRecommendationDateTime, ValueRecommendationDuration, ValueRecommendationNumber,
+	ValueRecommendationBoolean, ValueRecommendationNull, ValueRecommendationRange, ValueRecommendationTuple, ValueRecommendationField,
+	ValueRecommendationParameter, ValueRecommendationUUID, ValueRecommendationEmail, ValueRecommendationIP,
+}
+
+// frozenNode is a node record without pointers, strings are ranges of FrozenExpression.data
+// and children are stored contiguously starting at first
+type frozenNode struct {
+	kind        frozenKind
+	flags       uint8
+	operator    uint8
+	recommended uint8
+	quote       rune
+	start, end  uint32
+	first       uint32
+	count       uint32
+	line        int32
+	column      int32
+	offset      int32
+	byteOffset  int32
+	// layout is the index of the datetime layout in FrozenExpression.layouts plus one, 0 if there is none
+	layout uint32
+	// loc is the index of the default location in FrozenExpression.locations plus one, 0 if there is none
+	loc uint32
+}
+
+// FrozenExpression is a compact, immutable representation of a expression intended for long lived
+// (e.g. cached) filters. The nodes are stored as records in a single slice and all strings in a single string,
+// so the garbage collector does not need to scan a tree of pointers.
+// Use Accept to traverse it or Thaw to translate it.
+type FrozenExpression struct {
+	nodes []frozenNode
+	data  string
+	tuple TupleDelimiters
+	// separator is the path separator of the selectors, see WithSelectorPathSeparator
+	separator rune
+	// layouts are the datetime layouts of the constants, see WithDateTimeLayouts
+	layouts []DateTimeLayout
+	// locations are the default locations of the constants, see WithDefaultLocation
+	locations []*time.Location
+}
+
+// Freeze returns the compact representation of the expression
+func (e *Expression) Freeze() FrozenExpression {
+	f := &freezer{}
+	f.nodes = append(f.nodes, frozenNode{})
+	queue := []Node{e}
+	for i := 0; i < len(queue); i++ {
+		children := f.record(i, queue[i])
+		f.nodes[i].first = uint32(len(f.nodes))
+		f.nodes[i].count = uint32(len(children))
+		for range children {
+			f.nodes = append(f.nodes, frozenNode{})
+		}
+		queue = append(queue, children...)
+	}
+	return FrozenExpression{nodes: f.nodes, data: string(f.data), tuple: f.tuple, separator: f.separator, layouts: f.layouts, locations: f.locations}
+}
+
+type freezer struct {
+	nodes     []frozenNode
+	data      []byte
+	tuple     TupleDelimiters
+	separator rune
+	layouts   []DateTimeLayout
+	locations []*time.Location
+}
+
+func (f *freezer) str(s string) (uint32, uint32) {
+	start := uint32(len(f.data))
+	f.data = append(f.data, s...)
+	return start, uint32(len(f.data))
+}
+
+// location returns the index of loc in the locations plus one
+func (f *freezer) location(loc *time.Location) uint32 {
+	for i, l := range f.locations {
+		if l == loc {
+			return uint32(i + 1)
+		}
+	}
+	f.locations = append(f.locations, loc)
+	return uint32(len(f.locations))
+}
+
+// record fills the record of node i and returns the children to store
+func (f *freezer) record(i int, n Node) []Node {
+	r := &f.nodes[i]
+	switch node := n.(type) {
+	case *Expression:
+		r.kind = frozenExpression
+		if node.root {
+			r.flags |= frozenRoot
+		}
+		r.start, r.end = f.str(node.label)
+		if node.node != nil {
+			return []Node{node.node}
+		}
+	case *binaryExpression:
+		r.kind = frozenBinary
+		r.operator = frozenOperator(node.operator)
+		return node.Children()
+	case *logicalExpression:
+		r.kind = frozenLogical
+		r.operator = frozenOperator(node.operator)
+		return node.nodes
+	case *notExpression:
+		r.kind = frozenNot
+		if node.node != nil {
+			return []Node{node.node}
+		}
+	case *constantExpression:
+		r.kind = frozenConstant
+		r.start, r.end = f.str(node.value)
+		r.quote = node.quote
+		r.line, r.column, r.offset, r.byteOffset = int32(node.pos.Line), int32(node.pos.Column), int32(node.pos.Offset), int32(node.pos.ByteOffset)
+		for i, v := range frozenRecommendations {
+			if v == node.recommended {
+				r.recommended = uint8(i)
+			}
+		}
+		if node.layout != nil {
+			// layouts are functions which can not be compared, so every constant gets its own entry
+			f.layouts = append(f.layouts, node.layout)
+			r.layout = uint32(len(f.layouts))
+		}
+		if node.loc != nil {
+			r.loc = f.location(node.loc)
+		}
+		if node.selector {
+			f.separator = node.pathSeparator
+		}
+		r.flags |= frozenFlag(node.selector, frozenSelector) | frozenFlag(node.unary, frozenUnary) |
+			frozenFlag(node.prefixWildcard, frozenPrefixWildcard) | frozenFlag(node.suffixWildcard, frozenSuffixWildcard)
+		if node.tuple != nil {
+			r.flags |= frozenTuple
+			f.tuple = node.tuple.delimiters
+			children := make([]Node, 0, len(node.tuple.elements))
+			for _, el := range node.tuple.elements {
+				children = append(children, el)
+			}
+			return children
+		}
+	}
+	return nil
+}
+
+func frozenOperator(operator string) uint8 {
+	for i, v := range frozenOperators {
+		if v == operator {
+			return uint8(i)
+		}
+	}
+	return 0
+}
+
+func frozenFlag(set bool, flag uint8) uint8 {
+	if set {
+		return flag
+	}
+	return 0
+}
+
+// Len returns the number of nodes
+func (f FrozenExpression) Len() int {
+	return len(f.nodes)
+}
+
+// Thaw returns the expression as a regular tree, e.g. to translate it
+func (f FrozenExpression) Thaw() Expression {
+	if len(f.nodes) == 0 {
+		return Expression{root: true}
+	}
+	e, _ := f.thaw(0).(*Expression)
+	return *e
+}
+
+func (f FrozenExpression) thaw(i uint32) Node {
+	r := f.nodes[i]
+	children := make([]Node, 0, r.count)
+	for c := r.first; c < r.first+r.count; c++ {
+		children = append(children, f.thaw(c))
+	}
+	switch r.kind {
+	case frozenExpression:
+		e := &Expression{root: r.flags&frozenRoot != 0, label: f.data[r.start:r.end]}
+		if len(children) > 0 {
+			e.node = children[0]
+		}
+		return e
+	case frozenBinary:
+		bin := &binaryExpression{operator: frozenOperators[r.operator]}
+		copy(bin.nodes[:], children)
+		return bin
+	case frozenLogical:
+		return &logicalExpression{operator: frozenOperators[r.operator], nodes: children}
+	case frozenNot:
+		n := &notExpression{}
+		if len(children) > 0 {
+			n.node = children[0]
+		}
+		return n
+	}
+	c := &constantExpression{
+		value:          f.data[r.start:r.end],
+		quote:          r.quote,
+		recommended:    frozenRecommendations[r.recommended],
+		selector:       r.flags&frozenSelector != 0,
+		unary:          r.flags&frozenUnary != 0,
+		prefixWildcard: r.flags&frozenPrefixWildcard != 0,
+		suffixWildcard: r.flags&frozenSuffixWildcard != 0,
+		pos:            Position{Line: int(r.line), Column: int(r.column), Offset: int(r.offset), ByteOffset: int(r.byteOffset)},
+	}
+	if c.selector {
+		c.pathSeparator = f.separator
+	}
+	if r.layout > 0 {
+		c.layout = f.layouts[r.layout-1]
+	}
+	if r.loc > 0 {
+		c.loc = f.locations[r.loc-1]
+	}
+	if r.flags&frozenTuple != 0 {
+		c.tuple = &tupleArgument{delimiters: f.tuple, elements: make([]*constantExpression, 0, len(children))}
+		for _, el := range children {
+			c.tuple.elements = append(c.tuple.elements, el.(*constantExpression))
+		}
+	}
+	return c
+}
+
+// Accept traverses the expression like Expression.Accept without thawing it
+func (f FrozenExpression) Accept(visitor NodeVisitor) {
+	if len(f.nodes) > 0 {
+		f.accept(0, visitor)
+	}
+}
+
+func (f FrozenExpression) accept(i uint32, visitor NodeVisitor) {
+	r := f.nodes[i]
+	switch r.kind {
+	case frozenExpression:
+		visitor.VisitExpressionEntered()
+		if lv, ok := visitor.(LabelVisitor); ok && r.end > r.start {
+			lv.VisitLabel(f.data[r.start:r.end])
+		}
+		if r.count > 0 {
+			f.accept(r.first, visitor)
+		}
+		visitor.VisitExpressionLeft()
+	case frozenBinary, frozenLogical:
+		op := frozenOperators[r.operator]
+		for c := r.first; c < r.first+r.count; c++ {
+			if c > r.first {
+				if isLogicalOperator(op) {
+					visitor.VisitOperator(OperatorContext{op: Operator(op)})
+				} else {
+					visitor.VisitComparison(ComparisonContext{comparison: Comparison(op)})
+				}
+			}
+			f.accept(c, visitor)
+		}
+	case frozenNot:
+		visitor.VisitOperator(OperatorContext{op: OperatorNOT})
+		if r.count > 0 {
+			f.accept(r.first, visitor)
+		}
+	case frozenConstant:
+		if r.flags&frozenSelector != 0 {
+			visitor.VisitSelector(SelectorContext{unary: r.flags&frozenUnary != 0, selector: f.data[r.start:r.end], separator: f.separator})
+			return
+		}
+		visitor.VisitArgument(f.thaw(i).(*constantExpression).Argument())
+	}
+}
@@ -0,0 +1,136 @@
+package fiqlparser
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFreeze(t *testing.T) {
+	var values = []string{
+		"a==1",
+		"title==foo*;(updated=lt=-P1D,title==*bar)",
+		`name=="John Doe",x:(age=gt=18;deleted==null;active)`,
+		"status=in=[open+*closed+'a b'];age==18..65",
+	}
+	for _, fiql := range values {
+		res, err := Parse(context.Background(), fiql)
+		if !assert.NoError(t, err, fiql) {
+			continue
+		}
+		frozen := res.Freeze()
+		thawed := frozen.Thaw()
+		assert.Equal(t, res.String(), thawed.String(), fiql)
+		assert.Equal(t, res.ToFIQL(), thawed.ToFIQL(), fiql)
+
+		expected := &labelVisitor{}
+		res.Accept(expected)
+		visited := &labelVisitor{}
+		frozen.Accept(visited)
+		assert.Equal(t, expected.String(), visited.String(), fiql)
+	}
+}
+
+func TestFreezeEmpty(t *testing.T) {
+	res, err := Parse(context.Background(), "")
+	if !assert.NoError(t, err) {
+		return
+	}
+	frozen := res.Freeze()
+	assert.Equal(t, 1, frozen.Len())
+	thawed := frozen.Thaw()
+	assert.Equal(t, "", thawed.ToFIQL())
+	zero := FrozenExpression{}.Thaw()
+	assert.Equal(t, "", zero.ToFIQL())
+}
+
+func TestFreezeLogicalNodes(t *testing.T) {
+	res, err := New(WithLogicalNodes()).Parse(context.Background(), "a==1;b==2;c==3,d=in=[1+2]")
+	if !assert.NoError(t, err) {
+		return
+	}
+	frozen := res.Freeze()
+	// root, OR, AND, 4 comparisons with 8 constants and 2 tuple elements
+	assert.Equal(t, 17, frozen.Len())
+	thawed := frozen.Thaw()
+	assert.Equal(t, res.String(), thawed.String())
+
+	sql, args, err := (&SQLTranslator{Columns: map[string]string{"a": "a", "b": "b", "c": "c", "d": "d"}}).Translate(thawed)
+	assert.NoError(t, err)
+	assert.Equal(t, "(a = ? AND b = ? AND c = ?) OR d IN (?, ?)", sql)
+	assert.Len(t, args, 5)
+}
+
+func TestFreezeDateTimeLayout(t *testing.T) {
+	res, err := New(WithDateTimeLayouts(DateTimeLayoutOf("02.01.2006"))).Parse(context.Background(), "created==15.01.2024")
+	if !assert.NoError(t, err) {
+		return
+	}
+	frozen := res.Freeze()
+	thawed := frozen.Thaw()
+	fromThawed := &quotedVisitor{}
+	thawed.Accept(fromThawed)
+	fromFrozen := &quotedVisitor{}
+	frozen.Accept(fromFrozen)
+	for _, args := range [][]ArgumentContext{fromThawed.args, fromFrozen.args} {
+		if !assert.Len(t, args, 1) {
+			continue
+		}
+		created, err := args[0].AsTime()
+		assert.NoError(t, err)
+		assert.True(t, time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC).Equal(created))
+	}
+}
+
+func TestFreezeDefaultLocation(t *testing.T) {
+	vienna, err := time.LoadLocation("Europe/Vienna")
+	if err != nil {
+		t.Skip("time zone data not available")
+	}
+	res, err := New(WithDefaultLocation(vienna)).Parse(context.Background(), "d==2024-01-15")
+	if !assert.NoError(t, err) {
+		return
+	}
+	frozen := res.Freeze()
+	thawed := frozen.Thaw()
+	fromThawed := &quotedVisitor{}
+	thawed.Accept(fromThawed)
+	fromFrozen := &quotedVisitor{}
+	frozen.Accept(fromFrozen)
+	for _, args := range [][]ArgumentContext{fromThawed.args, fromFrozen.args} {
+		if !assert.Len(t, args, 1) {
+			continue
+		}
+		d, err := args[0].AsTime()
+		assert.NoError(t, err)
+		assert.True(t, time.Date(2024, 1, 15, 0, 0, 0, 0, vienna).Equal(d))
+	}
+}
+
+type frozenPathVisitor struct {
+	testTypeVisitor
+	paths [][]string
+}
+
+func (v *frozenPathVisitor) VisitSelector(ctx SelectorContext) {
+	v.paths = append(v.paths, ctx.Path())
+}
+
+func TestFreezeSelectorPathSeparator(t *testing.T) {
+	res, err := New(WithSelectorPathSeparator('/')).Parse(context.Background(), "author/name==b;a.b==c")
+	if !assert.NoError(t, err) {
+		return
+	}
+	expected := [][]string{{"author", "name"}, {"a.b"}}
+	frozen := res.Freeze()
+	visited := &frozenPathVisitor{}
+	frozen.Accept(visited)
+	assert.Equal(t, expected, visited.paths)
+
+	thawed := frozen.Thaw()
+	v := &pathVisitor{}
+	assert.NoError(t, thawed.Visit(v))
+	assert.Equal(t, expected, v.paths)
+}
@@ -0,0 +1,72 @@
+package fiqlparser
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func FuzzParse(f *testing.F) {
+	for _, seed := range []string{
+		"name==John;age=gt=18",
+		"(title==foo*);(fml==x,(xfs==a;f==fx))",
+		`a=="b \"c\"";d=='e'`,
+		"created=lt=-P1DT2H,created=gt=2022-01-01T10:00:00Z",
+		"a=in=[1,2,3];b=bt=1..5;c=q=text",
+		"f:(a==1)",
+		"a==@b",
+		"P",
+		"",
+	} {
+		f.Add(seed)
+	}
+	parsers := []*Parser{
+		New(),
+		New(WithNegation(), WithFieldReferences(), WithSpacesInValues(), WithTupleDelimiters('(', ',', ')')),
+		// escapes written by ToFIQL are violations of the specification, the output is not parsed again
+		New(WithStrictSpec()),
+	}
+	f.Fuzz(func(t *testing.T, input string) {
+		for i, p := range parsers {
+			e, err := p.Parse(context.Background(), input)
+			if err != nil {
+				continue
+			}
+			// a parsed expression can be printed, parsed again and evaluated
+			if fiql := e.ToFIQL(); i < 2 {
+				if _, err := p.Parse(context.Background(), fiql); err != nil {
+					t.Fatalf("unable to parse the FIQL `%s` of `%s`: %v", fiql, input, err)
+				}
+			}
+			_ = e.String()
+			_, _ = Filter(e, []int{0}, func(int, string) any { return time.Time{} })
+		}
+		// the repairs of ParseLenient are quadratic in the length of the input
+		if len(input) <= 256 {
+			_, _ = ParseLenient(context.Background(), input)
+		}
+		_ = Highlight(input)
+		_ = Complete(input, len(input)/2, Schema{})
+	})
+}
+
+func FuzzDuration(f *testing.F) {
+	for _, seed := range []string{"P1Y2M3W4DT5H6M7.5S", "-P1D", "+PT1H", "P", "PT", "P1", "P1.5.5D"} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, input string) {
+		d, err := durationConverter.tryParseISO8601Duration(input)
+		if err != nil || input == "" {
+			return
+		}
+		// a valid duration is recognized by the parser and formats canonically as valid duration
+		if !isDurationValue(input) {
+			t.Fatalf("`%s` parsed as duration but is not recognized by the parser", input)
+		}
+		if _, err := durationConverter.tryParseISO8601Duration(d.Canonical()); err != nil {
+			t.Fatalf("canonical form `%s` of `%s` is invalid: %v", d.Canonical(), input, err)
+		}
+		_, _ = d.ToTimeDuration()
+		_ = d.AddTo(time.Time{})
+	})
+}
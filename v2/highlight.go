@@ -0,0 +1,88 @@
+package fiqlparser
+
+// SpanClass classifies a span of a input, see Highlight
+type SpanClass string
+
+// SpanSelector is a selector, also of a unary comparison
+const SpanSelector SpanClass = "selector"
+
+// SpanComparator is a comparison, e.g. `==` or `=gt=`
+const SpanComparator SpanClass = "comparator"
+
+// SpanLogical is a logical operator (`;` or `,`) or a negation (`!`)
+const SpanLogical SpanClass = "logical"
+
+// SpanValue is a argument including its quotes, tuples are a single span
+const SpanValue SpanClass = "value"
+
+// SpanBrace is a opening or closing brace
+const SpanBrace SpanClass = "brace"
+
+// SpanWildcard is a wildcard of a argument
+const SpanWildcard SpanClass = "wildcard"
+
+// SpanLabel is the label of a sub expression including the colon
+const SpanLabel SpanClass = "label"
+
+// SpanInvalid is input which can not be tokenized, e.g. a unterminated quote, it extends to the end of the input
+const SpanInvalid SpanClass = "invalid"
+
+// Span is a classified part of a input
+type Span struct {
+	Class SpanClass
+	// Start and End are the byte offsets of the span within the input, End is exclusive
+	Start int
+	End   int
+}
+
+// Highlight classifies the tokens of the input for syntax highlighting, e.g. in web UIs or terminals.
+// Whitespace between tokens is not covered by any span. Spans are ordered by position and do not overlap.
+// The input is tokenized only, so invalid expressions are highlighted as far as possible;
+// the options are applied like by NewTokenizer.
+func Highlight(input string, opts ...Option) []Span {
+	t := NewTokenizer(input, opts...)
+	spans := make([]Span, 0)
+	argument := false
+	for {
+		tok, err := t.Next()
+		if err != nil {
+			if start := t.lex.start.ByteOffset; start < len(input) {
+				spans = append(spans, Span{Class: SpanInvalid, Start: start, End: len(input)})
+			}
+			return spans
+		}
+		if tok.Type == TokenTypeEOF {
+			return spans
+		}
+		class := tokenClass(tok, &argument)
+		spans = append(spans, Span{Class: class, Start: tok.Position.ByteOffset, End: tok.End.ByteOffset})
+	}
+}
+
+// tokenClass classifies a token, argument tracks whether the following values are arguments
+func tokenClass(tok Token, argument *bool) SpanClass {
+	var class SpanClass
+	switch tok.Type {
+	case TokenTypeValue:
+		class = SpanSelector
+		if *argument {
+			class = SpanValue
+		}
+	case TokenTypeWildcard:
+		class = SpanWildcard
+	case TokenTypeLabel:
+		class = SpanLabel
+	case TokenTypeOpen, TokenTypeClose:
+		class = SpanBrace
+	case TokenTypeAND, TokenTypeOR, TokenTypeNot:
+		class = SpanLogical
+	default:
+		class = SpanComparator
+	}
+	if class == SpanComparator {
+		*argument = true
+	} else if class != SpanValue && class != SpanWildcard {
+		*argument = false
+	}
+	return class
+}
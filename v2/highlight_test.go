@@ -0,0 +1,31 @@
+package fiqlparser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHighlight(t *testing.T) {
+	var values = []struct {
+		input    string
+		opts     []Option
+		expected []string
+	}{
+		{input: "", expected: []string{}},
+		{input: `name=="John Doe";age=gt=18`, expected: []string{"selector:name", "comparator:==", `value:"John Doe"`, "logical:;", "selector:age", "comparator:=gt=", "value:18"}},
+		{input: "l:(deleted,tags=in=[a+b])", expected: []string{"label:l:", "brace:(", "selector:deleted", "logical:,", "selector:tags", "comparator:=in=", "value:[a+b]", "brace:)"}},
+		{input: "name==*oh*", expected: []string{"selector:name", "comparator:==", "wildcard:*", "value:oh", "wildcard:*"}},
+		{input: "!(a==1)", opts: []Option{WithNegation()}, expected: []string{"logical:!", "brace:(", "selector:a", "comparator:==", "value:1", "brace:)"}},
+		{input: `a==1;b=="open`, expected: []string{"selector:a", "comparator:==", "value:1", "logical:;", "selector:b", "comparator:==", `invalid:"open`}},
+		{input: "a=x=1", expected: []string{"selector:a", "invalid:=x=1"}},
+		{input: "ä== ö", expected: []string{"selector:ä", "comparator:==", "value:ö"}},
+	}
+	for _, v := range values {
+		spans := make([]string, 0)
+		for _, s := range Highlight(v.input, v.opts...) {
+			spans = append(spans, string(s.Class)+":"+v.input[s.Start:s.End])
+		}
+		assert.Equal(t, v.expected, spans, v.input)
+	}
+}
@@ -0,0 +1,164 @@
+package fiqlparser
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// DefaultMaxFilterLength is the maximum length (in bytes) of a filter parsed by ParseRequest,
+// use WithMaxLength to change it
+const DefaultMaxFilterLength = 2048
+
+// WithMaxLength rejects expressions longer than max bytes with ErrorCodeInputTooLong,
+// a value <= 0 disables the check
+func WithMaxLength(max int) Option {
+	return func(p *Parser) {
+		p.maxLength = max
+	}
+}
+
+// queryParam returns the percent-decoded value of the first query parameter named param, if any.
+// The raw query is used on purpose: url.ParseQuery rejects `;` (the FIQL AND operator)
+// and decodes `+` to a space, which breaks tuples like `[a+b]`.
+func queryParam(rawQuery string, param string) (string, error) {
+	values, err := queryParams(rawQuery, []string{param}, 1)
+	if err != nil || len(values) == 0 {
+		return "", err
+	}
+	return values[0].Filter, nil
+}
+
+// queryParams returns up to max (all if max <= 0) percent-decoded values of the query parameters
+// named params in order of appearance, see queryParam
+func queryParams(rawQuery string, params []string, max int) ([]FilterBranch, error) {
+	res := make([]FilterBranch, 0)
+	indices := make(map[string]int)
+	for _, pair := range strings.Split(rawQuery, "&") {
+		key, value, _ := strings.Cut(pair, "=")
+		k, err := url.QueryUnescape(key)
+		if err != nil || !containsString(params, k) {
+			continue
+		}
+		v, err := url.PathUnescape(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid filter parameter `%s`: %w", k, err)
+		}
+		res = append(res, FilterBranch{Param: k, Index: indices[k], Filter: v})
+		indices[k]++
+		if len(res) == max {
+			break
+		}
+	}
+	return res, nil
+}
+
+// ParseRequest parses the filter in the query parameter param of the request.
+// The value is percent-decoded (`+` is kept as is) and limited to DefaultMaxFilterLength bytes
+// unless opts contain WithMaxLength. A missing or empty parameter results in a empty expression.
+// The filter is parsed with the context of the request, so parsing stops once the client is gone.
+func ParseRequest(r *http.Request, param string, opts ...Option) (Expression, error) {
+	return newRequestParser(opts).parseRequest(r, param)
+}
+
+func newRequestParser(opts []Option) *Parser {
+	return New(append([]Option{WithMaxLength(DefaultMaxFilterLength)}, opts...)...)
+}
+
+func (p *Parser) parseRequest(r *http.Request, param string) (Expression, error) {
+	filter, err := queryParam(r.URL.RawQuery, param)
+	if err != nil {
+		return Expression{root: true}, err
+	}
+	return p.Parse(r.Context(), filter)
+}
+
+// FilterBranch is a filter parameter of a request combined by ParseRequestFilters
+type FilterBranch struct {
+	// Param is the name of the query parameter
+	Param string
+	// Index is the position among the values of repeated parameters, starting at 0
+	Index int
+	// Filter is the percent-decoded value
+	Filter string
+	// Expression is the parsed filter
+	Expression Expression
+}
+
+// ParseRequestFilters parses every value of the query parameters params, repeated (`?filter=a==1&filter=b==2`)
+// as well as different ones (`?filter=a==1&q=b==2`), and combines them with operator:
+// OperatorAND requires every filter to match, OperatorOR any of them.
+// Each filter becomes a sub expression labeled with its parameter (see Expression.Label),
+// the branches are returned in order of appearance with their origin.
+// Values are decoded and parsed like by ParseRequest, the limit of the length applies to all values together
+// so repeating the parameter does not bypass it. Empty values are skipped and without any filter the expression is empty.
+func ParseRequestFilters(r *http.Request, params []string, operator Operator, opts ...Option) (Expression, []FilterBranch, error) {
+	if operator != OperatorAND && operator != OperatorOR {
+		return Expression{root: true}, nil, fmt.Errorf("unsupported operator `%s`", operator)
+	}
+	p := newRequestParser(opts)
+	branches, err := queryParams(r.URL.RawQuery, params, 0)
+	if err != nil {
+		return Expression{root: true}, nil, err
+	}
+	length := 0
+	for _, b := range branches {
+		length += len(b.Filter)
+	}
+	if p.maxLength > 0 && length > p.maxLength {
+		return Expression{root: true}, nil, (&lexer{ln: 1, start: Position{Line: 1}}).errInputTooLong(length, p.maxLength)
+	}
+	res := Expression{root: true}
+	parsed := branches[:0]
+	for _, b := range branches {
+		if b.Filter == "" {
+			continue
+		}
+		b.Expression, err = p.Parse(r.Context(), b.Filter)
+		if err != nil {
+			return Expression{root: true}, nil, fmt.Errorf("invalid filter parameter `%s` (value %d): %w", b.Param, b.Index+1, err)
+		}
+		if b.Expression.node == nil {
+			continue
+		}
+		sub := newSubExpression(b.Expression.node)
+		sub.label = b.Param
+		if res.node == nil {
+			res.node = sub
+		} else {
+			res.node = newBinary(string(operator), res.node, sub)
+		}
+		parsed = append(parsed, b)
+	}
+	if p.logicalNodes {
+		res = res.CollapseLogical()
+	}
+	return res, parsed, nil
+}
+
+type expressionContextKey struct{}
+
+// ExpressionFromContext returns the expression stored by FilterMiddleware
+func ExpressionFromContext(ctx context.Context) (Expression, bool) {
+	e, ok := ctx.Value(expressionContextKey{}).(Expression)
+	return e, ok
+}
+
+// FilterMiddleware parses the query parameter param like ParseRequest and stores the expression in the
+// request context, use ExpressionFromContext to retrieve it. Invalid filters are answered with
+// 400 Bad Request and the error message, the next handler is not called.
+func FilterMiddleware(param string, opts ...Option) func(http.Handler) http.Handler {
+	p := newRequestParser(opts)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			e, err := p.parseRequest(r, param)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), expressionContextKey{}, e)))
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package fiqlparser
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRequest(t *testing.T) {
+	var values = []struct {
+		query    string
+		expected string
+		err      bool
+	}{
+		{query: "filter=a%3D%3Db%3Bc%3Dgt%3D1", expected: "(a == b AND c > 1)"},
+		{query: "page=1&filter=a==b;c=gt=1", expected: "(a == b AND c > 1)"},
+		{query: "filter=name==%22John%20Doe%22", expected: `(name == "John Doe")`},
+		{query: "filter=a=in=[x+y]", expected: "(a IN [x+y])"},
+		{query: "page=1", expected: ""},
+		{query: "filter=", expected: ""},
+		{query: "filter=a==%zz", err: true},
+		{query: "filter=a==b)", err: true},
+	}
+	for _, v := range values {
+		r := httptest.NewRequest(http.MethodGet, "/items?"+v.query, nil)
+		res, err := ParseRequest(r, "filter")
+		if v.err {
+			assert.Error(t, err, v.query)
+			continue
+		}
+		if !assert.NoError(t, err, v.query) {
+			continue
+		}
+		if v.expected == "" {
+			assert.Nil(t, res.node, v.query)
+			continue
+		}
+		assert.Equal(t, v.expected, res.String(), v.query)
+	}
+}
+
+func TestParseRequestMaxLength(t *testing.T) {
+	filter := "a==" + strings.Repeat("x", DefaultMaxFilterLength)
+	r := httptest.NewRequest(http.MethodGet, "/items?filter="+filter, nil)
+	_, err := ParseRequest(r, "filter")
+	var perr *ParseError
+	if assert.True(t, errors.As(err, &perr)) {
+		assert.Equal(t, ErrorCodeInputTooLong, perr.Code)
+	}
+	_, err = ParseRequest(r, "filter", WithMaxLength(0))
+	assert.NoError(t, err)
+	_, err = ParseRequest(r, "filter", WithMaxLength(5))
+	assert.Error(t, err)
+}
+
+func TestFilterMiddleware(t *testing.T) {
+	var got Expression
+	var found bool
+	handler := FilterMiddleware("q")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, found = ExpressionFromContext(r.Context())
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/items?q=a==b,c==d", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	if assert.True(t, found) {
+		assert.Equal(t, "(a == b OR c == d)", got.String())
+	}
+
+	found = false
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/items?q=a==b;", nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "dangling operator")
+	assert.False(t, found)
+}
+
+func TestParseRequestFilters(t *testing.T) {
+	var values = []struct {
+		query    string
+		operator Operator
+		expected string
+		branches []string
+		err      bool
+	}{
+		{query: "filter=a==1&filter=b==2", operator: OperatorAND, expected: "(filter:(a == 1) AND filter:(b == 2))", branches: []string{"filter#0", "filter#1"}},
+		{query: "filter=a==1&page=2&q=b==2,c==3", operator: OperatorOR, expected: "(filter:(a == 1) OR q:(b == 2 OR c == 3))", branches: []string{"filter#0", "q#0"}},
+		{query: "filter=&filter=a=in=[x+y]", operator: OperatorAND, expected: "(filter:(a IN [x+y]))", branches: []string{"filter#1"}},
+		{query: "page=1", operator: OperatorAND, expected: "()", branches: []string{}},
+		{query: "filter=a==1&filter=b==", operator: OperatorAND, err: true},
+		{query: "filter=a==1", operator: Operator("XOR"), err: true},
+	}
+	for _, v := range values {
+		r := httptest.NewRequest(http.MethodGet, "/items?"+v.query, nil)
+		res, branches, err := ParseRequestFilters(r, []string{"filter", "q"}, v.operator)
+		if v.err {
+			assert.Error(t, err, v.query)
+			continue
+		}
+		if !assert.NoError(t, err, v.query) {
+			continue
+		}
+		assert.Equal(t, v.expected, res.String(), v.query)
+		origins := make([]string, 0, len(branches))
+		for _, b := range branches {
+			origins = append(origins, fmt.Sprintf("%s#%d", b.Param, b.Index))
+			assert.NotEmpty(t, b.Filter, v.query)
+			assert.NotNil(t, b.Expression.node, v.query)
+		}
+		assert.Equal(t, v.branches, origins, v.query)
+	}
+}
+
+func TestParseRequestFiltersMaxLength(t *testing.T) {
+	filter := "a==" + strings.Repeat("x", DefaultMaxFilterLength/2)
+	r := httptest.NewRequest(http.MethodGet, "/items?filter="+filter+"&filter="+filter, nil)
+	_, _, err := ParseRequestFilters(r, []string{"filter"}, OperatorAND)
+	var perr *ParseError
+	if assert.True(t, errors.As(err, &perr)) {
+		assert.Equal(t, ErrorCodeInputTooLong, perr.Code)
+	}
+	_, _, err = ParseRequestFilters(r, []string{"filter"}, OperatorAND, WithMaxLength(0))
+	assert.NoError(t, err)
+}
+
+func TestParseRequestContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	r := httptest.NewRequest(http.MethodGet, "/items?filter=a==1", nil).WithContext(ctx)
+	_, err := ParseRequest(r, "filter")
+	assert.ErrorIs(t, err, context.Canceled)
+	_, _, err = ParseRequestFilters(r, []string{"filter"}, OperatorAND)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestParseRequestFiltersError(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/items?filter=a==1&filter=b==", nil)
+	_, _, err := ParseRequestFilters(r, []string{"filter"}, OperatorAND)
+	var perr *ParseError
+	assert.True(t, errors.As(err, &perr))
+	assert.Contains(t, err.Error(), "`filter` (value 2)")
+}
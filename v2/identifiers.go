@@ -0,0 +1,122 @@
+package fiqlparser
+
+import (
+	"encoding/hex"
+	"errors"
+	"net/mail"
+	"net/netip"
+	"regexp"
+	"strings"
+)
+
+// ValueRecommendationUUID suggests a UUID (`123e4567-e89b-12d3-a456-426614174000`), see WithIdentifierRecommendations
+const ValueRecommendationUUID ValueRecommendation = "uuid"
+
+// ValueRecommendationEmail suggests a email address (`jane@example.com`), see WithIdentifierRecommendations
+const ValueRecommendationEmail ValueRecommendation = "email"
+
+// ValueRecommendationIP suggests a IPv4 or IPv6 address or a CIDR prefix (`10.0.0.0/8`), see WithIdentifierRecommendations
+const ValueRecommendationIP ValueRecommendation = "ip"
+
+// ErrNoUUID is generated if a argument is not a UUID
+var ErrNoUUID = errors.New("argument is not a uuid")
+
+// ErrNoIP is generated if a argument is not a IP address or CIDR prefix
+var ErrNoIP = errors.New("argument is not a ip address")
+
+var uuidRegex = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// WithIdentifierRecommendations recommends unquoted or quoted arguments without wildcards as ValueRecommendationUUID,
+// ValueRecommendationEmail or ValueRecommendationIP if they are one, instead of ValueRecommendationString
+// (or ValueRecommendationNumber for IPv4 addresses like `10.0.0.1`). The option is off by default
+// as it changes the recommendation of existing arguments.
+func WithIdentifierRecommendations() Option {
+	return func(p *Parser) {
+		p.identifiers = true
+	}
+}
+
+// recommendIdentifier applies the identifier recommendations to the argument if enabled
+func (p *Parser) recommendIdentifier(con *constantExpression) {
+	if !p.identifiers || con.prefixWildcard || con.suffixWildcard {
+		return
+	}
+	if con.recommended != ValueRecommendationString && con.recommended != ValueRecommendationNumber {
+		return
+	}
+	if rec, ok := identifierRecommendation(con.value); ok {
+		con.recommended = rec
+	}
+}
+
+// identifierRecommendation detects UUIDs, IP addresses and CIDR prefixes and email addresses
+func identifierRecommendation(value string) (ValueRecommendation, bool) {
+	if uuidRegex.MatchString(value) {
+		return ValueRecommendationUUID, true
+	}
+	if isIPValue(value) {
+		return ValueRecommendationIP, true
+	}
+	if isEmailValue(value) {
+		return ValueRecommendationEmail, true
+	}
+	return "", false
+}
+
+// isIdentifierRecommendation reports whether rec is detected by identifierRecommendation
+func isIdentifierRecommendation(rec ValueRecommendation) bool {
+	return rec == ValueRecommendationUUID || rec == ValueRecommendationEmail || rec == ValueRecommendationIP
+}
+
+func isIPValue(value string) bool {
+	if _, err := netip.ParseAddr(value); err == nil {
+		return true
+	}
+	_, err := netip.ParsePrefix(value)
+	return err == nil
+}
+
+// isEmailValue accepts plain addresses (`jane@example.com`), but no display names (`Jane <jane@example.com>`)
+func isEmailValue(value string) bool {
+	if !strings.Contains(value, "@") {
+		return false
+	}
+	addr, err := mail.ParseAddress(value)
+	return err == nil && addr.Name == "" && addr.Address == value
+}
+
+// AsUUID returns the UUID as its 16 bytes, e.g. to convert it to the UUID type of a UUID package
+func (c ArgumentContext) AsUUID() ([16]byte, error) {
+	var id [16]byte
+	if !uuidRegex.MatchString(c.val) {
+		return id, ErrNoUUID
+	}
+	if _, err := hex.Decode(id[:], []byte(strings.ReplaceAll(c.val, "-", ""))); err != nil {
+		return id, ErrNoUUID
+	}
+	return id, nil
+}
+
+// AsIP returns the IP address, for CIDR prefixes (`10.0.0.0/8`) the address of the prefix
+func (c ArgumentContext) AsIP() (netip.Addr, error) {
+	if addr, err := netip.ParseAddr(c.val); err == nil {
+		return addr, nil
+	}
+	prefix, err := netip.ParsePrefix(c.val)
+	if err != nil {
+		return netip.Addr{}, ErrNoIP
+	}
+	return prefix.Addr(), nil
+}
+
+// AsIPPrefix returns the CIDR prefix, a single address is returned as prefix of its full length (e.g. `10.0.0.1/32`)
+func (c ArgumentContext) AsIPPrefix() (netip.Prefix, error) {
+	if addr, err := netip.ParseAddr(c.val); err == nil {
+		return netip.PrefixFrom(addr, addr.BitLen()), nil
+	}
+	prefix, err := netip.ParsePrefix(c.val)
+	if err != nil {
+		return netip.Prefix{}, ErrNoIP
+	}
+	return prefix, nil
+}
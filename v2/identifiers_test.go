@@ -0,0 +1,117 @@
+package fiqlparser
+
+import (
+	"context"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithIdentifierRecommendations(t *testing.T) {
+	var values = []struct {
+		input    string
+		expected ValueRecommendation
+	}{
+		{input: "id==123e4567-e89b-12d3-a456-426614174000", expected: ValueRecommendationUUID},
+		{input: "id==123E4567-E89B-12D3-A456-426614174000", expected: ValueRecommendationUUID},
+		{input: `id=="123e4567-e89b-12d3-a456-426614174000"`, expected: ValueRecommendationUUID},
+		{input: "id==123e4567-e89b-12d3-a456*", expected: ValueRecommendationString},
+		{input: "mail==jane@example.com", expected: ValueRecommendationEmail},
+		{input: `mail=="Jane <jane@example.com>"`, expected: ValueRecommendationString},
+		{input: "ip==10.0.0.1", expected: ValueRecommendationIP},
+		{input: "ip==10.0.0.0/8", expected: ValueRecommendationIP},
+		{input: "ip==2001:db8::1", expected: ValueRecommendationIP},
+		{input: "ip==2001:db8::/32", expected: ValueRecommendationIP},
+		{input: "ip==10.0.0", expected: ValueRecommendationString},
+		{input: "ip==1.5", expected: ValueRecommendationNumber},
+		{input: "n==true", expected: ValueRecommendationBoolean},
+		{input: "n==2024-05-01", expected: ValueRecommendationDateTime},
+	}
+	p := New(WithIdentifierRecommendations())
+	for _, v := range values {
+		e, err := p.Parse(context.Background(), v.input)
+		if assert.NoError(t, err, v.input) {
+			pred, ok := predicateOf(e.node)
+			if assert.True(t, ok, v.input) {
+				assert.Equal(t, v.expected, pred.Argument.ValueRecommendation(), v.input)
+			}
+			assert.Equal(t, v.input, e.ToFIQL(), v.input)
+		}
+	}
+
+	// the recommendations are unchanged without the option
+	e := mustParse(t, "ip==10.0.0.1;id==123e4567-e89b-12d3-a456-426614174000")
+	recommendations := make([]ValueRecommendation, 0)
+	Walk(&e, func(n Node) bool {
+		if pred, ok := predicateOf(n); ok {
+			recommendations = append(recommendations, pred.Argument.ValueRecommendation())
+		}
+		return true
+	})
+	assert.Equal(t, []ValueRecommendation{ValueRecommendationString, ValueRecommendationString}, recommendations)
+}
+
+func TestWithIdentifierRecommendationsTuple(t *testing.T) {
+	e, err := New(WithIdentifierRecommendations()).Parse(context.Background(), "ip=in=[10.0.0.1+example]")
+	if !assert.NoError(t, err) {
+		return
+	}
+	pred, _ := predicateOf(e.node)
+	elements, err := pred.Argument.AsTuple()
+	if assert.NoError(t, err) && assert.Len(t, elements, 2) {
+		assert.Equal(t, ValueRecommendationIP, elements[0].ValueRecommendation())
+		assert.Equal(t, ValueRecommendationString, elements[1].ValueRecommendation())
+	}
+}
+
+func TestArgumentContextIdentifiers(t *testing.T) {
+	id, err := ArgumentContext{val: "123e4567-e89b-12d3-a456-426614174000"}.AsUUID()
+	if assert.NoError(t, err) {
+		assert.Equal(t, [16]byte{0x12, 0x3e, 0x45, 0x67, 0xe8, 0x9b, 0x12, 0xd3, 0xa4, 0x56, 0x42, 0x66, 0x14, 0x17, 0x40, 0x00}, id)
+	}
+	_, err = ArgumentContext{val: "123e4567"}.AsUUID()
+	assert.ErrorIs(t, err, ErrNoUUID)
+
+	addr, err := ArgumentContext{val: "10.0.0.1"}.AsIP()
+	if assert.NoError(t, err) {
+		assert.Equal(t, netip.MustParseAddr("10.0.0.1"), addr)
+	}
+	addr, err = ArgumentContext{val: "10.0.0.0/8"}.AsIP()
+	if assert.NoError(t, err) {
+		assert.Equal(t, netip.MustParseAddr("10.0.0.0"), addr)
+	}
+	prefix, err := ArgumentContext{val: "2001:db8::1"}.AsIPPrefix()
+	if assert.NoError(t, err) {
+		assert.Equal(t, netip.MustParsePrefix("2001:db8::1/128"), prefix)
+	}
+	_, err = ArgumentContext{val: "example"}.AsIP()
+	assert.ErrorIs(t, err, ErrNoIP)
+	_, err = ArgumentContext{val: "example"}.AsIPPrefix()
+	assert.ErrorIs(t, err, ErrNoIP)
+}
+
+func TestSchemaIdentifierTypes(t *testing.T) {
+	schema := Schema{Selectors: []SelectorSchema{
+		{Name: "id", Type: ValueRecommendationUUID},
+		{Name: "mail", Type: ValueRecommendationEmail},
+		{Name: "ip", Type: ValueRecommendationIP},
+	}}
+	// identifiers are accepted with and without the option
+	for _, p := range []*Parser{New(), New(WithIdentifierRecommendations())} {
+		e, err := p.Parse(context.Background(), "id==123e4567-e89b-12d3-a456-426614174000;mail==jane@example.com;ip=in=[10.0.0.1+10.1.0.0/16]")
+		if assert.NoError(t, err) {
+			assert.NoError(t, schema.Validate(e))
+		}
+		e, err = p.Parse(context.Background(), "id==42")
+		if assert.NoError(t, err) {
+			assert.ErrorIs(t, schema.Validate(e), ErrInvalidArgumentType)
+		}
+	}
+	for _, example := range schema.documentation().examples {
+		e, err := Parse(context.Background(), example)
+		if assert.NoError(t, err, example) {
+			assert.NoError(t, schema.Validate(e), example)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package fiqlparser
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// includeDirective is the directive used to include other files in ParseFile
+const includeDirective = "@include"
+
+// ErrIncludeCycle is generated if files include each other
+var ErrIncludeCycle = errors.New("include cycle")
+
+// IncludeResolver resolves the files used by ParseFile
+type IncludeResolver interface {
+	// Resolve returns the canonical name and content of the file name included by the file from.
+	// from is empty for the initial file. The canonical name is used for cycle detection
+	// and error reporting, it should be the same for every reference to the same file.
+	Resolve(from string, name string) (canonical string, content string, err error)
+}
+
+// IncludeResolverFunc is a function adapter for IncludeResolver
+type IncludeResolverFunc func(from string, name string) (string, string, error)
+
+// Resolve calls f(from, name)
+func (f IncludeResolverFunc) Resolve(from string, name string) (string, string, error) {
+	return f(from, name)
+}
+
+// FileError attributes a error to the file it occurred in
+type FileError struct {
+	// File is the canonical name of the file
+	File string
+	// Err is the underlying error, usually a *ParseError
+	Err error
+}
+
+// Error returns the error prefixed with the file name
+func (e *FileError) Error() string {
+	return fmt.Sprintf("%s: %s", e.File, e.Err.Error())
+}
+
+// Unwrap returns the underlying error
+func (e *FileError) Unwrap() error {
+	return e.Err
+}
+
+type fileParser struct {
+	ctx      context.Context
+	p        *Parser
+	resolver IncludeResolver
+	stack    []string
+	res      []Expression
+}
+
+// readIncludeDirective returns the quoted file name of a include directive
+func readIncludeDirective(line string) (string, bool) {
+	arg := strings.TrimFunc(strings.TrimPrefix(line, includeDirective), unicode.IsSpace)
+	if len(arg) < 2 || arg[0] != '"' || arg[len(arg)-1] != '"' || strings.Count(arg, "\"") != 2 {
+		return "", false
+	}
+	return arg[1 : len(arg)-1], true
+}
+
+func directiveError(start Position, line string, code ErrorCode, msg string, err error) *ParseError {
+	col, bytes := 0, 0
+	for _, r := range line {
+		if !unicode.IsSpace(r) {
+			break
+		}
+		col++
+		bytes += utf8.RuneLen(r)
+	}
+	perr := &ParseError{Code: code, Token: strings.TrimFunc(line, unicode.IsSpace), msg: msg, err: err}
+	return perr.at(Position{Line: start.Line, Column: col, Offset: start.Offset + col, ByteOffset: start.ByteOffset + bytes})
+}
+
+func (f *fileParser) include(name string, content string) error {
+	return forEachLine(content, func(start Position, line string) error {
+		trimmed := strings.TrimLeftFunc(line, unicode.IsSpace)
+		if err := f.ctx.Err(); err != nil {
+			return err
+		}
+		if !strings.HasPrefix(trimmed, includeDirective) {
+			exp, err := f.p.withInput(f.ctx, newLineLexer(line, start)).parse()
+			if err != nil {
+				return &FileError{File: name, Err: err}
+			}
+			f.res = append(f.res, exp)
+			return nil
+		}
+		included, ok := readIncludeDirective(trimmed)
+		if !ok {
+			return &FileError{File: name, Err: directiveError(start, line, ErrorCodeInvalidDirective,
+				fmt.Sprintf("invalid directive (expected %s \"<file>\")", includeDirective), nil)}
+		}
+		canonical, content, err := f.resolver.Resolve(name, included)
+		if err != nil {
+			return &FileError{File: name, Err: directiveError(start, line, ErrorCodeUnresolvedInclude,
+				fmt.Sprintf("unable to resolve `%s` (%s)", included, err), err)}
+		}
+		for _, v := range f.stack {
+			if v == canonical {
+				return &FileError{File: name, Err: directiveError(start, line, ErrorCodeIncludeCycle,
+					fmt.Sprintf("%s (%s -> %s)", ErrIncludeCycle, strings.Join(f.stack, " -> "), canonical), ErrIncludeCycle)}
+			}
+		}
+		f.stack = append(f.stack, canonical)
+		err = f.include(canonical, content)
+		f.stack = f.stack[:len(f.stack)-1]
+		return err
+	})
+}
+
+// ParseFile parses a filter file, every non-empty line is parsed as a separate expression (see ParseMulti).
+// Lines starting with `@include "<file>"` are replaced by the expressions of the referenced file,
+// which is loaded by the resolver. The initial file name is resolved as well.
+// Errors in included files are reported as *FileError, include cycles are reported as ErrIncludeCycle.
+// Once the context is done parsing stops with its error.
+func (p *Parser) ParseFile(ctx context.Context, name string, resolver IncludeResolver) ([]Expression, error) {
+	canonical, content, err := resolver.Resolve("", name)
+	if err != nil {
+		return nil, err
+	}
+	f := &fileParser{ctx: ctx, p: p, resolver: resolver, stack: []string{canonical}, res: make([]Expression, 0)}
+	err = f.include(canonical, content)
+	return f.res, err
+}
+
+// ParseFile instant parses a filter file, resolving includes with the supplied resolver,
+// with a parser configured by opts
+func ParseFile(ctx context.Context, name string, resolver IncludeResolver, opts ...Option) ([]Expression, error) {
+	return New(opts...).ParseFile(ctx, name, resolver)
+}
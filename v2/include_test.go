@@ -0,0 +1,98 @@
+package fiqlparser
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func mapResolver(files map[string]string) IncludeResolver {
+	return IncludeResolverFunc(func(from string, name string) (string, string, error) {
+		content, ok := files[name]
+		if !ok {
+			return "", "", os.ErrNotExist
+		}
+		return name, content, nil
+	})
+}
+
+func TestParseFile(t *testing.T) {
+	files := map[string]string{
+		"main.fiql":   "a==b\n@include \"common.fiql\"\n  @include   \"other.fiql\"  \nc==d",
+		"common.fiql": "x==1;y==2\n@include \"other.fiql\"",
+		"other.fiql":  "z=gt=3",
+	}
+	res, err := ParseFile(context.Background(), "main.fiql", mapResolver(files))
+	assert.NoError(t, err)
+	out := make([]string, 0)
+	for _, v := range res {
+		out = append(out, v.String())
+	}
+	assert.Equal(t, []string{"(a == b)", "(x == 1 AND y == 2)", "(z > 3)", "(z > 3)", "(c == d)"}, out)
+}
+
+func TestParseFileErrors(t *testing.T) {
+	var values = []struct {
+		files map[string]string
+		err   string
+		code  ErrorCode
+		file  string
+	}{
+		{
+			files: map[string]string{"main.fiql": "a==b\n@include \"a.fiql\"", "a.fiql": "x==1\nx=="},
+			err:   "a.fiql: ln:2:3 syntax error (got `eof` but expected a value)",
+			code:  ErrorCodeUnexpectedToken,
+			file:  "a.fiql",
+		},
+		{
+			files: map[string]string{"main.fiql": "@include \"a.fiql\"", "a.fiql": "@include \"b.fiql\"", "b.fiql": " @include \"main.fiql\""},
+			err:   "b.fiql: ln:1:2 include cycle (main.fiql -> a.fiql -> b.fiql -> main.fiql)",
+			code:  ErrorCodeIncludeCycle,
+			file:  "b.fiql",
+		},
+		{
+			files: map[string]string{"main.fiql": "@include \"main.fiql\""},
+			err:   "main.fiql: ln:1:1 include cycle (main.fiql -> main.fiql)",
+			code:  ErrorCodeIncludeCycle,
+			file:  "main.fiql",
+		},
+		{
+			files: map[string]string{"main.fiql": "a==b\n@include a.fiql"},
+			err:   "main.fiql: ln:2:1 invalid directive (expected @include \"<file>\")",
+			code:  ErrorCodeInvalidDirective,
+			file:  "main.fiql",
+		},
+		{
+			files: map[string]string{"main.fiql": "@include \"missing.fiql\""},
+			err:   "main.fiql: ln:1:1 unable to resolve `missing.fiql` (file does not exist)",
+			code:  ErrorCodeUnresolvedInclude,
+			file:  "main.fiql",
+		},
+	}
+	for _, v := range values {
+		_, err := ParseFile(context.Background(), "main.fiql", mapResolver(v.files))
+		assert.EqualError(t, err, v.err)
+		var ferr *FileError
+		if assert.True(t, errors.As(err, &ferr)) {
+			assert.Equal(t, v.file, ferr.File)
+		}
+		var perr *ParseError
+		if assert.True(t, errors.As(err, &perr)) {
+			assert.Equal(t, v.code, perr.Code)
+		}
+	}
+}
+
+func TestParseFileErrorCauses(t *testing.T) {
+	_, err := ParseFile(context.Background(), "main.fiql", mapResolver(map[string]string{"main.fiql": "@include \"main.fiql\""}))
+	assert.ErrorIs(t, err, ErrIncludeCycle)
+
+	_, err = ParseFile(context.Background(), "main.fiql", mapResolver(map[string]string{"main.fiql": "@include \"x.fiql\""}))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	_, err = ParseFile(context.Background(), "missing.fiql", mapResolver(map[string]string{}))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
@@ -0,0 +1,74 @@
+package fiqlparser
+
+import "sync"
+
+// DefaultInternMaxValueLength is the length up to which values are interned by default,
+// longer values are rarely repeated
+const DefaultInternMaxValueLength = 32
+
+// Interner deduplicates the selectors, labels and short values of parsed expressions,
+// share one interner between parsers to reduce the memory used by retained expressions.
+// It is safe for concurrent use.
+type Interner struct {
+	mu      sync.Mutex
+	strings map[string]string
+	// MaxValueLength is the length (in bytes) up to which values are interned,
+	// selectors and labels are always interned
+	MaxValueLength int
+}
+
+// NewInterner returns a interner using DefaultInternMaxValueLength
+func NewInterner() *Interner {
+	return &Interner{strings: make(map[string]string), MaxValueLength: DefaultInternMaxValueLength}
+}
+
+// Intern returns the shared copy of s, the copy does not reference the parsed input
+func (i *Interner) Intern(s string) string {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if v, ok := i.strings[s]; ok {
+		return v
+	}
+	if i.strings == nil {
+		i.strings = make(map[string]string)
+	}
+	v := string([]byte(s))
+	i.strings[v] = v
+	return v
+}
+
+// Len returns the number of distinct strings
+func (i *Interner) Len() int {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return len(i.strings)
+}
+
+// WithInterner interns the selectors, labels and short values of all parsed expressions
+func WithInterner(i *Interner) Option {
+	return func(p *Parser) {
+		p.interner = i
+	}
+}
+
+// internNodes replaces the strings of the tree by their interned copies
+func (i *Interner) internNodes(n Node) {
+	Walk(n, func(n Node) bool {
+		switch node := n.(type) {
+		case *Expression:
+			if node.label != "" {
+				node.label = i.Intern(node.label)
+			}
+		case *constantExpression:
+			// Walk visits the elements of tuples as well
+			i.internConstant(node)
+		}
+		return true
+	})
+}
+
+func (i *Interner) internConstant(c *constantExpression) {
+	if c.selector || len(c.value) <= i.MaxValueLength {
+		c.value = i.Intern(c.value)
+	}
+}
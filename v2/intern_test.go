@@ -0,0 +1,42 @@
+package fiqlparser
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInterner(t *testing.T) {
+	interner := NewInterner()
+	interner.MaxValueLength = 5
+	parser := New(WithInterner(interner))
+	a, err := parser.Parse(context.Background(), "status==open;tag=in=[a+b];note==averylongvalue")
+	assert.NoError(t, err)
+	b, err := parser.Parse(context.Background(), "status==open,urgent:(tag==a)")
+	assert.NoError(t, err)
+
+	// status, open, tag, [a+b], a, b, note, urgent
+	assert.Equal(t, 8, interner.Len())
+	assert.Equal(t, "status==open;tag=in=[a+b];note==averylongvalue", a.ToFIQL())
+	assert.Equal(t, "status==open,urgent:(tag==a)", b.ToFIQL())
+}
+
+func TestInternerConcurrent(t *testing.T) {
+	interner := NewInterner()
+	parser := New(WithInterner(interner))
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				_, err := parser.Parse(context.Background(), "a==1;b==2,c")
+				assert.NoError(t, err)
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 5, interner.Len())
+}
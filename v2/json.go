@@ -0,0 +1,275 @@
+package fiqlparser
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"unicode/utf8"
+)
+
+// MaxJSONDepth limits the nesting of nodes written by MarshalJSON, MarshalDetailedJSON and WriteJSONTo,
+// deeper trees (e.g. constructed programmatically or restored from a cache) fail with ErrJSONTooDeep.
+// The default keeps the output readable by encoding/json, which rejects more than 10000 nested objects and arrays.
+// A value <= 0 disables the limit, the JSON is written without recursion regardless of the depth.
+var MaxJSONDepth = 5000
+
+// ErrJSONTooDeep is returned if a tree is nested deeper than MaxJSONDepth
+var ErrJSONTooDeep = errors.New("expression too deep for JSON")
+
+// WriteJSONTo writes the expression as JSON (see MarshalJSON) to w,
+// the output written before a ErrJSONTooDeep is incomplete
+func (e *Expression) WriteJSONTo(w io.Writer) (int64, error) {
+	var err error
+	n, werr := writeText(w, func(w textWriter) { err = writeJSON(w, e, false, MaxJSONDepth) })
+	if err != nil {
+		return n, err
+	}
+	return n, werr
+}
+
+// AppendJSON appends the expression as JSON (see MarshalJSON) to dst and returns the extended buffer,
+// the depth is not limited
+func (e *Expression) AppendJSON(dst []byte) []byte {
+	return appendText(dst, func(w textWriter) { _ = writeJSON(w, e, false, 0) })
+}
+
+// MarshalDetailedJSON returns the expression as JSON like MarshalJSON, but selectors and arguments are distinguishable
+// and contain all details of the Go API:
+// selectors are of type `Selector` (`{"Type":"Selector","Value":"flag","Unary":true,"Position":{...}}`),
+// arguments of type `Value` (`{"Type":"Value","Value":"foo","Recommendation":"string","PrefixWildcard":false,
+// "SuffixWildcard":true,"Quoted":false,"Position":{"Line":1,"Column":7,"Offset":7}}`)
+// and tuple arguments of type `Tuple` with the elements as `Value` nodes.
+// The value of arguments is written without wildcards and quotes.
+func (e *Expression) MarshalDetailedJSON() ([]byte, error) {
+	var err error
+	b := appendText(nil, func(w textWriter) { err = writeJSON(w, e, true, MaxJSONDepth) })
+	if err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// AppendDetailedJSON appends the expression as detailed JSON (see MarshalDetailedJSON) to dst
+// and returns the extended buffer, the depth is not limited
+func (e *Expression) AppendDetailedJSON(dst []byte) []byte {
+	return appendText(dst, func(w textWriter) { _ = writeJSON(w, e, true, 0) })
+}
+
+// marshalNode returns the JSON of the node, all nodes are written into a single buffer
+// instead of marshalling (and validating) every child separately
+func marshalNode(n Node) ([]byte, error) {
+	var err error
+	b := appendText(nil, func(w textWriter) { err = writeJSON(w, n, false, MaxJSONDepth) })
+	if err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// jsonStep is a pending step of writeJSON, either literal text or a node at depth
+type jsonStep struct {
+	text  string
+	node  Node
+	depth int
+}
+
+// writeJSON writes the node in the same format encoding/json would produce for the node structs,
+// e.g. `{"Type":"Binary","Operator":"==","Nodes":[...]}`, detailed adds the details of constants.
+// The children are kept on a explicit stack instead of recursing, nodes deeper than maxDepth (if > 0)
+// fail with ErrJSONTooDeep.
+func writeJSON(w textWriter, n Node, detailed bool, maxDepth int) error {
+	stack := []jsonStep{{node: n, depth: 1}}
+	for len(stack) > 0 {
+		s := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if s.text != "" {
+			w.WriteString(s.text)
+			continue
+		}
+		if maxDepth > 0 && s.depth > maxDepth {
+			return fmt.Errorf("%w (more than %d levels)", ErrJSONTooDeep, maxDepth)
+		}
+		nodes, open := writeJSONObject(w, s.node, detailed)
+		if !open {
+			continue
+		}
+		if nodes == nil {
+			w.WriteString(`,"Nodes":null}`)
+			continue
+		}
+		w.WriteString(`,"Nodes":[`)
+		stack = append(stack, jsonStep{text: "]}"})
+		for i := len(nodes) - 1; i >= 0; i-- {
+			stack = append(stack, jsonStep{node: nodes[i], depth: s.depth + 1})
+			if i > 0 {
+				stack = append(stack, jsonStep{text: ","})
+			}
+		}
+	}
+	return nil
+}
+
+// writeJSONObject writes the fields of the node, if open is returned the object
+// is closed by writeJSON after the Nodes field containing nodes
+func writeJSONObject(w textWriter, n Node, detailed bool) (nodes []Node, open bool) {
+	switch node := n.(type) {
+	case *Expression:
+		if node == nil {
+			break
+		}
+		w.WriteString(`{"Type":`)
+		writeJSONString(w, string(node.NodeType()))
+		w.WriteString(`,"Operator":""`)
+		if node.label != "" {
+			w.WriteString(`,"Label":`)
+			writeJSONString(w, node.label)
+		}
+		return []Node{node.node}, true
+	case *binaryExpression:
+		if node == nil {
+			break
+		}
+		writeJSONOperation(w, node.NodeType(), node.operator)
+		return node.nodes[:], true
+	case *logicalExpression:
+		if node == nil {
+			break
+		}
+		writeJSONOperation(w, node.NodeType(), node.operator)
+		return node.nodes, true
+	case *notExpression:
+		if node == nil {
+			break
+		}
+		writeJSONOperation(w, node.NodeType(), string(OperatorNOT))
+		return []Node{node.node}, true
+	case *constantExpression:
+		if node == nil {
+			break
+		}
+		if detailed {
+			writeDetailedJSONConstant(w, node)
+		} else {
+			w.WriteString(`{"Type":`)
+			writeJSONString(w, string(node.NodeType()))
+			w.WriteString(`,"Value":`)
+			writeJSONString(w, node.String())
+		}
+		if node.tuple != nil {
+			return node.Children(), true
+		}
+		w.WriteRune('}')
+		return nil, false
+	}
+	w.WriteString("null")
+	return nil, false
+}
+
+func writeJSONOperation(w textWriter, t NodeType, operator string) {
+	w.WriteString(`{"Type":`)
+	writeJSONString(w, string(t))
+	w.WriteString(`,"Operator":`)
+	writeJSONString(w, operator)
+}
+
+// writeDetailedJSONConstant writes the fields of a selector, argument or tuple, see MarshalDetailedJSON
+func writeDetailedJSONConstant(w textWriter, c *constantExpression) {
+	switch {
+	case c.selector:
+		w.WriteString(`{"Type":"Selector","Value":`)
+		writeJSONString(w, c.value)
+		w.WriteString(`,"Unary":`)
+		writeJSONBool(w, c.unary)
+	case c.tuple != nil:
+		w.WriteString(`{"Type":"Tuple","Value":`)
+		writeJSONString(w, c.value)
+		w.WriteString(`,"Recommendation":`)
+		writeJSONString(w, string(c.recommended))
+	default:
+		w.WriteString(`{"Type":"Value","Value":`)
+		writeJSONString(w, c.value)
+		w.WriteString(`,"Recommendation":`)
+		writeJSONString(w, string(c.recommended))
+		w.WriteString(`,"PrefixWildcard":`)
+		writeJSONBool(w, c.prefixWildcard)
+		w.WriteString(`,"SuffixWildcard":`)
+		writeJSONBool(w, c.suffixWildcard)
+		w.WriteString(`,"Quoted":`)
+		writeJSONBool(w, c.quote != 0)
+	}
+	w.WriteString(`,"Position":{"Line":`)
+	w.WriteString(strconv.Itoa(c.pos.Line))
+	w.WriteString(`,"Column":`)
+	w.WriteString(strconv.Itoa(c.pos.Column))
+	w.WriteString(`,"Offset":`)
+	w.WriteString(strconv.Itoa(c.pos.Offset))
+	w.WriteRune('}')
+}
+
+func writeJSONBool(w textWriter, b bool) {
+	if b {
+		w.WriteString("true")
+		return
+	}
+	w.WriteString("false")
+}
+
+const jsonHex = "0123456789abcdef"
+
+// writeJSONString writes s as JSON string escaped like encoding/json
+// (including HTML characters, U+2028 and U+2029, invalid UTF-8 is replaced by U+FFFD)
+func writeJSONString(w textWriter, s string) {
+	w.WriteRune('"')
+	start := 0
+	for i := 0; i < len(s); {
+		if c := s[i]; c < utf8.RuneSelf {
+			if c >= 0x20 && c != '"' && c != '\\' && c != '<' && c != '>' && c != '&' {
+				i++
+				continue
+			}
+			w.WriteString(s[start:i])
+			switch c {
+			case '"', '\\':
+				w.WriteRune('\\')
+				w.WriteRune(rune(c))
+			case '\b':
+				w.WriteString(`\b`)
+			case '\f':
+				w.WriteString(`\f`)
+			case '\n':
+				w.WriteString(`\n`)
+			case '\r':
+				w.WriteString(`\r`)
+			case '\t':
+				w.WriteString(`\t`)
+			default:
+				w.WriteString(`\u00`)
+				w.WriteRune(rune(jsonHex[c>>4]))
+				w.WriteRune(rune(jsonHex[c&0xF]))
+			}
+			i++
+			start = i
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			w.WriteString(s[start:i])
+			w.WriteRune(utf8.RuneError)
+			i += size
+			start = i
+			continue
+		}
+		if r == '\u2028' || r == '\u2029' {
+			w.WriteString(s[start:i])
+			w.WriteString(`\u202`)
+			w.WriteRune(rune(jsonHex[r&0xF]))
+			i += size
+			start = i
+			continue
+		}
+		i += size
+	}
+	w.WriteString(s[start:])
+	w.WriteRune('"')
+}
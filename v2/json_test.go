@@ -0,0 +1,207 @@
+package fiqlparser
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteJSONString(t *testing.T) {
+	for _, s := range []string{"", "plain", `"quoted" \back\`, "<a>&b", "tab\tnew\nline\r\b\f\x00\x1f", "äöü 日本", "  ", "in\xffvalid"} {
+		expected, err := json.Marshal(s)
+		assert.NoError(t, err)
+		var b strings.Builder
+		writeJSONString(&b, s)
+		assert.Equal(t, string(expected), b.String(), s)
+	}
+}
+
+func TestMarshalJSONPartialTrees(t *testing.T) {
+	e := Expression{root: true, node: &logicalExpression{operator: string(OperatorOR)}}
+	b, err := json.Marshal(&e)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"Type":"Expr","Operator":"","Nodes":[{"Type":"Logical","Operator":"OR","Nodes":null}]}`, string(b))
+
+	e = Expression{root: true, node: newBinary(string(ComparisonEq), &constantExpression{value: "a<b", selector: true}, nil)}
+	b, err = json.Marshal(&e)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"Type":"Expr","Operator":"","Nodes":[{"Type":"Binary","Operator":"==","Nodes":[{"Type":"Const","Value":"a\u003cb"},null]}]}`, string(b))
+}
+
+// deepExpression nests a comparison depth times in negations
+func deepExpression(depth int) Expression {
+	var n Node = newBinary(string(ComparisonEq), &constantExpression{value: "a", selector: true}, &constantExpression{value: "b"})
+	for i := 0; i < depth; i++ {
+		n = &notExpression{node: n}
+	}
+	return Expression{root: true, node: n}
+}
+
+func TestMarshalJSONDepth(t *testing.T) {
+	defer func(max int) { MaxJSONDepth = max }(MaxJSONDepth)
+	MaxJSONDepth = 4
+
+	e := deepExpression(1)
+	b, err := json.Marshal(&e)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"Type":"Expr","Operator":"","Nodes":[{"Type":"UnaryLogical","Operator":"NOT","Nodes":[{"Type":"Binary","Operator":"==","Nodes":[{"Type":"Const","Value":"a"},{"Type":"Const","Value":"b"}]}]}]}`, string(b))
+
+	e = deepExpression(2)
+	_, err = json.Marshal(&e)
+	assert.ErrorIs(t, err, ErrJSONTooDeep)
+	_, err = e.MarshalDetailedJSON()
+	assert.ErrorIs(t, err, ErrJSONTooDeep)
+	_, err = e.WriteJSONTo(&bytes.Buffer{})
+	assert.ErrorIs(t, err, ErrJSONTooDeep)
+	assert.NotEmpty(t, e.AppendJSON(nil))
+
+	e = deepExpression(MaxJSONDepth)
+	MaxJSONDepth = 0
+	_, err = json.Marshal(&e)
+	assert.NoError(t, err)
+}
+
+func TestMarshalJSONDeepTreeWithoutRecursion(t *testing.T) {
+	e := deepExpression(100000)
+	b := e.AppendJSON(nil)
+	assert.True(t, bytes.HasSuffix(b, []byte(`"Value":"b"}]}`+strings.Repeat("]}", 100001))))
+	_, err := json.Marshal(&e)
+	assert.ErrorIs(t, err, ErrJSONTooDeep)
+}
+
+func TestAppendAndWriteTo(t *testing.T) {
+	res, err := New(WithLogicalNodes()).Parse(context.Background(), `x:(name=="John Doe",age=gt=30);a==b*`)
+	if !assert.NoError(t, err) {
+		return
+	}
+	prefix := []byte("filter: ")
+	expectedJSON, err := json.Marshal(&res)
+	assert.NoError(t, err)
+	var values = []struct {
+		name     string
+		expected string
+		append   func([]byte) []byte
+		write    func(*bytes.Buffer) (int64, error)
+	}{
+		{name: "string", expected: res.String(), append: res.AppendTo, write: func(b *bytes.Buffer) (int64, error) { return res.WriteTo(b) }},
+		{name: "fiql", expected: res.ToFIQL(), append: res.AppendFIQL, write: func(b *bytes.Buffer) (int64, error) { return res.WriteFIQLTo(b) }},
+		{name: "json", expected: string(expectedJSON), append: res.AppendJSON, write: func(b *bytes.Buffer) (int64, error) { return res.WriteJSONTo(b) }},
+	}
+	for _, v := range values {
+		assert.Equal(t, "filter: "+v.expected, string(v.append(append([]byte{}, prefix...))), v.name)
+		var b bytes.Buffer
+		n, err := v.write(&b)
+		assert.NoError(t, err, v.name)
+		assert.Equal(t, v.expected, b.String(), v.name)
+		assert.Equal(t, int64(len(v.expected)), n, v.name)
+	}
+
+	translator := &SQLTranslator{Columns: map[string]string{"name": "name", "age": "age", "a": "a"}}
+	sql, args, err := translator.Translate(res)
+	assert.NoError(t, err)
+	appended, appendedArgs, err := translator.AppendSQL(append([]byte{}, prefix...), res)
+	assert.NoError(t, err)
+	assert.Equal(t, "filter: "+sql, string(appended))
+	assert.Equal(t, args, appendedArgs)
+	var b bytes.Buffer
+	n, writtenArgs, err := translator.WriteSQLTo(&b, res)
+	assert.NoError(t, err)
+	assert.Equal(t, sql, b.String())
+	assert.Equal(t, int64(len(sql)), n)
+	assert.Equal(t, args, writtenArgs)
+
+	b.Reset()
+	_, _, err = (&SQLTranslator{}).WriteSQLTo(&b, res)
+	assert.Error(t, err)
+	assert.Equal(t, 0, b.Len())
+}
+
+func BenchmarkMarshalJSONDeep(b *testing.B) {
+	res, err := Parse(context.Background(), strings.Repeat("(a==b;", 200)+"c==d"+strings.Repeat(")", 200))
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := json.Marshal(&res); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestMarshalDetailedJSON(t *testing.T) {
+	res, err := Parse(context.Background(), `title==foo*;"x y"==10,flag`)
+	if !assert.NoError(t, err) {
+		return
+	}
+	data, err := res.MarshalDetailedJSON()
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.True(t, json.Valid(data))
+	assert.Contains(t, string(data), `{"Type":"Selector","Value":"title","Unary":false,"Position":{"Line":1,"Column":0,"Offset":0}}`)
+	assert.Contains(t, string(data), `{"Type":"Value","Value":"foo","Recommendation":"string","PrefixWildcard":false,"SuffixWildcard":true,"Quoted":false,"Position":{"Line":1,"Column":7,"Offset":7}}`)
+	assert.Contains(t, string(data), `{"Type":"Selector","Value":"flag","Unary":true,"Position":{"Line":1,"Column":22,"Offset":22}}`)
+
+	type constant struct {
+		Type           string
+		Value          string
+		Recommendation string
+		Quoted         bool
+		Position       Position
+		Nodes          []constant
+	}
+	var tree struct {
+		Nodes []struct {
+			Nodes []struct {
+				Nodes []struct {
+					Nodes []constant
+				}
+			}
+		}
+	}
+	if assert.NoError(t, json.Unmarshal(data, &tree)) {
+		operands := tree.Nodes[0].Nodes[0].Nodes[1].Nodes
+		if assert.Len(t, operands, 2) {
+			assert.Equal(t, "x y", operands[0].Value)
+			assert.Equal(t, "Selector", operands[0].Type)
+			assert.Equal(t, "Value", operands[1].Type)
+			assert.Equal(t, "number", operands[1].Recommendation)
+			assert.Equal(t, 19, operands[1].Position.Column)
+		}
+	}
+
+	// the default JSON is unchanged
+	plain, err := json.Marshal(&res)
+	if assert.NoError(t, err) {
+		assert.NotContains(t, string(plain), "Recommendation")
+	}
+}
+
+func TestMarshalDetailedJSONTuple(t *testing.T) {
+	res, err := Parse(context.Background(), `a=in=[x+*y]`)
+	if !assert.NoError(t, err) {
+		return
+	}
+	data := res.AppendDetailedJSON([]byte("prefix:"))
+	assert.True(t, bytes.HasPrefix(data, []byte("prefix:")))
+	assert.True(t, json.Valid(data[len("prefix:"):]))
+	assert.Contains(t, string(data), `{"Type":"Tuple","Value":"[x+*y]","Recommendation":"tuple","Position":{"Line":1,"Column":5,"Offset":5},"Nodes":[{"Type":"Value","Value":"x",`)
+	assert.Contains(t, string(data), `{"Type":"Value","Value":"y","Recommendation":"string","PrefixWildcard":true`)
+}
+
+func TestMarshalDetailedJSONQuoted(t *testing.T) {
+	res, err := Parse(context.Background(), `a=="b c"`)
+	if !assert.NoError(t, err) {
+		return
+	}
+	data, err := res.MarshalDetailedJSON()
+	if assert.NoError(t, err) {
+		assert.Contains(t, string(data), `{"Type":"Value","Value":"b c","Recommendation":"string","PrefixWildcard":false,"SuffixWildcard":false,"Quoted":true,`)
+	}
+}
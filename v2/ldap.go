@@ -0,0 +1,261 @@
+package fiqlparser
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// LDAPTranslator translates a expression to a RFC 4515 LDAP search filter,
+// e.g. `cn==Jo*;uid!=admin` becomes `(&(cn=Jo*)(!(uid=admin)))`.
+// The zero value maps every selector to the attribute of the same name.
+type LDAPTranslator struct {
+	// Attributes maps selectors to attribute descriptions, they are used as is in the filter
+	Attributes map[string]string
+	// Strict rejects selectors which are not mapped in Attributes
+	Strict bool
+	// Resolver resolves the attribute of selectors which are not mapped in Attributes
+	Resolver SelectorResolver
+}
+
+// ldapAttribute matches selectors which are valid attribute descriptions (descr or numeric OID with options)
+var ldapAttribute = regexp.MustCompile(`^([A-Za-z][A-Za-z0-9-]*|[0-9]+(\.[0-9]+)+)(;[A-Za-z0-9-]+)*$`)
+
+// Translate translates the expression to a LDAP filter, the empty expression is translated to `(objectClass=*)`
+func (t *LDAPTranslator) Translate(e Expression) (string, error) {
+	if e.node == nil {
+		return "(objectClass=*)", nil
+	}
+	var b strings.Builder
+	if err := t.write(&b, &e); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+func (t *LDAPTranslator) attribute(selector string) (string, error) {
+	if a, ok := t.Attributes[selector]; ok && a != "" {
+		return a, nil
+	}
+	if t.Resolver != nil {
+		return t.Resolver(selector)
+	}
+	if t.Strict || !ldapAttribute.MatchString(selector) {
+		return "", fmt.Errorf("%w `%s`", ErrUnknownSelector, selector)
+	}
+	return selector, nil
+}
+
+func (t *LDAPTranslator) write(b *strings.Builder, n Node) error {
+	switch node := n.(type) {
+	case *Expression:
+		return t.write(b, node.node)
+	case *binaryExpression:
+		if node.IsLogical() {
+			return t.writeLogical(b, node)
+		}
+		return t.writePredicate(b, node)
+	case *logicalExpression:
+		return t.writeLogical(b, node)
+	case *notExpression:
+		b.WriteString("(!")
+		if err := t.write(b, node.node); err != nil {
+			return err
+		}
+		b.WriteRune(')')
+		return nil
+	case *constantExpression:
+		a, err := t.attribute(node.value)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(b, "(%s=*)", a)
+		return nil
+	}
+	return fmt.Errorf("unsupported node `%v`", n)
+}
+
+// writeLogical writes all operands of a chain of the same operator into a single `&` or `|` filter
+func (t *LDAPTranslator) writeLogical(b *strings.Builder, node Node) error {
+	operator, operands, _ := logicalOperands(node)
+	b.WriteRune('(')
+	if operator == string(OperatorAND) {
+		b.WriteRune('&')
+	} else {
+		b.WriteRune('|')
+	}
+	for _, o := range operands {
+		if err := t.write(b, o); err != nil {
+			return err
+		}
+	}
+	b.WriteRune(')')
+	return nil
+}
+
+func (t *LDAPTranslator) writePredicate(b *strings.Builder, node *binaryExpression) error {
+	sel, arg, ok := predicateOperands(node)
+	if !ok {
+		return fmt.Errorf("incomplete comparison `%s`", node.String())
+	}
+	a, err := t.attribute(sel.value)
+	if err != nil {
+		return err
+	}
+	if arg.recommended == ValueRecommendationField {
+		return fmt.Errorf("field references are not supported by LDAP filters `@%s`", arg.value)
+	}
+	if arg.recommended == ValueRecommendationRange {
+		return t.writeBetween(b, a, node.operator, arg)
+	}
+	if arg.tuple != nil {
+		if node.operator != string(ComparisonIn) {
+			return fmt.Errorf("unsupported tuple comparison `%s`", node.operator)
+		}
+		b.WriteString("(|")
+		for _, el := range arg.tuple.elements {
+			if err := writeLDAPEquality(b, a, el); err != nil {
+				return err
+			}
+		}
+		b.WriteRune(')')
+		return nil
+	}
+	switch node.operator {
+	case string(ComparisonEq):
+		return writeLDAPEquality(b, a, arg)
+	case string(ComparisonNeq):
+		if arg.recommended == ValueRecommendationNull && arg.quote == 0 {
+			fmt.Fprintf(b, "(%s=*)", a)
+			return nil
+		}
+		b.WriteString("(!")
+		if err := writeLDAPEquality(b, a, arg); err != nil {
+			return err
+		}
+		b.WriteRune(')')
+		return nil
+	case string(ComparisonGte), string(ComparisonLte):
+		return writeLDAPOrdering(b, a, node.operator, arg)
+	case string(ComparisonGt), string(ComparisonLt):
+		// LDAP has no strict ordering, the value itself is excluded
+		op := string(ComparisonGte)
+		if node.operator == string(ComparisonLt) {
+			op = string(ComparisonLte)
+		}
+		b.WriteString("(&")
+		if err := writeLDAPOrdering(b, a, op, arg); err != nil {
+			return err
+		}
+		b.WriteString("(!")
+		if err := writeLDAPEquality(b, a, arg); err != nil {
+			return err
+		}
+		b.WriteString("))")
+		return nil
+	}
+	return fmt.Errorf("unsupported comparison `%s`", node.operator)
+}
+
+// writeBetween writes a range comparison, `==` and `=bt=` match the range and `!=` excludes it
+func (t *LDAPTranslator) writeBetween(b *strings.Builder, a string, operator string, arg *constantExpression) error {
+	low, high, ok := argumentRangeBounds(arg.value, arg.tuple)
+	if !ok {
+		return fmt.Errorf("%w `%s`", ErrNoRange, arg.value)
+	}
+	negate := false
+	switch operator {
+	case string(ComparisonEq), string(ComparisonBetween):
+	case string(ComparisonNeq):
+		negate = true
+		b.WriteString("(!")
+	default:
+		return fmt.Errorf("unsupported range comparison `%s`", operator)
+	}
+	b.WriteString("(&")
+	if err := writeLDAPOrdering(b, a, string(ComparisonGte), low); err != nil {
+		return err
+	}
+	if err := writeLDAPOrdering(b, a, string(ComparisonLte), high); err != nil {
+		return err
+	}
+	b.WriteRune(')')
+	if negate {
+		b.WriteRune(')')
+	}
+	return nil
+}
+
+// writeLDAPEquality writes a equality or substring filter, `null` tests the absence of the attribute
+func writeLDAPEquality(b *strings.Builder, a string, arg *constantExpression) error {
+	if arg.recommended == ValueRecommendationNull && arg.quote == 0 {
+		fmt.Fprintf(b, "(!(%s=*))", a)
+		return nil
+	}
+	v, err := ldapValue(arg)
+	if err != nil {
+		return err
+	}
+	b.WriteRune('(')
+	b.WriteString(a)
+	b.WriteRune('=')
+	if arg.prefixWildcard {
+		b.WriteRune('*')
+	}
+	b.WriteString(v)
+	if arg.suffixWildcard {
+		b.WriteRune('*')
+	}
+	b.WriteRune(')')
+	return nil
+}
+
+func writeLDAPOrdering(b *strings.Builder, a string, operator string, arg *constantExpression) error {
+	if arg.prefixWildcard || arg.suffixWildcard {
+		return fmt.Errorf("wildcards are not supported by `%s`", fiqlOperators[operator])
+	}
+	v, err := ldapValue(arg)
+	if err != nil {
+		return err
+	}
+	op := ">="
+	if operator == string(ComparisonLte) {
+		op = "<="
+	}
+	fmt.Fprintf(b, "(%s%s%s)", a, op, v)
+	return nil
+}
+
+// ldapValue converts the argument to a escaped assertion value, booleans become `TRUE` or `FALSE`
+// and datetimes generalized time (e.g. `20240501000000Z`)
+func ldapValue(arg *constantExpression) (string, error) {
+	if arg.quote == 0 && !arg.prefixWildcard && !arg.suffixWildcard {
+		switch arg.recommended {
+		case ValueRecommendationBoolean:
+			return strings.ToUpper(arg.value), nil
+		case ValueRecommendationDateTime:
+			tm, err := arg.Argument().AsTime()
+			if err != nil {
+				return "", err
+			}
+			return tm.UTC().Format("20060102150405.999999999Z"), nil
+		case ValueRecommendationDuration:
+			return "", fmt.Errorf("duration `%s` is not supported by LDAP filters", arg.value)
+		}
+	}
+	return escapeLDAPValue(arg.value), nil
+}
+
+// escapeLDAPValue escapes the characters reserved in assertion values by RFC 4515
+func escapeLDAPValue(v string) string {
+	var b strings.Builder
+	for i := 0; i < len(v); i++ {
+		switch c := v[i]; c {
+		case '*', '(', ')', '\\', 0:
+			fmt.Fprintf(&b, "\\%02x", c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,68 @@
+package fiqlparser
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLDAPTranslator(t *testing.T) {
+	var values = []struct {
+		fiql string
+		ldap string
+		err  string
+	}{
+		{fiql: "cn==Jo*;uid!=admin", ldap: "(&(cn=Jo*)(!(uid=admin)))"},
+		{fiql: "cn==*oh*,cn==*n", ldap: "(|(cn=*oh*)(cn=*n))"},
+		{fiql: "a==1;b==2;c==3", ldap: "(&(a=1)(b=2)(c=3))"},
+		{fiql: `cn=="a*b (c) \\d"`, ldap: `(cn=a\2ab \28c\29 \5cd)`},
+		{fiql: "age=le=30;age=lt=40", ldap: "(&(age<=30)(&(age<=40)(!(age=40))))"},
+		{fiql: "mail==null,phone!=null", ldap: "(|(!(mail=*))(phone=*))"},
+		{fiql: "enabled==true", ldap: "(enabled=TRUE)"},
+		{fiql: "created=ge=2024-05-01T10:30:00+02:00", ldap: "(created>=20240501083000Z)"},
+		{fiql: "age!=1..5", ldap: "(!(&(age>=1)(age<=5)))"},
+		{fiql: "ou=in=[sales+dev*]", ldap: "(|(ou=sales)(ou=dev*))"},
+		{fiql: "2.5.4.3==x", ldap: "(2.5.4.3=x)"},
+		{fiql: "created=gt=-P1D", err: "duration `-P1D` is not supported by LDAP filters"},
+		{fiql: "cn=q=john", err: "unsupported comparison `QUERY`"},
+		{fiql: "first_name==x", err: "unknown selector `first_name`"},
+		{fiql: "age=ge=1*", err: "wildcards are not supported by `=ge=`"},
+	}
+	translator := &LDAPTranslator{}
+	for _, v := range values {
+		res, err := Parse(context.Background(), v.fiql)
+		if !assert.NoError(t, err, v.fiql) {
+			continue
+		}
+		ldap, err := translator.Translate(res)
+		if v.err != "" {
+			assert.EqualError(t, err, v.err, v.fiql)
+			continue
+		}
+		if assert.NoError(t, err, v.fiql) {
+			assert.Equal(t, v.ldap, ldap, v.fiql)
+		}
+	}
+}
+
+func TestLDAPTranslatorOptions(t *testing.T) {
+	translator := &LDAPTranslator{
+		Attributes: map[string]string{"name": "cn"},
+		Resolver: func(selector string) (string, error) {
+			return "x-" + selector, nil
+		},
+	}
+	res, err := New(WithNegation()).Parse(context.Background(), "name==x;!(group==admins,group==ops)")
+	if !assert.NoError(t, err) {
+		return
+	}
+	ldap, err := translator.Translate(res)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "(&(cn=x)(!(|(x-group=admins)(x-group=ops))))", ldap)
+	}
+
+	ldap, err = (&LDAPTranslator{}).Translate(Expression{})
+	assert.NoError(t, err)
+	assert.Equal(t, "(objectClass=*)", ldap)
+}
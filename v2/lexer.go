@@ -0,0 +1,624 @@
+package fiqlparser
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+type tokenType int
+
+const tokenValue tokenType = 10
+const tokenWildcard tokenType = 11
+const tokenLabel tokenType = 12 // label:(
+const tokenNot tokenType = 13   // !( (WithNegation)
+
+const tokenBraceOpen tokenType = 20  // (
+const tokenBraceClose tokenType = 21 // )
+const tokenAND tokenType = 50        //;
+const tokenOR tokenType = 51         //,
+
+// standard FIQL comparison
+// https://datatracker.ietf.org/doc/html/draft-nottingham-atompub-fiql-00
+const tokenCompareEqual = 61    //==
+const tokenCompareNotEqual = 62 //!=
+const tokenCompareGt = 63       // =gt=
+const tokenCompareLt = 64       // =lt=
+const tokenCompareGte = 65      // =ge=
+const tokenCompareLte = 66      // =le=
+const tokenCompareBetween = 67  // =bt= or =between=
+const tokenCompareIn = 68       // =in=
+const tokenCompareQuery = 69    // =q=
+
+const tokenEOF = 0
+
+func (t tokenType) String() string {
+	switch t {
+	case tokenValue:
+		return "Value"
+	case tokenWildcard:
+		return "*"
+	case tokenLabel:
+		return "Label"
+	case tokenNot:
+		return "NOT"
+	case tokenBraceOpen:
+		return "("
+	case tokenBraceClose:
+		return ")"
+	case tokenAND:
+		return "AND"
+	case tokenOR:
+		return "OR"
+	case tokenCompareEqual:
+		return "=="
+	case tokenCompareNotEqual:
+		return "<>"
+	case tokenCompareGt:
+		return ">"
+	case tokenCompareLt:
+		return "<"
+	case tokenCompareGte:
+		return ">="
+	case tokenCompareLte:
+		return "<="
+	case tokenCompareBetween:
+		return "BETWEEN"
+	case tokenCompareIn:
+		return "IN"
+	case tokenCompareQuery:
+		return "QUERY"
+	}
+	return "eof"
+}
+
+// literal returns the token as it appears in the input
+func (p *lexer) literal(t tokenType) string {
+	switch t {
+	case tokenValue:
+		return p.currentVal
+	case tokenLabel:
+		return p.currentVal + ":"
+	case tokenNot:
+		return "!"
+	case tokenAND:
+		return ";"
+	case tokenOR:
+		return ","
+	case tokenCompareNotEqual:
+		return "!="
+	case tokenCompareGt:
+		return "=gt="
+	case tokenCompareLt:
+		return "=lt="
+	case tokenCompareGte:
+		return "=ge="
+	case tokenCompareLte:
+		return "=le="
+	case tokenCompareBetween:
+		return "=bt="
+	case tokenCompareIn:
+		return "=in="
+	case tokenCompareQuery:
+		return "=q="
+	case tokenEOF:
+		return ""
+	}
+	return t.String()
+}
+
+func isCompareToken(t tokenType) bool {
+	switch t {
+	case tokenCompareEqual, tokenCompareNotEqual, tokenCompareGt, tokenCompareLt, tokenCompareGte, tokenCompareLte, tokenCompareBetween, tokenCompareIn, tokenCompareQuery:
+		return true
+	}
+	return false
+}
+
+func isNumberOrDateComparision(t tokenType) bool {
+	switch t {
+	case tokenCompareGt, tokenCompareLt, tokenCompareGte, tokenCompareLte:
+		return true
+	}
+	return false
+}
+
+func isLogicToken(t tokenType) bool {
+	return t == tokenAND || t == tokenOR
+}
+
+func isSeperatorUnary(t tokenType) bool {
+	return isLogicToken(t) || t == tokenEOF || t == tokenBraceClose
+}
+
+type lexer struct {
+	input string
+	// pos is the byte position within input
+	pos int
+	// runes is the number of runes consumed, used for the offset
+	runes      int
+	ln         int
+	posInLine  int
+	currentVal string
+	// currentQuote is the quote character of the current value, 0 if unquoted
+	currentQuote rune
+	// offset of input within the whole document (in runes and bytes), if only a part is lexed
+	offset     int
+	byteOffset int
+	// start is the position of the last consumed token
+	start Position
+	// peeked is the position of the token last returned by PeekNextToken
+	peeked Position
+	// literalQuotes disables quoted values, quotes are part of the value (strict mode)
+	literalQuotes bool
+	// valueSpaces allows whitespace within unquoted values
+	valueSpaces bool
+	// negation enables `!` directly followed by a opening brace as tokenNot
+	negation bool
+	// trace enables recording the consumed tokens into tokens
+	trace  bool
+	tokens []Token
+	// metrics are counted while parsing, budget limits them
+	metrics ParseMetrics
+	budget  ParseBudget
+	// ctx aborts the parse once it is done, nil if it can not be canceled
+	ctx context.Context
+}
+
+// lexerPool reuses lexers between parses,
+// nodes never reference the lexer so it can be reused once parsing is done
+var lexerPool = sync.Pool{New: func() interface{} { return &lexer{} }}
+
+// acquireLexer returns a pooled lexer reading input
+func acquireLexer(input string) *lexer {
+	lex := lexerPool.Get().(*lexer)
+	*lex = lexer{input: input, ln: 1, start: Position{Line: 1}}
+	return lex
+}
+
+// releaseLexer returns the lexer to the pool
+func releaseLexer(lex *lexer) {
+	*lex = lexer{}
+	lexerPool.Put(lex)
+}
+
+// newLineLexer returns a lexer for a line of a larger input starting at start
+func newLineLexer(line string, start Position) *lexer {
+	return &lexer{input: line, ln: start.Line, offset: start.Offset, byteOffset: start.ByteOffset, start: start}
+}
+
+// position returns the current position
+func (p *lexer) position() Position {
+	return Position{Line: p.ln, Column: p.posInLine, Offset: p.offset + p.runes, ByteOffset: p.byteOffset + p.pos}
+}
+
+func (p *lexer) lastValue() string {
+	return p.currentVal
+}
+
+func (p *lexer) lastQuote() rune {
+	return p.currentQuote
+}
+
+func (p *lexer) toCompareToken(cmp string) (tokenType, error) {
+	switch strings.ToLower(cmp) {
+	case "==":
+		return tokenCompareEqual, nil
+	case "!=":
+		return tokenCompareNotEqual, nil
+	case "=gt=":
+		return tokenCompareGt, nil
+	case "=ge=":
+		return tokenCompareGte, nil
+	case "=lt=":
+		return tokenCompareLt, nil
+	case "=le=":
+		return tokenCompareLte, nil
+	case "=bt=", "=between=":
+		return tokenCompareBetween, nil
+	case "=in=":
+		return tokenCompareIn, nil
+	case "=q=":
+		return tokenCompareQuery, nil
+	}
+	return tokenEOF, p.errUnexpectedComparator(cmp)
+}
+
+var comparators = []string{"==", "!=", "=gt=", "=ge=", "=lt=", "=le=", "=bt=", "=between=", "=in=", "=q="}
+
+// isComparatorRune checks if the rune is part of the name of any comparator
+func isComparatorRune(r rune) bool {
+	for _, c := range comparators {
+		if strings.ContainsRune(c[1:], unicode.ToLower(r)) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *lexer) readComparator() (tokenType, error) {
+	start := p.pos
+	//consume first = or !
+	first := p.consume()
+	for {
+		r, ok := p.peek()
+		if !ok {
+			return tokenEOF, p.errUnexpectedEOF(p.input[start:p.pos], comparators)
+		}
+		if r != '=' && (first == '!' || !isComparatorRune(r)) {
+			return tokenEOF, p.errUnexpectedComparator(p.input[start:p.pos] + string(r))
+		}
+		p.consume()
+		if r == '=' {
+			break
+		}
+	}
+	return p.toCompareToken(p.input[start:p.pos])
+}
+
+func (p *lexer) peek() (rune, bool) {
+	if p.pos >= len(p.input) {
+		return 0, false
+	}
+	if c := p.input[p.pos]; c < utf8.RuneSelf {
+		return rune(c), true
+	}
+	r, _ := utf8.DecodeRuneInString(p.input[p.pos:])
+	return r, true
+}
+
+func (p *lexer) consume() rune {
+	r, size := rune(p.input[p.pos]), 1
+	if r >= utf8.RuneSelf {
+		r, size = utf8.DecodeRuneInString(p.input[p.pos:])
+	}
+	if r == '\n' {
+		p.ln = p.ln + 1
+		p.posInLine = 0
+	} else {
+		p.posInLine = p.posInLine + 1
+	}
+	p.pos += size
+	p.runes++
+	return r
+}
+
+// valueBuilder collects a value which may contain escape sequences,
+// as long as there are none the value is a slice of the input and nothing is allocated
+type valueBuilder struct {
+	input   string
+	segment int
+	buf     []byte
+}
+
+// skip drops the input between the segment start and pos (e.g. a escape character)
+func (b *valueBuilder) skip(from int, to int) {
+	b.buf = append(b.buf, b.input[b.segment:from]...)
+	if b.buf == nil {
+		b.buf = make([]byte, 0, 16)
+	}
+	b.segment = to
+}
+
+// value returns the collected value up to pos
+func (b *valueBuilder) value(pos int) string {
+	if b.buf == nil {
+		return b.input[b.segment:pos]
+	}
+	return string(append(b.buf, b.input[b.segment:pos]...))
+}
+
+func (p *lexer) readValue() (tokenType, string, error) {
+	b := valueBuilder{input: p.input, segment: p.pos}
+	escaped := false
+	labelColon := false
+	if r, _ := p.peek(); r == '\\' {
+		from := p.pos
+		p.consume()
+		b.skip(from, p.pos)
+		escaped = true
+	} else {
+		p.consume()
+	}
+	for {
+		if p.pos >= len(p.input) {
+			break
+		}
+		v, ok := p.peek()
+		if ok {
+			if unicode.IsSpace(v) && !(p.valueSpaces && p.spaceWithinValue()) {
+				break
+			}
+			if !escaped && (v == ';' || v == ',' || v == '!' || v == '=' || v == ')' || v == '*') {
+				break
+			}
+			// a unescaped colon directly followed by a brace labels the sub expression
+			if v == '(' && labelColon {
+				if val := b.value(p.pos); len(val) > 1 {
+					val = strings.TrimSuffix(val, ":")
+					p.currentVal = val
+					p.currentQuote = 0
+					return tokenLabel, val, nil
+				}
+			}
+		}
+		if v == '\\' && !escaped {
+			escaped = true
+			from := p.pos
+			p.consume()
+			b.skip(from, p.pos)
+		} else {
+			r := p.consume()
+			labelColon = r == ':' && !escaped
+			escaped = false
+		}
+
+	}
+	val := b.value(p.pos)
+	p.currentVal = val
+	p.currentQuote = 0
+	return tokenValue, val, nil
+}
+
+// spaceWithinValue checks if the whitespace at the current position is followed by
+// more of the value instead of the end of the input, a operator or a closing brace
+func (p *lexer) spaceWithinValue() bool {
+	for _, r := range p.input[p.pos:] {
+		if unicode.IsSpace(r) {
+			continue
+		}
+		return r != ';' && r != ',' && r != ')'
+	}
+	return false
+}
+
+func isQuote(r rune) bool {
+	return r == '"' || r == '\''
+}
+
+// readQuotedValue reads a value enclosed in single or double quotes,
+// within the quotes any character can be escaped with a backslash
+func (p *lexer) readQuotedValue() (tokenType, string, error) {
+	quote := p.consume()
+	b := valueBuilder{input: p.input, segment: p.pos}
+	escaped := false
+	var val string
+	for {
+		v, ok := p.peek()
+		if !ok {
+			return tokenEOF, "", p.errUnterminatedQuote(quote, b.value(p.pos))
+		}
+		from := p.pos
+		p.consume()
+		if escaped {
+			escaped = false
+			continue
+		}
+		if v == '\\' {
+			b.skip(from, p.pos)
+			escaped = true
+			continue
+		}
+		if v == quote {
+			val = b.value(from)
+			break
+		}
+	}
+	p.currentVal = val
+	p.currentQuote = quote
+	return tokenValue, val, nil
+}
+
+func (p *lexer) skipSpace() {
+	for {
+		r, ok := p.peek()
+		if !ok || !unicode.IsSpace(r) {
+			return
+		}
+		p.consume()
+	}
+}
+
+// readTuple reads a tuple (e.g. `[a+"b c"+d\+e]`) enclosed in the delimiters,
+// elements may be quoted, contain escaped characters or start and end with a wildcard.
+// If the input does not start with the opening delimiter nothing is consumed and false is returned.
+func (p *lexer) readTuple(d TupleDelimiters) ([]*constantExpression, bool, error) {
+	p.skipSpace()
+	if r, ok := p.peek(); !ok || r != d.Open {
+		return nil, false, nil
+	}
+	p.start = p.position()
+	start := p.pos
+	p.consume()
+	elements := make([]*constantExpression, 0)
+	p.skipSpace()
+	if r, ok := p.peek(); ok && r == d.Close {
+		p.consume()
+		p.currentVal = p.input[start:p.pos]
+		p.currentQuote = 0
+		if p.trace {
+			p.record(tokenValue)
+		}
+		return elements, true, nil
+	}
+	for {
+		p.skipSpace()
+		el, err := p.readTupleElement(d, start)
+		if err != nil {
+			return nil, true, err
+		}
+		elements = append(elements, el)
+		p.skipSpace()
+		r, ok := p.peek()
+		if !ok {
+			return nil, true, p.errUnexpectedEOF(p.input[start:p.pos], []string{string(d.Separator), string(d.Close)})
+		}
+		p.consume()
+		if r == d.Close {
+			break
+		}
+		if r != d.Separator {
+			p.currentVal = p.input[start:p.pos]
+			return nil, true, p.errInvalidValue(p.currentVal, []string{string(d.Separator), string(d.Close)})
+		}
+	}
+	p.currentVal = p.input[start:p.pos]
+	p.currentQuote = 0
+	if p.trace {
+		p.record(tokenValue)
+	}
+	p.metrics.Tokens++
+	return elements, true, nil
+}
+
+func (p *lexer) readTupleElement(d TupleDelimiters, start int) (*constantExpression, error) {
+	el := &constantExpression{pos: p.position()}
+	r, ok := p.peek()
+	if !ok {
+		return nil, p.errUnexpectedEOF(p.input[start:p.pos], []string{"value"})
+	}
+	if r == '*' {
+		p.consume()
+		el.prefixWildcard = true
+		r, ok = p.peek()
+	}
+	if ok && isQuote(r) {
+		if _, _, err := p.readQuotedValue(); err != nil {
+			return nil, err
+		}
+		el.value = p.currentVal
+		el.quote = p.currentQuote
+	} else {
+		b := valueBuilder{input: p.input, segment: p.pos}
+		escaped := false
+		for {
+			v, ok := p.peek()
+			if !ok {
+				return nil, p.errUnexpectedEOF(p.input[start:p.pos], []string{string(d.Separator), string(d.Close)})
+			}
+			if !escaped && (v == d.Separator || v == d.Close || v == '*' || unicode.IsSpace(v)) {
+				break
+			}
+			from := p.pos
+			p.consume()
+			if v == '\\' && !escaped {
+				b.skip(from, p.pos)
+				escaped = true
+				continue
+			}
+			escaped = false
+		}
+		el.value = b.value(p.pos)
+	}
+	if r, ok := p.peek(); ok && r == '*' {
+		p.consume()
+		el.suffixWildcard = true
+	}
+	if el.value == "" && el.quote == 0 && !el.prefixWildcard && !el.suffixWildcard {
+		p.currentVal = p.input[start:p.pos]
+		return nil, p.errInvalidValue(p.currentVal, []string{"value"})
+	}
+	return el, nil
+}
+
+func (p *lexer) PeekNextToken() (tokenType, string, error) {
+	ln := p.ln
+	pos := p.pos
+	runes := p.runes
+	posln := p.posInLine
+	val := p.currentVal
+	quote := p.currentQuote
+	start := p.start
+	t, err := p.nextToken()
+	newCur := p.currentVal
+	p.peeked = p.start
+	p.currentVal = val
+	p.currentQuote = quote
+	p.start = start
+	p.ln = ln
+	p.pos = pos
+	p.runes = runes
+	p.posInLine = posln
+	return t, newCur, err
+}
+
+func (p *lexer) ConsumeToken() (tokenType, error) {
+	t, err := p.nextToken()
+	if err != nil {
+		return t, err
+	}
+	if p.trace {
+		p.record(t)
+	}
+	if t != tokenEOF {
+		p.metrics.Tokens++
+	}
+	if err := p.checkContext(); err != nil {
+		return t, err
+	}
+	return t, p.checkBudget()
+}
+
+// contextCheckInterval is the number of tokens consumed between checks of the context
+const contextCheckInterval = 64
+
+// checkContext returns the error of the context once it is done,
+// it is checked every contextCheckInterval tokens so long inputs are aborted while parsing
+func (p *lexer) checkContext() error {
+	if p.ctx == nil || p.metrics.Tokens%contextCheckInterval != 0 {
+		return nil
+	}
+	return p.ctx.Err()
+}
+
+func (p *lexer) nextToken() (tokenType, error) {
+	for {
+		r, ok := p.peek()
+		if !ok {
+			p.start = p.position()
+			return tokenEOF, nil
+		}
+
+		if unicode.IsSpace(r) {
+			p.consume()
+			continue
+		}
+		p.start = p.position()
+
+		if r == '!' && p.negation && strings.HasPrefix(p.input[p.pos+1:], "(") {
+			p.consume()
+			return tokenNot, nil
+		}
+		if r == '!' || r == '=' {
+			return p.readComparator()
+		}
+		if r == '(' {
+			p.consume()
+			return tokenBraceOpen, nil
+		}
+		if r == ')' {
+			p.consume()
+			return tokenBraceClose, nil
+		}
+		if r == ';' {
+			p.consume()
+			return tokenAND, nil
+		}
+		if r == ',' {
+			p.consume()
+			return tokenOR, nil
+		}
+		if r == '*' {
+			p.consume()
+			return tokenWildcard, nil
+		}
+		if isQuote(r) && !p.literalQuotes {
+			t, _, err := p.readQuotedValue()
+			return t, err
+		}
+		t, _, err := p.readValue()
+		return t, err
+	}
+}
@@ -0,0 +1,143 @@
+package fiqlparser
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type quotedVisitor struct {
+	testTypeVisitor
+	args []ArgumentContext
+}
+
+func (t *quotedVisitor) VisitArgument(argumentCtx ArgumentContext) {
+	t.args = append(t.args, argumentCtx)
+}
+
+func TestQuotedArguments(t *testing.T) {
+	var values = []struct {
+		fiql        string
+		stringOuput string
+		value       string
+		quoted      bool
+	}{
+		{fiql: `name=="John Doe"`, stringOuput: `(name == "John Doe")`, value: "John Doe", quoted: true},
+		{fiql: `name=='John Doe'`, stringOuput: `(name == 'John Doe')`, value: "John Doe", quoted: true},
+		{fiql: `name=="a;b,c==d(e)"`, stringOuput: `(name == "a;b,c==d(e)")`, value: "a;b,c==d(e)", quoted: true},
+		{fiql: `name=="say \"hi\""`, stringOuput: `(name == "say \"hi\"")`, value: `say "hi"`, quoted: true},
+		{fiql: `name=='it\'s'`, stringOuput: `(name == 'it\'s')`, value: `it's`, quoted: true},
+		{fiql: `name=="it's"`, stringOuput: `(name == "it's")`, value: `it's`, quoted: true},
+		{fiql: `name=="back\\slash"`, stringOuput: `(name == "back\\slash")`, value: `back\slash`, quoted: true},
+		{fiql: `name==""`, stringOuput: `(name == "")`, value: "", quoted: true},
+		{fiql: `name=="John *"*`, stringOuput: `(name == "John *"*)`, value: "John *", quoted: true},
+		{fiql: `name==John`, stringOuput: `(name == John)`, value: "John", quoted: false},
+		{fiql: `name==Jo"hn`, stringOuput: `(name == Jo"hn)`, value: `Jo"hn`, quoted: false},
+	}
+	for _, v := range values {
+		res, err := Parse(context.Background(), v.fiql)
+		if !assert.NoError(t, err, v.fiql) {
+			continue
+		}
+		assert.Equal(t, v.stringOuput, res.String())
+		visitor := &quotedVisitor{}
+		res.Accept(visitor)
+		if assert.Len(t, visitor.args, 1) {
+			assert.Equal(t, v.value, visitor.args[0].AsString())
+			assert.Equal(t, v.quoted, visitor.args[0].IsQuoted())
+		}
+	}
+}
+
+func TestQuotedArgumentsCombined(t *testing.T) {
+	res, err := Parse(context.Background(), `name=="John Doe",(name=='Jane Doe';age=gt="30")`)
+	assert.NoError(t, err)
+	assert.Equal(t, `(name == "John Doe" OR (name == 'Jane Doe' AND age > "30"))`, res.String())
+}
+
+func TestUnterminatedQuote(t *testing.T) {
+	_, err := Parse(context.Background(), `name=="John Doe`)
+	assert.EqualError(t, err, "ln:1:15 unexpected end of file (unterminated quote `\"`)")
+	assert.ErrorIs(t, err, ErrUnexpectedEOF)
+	var perr *ParseError
+	if assert.True(t, errors.As(err, &perr)) {
+		assert.Equal(t, ErrorCodeUnexpectedEOF, perr.Code)
+		assert.Equal(t, `"John Doe`, perr.Token)
+	}
+}
+
+func TestLexerValues(t *testing.T) {
+	var values = []struct {
+		input  string
+		values []string
+	}{
+		{input: `näme==Jürgen;ü=gt=1`, values: []string{"näme", "Jürgen", "ü", "1"}},
+		{input: `a==b\,c\;d`, values: []string{"a", "b,c;d"}},
+		{input: `a==\*x`, values: []string{"a", "*x"}},
+		{input: `a=="日本 \"語\""`, values: []string{"a", `日本 "語"`}},
+	}
+	for _, v := range values {
+		lex := &lexer{input: v.input, ln: 1}
+		res := make([]string, 0)
+		for {
+			tok, err := lex.ConsumeToken()
+			if !assert.NoError(t, err, v.input) || tok == tokenEOF {
+				break
+			}
+			if tok == tokenValue {
+				res = append(res, lex.lastValue())
+			}
+		}
+		assert.Equal(t, v.values, res, v.input)
+	}
+}
+
+func TestLexerPosition(t *testing.T) {
+	lex := &lexer{input: "ä==b;\nc==日", ln: 1}
+	positions := make([]Position, 0)
+	for {
+		tok, err := lex.ConsumeToken()
+		if !assert.NoError(t, err) || tok == tokenEOF {
+			break
+		}
+		positions = append(positions, lex.start)
+	}
+	assert.Equal(t, []Position{
+		{Line: 1, Column: 0, Offset: 0, ByteOffset: 0}, {Line: 1, Column: 1, Offset: 1, ByteOffset: 2},
+		{Line: 1, Column: 3, Offset: 3, ByteOffset: 4}, {Line: 1, Column: 4, Offset: 4, ByteOffset: 5},
+		{Line: 2, Column: 0, Offset: 6, ByteOffset: 7}, {Line: 2, Column: 1, Offset: 7, ByteOffset: 8},
+		{Line: 2, Column: 3, Offset: 9, ByteOffset: 10},
+	}, positions)
+}
+
+// lexAll consumes all tokens of the input
+func lexAll(input string) error {
+	lex := &lexer{input: input, ln: 1}
+	for {
+		tok, err := lex.ConsumeToken()
+		if err != nil || tok == tokenEOF {
+			return err
+		}
+	}
+}
+
+const benchmarkLexerInput = `title==foo*;(updated=lt=-P1D,title==*bar);name=="John Doe";status=ge=2003-12-13T00:00:00Z`
+
+func TestLexerAllocations(t *testing.T) {
+	allocs := testing.AllocsPerRun(100, func() {
+		_ = lexAll(benchmarkLexerInput)
+	})
+	// only the lexer itself is allocated, values without escape sequences are slices of the input
+	assert.LessOrEqual(t, allocs, 1.0)
+}
+
+func BenchmarkLexer(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if err := lexAll(benchmarkLexerInput); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package fiqlparser
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DialectCapabilities describes what a backend handles well, it is used by Lint
+type DialectCapabilities struct {
+	// LeadingWildcards marks backends which can use indexes for leading wildcards (e.g. trigram indexes)
+	LeadingWildcards bool
+	// IndexMerge marks backends which can combine indexes of different columns for OR
+	IndexMerge bool
+	// MaxTupleElements is the number of =in= elements above which a warning is generated, 0 disables the check
+	MaxTupleElements int
+	// FullText marks backends supporting full text searches (=q=)
+	FullText bool
+}
+
+// LintCode classifies a LintWarning
+type LintCode string
+
+// LintLeadingWildcard is used for arguments starting with a wildcard
+const LintLeadingWildcard LintCode = "LeadingWildcard"
+
+// LintOrAcrossSelectors is used for OR operations comparing different selectors
+const LintOrAcrossSelectors LintCode = "OrAcrossSelectors"
+
+// LintLargeTuple is used for =in= comparisons with more elements than allowed
+const LintLargeTuple LintCode = "LargeTuple"
+
+// LintUnsupportedComparison is used for comparisons the backend does not support
+const LintUnsupportedComparison LintCode = "UnsupportedComparison"
+
+// LintWarning describes a construct the backend will handle poorly
+type LintWarning struct {
+	// Position is the position of the offending selector or argument
+	Position Position
+	// Code classifies the warning
+	Code LintCode
+	// Selector is the selector the warning relates to
+	Selector string
+	// Message describes the warning
+	Message string
+}
+
+// String returns the warning in the form `ln:<line>:<column> <message>`
+func (w LintWarning) String() string {
+	return fmt.Sprintf("ln:%d:%d %s", w.Position.Line, w.Position.Column+1, w.Message)
+}
+
+// Lint returns warnings for constructs the backend described by caps will handle poorly,
+// in order of their position
+func Lint(e Expression, caps DialectCapabilities) []LintWarning {
+	warnings := make([]LintWarning, 0)
+	covered := make(map[Node]bool)
+	Walk(&e, func(n Node) bool {
+		if op, operands, ok := logicalOperands(n); ok && op == string(OperatorOR) && !caps.IndexMerge && !covered[unwrapExpression(n)] {
+			markOrChain(n, operands, covered)
+			if w, ok := lintOr(operands); ok {
+				warnings = append(warnings, w)
+			}
+		}
+		bin, ok := n.(*binaryExpression)
+		if !ok || isLogicalOperator(bin.operator) {
+			return true
+		}
+		sel, arg, ok := predicateOperands(bin)
+		if !ok {
+			return true
+		}
+		if arg.prefixWildcard && !caps.LeadingWildcards {
+			warnings = append(warnings, LintWarning{Position: arg.pos, Code: LintLeadingWildcard, Selector: sel.value,
+				Message: fmt.Sprintf("leading wildcard on `%s` prevents index usage", sel.value)})
+		}
+		if arg.tuple != nil && caps.MaxTupleElements > 0 && len(arg.tuple.elements) > caps.MaxTupleElements {
+			warnings = append(warnings, LintWarning{Position: arg.pos, Code: LintLargeTuple, Selector: sel.value,
+				Message: fmt.Sprintf("`%s` is compared with %d values (at most %d recommended)", sel.value, len(arg.tuple.elements), caps.MaxTupleElements)})
+		}
+		if bin.operator == string(ComparisonQuery) && !caps.FullText {
+			warnings = append(warnings, LintWarning{Position: sel.pos, Code: LintUnsupportedComparison, Selector: sel.value,
+				Message: fmt.Sprintf("full text search on `%s` is not supported", sel.value)})
+		}
+		return true
+	})
+	return warnings
+}
+
+// markOrChain marks the nodes a flattened OR consists of, so the chain is only reported once
+func markOrChain(n Node, operands []Node, covered map[Node]bool) {
+	for _, o := range operands {
+		if o == n {
+			return
+		}
+	}
+	covered[unwrapExpression(n)] = true
+	for _, c := range unwrapExpression(n).Children() {
+		markOrChain(c, operands, covered)
+	}
+}
+
+// lintOr checks if the operands of a OR compare different selectors
+func lintOr(operands []Node) (LintWarning, bool) {
+	selectors := make([]string, 0)
+	seen := make(map[string]bool)
+	var first *constantExpression
+	for _, o := range operands {
+		Walk(o, func(c Node) bool {
+			if con, ok := c.(*constantExpression); ok && con.selector && !seen[con.value] {
+				seen[con.value] = true
+				selectors = append(selectors, "`"+con.value+"`")
+				if first == nil {
+					first = con
+				}
+			}
+			return true
+		})
+	}
+	if len(selectors) < 2 {
+		return LintWarning{}, false
+	}
+	return LintWarning{Position: first.pos, Code: LintOrAcrossSelectors, Selector: first.value,
+		Message: fmt.Sprintf("OR across %s may prevent index usage", strings.Join(selectors, ", "))}, true
+}
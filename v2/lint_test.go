@@ -0,0 +1,65 @@
+package fiqlparser
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLint(t *testing.T) {
+	var values = []struct {
+		fiql     string
+		caps     DialectCapabilities
+		codes    []LintCode
+		position []Position
+	}{
+		{fiql: "a==1;b==x*", caps: DialectCapabilities{}},
+		{fiql: "a==1;b==*x", caps: DialectCapabilities{},
+			codes: []LintCode{LintLeadingWildcard}, position: []Position{{Line: 1, Column: 8, Offset: 8, ByteOffset: 8}}},
+		{fiql: "a==1;b==*x", caps: DialectCapabilities{LeadingWildcards: true}},
+		{fiql: "a==1,b==2", caps: DialectCapabilities{},
+			codes: []LintCode{LintOrAcrossSelectors}, position: []Position{{Line: 1, Column: 0, Offset: 0, ByteOffset: 0}}},
+		{fiql: "a==1,a==2", caps: DialectCapabilities{}},
+		{fiql: "a==1,b==2", caps: DialectCapabilities{IndexMerge: true}},
+		{fiql: "x==1;(a==1,b==2,c==3)", caps: DialectCapabilities{},
+			codes: []LintCode{LintOrAcrossSelectors}, position: []Position{{Line: 1, Column: 6, Offset: 6, ByteOffset: 6}}},
+		{fiql: "a=in=[1+2+3]", caps: DialectCapabilities{MaxTupleElements: 2},
+			codes: []LintCode{LintLargeTuple}, position: []Position{{Line: 1, Column: 5, Offset: 5, ByteOffset: 5}}},
+		{fiql: "a=in=[1+2]", caps: DialectCapabilities{MaxTupleElements: 2}},
+		{fiql: "title=q=fox", caps: DialectCapabilities{},
+			codes: []LintCode{LintUnsupportedComparison}, position: []Position{{Line: 1, Column: 0, Offset: 0, ByteOffset: 0}}},
+		{fiql: "title=q=fox", caps: DialectCapabilities{FullText: true}},
+	}
+	for _, v := range values {
+		res, err := Parse(context.Background(), v.fiql)
+		if !assert.NoError(t, err, v.fiql) {
+			continue
+		}
+		warnings := Lint(res, v.caps)
+		codes := make([]LintCode, 0)
+		positions := make([]Position, 0)
+		for _, w := range warnings {
+			codes = append(codes, w.Code)
+			positions = append(positions, w.Position)
+		}
+		if v.codes == nil {
+			assert.Empty(t, warnings, v.fiql)
+			continue
+		}
+		assert.Equal(t, v.codes, codes, v.fiql)
+		assert.Equal(t, v.position, positions, v.fiql)
+	}
+}
+
+func TestLintWarningString(t *testing.T) {
+	res, err := Parse(context.Background(), "a==1;b==*x")
+	if !assert.NoError(t, err) {
+		return
+	}
+	warnings := Lint(res, DialectCapabilities{})
+	if assert.Len(t, warnings, 1) {
+		// columns of positions start at 0, the string uses 1-based columns like ParseError
+		assert.Equal(t, "ln:1:9 leading wildcard on `b` prevents index usage", warnings[0].String())
+	}
+}
@@ -0,0 +1,86 @@
+package fiqlparser
+
+// NodeTypeLogical is a logical operation with any number of operands
+const NodeTypeLogical NodeType = "Logical"
+
+// LogicalNode is a logical operation (AND, OR) with any number of operands,
+// it is produced by CollapseLogical or the WithLogicalNodes option
+type LogicalNode interface {
+	Node
+	// Operator returns the logical operator
+	Operator() string
+	// Operands returns the operands in order
+	Operands() []Node
+}
+
+var _ LogicalNode = &logicalExpression{}
+
+type logicalExpression struct {
+	operator string
+	nodes    []Node
+}
+
+// Operator returns the logical operator
+func (e *logicalExpression) Operator() string {
+	return e.operator
+}
+
+// Operands returns the operands in order
+func (e *logicalExpression) Operands() []Node {
+	return e.nodes
+}
+
+func (e *logicalExpression) NodeType() NodeType {
+	return NodeTypeLogical
+}
+
+func (e *logicalExpression) Add(node Node) {
+	e.nodes = append(e.nodes, node)
+}
+
+// Accept accepts a vistor to visit the tree, the operator is visited between the operands
+func (e *logicalExpression) Accept(visitor NodeVisitor) {
+	for i, n := range e.nodes {
+		if i > 0 {
+			visitor.VisitOperator(OperatorContext{op: Operator(e.operator)})
+		}
+		n.Accept(visitor)
+	}
+}
+
+func (e *logicalExpression) Children() []Node {
+	return e.nodes
+}
+
+func (e *logicalExpression) isRoot() bool {
+	return false
+}
+
+func (e *logicalExpression) MarshalJSON() ([]byte, error) {
+	return marshalNode(e)
+}
+
+func (e *logicalExpression) String() string {
+	return nodeString(e)
+}
+
+// CollapseLogical returns a copy of the expression where chains of the same logical operator
+// are collapsed into a single LogicalNode, e.g. `a==1,b==2,c==3` results in one OR with three operands
+// instead of nested binary nodes. Braces without effect on the chain are removed, labeled sub expressions are kept.
+func (e *Expression) CollapseLogical() Expression {
+	return rewriteExpression(*e, func(n Node) Node {
+		bin, ok := n.(*binaryExpression)
+		if !ok || !isLogicalOperator(bin.operator) {
+			return n
+		}
+		return &logicalExpression{operator: bin.operator, nodes: flattenLogical(bin, bin.operator, nil)}
+	})
+}
+
+// WithLogicalNodes collapses chains of the same logical operator into LogicalNodes after parsing,
+// see Expression.CollapseLogical
+func WithLogicalNodes() Option {
+	return func(p *Parser) {
+		p.logicalNodes = true
+	}
+}
@@ -0,0 +1,81 @@
+package fiqlparser
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCollapseLogical(t *testing.T) {
+	var values = []struct {
+		fiql     string
+		operands int
+		output   string
+	}{
+		{fiql: "a==1,b==2,c==3", operands: 3, output: "a==1,b==2,c==3"},
+		{fiql: "a==1;(b==2;(c==3;d==4))", operands: 4, output: "a==1;b==2;c==3;d==4"},
+		{fiql: "a==1;(b==2,c==3)", operands: 2, output: "a==1;(b==2,c==3)"},
+		{fiql: "a==1;x:(b==2;c==3)", operands: 2, output: "a==1;x:(b==2;c==3)"},
+	}
+	for _, v := range values {
+		res, err := Parse(context.Background(), v.fiql)
+		if !assert.NoError(t, err, v.fiql) {
+			continue
+		}
+		collapsed := res.CollapseLogical()
+		logical, ok := collapsed.Children()[0].(LogicalNode)
+		if assert.True(t, ok, v.fiql) {
+			assert.Len(t, logical.Operands(), v.operands, v.fiql)
+			assert.Equal(t, NodeTypeLogical, logical.NodeType())
+		}
+		assert.Equal(t, v.output, collapsed.ToFIQL(), v.fiql)
+		// the original tree is not modified
+		assert.Equal(t, v.fiql, res.ToFIQL(), v.fiql)
+	}
+}
+
+func TestCollapseLogicalKeepsSemantics(t *testing.T) {
+	sqlTranslator := &SQLTranslator{Columns: map[string]string{"a": "a", "b": "b", "c": "c", "d": "d"}}
+	esTranslator := &ElasticsearchTranslator{}
+	for _, fiql := range []string{"a==1,b==2,c==3", "a==1;(b==2;(c==3,d==4))", "a==1,a==2;b==3", "x:(a==1;b==2),c"} {
+		res, err := Parse(context.Background(), fiql)
+		if !assert.NoError(t, err, fiql) {
+			continue
+		}
+		collapsed := res.CollapseLogical()
+
+		sql, _, err := sqlTranslator.Translate(res)
+		assert.NoError(t, err)
+		csql, _, err := sqlTranslator.Translate(collapsed)
+		assert.NoError(t, err)
+		assert.Equal(t, sql, csql, fiql)
+
+		es, err := esTranslator.TranslateJSON(res)
+		assert.NoError(t, err)
+		ces, err := esTranslator.TranslateJSON(collapsed)
+		assert.NoError(t, err)
+		assert.JSONEq(t, string(es), string(ces), fiql)
+
+		assert.Equal(t, EstimateCost(res, testStatistics), EstimateCost(collapsed, testStatistics), fiql)
+		assert.Equal(t, canonicalFIQL(&res), canonicalFIQL(&collapsed), fiql)
+	}
+}
+
+func TestWithLogicalNodes(t *testing.T) {
+	res, err := New(WithLogicalNodes()).Parse(context.Background(), "a==1,b==2,c==3")
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, "(a == 1 OR b == 2 OR c == 3)", res.String())
+	v := &testVisitor{}
+	res.Accept(v)
+	assert.Equal(t, "(a==1ORb==2ORc==3)", v.String())
+	j, err := json.Marshal(&res)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"Type":"Expr","Operator":"","Nodes":[{"Type":"Logical","Operator":"OR","Nodes":[
+		{"Type":"Binary","Operator":"==","Nodes":[{"Type":"Const","Value":"a"},{"Type":"Const","Value":"1"}]},
+		{"Type":"Binary","Operator":"==","Nodes":[{"Type":"Const","Value":"b"},{"Type":"Const","Value":"2"}]},
+		{"Type":"Binary","Operator":"==","Nodes":[{"Type":"Const","Value":"c"},{"Type":"Const","Value":"3"}]}]}]}`, string(j))
+}
@@ -0,0 +1,266 @@
+package fiqlparser
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// LuceneTranslator translates a expression to a Lucene query string,
+// e.g. `name==Jo*;age=gt=18` becomes `name:Jo* AND age:{18 TO *}`,
+// so filters can be passed to bleve, Elasticsearch or OpenSearch query string queries.
+// Missing and present values are written as `_exists_:<field>`, which is supported by Elasticsearch and OpenSearch.
+// The zero value maps every selector to the field of the same name.
+type LuceneTranslator struct {
+	// Fields maps selectors to field names, they are used as is in the query
+	Fields map[string]string
+	// Strict rejects selectors which are not mapped in Fields
+	Strict bool
+	// Resolver resolves the field of selectors which are not mapped in Fields
+	Resolver SelectorResolver
+}
+
+// luceneField matches selectors which can be used as field names without escaping
+var luceneField = regexp.MustCompile(`^[A-Za-z_@][A-Za-z0-9_@]*(\.[A-Za-z_@][A-Za-z0-9_@]*)*$`)
+
+// luceneReserved are the characters escaped in terms
+const luceneReserved = `+-=&|><!(){}[]^"~*?:\/`
+
+// Translate translates the expression to a query string, the empty expression is translated to `*:*`
+func (t *LuceneTranslator) Translate(e Expression) (string, error) {
+	if e.node == nil {
+		return "*:*", nil
+	}
+	var b strings.Builder
+	if err := t.write(&b, &e); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+func (t *LuceneTranslator) field(selector string) (string, error) {
+	if f, ok := t.Fields[selector]; ok && f != "" {
+		return f, nil
+	}
+	if t.Resolver != nil {
+		return t.Resolver(selector)
+	}
+	if t.Strict || !luceneField.MatchString(selector) {
+		return "", fmt.Errorf("%w `%s`", ErrUnknownSelector, selector)
+	}
+	return selector, nil
+}
+
+func (t *LuceneTranslator) write(b *strings.Builder, n Node) error {
+	switch node := n.(type) {
+	case *Expression:
+		return t.write(b, node.node)
+	case *binaryExpression:
+		if node.IsLogical() {
+			return t.writeLogical(b, node)
+		}
+		return t.writePredicate(b, node)
+	case *logicalExpression:
+		return t.writeLogical(b, node)
+	case *notExpression:
+		b.WriteString("NOT (")
+		if err := t.write(b, node.node); err != nil {
+			return err
+		}
+		b.WriteRune(')')
+		return nil
+	case *constantExpression:
+		f, err := t.field(node.value)
+		if err != nil {
+			return err
+		}
+		b.WriteString("_exists_:")
+		b.WriteString(f)
+		return nil
+	}
+	return fmt.Errorf("unsupported node `%v`", n)
+}
+
+// writeLogical joins the operands with `AND` or `OR`, nested logical operations are enclosed in parentheses
+func (t *LuceneTranslator) writeLogical(b *strings.Builder, node Node) error {
+	operator, operands, _ := logicalOperands(node)
+	for i, o := range operands {
+		if i > 0 {
+			b.WriteRune(' ')
+			b.WriteString(operator)
+			b.WriteRune(' ')
+		}
+		_, _, nested := logicalOperands(unwrapExpression(o))
+		if nested {
+			b.WriteRune('(')
+		}
+		if err := t.write(b, o); err != nil {
+			return err
+		}
+		if nested {
+			b.WriteRune(')')
+		}
+	}
+	return nil
+}
+
+func (t *LuceneTranslator) writePredicate(b *strings.Builder, node *binaryExpression) error {
+	sel, arg, ok := predicateOperands(node)
+	if !ok {
+		return fmt.Errorf("incomplete comparison `%s`", node.String())
+	}
+	f, err := t.field(sel.value)
+	if err != nil {
+		return err
+	}
+	if arg.recommended == ValueRecommendationField {
+		return fmt.Errorf("field references are not supported by Lucene queries `@%s`", arg.value)
+	}
+	if arg.recommended == ValueRecommendationRange {
+		return writeLuceneBetween(b, f, node.operator, arg)
+	}
+	if arg.tuple != nil {
+		if node.operator != string(ComparisonIn) {
+			return fmt.Errorf("unsupported tuple comparison `%s`", node.operator)
+		}
+		b.WriteString(f)
+		b.WriteString(":(")
+		for i, el := range arg.tuple.elements {
+			if i > 0 {
+				b.WriteString(" OR ")
+			}
+			if err := writeLuceneTerm(b, el); err != nil {
+				return err
+			}
+		}
+		b.WriteRune(')')
+		return nil
+	}
+	switch node.operator {
+	case string(ComparisonEq):
+		return writeLuceneEquality(b, f, arg)
+	case string(ComparisonNeq):
+		if arg.recommended == ValueRecommendationNull && arg.quote == 0 {
+			b.WriteString("_exists_:")
+			b.WriteString(f)
+			return nil
+		}
+		b.WriteString("NOT ")
+		return writeLuceneEquality(b, f, arg)
+	case string(ComparisonGt), string(ComparisonGte), string(ComparisonLt), string(ComparisonLte):
+		v, err := luceneRangeValue(node.operator, arg)
+		if err != nil {
+			return err
+		}
+		switch node.operator {
+		case string(ComparisonGt):
+			fmt.Fprintf(b, "%s:{%s TO *}", f, v)
+		case string(ComparisonGte):
+			fmt.Fprintf(b, "%s:[%s TO *]", f, v)
+		case string(ComparisonLt):
+			fmt.Fprintf(b, "%s:{* TO %s}", f, v)
+		default:
+			fmt.Fprintf(b, "%s:[* TO %s]", f, v)
+		}
+		return nil
+	}
+	return fmt.Errorf("unsupported comparison `%s`", node.operator)
+}
+
+// writeLuceneBetween writes a inclusive range, `==` and `=bt=` match the range and `!=` excludes it
+func writeLuceneBetween(b *strings.Builder, f string, operator string, arg *constantExpression) error {
+	low, high, ok := argumentRangeBounds(arg.value, arg.tuple)
+	if !ok {
+		return fmt.Errorf("%w `%s`", ErrNoRange, arg.value)
+	}
+	switch operator {
+	case string(ComparisonEq), string(ComparisonBetween):
+	case string(ComparisonNeq):
+		b.WriteString("NOT ")
+	default:
+		return fmt.Errorf("unsupported range comparison `%s`", operator)
+	}
+	lv, err := luceneRangeValue(operator, low)
+	if err != nil {
+		return err
+	}
+	hv, err := luceneRangeValue(operator, high)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(b, "%s:[%s TO %s]", f, lv, hv)
+	return nil
+}
+
+// writeLuceneEquality writes a term query, `null` tests the absence of the field
+func writeLuceneEquality(b *strings.Builder, f string, arg *constantExpression) error {
+	if arg.recommended == ValueRecommendationNull && arg.quote == 0 {
+		b.WriteString("NOT _exists_:")
+		b.WriteString(f)
+		return nil
+	}
+	b.WriteString(f)
+	b.WriteRune(':')
+	return writeLuceneTerm(b, arg)
+}
+
+// writeLuceneTerm writes the argument as term, values with wildcards are escaped with backslashes,
+// other values containing reserved characters or whitespace are written as phrase
+func writeLuceneTerm(b *strings.Builder, arg *constantExpression) error {
+	if arg.quote == 0 && arg.recommended == ValueRecommendationDuration {
+		return fmt.Errorf("duration `%s` is not supported by Lucene queries", arg.value)
+	}
+	if arg.prefixWildcard || arg.suffixWildcard {
+		if arg.prefixWildcard {
+			b.WriteRune('*')
+		}
+		b.WriteString(escapeLuceneTerm(arg.value))
+		if arg.suffixWildcard {
+			b.WriteRune('*')
+		}
+		return nil
+	}
+	v := arg.value
+	if arg.quote == 0 && arg.recommended == ValueRecommendationDateTime {
+		tm, err := arg.Argument().AsTime()
+		if err != nil {
+			return err
+		}
+		v = tm.UTC().Format(time.RFC3339Nano)
+	}
+	b.WriteString(luceneValue(v))
+	return nil
+}
+
+// luceneRangeValue converts a bound of a range query, datetimes are converted to RFC 3339 in UTC
+func luceneRangeValue(operator string, arg *constantExpression) (string, error) {
+	if arg.prefixWildcard || arg.suffixWildcard {
+		return "", fmt.Errorf("wildcards are not supported by `%s`", fiqlOperators[operator])
+	}
+	var b strings.Builder
+	if err := writeLuceneTerm(&b, arg); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// luceneValue returns v as is if it needs no escaping, as phrase otherwise
+func luceneValue(v string) string {
+	if v != "" && !strings.ContainsAny(v, luceneReserved+" \t\r\n") {
+		return v
+	}
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(v) + `"`
+}
+
+// escapeLuceneTerm escapes the reserved characters and whitespace with backslashes
+func escapeLuceneTerm(v string) string {
+	var b strings.Builder
+	for _, r := range v {
+		if strings.ContainsRune(luceneReserved, r) || r == ' ' || r == '\t' || r == '\r' || r == '\n' {
+			b.WriteRune('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
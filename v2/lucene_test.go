@@ -0,0 +1,70 @@
+package fiqlparser
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLuceneTranslator(t *testing.T) {
+	var values = []struct {
+		fiql   string
+		lucene string
+		err    string
+	}{
+		{fiql: "name==Jo*;age=gt=18", lucene: "name:Jo* AND age:{18 TO *}"},
+		{fiql: "name==*oh*,name!=x", lucene: "name:*oh* OR NOT name:x"},
+		{fiql: "a==1;(b==2,c==3)", lucene: "a:1 AND (b:2 OR c:3)"},
+		{fiql: `name=="John Doe"`, lucene: `name:"John Doe"`},
+		{fiql: `path==a/b*`, lucene: `path:a\/b*`},
+		{fiql: `name=="x \"y\""*`, lucene: `name:x\ \"y\"*`},
+		{fiql: "age=ge=1;age=lt=5;age=le=9", lucene: "age:[1 TO *] AND age:{* TO 5} AND age:[* TO 9]"},
+		{fiql: "age==1..5", lucene: "age:[1 TO 5]"},
+		{fiql: "age!=1..5", lucene: "NOT age:[1 TO 5]"},
+		{fiql: "created=ge=2024-05-01T10:30:00+02:00", lucene: `created:["2024-05-01T08:30:00Z" TO *]`},
+		{fiql: "mail==null,phone!=null", lucene: "NOT _exists_:mail OR _exists_:phone"},
+		{fiql: "deleted", lucene: "_exists_:deleted"},
+		{fiql: "status=in=[open+closed*]", lucene: "status:(open OR closed*)"},
+		{fiql: "created=gt=-P1D", err: "duration `-P1D` is not supported by Lucene queries"},
+		{fiql: "name=q=john", err: "unsupported comparison `QUERY`"},
+		{fiql: "first-name==x", err: "unknown selector `first-name`"},
+		{fiql: "age=ge=1*", err: "wildcards are not supported by `=ge=`"},
+	}
+	translator := &LuceneTranslator{}
+	for _, v := range values {
+		res, err := Parse(context.Background(), v.fiql)
+		if !assert.NoError(t, err, v.fiql) {
+			continue
+		}
+		lucene, err := translator.Translate(res)
+		if v.err != "" {
+			assert.EqualError(t, err, v.err, v.fiql)
+			continue
+		}
+		if assert.NoError(t, err, v.fiql) {
+			assert.Equal(t, v.lucene, lucene, v.fiql)
+		}
+	}
+}
+
+func TestLuceneTranslatorOptions(t *testing.T) {
+	translator := &LuceneTranslator{
+		Fields: map[string]string{"name": "user.name"},
+		Resolver: func(selector string) (string, error) {
+			return "x_" + selector, nil
+		},
+	}
+	res, err := New(WithNegation()).Parse(context.Background(), "name==x;!(group==admins,group==ops)")
+	if !assert.NoError(t, err) {
+		return
+	}
+	lucene, err := translator.Translate(res)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "user.name:x AND NOT (x_group:admins OR x_group:ops)", lucene)
+	}
+
+	lucene, err = (&LuceneTranslator{}).Translate(Expression{})
+	assert.NoError(t, err)
+	assert.Equal(t, "*:*", lucene)
+}
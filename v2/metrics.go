@@ -0,0 +1,98 @@
+package fiqlparser
+
+import (
+	"context"
+	"fmt"
+	"unsafe"
+)
+
+// ErrorCodeBudgetExceeded is used if a parse exceeds the limits configured by WithBudget
+const ErrorCodeBudgetExceeded ErrorCode = "BudgetExceeded"
+
+// ParseMetrics are counted by the parser while parsing a single input,
+// they are cheap internal counters and do not rely on runtime metrics
+type ParseMetrics struct {
+	// Input is the length of the input in bytes
+	Input int
+	// Tokens is the number of tokens consumed
+	Tokens int
+	// Nodes is the number of nodes created
+	Nodes int
+	// Bytes estimates the memory of the created nodes (struct sizes plus value lengths)
+	Bytes int
+}
+
+// ParseBudget limits the work done for a single parse, zero fields are unlimited
+type ParseBudget struct {
+	MaxTokens int
+	MaxNodes  int
+	MaxBytes  int
+}
+
+// exceeded returns the name and limit of the first exceeded limit, if any
+func (b ParseBudget) exceeded(m ParseMetrics) (string, int, bool) {
+	switch {
+	case b.MaxTokens > 0 && m.Tokens > b.MaxTokens:
+		return "tokens", b.MaxTokens, true
+	case b.MaxNodes > 0 && m.Nodes > b.MaxNodes:
+		return "nodes", b.MaxNodes, true
+	case b.MaxBytes > 0 && m.Bytes > b.MaxBytes:
+		return "bytes", b.MaxBytes, true
+	}
+	return "", 0, false
+}
+
+// WithMetrics reports the metrics of every parse to fn, including failed ones
+func WithMetrics(fn func(ParseMetrics)) Option {
+	return func(p *Parser) {
+		p.metrics = fn
+	}
+}
+
+// WithBudget aborts parses exceeding b with ErrorCodeBudgetExceeded,
+// the budget is checked while parsing so pathological inputs fail early
+func WithBudget(b ParseBudget) Option {
+	return func(p *Parser) {
+		p.budget = b
+	}
+}
+
+// ParseWithMetrics parses the supplied fiql like Parse and additionally returns its metrics
+func (p *Parser) ParseWithMetrics(ctx context.Context, input string) (Expression, ParseMetrics, error) {
+	if err := ctx.Err(); err != nil {
+		return Expression{root: true}, ParseMetrics{}, err
+	}
+	lex := acquireLexer(input)
+	defer releaseLexer(lex)
+	exp, err := p.withInput(ctx, lex).parse()
+	return exp, lex.metrics, err
+}
+
+// sizes of the nodes created while parsing
+var (
+	expressionSize = int(unsafe.Sizeof(Expression{}))
+	constantSize   = int(unsafe.Sizeof(constantExpression{}))
+	binarySize     = int(unsafe.Sizeof(binaryExpression{}))
+	notSize        = int(unsafe.Sizeof(notExpression{}))
+	tupleSize      = int(unsafe.Sizeof(tupleArgument{}))
+	pointerSize    = int(unsafe.Sizeof(uintptr(0)))
+)
+
+// countNode records a created node of size bytes holding value
+func (p *lexer) countNode(size int, value string) {
+	p.metrics.Nodes++
+	p.metrics.Bytes += size + len(value)
+}
+
+// checkBudget returns a error once the budget is exceeded
+func (p *lexer) checkBudget() error {
+	if name, max, ok := p.budget.exceeded(p.metrics); ok {
+		return p.errBudgetExceeded(name, max)
+	}
+	return nil
+}
+
+// errBudgetExceeded is positioned at the token exceeding the budget
+func (p *lexer) errBudgetExceeded(name string, max int) *ParseError {
+	return p.newParseError(ErrorCodeBudgetExceeded, "", nil, fmt.Sprintf("parse budget exceeded (more than %d %s)", max, name))
+}
@@ -0,0 +1,84 @@
+package fiqlparser
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseWithMetrics(t *testing.T) {
+	var values = []struct {
+		input  string
+		tokens int
+		nodes  int
+	}{
+		{input: "", tokens: 0, nodes: 1},
+		{input: "a==b", tokens: 3, nodes: 4},
+		{input: "a==b;c==d", tokens: 7, nodes: 8},
+		{input: "(a==b,c)", tokens: 7, nodes: 7},
+		{input: "a=in=[x+y+z]", tokens: 3, nodes: 7},
+	}
+	p := New()
+	for _, v := range values {
+		_, m, err := p.ParseWithMetrics(context.Background(), v.input)
+		if !assert.NoError(t, err, v.input) {
+			continue
+		}
+		assert.Equal(t, len(v.input), m.Input, v.input)
+		assert.Equal(t, v.tokens, m.Tokens, v.input)
+		assert.Equal(t, v.nodes, m.Nodes, v.input)
+		assert.Greater(t, m.Bytes, m.Nodes, v.input)
+	}
+}
+
+func TestWithMetrics(t *testing.T) {
+	var got []ParseMetrics
+	p := New(WithMetrics(func(m ParseMetrics) { got = append(got, m) }))
+	_, err := p.Parse(context.Background(), "a==b")
+	assert.NoError(t, err)
+	_, err = p.Parse(context.Background(), "a==b;")
+	assert.Error(t, err)
+	if assert.Len(t, got, 2) {
+		assert.Equal(t, 3, got[0].Tokens)
+		assert.Equal(t, 4, got[1].Tokens)
+	}
+}
+
+func TestWithBudget(t *testing.T) {
+	var values = []struct {
+		input  string
+		budget ParseBudget
+		err    bool
+	}{
+		{input: "a==b;c==d", budget: ParseBudget{MaxTokens: 7}},
+		{input: "a==b;c==d", budget: ParseBudget{MaxTokens: 6}, err: true},
+		{input: "a==b;c==d", budget: ParseBudget{MaxNodes: 8}},
+		{input: "a==b;c==d", budget: ParseBudget{MaxNodes: 7}, err: true},
+		{input: "a=in=[x+y+z]", budget: ParseBudget{MaxNodes: 6}, err: true},
+		{input: "a==b", budget: ParseBudget{MaxBytes: 1 << 20}},
+		{input: "a==b", budget: ParseBudget{MaxBytes: 1}, err: true},
+		{input: "a==b", budget: ParseBudget{}},
+	}
+	for _, v := range values {
+		_, err := New(WithBudget(v.budget)).Parse(context.Background(), v.input)
+		if !v.err {
+			assert.NoError(t, err, v.input)
+			continue
+		}
+		var perr *ParseError
+		if assert.True(t, errors.As(err, &perr), v.input) {
+			assert.Equal(t, ErrorCodeBudgetExceeded, perr.Code, v.input)
+		}
+	}
+}
+
+func TestWithBudgetStopsEarly(t *testing.T) {
+	var m ParseMetrics
+	input := "a==b" + strings.Repeat(";a==b", 1000)
+	_, err := New(WithBudget(ParseBudget{MaxTokens: 10}), WithMetrics(func(pm ParseMetrics) { m = pm })).Parse(context.Background(), input)
+	assert.Error(t, err)
+	assert.Equal(t, 11, m.Tokens)
+}
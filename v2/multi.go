@@ -0,0 +1,52 @@
+package fiqlparser
+
+import (
+	"context"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// forEachLine calls fn for every non-empty line with the position of its start within the input
+func forEachLine(input string, fn func(start Position, line string) error) error {
+	offset, byteOffset := 0, 0
+	for i, line := range strings.Split(input, "\n") {
+		start := Position{Line: i + 1, Offset: offset, ByteOffset: byteOffset}
+		offset += utf8.RuneCountInString(line) + 1
+		byteOffset += len(line) + 1
+		line = strings.TrimSuffix(line, "\r")
+		if strings.TrimFunc(line, unicode.IsSpace) == "" {
+			continue
+		}
+		if err := fn(start, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ParseMulti parses every non-empty line of the input as a separate expression,
+// this is intended for files containing one filter per line.
+// Parsing stops at the first invalid line, the error reports the line within the whole input.
+// All expressions parsed up to that point are returned alongside the error.
+func (p *Parser) ParseMulti(ctx context.Context, input string) ([]Expression, error) {
+	res := make([]Expression, 0)
+	err := forEachLine(input, func(start Position, line string) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		exp, err := p.withInput(ctx, newLineLexer(line, start)).parse()
+		if err != nil {
+			return err
+		}
+		res = append(res, exp)
+		return nil
+	})
+	return res, err
+}
+
+// ParseMulti instant parses every non-empty line of the input as a separate expression
+// with a parser configured by opts
+func ParseMulti(ctx context.Context, input string, opts ...Option) ([]Expression, error) {
+	return New(opts...).ParseMulti(ctx, input)
+}
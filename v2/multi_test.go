@@ -0,0 +1,40 @@
+package fiqlparser
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMulti(t *testing.T) {
+	input := "a==b;c==d\n\n   \r\n(e==f,g==h)\r\ni=gt=1\n"
+	res, err := ParseMulti(context.Background(), input)
+	assert.NoError(t, err)
+	if !assert.Len(t, res, 3) {
+		return
+	}
+	assert.Equal(t, "(a == b AND c == d)", res[0].String())
+	assert.Equal(t, "((e == f OR g == h))", res[1].String())
+	assert.Equal(t, "(i > 1)", res[2].String())
+}
+
+func TestParseMultiEmpty(t *testing.T) {
+	res, err := ParseMulti(context.Background(), "\n  \n")
+	assert.NoError(t, err)
+	assert.Empty(t, res)
+}
+
+func TestParseMultiErrorAttribution(t *testing.T) {
+	p := New()
+	res, err := p.ParseMulti(context.Background(), "a==b\n\nc==d;\ne==f")
+	assert.EqualError(t, err, "ln:3:5 dangling operator")
+	var perr *ParseError
+	if assert.True(t, errors.As(err, &perr)) {
+		assert.Equal(t, 3, perr.Line)
+		assert.Equal(t, 5, perr.Column)
+		assert.Equal(t, 11, perr.Offset)
+	}
+	assert.Len(t, res, 1)
+}
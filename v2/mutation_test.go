@@ -0,0 +1,131 @@
+package fiqlparser
+
+import (
+	"context"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// mutationTolerance is the maximum distance (in runes) an error
+// may be reported away from the position of the mutation
+const mutationTolerance = 8
+
+var mutationCorpus = []string{
+	"column==value",
+	"column!=value",
+	"column=ge=1",
+	"column=lt=-100",
+	"updated=gt=2003-12-13T00:00:00Z",
+	"column==value,b==a",
+	"column==va\\,lue,b==a",
+	"title==foo*;(updated=lt=-P1D,title==*bar)",
+	"(title==foo*);(fml==x,(xfs==a;f==fx))",
+	"(title==foo*,test==a,fx==fa);(fml==x)",
+	"columnA,(columnB==c;columnC)",
+	"a==b;c==d,f==g",
+	"(a==b;c==d),f==g",
+}
+
+// mutation is a near-valid invalid expression derived from a valid one,
+// detectable marks the last position the error can possibly be detected at
+// (e.g. a missing closing brace is only noticed at the end of the input)
+type mutation struct {
+	kind       string
+	input      string
+	offset     int
+	detectable int
+}
+
+type mutator func(input []rune) []mutation
+
+// dropClosingBrace removes each closing brace in turn
+func dropClosingBrace(input []rune) []mutation {
+	res := make([]mutation, 0)
+	for i, r := range input {
+		if r != ')' {
+			continue
+		}
+		m := append(append([]rune{}, input[:i]...), input[i+1:]...)
+		res = append(res, mutation{kind: "drop brace", input: string(m), offset: i, detectable: len(m)})
+	}
+	return res
+}
+
+// dropOpeningBrace removes each opening brace in turn, the unmatched closing brace
+// must not be ignored as trailing input
+func dropOpeningBrace(input []rune) []mutation {
+	res := make([]mutation, 0)
+	for i, r := range input {
+		if r != '(' {
+			continue
+		}
+		m := append(append([]rune{}, input[:i]...), input[i+1:]...)
+		res = append(res, mutation{kind: "drop opening brace", input: string(m), offset: i, detectable: len(m)})
+	}
+	return res
+}
+
+// duplicateOperator doubles each logical operator in turn
+func duplicateOperator(input []rune) []mutation {
+	res := make([]mutation, 0)
+	for i, r := range input {
+		if r != ';' && r != ',' || (i > 0 && input[i-1] == '\\') {
+			continue
+		}
+		m := append(append([]rune{}, input[:i+1]...), input[i:]...)
+		res = append(res, mutation{kind: "duplicate operator", input: string(m), offset: i + 1, detectable: i + 1})
+	}
+	return res
+}
+
+// truncate cuts the input directly after and inside of each comparison
+// as well as directly after each logical operator
+func truncate(input []rune) []mutation {
+	res := make([]mutation, 0)
+	s := string(input)
+	for _, loc := range regexp.MustCompile(`==|!=|=[a-z]+=`).FindAllStringIndex(s, -1) {
+		start := len([]rune(s[:loc[0]]))
+		end := len([]rune(s[:loc[1]]))
+		for i := start + 1; i <= end; i++ {
+			res = append(res, mutation{kind: "truncate", input: string(input[:i]), offset: i, detectable: i})
+		}
+	}
+	for i, r := range input {
+		if (r == ';' || r == ',') && (i == 0 || input[i-1] != '\\') {
+			res = append(res, mutation{kind: "truncate", input: string(input[:i+1]), offset: i + 1, detectable: i + 1})
+		}
+	}
+	return res
+}
+
+var errorPositionRegex = regexp.MustCompile(`^ln:(\d+):(\d+) `)
+
+func TestMutatedErrorPositions(t *testing.T) {
+	mutators := []mutator{dropClosingBrace, dropOpeningBrace, duplicateOperator, truncate}
+	for _, valid := range mutationCorpus {
+		_, err := Parse(context.Background(), valid)
+		if !assert.NoError(t, err, "corpus entry `%s` must be valid", valid) {
+			continue
+		}
+		for _, mutate := range mutators {
+			for _, m := range mutate([]rune(valid)) {
+				_, err := Parse(context.Background(), m.input)
+				if !assert.Error(t, err, "%s: `%s` should not parse", m.kind, m.input) {
+					continue
+				}
+				pos := errorPositionRegex.FindStringSubmatch(err.Error())
+				if !assert.NotNil(t, pos, "%s: `%s` error is missing a position (%s)", m.kind, m.input, err) {
+					continue
+				}
+				assert.Equal(t, "1", pos[1], "%s: `%s` wrong line (%s)", m.kind, m.input, err)
+				col, _ := strconv.Atoi(pos[2])
+				inRange := col >= m.offset-mutationTolerance && col <= m.detectable+mutationTolerance
+				assert.True(t, inRange, "%s: `%s` error too far away from mutation at %d (%s)\n%s^", m.kind, m.input, m.offset, err, strings.Repeat(" ", m.offset))
+			}
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package fiqlparser
+
+// NodeTypeUnaryLogical is a logical operation with a single operand, the negation (NOT)
+const NodeTypeUnaryLogical NodeType = "UnaryLogical"
+
+// OperatorNOT negates a sub expression, see WithNegation
+const OperatorNOT Operator = "NOT"
+
+// NegationNode negates its operand, a sub expression, it is produced by the WithNegation option
+type NegationNode interface {
+	Node
+	// Operator returns OperatorNOT
+	Operator() string
+	// Operand returns the negated sub expression
+	Operand() Node
+}
+
+var _ NegationNode = &notExpression{}
+
+// WithNegation enables negated sub expressions prefixed by `!`, e.g. `!(a==1;b==2)`,
+// which result in a NegationNode (NodeTypeUnaryLogical).
+// Visitors are notified by VisitOperator with OperatorNOT right before the negated sub expression is entered,
+// so visitors used with this option have to handle OperatorNOT.
+func WithNegation() Option {
+	return func(p *Parser) {
+		p.negation = true
+	}
+}
+
+type notExpression struct {
+	node Node
+}
+
+// Operator returns OperatorNOT
+func (e *notExpression) Operator() string {
+	return string(OperatorNOT)
+}
+
+// Operand returns the negated sub expression
+func (e *notExpression) Operand() Node {
+	return e.node
+}
+
+func (e *notExpression) NodeType() NodeType {
+	return NodeTypeUnaryLogical
+}
+
+// Add sets the operand, it will panic if the operand is already set
+//
+// Deprecated: use NewNegation, which returns a error instead of panicking.
+func (e *notExpression) Add(node Node) {
+	if e.node != nil {
+		panic("negation may not have more than one operand")
+	}
+	e.node = node
+}
+
+// Accept accepts a vistor to visit the tree, the operator is visited before the operand
+func (e *notExpression) Accept(visitor NodeVisitor) {
+	visitor.VisitOperator(OperatorContext{op: OperatorNOT})
+	if e.node != nil {
+		e.node.Accept(visitor)
+	}
+}
+
+func (e *notExpression) Children() []Node {
+	return []Node{e.node}
+}
+
+func (e *notExpression) isRoot() bool {
+	return false
+}
+
+func (e *notExpression) MarshalJSON() ([]byte, error) {
+	return marshalNode(e)
+}
+
+func (e *notExpression) String() string {
+	return nodeString(e)
+}
@@ -0,0 +1,98 @@
+package fiqlparser
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNegation(t *testing.T) {
+	parser := New(WithNegation())
+	var values = []struct {
+		fiql        string
+		stringOuput string
+		visited     string
+		fiqlOutput  string
+	}{
+		{fiql: "!(a==1;b==2)", stringOuput: "(NOT (a == 1 AND b == 2))", visited: "(NOT(a==1ANDb==2))", fiqlOutput: "!(a==1;b==2)"},
+		{fiql: "c==3;!(a==1,b==2)", stringOuput: "(c == 3 AND NOT (a == 1 OR b == 2))", visited: "(c==3ANDNOT(a==1ORb==2))", fiqlOutput: "c==3;!(a==1,b==2)"},
+		{fiql: "!(a==1),c==3", stringOuput: "(NOT (a == 1) OR c == 3)", visited: "(NOT(a==1)ORc==3)", fiqlOutput: "!(a==1),c==3"},
+		{fiql: "!(!(a==1))", stringOuput: "(NOT (NOT (a == 1)))", visited: "(NOT(NOT(a==1)))", fiqlOutput: "!(!(a==1))"},
+		{fiql: " ! (a==1)", stringOuput: "", visited: ""},
+		{fiql: "a!=1", stringOuput: "(a <> 1)", visited: "(a<>1)", fiqlOutput: "a!=1"},
+	}
+	for _, v := range values {
+		res, err := parser.Parse(context.Background(), v.fiql)
+		if v.stringOuput == "" {
+			assert.Error(t, err, v.fiql)
+			continue
+		}
+		if !assert.NoError(t, err, v.fiql) {
+			continue
+		}
+		assert.Equal(t, v.stringOuput, res.String(), v.fiql)
+		visitor := &testVisitor{}
+		res.Accept(visitor)
+		assert.Equal(t, v.visited, visitor.String(), v.fiql)
+		assert.Equal(t, v.fiqlOutput, res.ToFIQL(), v.fiql)
+		again, err := parser.Parse(context.Background(), res.ToFIQL())
+		if assert.NoError(t, err, v.fiql) {
+			assert.True(t, res.Equal(&again), v.fiql)
+		}
+	}
+
+	_, err := Parse(context.Background(), "!(a==1)")
+	assert.Error(t, err)
+}
+
+func TestNegationNode(t *testing.T) {
+	res, err := New(WithNegation()).Parse(context.Background(), "!(a==1)")
+	if !assert.NoError(t, err) {
+		return
+	}
+	not, ok := res.Children()[0].(NegationNode)
+	if assert.True(t, ok) {
+		assert.Equal(t, NodeTypeUnaryLogical, not.NodeType())
+		assert.Equal(t, string(OperatorNOT), not.Operator())
+		assert.Equal(t, NodeTypeExpression, not.Operand().NodeType())
+	}
+	b, err := json.Marshal(&res)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"Type":"Expr","Operator":"","Nodes":[{"Type":"UnaryLogical","Operator":"NOT","Nodes":[{"Type":"Expr","Operator":"","Nodes":[{"Type":"Binary","Operator":"==","Nodes":[{"Type":"Const","Value":"a"},{"Type":"Const","Value":"1"}]}]}]}]}`, string(b))
+
+	frozen := res.Freeze()
+	thawed := frozen.Thaw()
+	assert.Equal(t, res.String(), thawed.String())
+	visitor := &testVisitor{}
+	frozen.Accept(visitor)
+	assert.Equal(t, "(NOT(a==1))", visitor.String())
+}
+
+func TestNegationTranslation(t *testing.T) {
+	res, err := New(WithNegation()).Parse(context.Background(), "name==a;!(age==1,status==b)")
+	if !assert.NoError(t, err) {
+		return
+	}
+	sql, args, err := (&SQLTranslator{Columns: testSQLColumns}).Translate(res)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "users.name = ? AND NOT (users.age = ? OR users.status = ?)", sql)
+		assert.Equal(t, []interface{}{"a", int64(1), "b"}, args)
+	}
+	q, err := (&ElasticsearchTranslator{}).TranslateJSON(res)
+	if assert.NoError(t, err) {
+		assert.JSONEq(t, `{"bool":{"filter":[{"term":{"name":"a"}},{"bool":{"must_not":[{"bool":{"minimum_should_match":1,"should":[{"term":{"age":"1"}},{"term":{"status":"b"}}]}}]}}]}}`, string(q))
+	}
+
+	optimized, err := res.Optimize()
+	if assert.NoError(t, err) {
+		assert.Equal(t, res.ToFIQL(), optimized.ToFIQL())
+	}
+	res, err = New(WithNegation()).Parse(context.Background(), "name==a;!(age==1;age!=1)")
+	if assert.NoError(t, err) {
+		optimized, err := res.Optimize()
+		assert.NoError(t, err)
+		assert.Equal(t, "name==a", optimized.ToFIQL())
+	}
+}
@@ -0,0 +1,21 @@
+package fiqlparser
+
+// NullSemantics defines whether translators and FilterWithOptions let negated comparisons (e.g. `status!=open`)
+// match records where the selector is null or missing
+type NullSemantics int
+
+// NullSemanticsDefault keeps the behaviour of the backend,
+// SQL and Filter exclude nulls (three-valued logic) while elasticsearch includes missing fields
+const NullSemanticsDefault NullSemantics = 0
+
+// NullSemanticsInclude lets negated comparisons match nulls
+const NullSemanticsInclude NullSemantics = 1
+
+// NullSemanticsExclude never lets negated comparisons match nulls
+const NullSemanticsExclude NullSemantics = 2
+
+// isNullSensitive checks if the comparison is negated and therefore affected by the null semantics,
+// explicit null comparisons (`a!=null`) are not
+func isNullSensitive(operator string, arg *constantExpression) bool {
+	return operator == string(ComparisonNeq) && arg.recommended != ValueRecommendationNull
+}
@@ -0,0 +1,143 @@
+package fiqlparser
+
+import "errors"
+
+// ErrUnsatisfiable is returned by Optimize if the expression can never match
+var ErrUnsatisfiable = errors.New("expression can never match")
+
+// Optimize returns a simplified copy of the expression, e.g. before translating it:
+//   - duplicate operands of logical operations are removed (`a==1,a==1` results in `a==1`)
+//   - absorbed operands are removed (`a==1;(a==1,b==2)` results in `a==1`)
+//   - AND operations containing `s==v` and `s!=v` are contradictions, they are removed from OR operations
+//   - empty sub expressions are removed and braces without effect are unwrapped
+//
+// The order of the remaining operands is kept, labeled sub expressions are kept.
+// Tautologies like `s==v,s!=v` are not removed as they do not match if the selector is null or missing.
+// If the whole expression is a contradiction ErrUnsatisfiable is returned, e.g. to skip the query.
+func (e *Expression) Optimize() (Expression, error) {
+	n, ok := optimizeNode(e.node)
+	if !ok {
+		return Expression{root: true}, ErrUnsatisfiable
+	}
+	res := *e
+	res.node = n
+	return res, nil
+}
+
+// optimizeNode returns the simplified copy of n, nil if n is empty
+// and false if n is a contradiction
+func optimizeNode(n Node) (Node, bool) {
+	switch node := n.(type) {
+	case *Expression:
+		if node.node == nil {
+			return nil, true
+		}
+		inner, ok := optimizeNode(node.node)
+		if !ok || inner == nil || node.label == "" {
+			return inner, ok
+		}
+		c := *node
+		c.node = inner
+		return &c, true
+	case *notExpression:
+		// the negation of a contradiction matches everything like a empty expression
+		inner, ok := optimizeNode(node.node)
+		if !ok || inner == nil {
+			return nil, true
+		}
+		if _, sub := inner.(*Expression); !sub {
+			inner = newSubExpression(inner)
+		}
+		return &notExpression{node: inner}, true
+	case *binaryExpression, *logicalExpression:
+		if op, ok := logicalOperator(n); ok {
+			return optimizeLogical(n, op)
+		}
+	}
+	return rewriteNode(n, func(n Node) Node { return n }), true
+}
+
+// optimizeLogical simplifies the flattened operands of a logical operation
+func optimizeLogical(n Node, operator string) (Node, bool) {
+	and := operator == string(OperatorAND)
+	operands := make([]Node, 0)
+	keys := make(map[string]bool)
+	contradiction := false
+	for _, o := range flattenLogical(n, operator, nil) {
+		c, ok := optimizeNode(o)
+		if !ok {
+			if and {
+				return nil, false
+			}
+			contradiction = true
+			continue
+		}
+		if c == nil {
+			continue
+		}
+		// unwrapped braces may result in operands of the same operator
+		for _, f := range flattenLogical(c, operator, nil) {
+			if key := canonicalFIQL(f); !keys[key] {
+				keys[key] = true
+				operands = append(operands, f)
+			}
+		}
+	}
+	if and && isContradiction(operands, keys) {
+		return nil, false
+	}
+	operands = removeAbsorbed(operator, operands, keys)
+	switch len(operands) {
+	case 0:
+		return nil, !contradiction
+	case 1:
+		return operands[0], true
+	}
+	for i, o := range operands {
+		if op, ok := logicalOperator(o); ok && op != operator {
+			operands[i] = newSubExpression(o)
+		}
+	}
+	if _, ok := n.(*logicalExpression); ok {
+		return &logicalExpression{operator: operator, nodes: operands}, true
+	}
+	res := operands[len(operands)-1]
+	for i := len(operands) - 2; i >= 0; i-- {
+		res = newBinary(operator, operands[i], res)
+	}
+	return res, true
+}
+
+// isContradiction reports whether the operands of a AND operation contain `s==v` and `s!=v`,
+// keys are the canonical forms of the operands
+func isContradiction(operands []Node, keys map[string]bool) bool {
+	for _, o := range operands {
+		if bin, ok := o.(*binaryExpression); ok && bin.operator == string(ComparisonEq) {
+			if keys[canonicalFIQL(newBinary(string(ComparisonNeq), bin.nodes[0], bin.nodes[1]))] {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// removeAbsorbed removes operands of the dual operator which contain another operand,
+// e.g. `(a==1,b==2)` from `a==1;(a==1,b==2)`
+func removeAbsorbed(operator string, operands []Node, keys map[string]bool) []Node {
+	res := operands[:0]
+	for _, o := range operands {
+		absorbed := false
+		if op, dual, ok := logicalOperands(o); ok && op != operator {
+			for _, d := range dual {
+				if keys[canonicalFIQL(d)] {
+					absorbed = true
+					break
+				}
+			}
+		}
+		if !absorbed {
+			res = append(res, o)
+		}
+	}
+	return res
+}
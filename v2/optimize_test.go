@@ -0,0 +1,73 @@
+package fiqlparser
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOptimize(t *testing.T) {
+	var values = []struct {
+		fiql      string
+		optimized string
+	}{
+		{fiql: "a==1,a==1", optimized: "a==1"},
+		{fiql: "a==1;b==2;a==1", optimized: "a==1;b==2"},
+		{fiql: `a==1;a=="1"`, optimized: "a==1"},
+		{fiql: `a=="null",a==null`, optimized: `a=="null",a==null`},
+		{fiql: `a=="1..5",a==1..5`, optimized: `a=="1..5",a==1..5`},
+		{fiql: `a=="null",a=='null'`, optimized: `a=="null"`},
+		{fiql: `a=="true";a!=true`, optimized: `a=="true";a!=true`},
+		{fiql: "((a==1));b==2", optimized: "a==1;b==2"},
+		{fiql: "a==1;(b==2;c==3)", optimized: "a==1;b==2;c==3"},
+		{fiql: "a==1;(a==1,b==2)", optimized: "a==1"},
+		{fiql: "a==1,(a==1;b==2)", optimized: "a==1"},
+		{fiql: "(a==1,b==2);(b==2,a==1)", optimized: "a==1,b==2"},
+		{fiql: "c==3;(a==1,b==2);(b==2,a==1)", optimized: "c==3;(a==1,b==2)"},
+		{fiql: "(a==1;a!=1),b==2", optimized: "b==2"},
+		{fiql: "(a==1;a!=1),(b==2;c==3)", optimized: "b==2;c==3"},
+		{fiql: "a==1;(b==2;b!=2,c==3)", optimized: "a==1;c==3"},
+		{fiql: "a==1,a!=1", optimized: "a==1,a!=1"},
+		{fiql: "a==1;a!=2", optimized: "a==1;a!=2"},
+		{fiql: "x:(a==1;b==2);a==1", optimized: "x:(a==1;b==2);a==1"},
+		{fiql: "x:(a==1,a==1)", optimized: "x:(a==1)"},
+		{fiql: "a=gt=1", optimized: "a=gt=1"},
+		{fiql: "", optimized: ""},
+	}
+	for _, v := range values {
+		for _, parser := range []*Parser{New(), New(WithLogicalNodes())} {
+			res, err := parser.Parse(context.Background(), v.fiql)
+			if !assert.NoError(t, err, v.fiql) {
+				continue
+			}
+			before := res.ToFIQL()
+			optimized, err := res.Optimize()
+			if assert.NoError(t, err, v.fiql) {
+				assert.Equal(t, v.optimized, optimized.ToFIQL(), v.fiql)
+			}
+			// the original tree is not modified
+			assert.Equal(t, before, res.ToFIQL(), v.fiql)
+		}
+	}
+}
+
+func TestOptimizeUnsatisfiable(t *testing.T) {
+	for _, fiql := range []string{"a==1;a!=1", "b==2;(a==1;a!=1)", "(a==null;a!=null),(b==*x;b!=*x)"} {
+		res, err := Parse(context.Background(), fiql)
+		if !assert.NoError(t, err, fiql) {
+			continue
+		}
+		_, err = res.Optimize()
+		assert.ErrorIs(t, err, ErrUnsatisfiable, fiql)
+	}
+}
+
+func TestOptimizeEmptySubExpressions(t *testing.T) {
+	e := Expression{root: true, node: newBinary(string(OperatorAND), newSubExpression(nil),
+		newBinary(string(OperatorOR), newSubExpression(nil), newBinary(string(ComparisonEq), &constantExpression{value: "a", selector: true}, &constantExpression{value: "1"})))}
+	res, err := e.Optimize()
+	if assert.NoError(t, err) {
+		assert.Equal(t, "a==1", res.ToFIQL())
+	}
+}
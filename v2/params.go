@@ -0,0 +1,128 @@
+package fiqlparser
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// ValueRecommendationParameter suggests a named parameter (`:since`) which is bound later, see WithParameters
+const ValueRecommendationParameter ValueRecommendation = "parameter"
+
+// ErrUnboundParameter is generated by Bind if no value is supplied for a parameter
+var ErrUnboundParameter = errors.New("unbound parameter")
+
+var parameterNameRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// WithParameters enables named parameters prefixed by `:` as arguments, e.g. `created=gt=:since`,
+// so filter templates can be stored and bound to user values later (see Expression.Bind).
+// The argument is recommended as ValueRecommendationParameter, its value is the name of the parameter
+// (see ArgumentContext.Parameter). Names consist of letters, digits and underscores.
+// Quoted values (`":since"`) remain literals, tuple elements can not be parameters.
+func WithParameters() Option {
+	return func(p *Parser) {
+		p.parameters = true
+	}
+}
+
+// Parameter returns the name of the parameter if the argument is a named parameter (see WithParameters)
+func (c ArgumentContext) Parameter() (string, bool) {
+	if c.r != ValueRecommendationParameter {
+		return "", false
+	}
+	return c.val, true
+}
+
+// isParameter reports whether the consumed value is a named parameter
+func (p *Parser) isParameter(prefixWildcard bool) bool {
+	return p.parameters && !prefixWildcard && p.lex.lastQuote() == 0 && strings.HasPrefix(p.lex.lastValue(), ":")
+}
+
+// handleParameter creates the argument for the consumed named parameter
+func (p *Parser) handleParameter(pos Position) (Node, error) {
+	raw := p.lex.lastValue()
+	if !parameterNameRegex.MatchString(raw[1:]) {
+		return nil, p.lex.errInvalidValue(raw, []string{"parameter name"})
+	}
+	param := &constantExpression{value: raw[1:], recommended: ValueRecommendationParameter, pos: pos,
+		layout: p.anyDateTimeLayout, loc: p.location}
+	p.lex.countNode(constantSize, param.value)
+	n, _, err := p.lex.PeekNextToken()
+	if err != nil {
+		return nil, err
+	}
+	if n == tokenWildcard {
+		return nil, p.lex.errInvalidValue(raw+"*", []string{"parameter name"})
+	}
+	return param, nil
+}
+
+// Parameters returns the names of the named parameters in order of their first occurrence
+func (e *Expression) Parameters() []string {
+	names := make([]string, 0)
+	seen := make(map[string]bool)
+	Walk(e, func(n Node) bool {
+		if c, ok := n.(*constantExpression); ok && c.recommended == ValueRecommendationParameter && !seen[c.value] {
+			seen[c.value] = true
+			names = append(names, c.value)
+		}
+		return true
+	})
+	return names
+}
+
+// Bind returns a copy of the expression where the named parameters are replaced by the supplied values,
+// e.g. `created=gt=:since` becomes `created=gt=2024-05-01`. Values are taken literally (they are never parsed
+// as FIQL, so binding user input is safe) and typed like a parsed argument, including the datetime layouts and
+// the default location of the parser (see WithDateTimeLayouts and WithDefaultLocation). They have to satisfy the comparison
+// as a parsed argument would, e.g. a number, date or duration for `=gt=`, otherwise a error wrapping
+// ErrInvalidArgumentType is returned. A error wrapping ErrUnboundParameter is returned if a value is missing,
+// values without parameter are ignored.
+func (e *Expression) Bind(values map[string]string) (Expression, error) {
+	var err error
+	res := rewriteExpression(*e, func(n Node) Node {
+		bin, ok := n.(*binaryExpression)
+		if !ok || err != nil {
+			return n
+		}
+		_, arg, ok := predicateOperands(bin)
+		if !ok || arg.recommended != ValueRecommendationParameter {
+			return n
+		}
+		v, ok := values[arg.value]
+		if !ok {
+			err = fmt.Errorf("%w `:%s`", ErrUnboundParameter, arg.value)
+			return n
+		}
+		bound := arg.bind(v)
+		if msg := comparisonRequirement(Comparison(bin.operator), bound); msg != "" {
+			err = fmt.Errorf("%w for `:%s`: %s", ErrInvalidArgumentType, arg.value, msg)
+			return n
+		}
+		bin.nodes[1] = bound
+		return bin
+	})
+	if err != nil {
+		return *e, err
+	}
+	return res, nil
+}
+
+// bind returns the argument replacing the parameter or dynamic argument, it is typed with the datetime layouts
+// of the parser and keeps its default location (see WithDateTimeLayouts and WithDefaultLocation)
+func (c *constantExpression) bind(value string) *constantExpression {
+	_, rec, _ := layoutValidator(defaultValidator, c.layout)(value)
+	bound := &constantExpression{value: value, recommended: rec, pos: c.pos}
+	if rec != ValueRecommendationDateTime {
+		return bound
+	}
+	if c.layout != nil {
+		if _, ok := c.layout(value, time.UTC); ok {
+			bound.layout = c.layout
+		}
+	}
+	bound.loc = c.loc
+	return bound
+}
@@ -0,0 +1,151 @@
+package fiqlparser
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParameters(t *testing.T) {
+	var values = []struct {
+		input    string
+		expected string
+		fiql     string
+		err      bool
+	}{
+		{input: "created=gt=:since", expected: "(created > :since)", fiql: "created=gt=:since"},
+		{input: "a==:x;b!=:y_2,c=bt=:range", expected: "(a == :x AND b <> :y_2 OR c BETWEEN :range)", fiql: "a==:x;b!=:y_2,c=bt=:range"},
+		{input: `a==":x"`, expected: `(a == ":x")`, fiql: `a==":x"`},
+		{input: "a==*:x", expected: "(a == *:x)", fiql: `a==*":x"`},
+		{input: "a==:", err: true},
+		{input: "a==:1x", err: true},
+		{input: "a==:x*", err: true},
+		{input: "a=in=[:x+b]", expected: "(a IN [:x+b])", fiql: "a=in=[:x+b]"},
+	}
+	p := New(WithParameters())
+	for _, v := range values {
+		res, err := p.Parse(context.Background(), v.input)
+		if v.err {
+			assert.Error(t, err, v.input)
+			continue
+		}
+		if assert.NoError(t, err, v.input) {
+			assert.Equal(t, v.expected, res.String(), v.input)
+			assert.Equal(t, v.fiql, res.ToFIQL(), v.input)
+		}
+	}
+
+	res, err := p.Parse(context.Background(), "created=gt=:since")
+	if assert.NoError(t, err) {
+		var pred Predicate
+		Walk(&res, func(n Node) bool {
+			if pr, ok := predicateOf(n); ok {
+				pred = pr
+			}
+			return true
+		})
+		name, ok := pred.Argument.Parameter()
+		assert.True(t, ok)
+		assert.Equal(t, "since", name)
+		assert.Equal(t, ValueRecommendationParameter, pred.Argument.ValueRecommendation())
+	}
+
+	// without the option `:` is part of a literal, which is quoted to keep it one
+	res, err = Parse(context.Background(), "a==:x")
+	if assert.NoError(t, err) {
+		assert.Empty(t, res.Parameters())
+		assert.Equal(t, `a==":x"`, res.ToFIQL())
+	}
+}
+
+func TestBind(t *testing.T) {
+	var values = []struct {
+		input    string
+		values   map[string]string
+		expected string
+		err      error
+	}{
+		{input: "created=gt=:since", values: map[string]string{"since": "2024-05-01"}, expected: "created=gt=2024-05-01"},
+		{input: "a==:x;b==:x,c==:y", values: map[string]string{"x": "John Doe", "y": "a;b==c", "z": "1"}, expected: `a=="John Doe";b=="John Doe",c==a\;b\=\=c`},
+		{input: "a=bt=:r", values: map[string]string{"r": "1..5"}, expected: "a=bt=1..5"},
+		{input: "a==1", values: nil, expected: "a==1"},
+		{input: "a==:x;b==:y", values: map[string]string{"x": "1"}, err: ErrUnboundParameter},
+		{input: "a=gt=:x", values: map[string]string{"x": "abc"}, err: ErrInvalidArgumentType},
+		{input: "a=bt=:x", values: map[string]string{"x": "1"}, err: ErrInvalidArgumentType},
+	}
+	p := New(WithParameters())
+	for _, v := range values {
+		e, err := p.Parse(context.Background(), v.input)
+		if !assert.NoError(t, err, v.input) {
+			continue
+		}
+		bound, err := e.Bind(v.values)
+		if v.err != nil {
+			assert.ErrorIs(t, err, v.err, v.input)
+			continue
+		}
+		if assert.NoError(t, err, v.input) {
+			assert.Equal(t, v.expected, bound.ToFIQL(), v.input)
+			assert.Empty(t, bound.Parameters(), v.input)
+			// the template is not modified
+			assert.Equal(t, v.input, e.ToFIQL(), v.input)
+		}
+	}
+}
+
+func TestBindTypes(t *testing.T) {
+	e, err := New(WithParameters()).Parse(context.Background(), "age=gt=:age;name==:name")
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, []string{"age", "name"}, e.Parameters())
+	bound, err := e.Bind(map[string]string{"age": "18", "name": "true"})
+	if assert.NoError(t, err) {
+		recommendations := make([]ValueRecommendation, 0)
+		Walk(&bound, func(n Node) bool {
+			if c, ok := n.(*constantExpression); ok && !c.selector {
+				recommendations = append(recommendations, c.recommended)
+			}
+			return true
+		})
+		assert.Equal(t, []ValueRecommendation{ValueRecommendationNumber, ValueRecommendationBoolean}, recommendations)
+	}
+}
+
+func TestBindDateTime(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip("time zone data not available")
+	}
+	p := New(WithParameters(), WithDefaultLocation(berlin), WithDateTimeLayouts(DateTimeLayoutOf("02.01.2006")))
+	e, err := p.Parse(context.Background(), "created=gt=:since")
+	if !assert.NoError(t, err) {
+		return
+	}
+	var values = []struct {
+		value    string
+		expected time.Time
+	}{
+		{value: "2024-05-01", expected: time.Date(2024, 5, 1, 0, 0, 0, 0, berlin)},
+		{value: "15.01.2024", expected: time.Date(2024, 1, 15, 0, 0, 0, 0, berlin)},
+		{value: "2024-05-01T10:00:00Z", expected: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)},
+	}
+	for _, v := range values {
+		bound, err := e.Bind(map[string]string{"since": v.value})
+		if !assert.NoError(t, err, v.value) {
+			continue
+		}
+		// the bound value is typed and converted like the parsed value
+		parsed, err := p.Parse(context.Background(), "created=gt="+v.value)
+		if !assert.NoError(t, err, v.value) {
+			continue
+		}
+		assert.Equal(t, firstArgument(&parsed).ValueRecommendation(), firstArgument(&bound).ValueRecommendation(), v.value)
+		tm, err := firstArgument(&bound).AsTime()
+		if assert.NoError(t, err, v.value) {
+			assert.True(t, v.expected.Equal(tm), "%s: expected %s got %s", v.value, v.expected, tm)
+		}
+	}
+}
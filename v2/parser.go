@@ -0,0 +1,1188 @@
+package fiqlparser
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// NodeType defines the type of node in the ast
+type NodeType string
+
+// NodeTypeExpression is the root element of any expression
+const NodeTypeExpression NodeType = "Expr"
+
+// NodeTypeBinary is a binary expression
+const NodeTypeBinary NodeType = "Binary"
+
+// NodeTypeConstant is a constant value expression
+const NodeTypeConstant NodeType = "Const"
+
+// NodeTypeUnary is a selector without constraint (e.g. `deleted` in `deleted;name==x`),
+// it is a ConstantNode with IsUnary set
+const NodeTypeUnary NodeType = "Unary"
+
+// Operator defines the two operators fiql has
+type Operator string
+
+// OperatorOR defines the OR operation, AND binds tighter than OR
+// Associativity: Left to right
+const OperatorOR Operator = "OR"
+
+// OperatorAND defines the AND operation
+// Associativity: Left to right
+const OperatorAND Operator = "AND"
+
+// Comparison defines the fiql + custom comparisons
+type Comparison string
+
+// ComparisonEq equal comparison
+const ComparisonEq Comparison = "=="
+
+// ComparisonNeq not equal comparison
+const ComparisonNeq Comparison = "<>"
+
+// ComparisonGt greater comparison
+const ComparisonGt Comparison = ">"
+
+// ComparisonLt less comparison
+const ComparisonLt Comparison = "<"
+
+// ComparisonGte greater or equal comparison
+const ComparisonGte Comparison = ">="
+
+// ComparisonLte less or equal comparison
+const ComparisonLte Comparison = "<="
+
+// ComparisonBetween inclusive range comparison (`=bt=` or `=between=`),
+// the argument is a range literal (`10..20`) or a tuple of two bounds (`[10+20]`)
+const ComparisonBetween Comparison = "BETWEEN"
+
+// ComparisonIn membership comparison (`=in=`), the argument is a tuple
+const ComparisonIn Comparison = "IN"
+
+// ComparisonQuery full text search (`=q=`), the meaning is up to the backend
+const ComparisonQuery Comparison = "QUERY"
+
+// AllComparisons returns all comparisons the parser produces, e.g. to check visitors handle every comparison
+func AllComparisons() []Comparison {
+	return []Comparison{
+		ComparisonEq, ComparisonNeq, ComparisonGt, ComparisonLt, ComparisonGte, ComparisonLte,
+		ComparisonBetween, ComparisonIn, ComparisonQuery,
+	}
+}
+
+// ValueRecommendation suggests a detected datatype for a attribute
+type ValueRecommendation string
+
+// ValueRecommendationString suggests a string attribute
+const ValueRecommendationString ValueRecommendation = "string"
+
+// ValueRecommendationDateTime suggests a date attribute
+const ValueRecommendationDateTime ValueRecommendation = "datetime"
+
+// ValueRecommendationDuration suggests a duration attribute
+const ValueRecommendationDuration ValueRecommendation = "duration"
+
+// ValueRecommendationNumber suggests a number attribute
+const ValueRecommendationNumber ValueRecommendation = "number"
+
+// ValueRecommendationBoolean suggests a boolean attribute (`true` or `false`)
+const ValueRecommendationBoolean ValueRecommendation = "boolean"
+
+// ValueRecommendationNull suggests a missing value (`null`)
+const ValueRecommendationNull ValueRecommendation = "null"
+
+// ValueRecommendationRange suggests a inclusive range of numbers, dates or durations (`10..20`)
+const ValueRecommendationRange ValueRecommendation = "range"
+
+// ValueRecommendationTuple suggests a list of values (`[a+b+c]`), see ArgumentContext.AsTuple
+const ValueRecommendationTuple ValueRecommendation = "tuple"
+
+// ArgumentContext habours the value and
+// supplies the recommended type + conversion helpers
+type ArgumentContext struct {
+	pre    bool
+	post   bool
+	quoted bool
+	r      ValueRecommendation
+	val    string
+	tuple  *tupleArgument
+	layout DateTimeLayout
+	loc    *time.Location
+}
+
+// ValueRecommendation returns the value recommendation
+func (c ArgumentContext) ValueRecommendation() ValueRecommendation {
+	return c.r
+}
+
+// StartsWithWildcard indicates whether or not the given argument starts with a wildcard
+func (c ArgumentContext) StartsWithWildcard() bool {
+	return c.pre
+}
+
+// EndsWithWildcard indicates whether or not the given argument ends with a wildcard
+func (c ArgumentContext) EndsWithWildcard() bool {
+	return c.post
+}
+
+// IsQuoted indicates whether or not the given argument was enclosed in quotes
+func (c ArgumentContext) IsQuoted() bool {
+	return c.quoted
+}
+
+// AsString returns the argument as string
+func (c ArgumentContext) AsString() string {
+	return c.val
+}
+
+// AsDuration is a helper method for converting duration values
+func (c ArgumentContext) AsDuration() (ISO8601Duration, error) {
+	return durationConverter.tryParseISO8601Duration(c.val)
+}
+
+// AsTime is a helper method for converting date values,
+// coarse dates (e.g. `2024-05`) return the start of the period,
+// values in a layout registered with WithDateTimeLayouts are parsed with it.
+// Datetimes without offset are in UTC or the location set by WithDefaultLocation, see AsTimeIn.
+func (c ArgumentContext) AsTime() (time.Time, error) {
+	if start, _, _, ok := coarseDatePeriod(c.val, c.location()); ok {
+		return start, nil
+	}
+	t, err := time.Parse(time.RFC3339, c.val)
+	if err != nil && c.layout != nil {
+		if lt, ok := c.layout(c.val, c.location()); ok {
+			return lt, nil
+		}
+	}
+	return t, err
+}
+
+// AsBool returns the underlying value as bool
+func (c ArgumentContext) AsBool() (bool, error) {
+	return strconv.ParseBool(c.val)
+}
+
+// IsNull indicates whether or not the argument is the unquoted `null` literal
+func (c ArgumentContext) IsNull() bool {
+	return c.r == ValueRecommendationNull
+}
+
+// AsInt returns the underlying value as int
+func (c ArgumentContext) AsInt() (int, error) {
+	return strconv.Atoi(c.val)
+}
+
+// AsFloat64 returns the underlying value as float64
+func (c ArgumentContext) AsFloat64() (float64, error) {
+	return strconv.ParseFloat(c.val, 64)
+}
+
+// SelectorContext contains the selector details
+type SelectorContext struct {
+	unary    bool
+	selector string
+	// separator is the configured path separator, 0 for DefaultSelectorPathSeparator
+	separator rune
+}
+
+// Selector returns the selector as string
+func (s SelectorContext) Selector() string {
+	return s.selector
+}
+
+// IsUnary returns true if the selector has no constraint
+func (s SelectorContext) IsUnary() bool {
+	return s.unary
+}
+
+// OperatorContext contains the operator details
+type OperatorContext struct {
+	op Operator
+}
+
+// Operator returns the operator
+func (c OperatorContext) Operator() Operator {
+	return c.op
+}
+
+// ComparisonContext contains the comerator details
+type ComparisonContext struct {
+	comparison Comparison
+}
+
+// Comparison returns the used comparison
+func (c ComparisonContext) Comparison() Comparison {
+	return c.comparison
+}
+
+//Basically follow naming of https://datatracker.ietf.org/doc/html/draft-nottingham-atompub-fiql-00#section-3.2
+
+// NodeVisitor is used to visit the tree by Accept,
+// see Visitor for the interface with error returns, enter and leave of binary nodes and tuple elements
+type NodeVisitor interface {
+	// VisitExpressionEntered is called when a expression is entered
+	VisitExpressionEntered()
+
+	// VisitExpressionLeft is called when a expression is left
+	VisitExpressionLeft()
+
+	// VisitOperator is called when a operator is visited
+	VisitOperator(operatorCtx OperatorContext)
+
+	// VisitSelector is called when a selector is visited
+	VisitSelector(selectorCtx SelectorContext)
+
+	// VisitComparison is called when a comparison is visited
+	VisitComparison(comparisonCtx ComparisonContext)
+
+	// VisitArgument is called when a argument is visited
+	VisitArgument(argumentCtx ArgumentContext)
+}
+
+// LabelVisitor can optionally be implemented by a NodeVisitor
+// to be notified about labeled sub expressions
+type LabelVisitor interface {
+	// VisitLabel is called right after VisitExpressionEntered if the expression has a label
+	VisitLabel(label string)
+}
+
+// Node represents a AST node
+type Node interface {
+	// NodeType - node type in the AST - the root node will always be expression
+	NodeType() NodeType
+	// String prints the node
+	String() string
+	// Returns the children of this node
+	Children() []Node
+	// Accepts a Visitor
+	Accept(visitor NodeVisitor)
+	// Add adds a child node to this node
+	//
+	// Deprecated: Add panics if the node can not hold another child, use NewBinary, NewNegation
+	// and NewExpression to construct trees.
+	Add(Node)
+
+	// isRoot indicates the root node
+	isRoot() bool
+}
+
+// BinaryNode is either a logical operation (NodeTypeBinary with a Operator)
+// or a comparison (NodeTypeBinary with a Comparison)
+type BinaryNode interface {
+	Node
+	// Operator returns the operator, either a Operator or a Comparison
+	Operator() string
+	// IsLogical indicates a logical operation (AND, OR) instead of a comparison
+	IsLogical() bool
+	// Left returns the left operand, for comparisons this is the selector
+	Left() Node
+	// Right returns the right operand, for comparisons this is the argument
+	Right() Node
+}
+
+// ConstantNode is either a selector or a argument (NodeTypeConstant), a unary selector (NodeTypeUnary)
+// or a tuple argument (NodeTypeTuple)
+type ConstantNode interface {
+	Node
+	// Value returns the selector or the argument without wildcards
+	Value() string
+	// IsSelector indicates whether the constant is a selector or a argument
+	IsSelector() bool
+	// IsUnary indicates a selector without constraint
+	IsUnary() bool
+	// Wildcards returns whether or not the argument starts or ends with a wildcard
+	Wildcards() (prefix bool, suffix bool)
+	// ValueRecommendation returns the value recommendation of the argument
+	ValueRecommendation() ValueRecommendation
+	// IsQuoted indicates whether or not the argument was enclosed in quotes
+	IsQuoted() bool
+	// Position returns the position of the constant within the input
+	Position() Position
+	// Argument returns the argument context with its conversion helpers
+	Argument() ArgumentContext
+	// Elements returns the elements of a tuple argument (NodeTypeTuple), nil otherwise
+	Elements() []ConstantNode
+	// IsPlaceholder indicates a argument inserted by ParseLenient in place of a missing or invalid one
+	IsPlaceholder() bool
+}
+
+var _ BinaryNode = &binaryExpression{}
+var _ ConstantNode = &constantExpression{}
+
+// Expression is the root node
+type Expression struct {
+	node  Node
+	root  bool
+	label string
+}
+
+// Label returns the label of a sub expression (e.g. `label:(a==b)`), empty if unlabeled
+func (e *Expression) Label() string {
+	return e.label
+}
+
+func (e *Expression) isRoot() bool {
+	return e.root
+}
+
+// NodeType NodeTypeExpression
+func (e *Expression) NodeType() NodeType {
+	return NodeTypeExpression
+}
+
+// Accept accepts a vistor to visit the tree
+func (e *Expression) Accept(visitor NodeVisitor) {
+	visitor.VisitExpressionEntered()
+	if lv, ok := visitor.(LabelVisitor); ok && e.label != "" {
+		lv.VisitLabel(e.label)
+	}
+	if e.node != nil {
+		e.node.Accept(visitor)
+	}
+	visitor.VisitExpressionLeft()
+}
+
+// Add adds a child to the node, it will panic if more than one child exists on a expression node
+//
+// Deprecated: use NewExpression or NewBinary, which return a error instead of panicking.
+func (e *Expression) Add(node Node) {
+	if e.node != nil {
+		panic("node may not have more than one child")
+	}
+	e.node = node
+}
+
+// MarshalJSON overloading for json marshalling
+func (e *Expression) MarshalJSON() ([]byte, error) {
+	return marshalNode(e)
+}
+
+func (e *Expression) String() string {
+	return nodeString(e)
+}
+
+// Children returns the children of this expression
+func (e *Expression) Children() []Node {
+	return []Node{e.node}
+}
+
+type binaryExpression struct {
+	operator string
+	nodes    [2]Node
+}
+
+// Operator returns the operator, either a Operator or a Comparison
+func (e *binaryExpression) Operator() string {
+	return e.operator
+}
+
+// IsLogical indicates a logical operation (AND, OR) instead of a comparison
+func (e *binaryExpression) IsLogical() bool {
+	return isLogicalOperator(e.operator)
+}
+
+// Left returns the left operand, for comparisons this is the selector
+func (e *binaryExpression) Left() Node {
+	return e.nodes[0]
+}
+
+// Right returns the right operand, for comparisons this is the argument
+func (e *binaryExpression) Right() Node {
+	return e.nodes[1]
+}
+
+func (e *binaryExpression) NodeType() NodeType {
+	return NodeTypeBinary
+}
+
+// Add sets the next operand, it will panic if both operands are set
+//
+// Deprecated: use NewBinary, which returns a error instead of panicking.
+func (e *binaryExpression) Add(node Node) {
+	if e.nodes[0] == nil {
+		e.nodes[0] = node
+		return
+	}
+	if e.nodes[1] == nil {
+		e.nodes[1] = node
+		return
+	}
+	panic("binary node cant hold more than two values")
+}
+
+// Accept accepts a vistor to visit the tree
+func (e *binaryExpression) Accept(visitor NodeVisitor) {
+	if e.nodes[0] != nil {
+		e.nodes[0].Accept(visitor)
+	}
+	//conjs
+	if e.operator == "AND" || e.operator == "OR" {
+		visitor.VisitOperator(OperatorContext{op: Operator(e.operator)})
+	} else {
+		visitor.VisitComparison(ComparisonContext{comparison: Comparison(e.operator)})
+	}
+	if e.nodes[1] != nil {
+		e.nodes[1].Accept(visitor)
+	}
+}
+
+func (e *binaryExpression) Children() []Node {
+	nodes := make([]Node, 0)
+	if e.nodes[0] != nil {
+		nodes = append(nodes, e.nodes[0])
+	}
+	if e.nodes[1] != nil {
+		nodes = append(nodes, e.nodes[1])
+	}
+	return nodes
+}
+
+func (e *binaryExpression) isRoot() bool {
+	return false
+}
+
+func (e *binaryExpression) MarshalJSON() ([]byte, error) {
+	return marshalNode(e)
+}
+
+func (e *binaryExpression) String() string {
+	return nodeString(e)
+}
+
+type constantExpression struct {
+	prefixWildcard bool
+	suffixWildcard bool
+	selector       bool
+	value          string
+	recommended    ValueRecommendation
+	unary          bool
+	// quote is the quote character used for the value, 0 if unquoted
+	quote rune
+	// tuple holds the elements of a tuple argument
+	tuple *tupleArgument
+	// pos is the position of the constant within the input
+	pos Position
+	// layout is the registered datetime layout of the value, if any,
+	// named parameters keep all registered layouts to type the bound value
+	layout DateTimeLayout
+	// loc is the location of datetimes without offset, UTC if nil
+	loc *time.Location
+	// placeholder marks arguments inserted by ParseLenient
+	placeholder bool
+	// pathSeparator is the configured path separator of selectors, see WithSelectorPathSeparator
+	pathSeparator rune
+}
+
+// Position is a position within the parsed input
+type Position struct {
+	// Line starting at 1
+	Line int
+	// Column is the position within the line (in runes) starting at 0,
+	// errors and warnings in the form `ln:<line>:<column>` print the column starting at 1 (Column+1)
+	Column int
+	// Offset is the position within the whole input (in runes)
+	Offset int
+	// ByteOffset is the position within the whole input (in bytes), e.g. to slice the UTF-8 input
+	ByteOffset int
+}
+
+func (e *constantExpression) isRoot() bool {
+	return false
+}
+
+func (e *constantExpression) NodeType() NodeType {
+	if e.unary {
+		return NodeTypeUnary
+	}
+	if e.tuple != nil {
+		return NodeTypeTuple
+	}
+	return NodeTypeConstant
+}
+
+// Add always panics as constants have no children
+//
+// Deprecated: constants can not hold children.
+func (e *constantExpression) Add(node Node) {
+	panic("constant should not have a child")
+}
+
+func (e *constantExpression) Accept(visitor NodeVisitor) {
+	if e.selector {
+		visitor.VisitSelector(SelectorContext{unary: e.unary, selector: e.value, separator: e.pathSeparator})
+	} else {
+		visitor.VisitArgument(e.Argument())
+	}
+
+}
+
+// Value returns the selector or the argument without wildcards
+func (e *constantExpression) Value() string {
+	return e.value
+}
+
+// IsSelector indicates whether the constant is a selector or a argument
+func (e *constantExpression) IsSelector() bool {
+	return e.selector
+}
+
+// IsUnary indicates a selector without constraint
+func (e *constantExpression) IsUnary() bool {
+	return e.unary
+}
+
+// Wildcards returns whether or not the argument starts or ends with a wildcard
+func (e *constantExpression) Wildcards() (bool, bool) {
+	return e.prefixWildcard, e.suffixWildcard
+}
+
+// ValueRecommendation returns the value recommendation of the argument
+func (e *constantExpression) ValueRecommendation() ValueRecommendation {
+	return e.recommended
+}
+
+// IsQuoted indicates whether or not the argument was enclosed in quotes
+func (e *constantExpression) IsQuoted() bool {
+	return e.quote != 0
+}
+
+// Position returns the position of the constant within the input,
+// the zero value for constants which were not parsed
+func (e *constantExpression) Position() Position {
+	return e.pos
+}
+
+// IsPlaceholder indicates a argument inserted by ParseLenient in place of a missing or invalid one
+func (e *constantExpression) IsPlaceholder() bool {
+	return e.placeholder
+}
+
+// Argument returns the argument context of the constant
+func (e *constantExpression) Argument() ArgumentContext {
+	return ArgumentContext{
+		pre:    e.prefixWildcard,
+		post:   e.suffixWildcard,
+		quoted: e.quote != 0,
+		r:      e.recommended,
+		val:    e.value,
+		tuple:  e.tuple,
+		layout: e.layout,
+		loc:    e.loc,
+	}
+}
+
+func (e *constantExpression) MarshalJSON() ([]byte, error) {
+	return marshalNode(e)
+}
+
+func (e *constantExpression) String() string {
+	return nodeString(e)
+}
+
+// plainLiteral demotes quoted or wildcard literals to plain strings, e.g. `name=="null"`
+func (e *constantExpression) plainLiteral() {
+	if e.quote == 0 && !e.prefixWildcard && !e.suffixWildcard {
+		return
+	}
+	switch e.recommended {
+	case ValueRecommendationBoolean, ValueRecommendationNull, ValueRecommendationRange:
+		e.recommended = ValueRecommendationString
+	}
+}
+
+// Children returns the elements of a tuple argument, constants have no children otherwise
+func (e *constantExpression) Children() []Node {
+	if e.tuple == nil {
+		return []Node{}
+	}
+	children := make([]Node, 0, len(e.tuple.elements))
+	for _, el := range e.tuple.elements {
+		children = append(children, el)
+	}
+	return children
+}
+
+// Parser is the fiql parser, it only holds the configuration and is safe for concurrent use
+// by multiple goroutines, so one parser can be shared (e.g. by all HTTP handlers)
+type Parser struct {
+	// lex is only set on the per call copy created by withInput
+	lex          *lexer
+	tuple        TupleDelimiters
+	logicalNodes bool
+	// legacyPrecedence disables the precedence of AND over OR
+	legacyPrecedence bool
+	// selectorPattern restricts the allowed selectors if set
+	selectorPattern *regexp.Regexp
+	selectorCase    SelectorCase
+	selectorMapper  func(string) string
+	aliases         *SelectorAliases
+	validators      *ValidatorRegistry
+	identifiers     bool
+	interner        *Interner
+	strictSpec      bool
+	spacesInValues  bool
+	tokenTrace      bool
+	maxLength       int
+	negation        bool
+	dateTimeLayouts []DateTimeLayout
+	location        *time.Location
+	metrics         func(ParseMetrics)
+	budget          ParseBudget
+	fieldReferences bool
+	// anyDateTimeLayout accepts values in any of the dateTimeLayouts, nil without layouts
+	anyDateTimeLayout DateTimeLayout
+	// parameters accepts `:name` arguments
+	parameters bool
+	// pathSeparator separates the segments of nested selectors
+	pathSeparator rune
+	// dynamicArguments accepts `@name` arguments for every comparison
+	dynamicArguments bool
+	// knownSelectors maps the lower cased known selectors to their spelling, ambiguous ones to ""
+	knownSelectors map[string]string
+}
+
+// Option configures a Parser
+type Option func(*Parser)
+
+func (p *Parser) handleSubExpression(parent Node, label string) (Node, error) {
+	expr := &Expression{node: nil, label: label}
+	p.lex.countNode(expressionSize, label)
+	n, err := p.build(expr)
+	if err != nil {
+		return expr, err
+	}
+	// build adds nested sub expressions to expr directly and returns expr itself
+	if n != Node(expr) {
+		expr.node = n
+	}
+	return expr, nil
+}
+
+var numericRegex = regexp.MustCompile(`^[+-]?[0-9]+(\.[0-9]+)?$`)
+var durationRegex = regexp.MustCompile(`^(\+|-|)P(?:\d+(?:\.\d+)?Y)?(?:\d+(?:\.\d+)?M)?(?:\d+(?:\.\d+)?W)?(?:\d+(?:\.\d+)?D)?(?:T(?:\d+(?:\.\d+)?H)?(?:\d+(?:\.\d+)?M)?(?:\d+(?:\.\d+)?S)?)?$`)
+
+// isDurationValue checks for a ISO 8601 duration with at least one component, e.g. `P1D` but not `P` or `P1DT`
+func isDurationValue(s string) bool {
+	return durationRegex.MatchString(s) && !strings.HasSuffix(s, "P") && !strings.HasSuffix(s, "T")
+}
+
+func isDateValue(stringDate string) bool {
+	if _, _, ok := coarseDateRange(stringDate); ok {
+		return true
+	}
+	_, err := time.Parse(time.RFC3339, stringDate)
+	return err == nil
+}
+
+// argumentValidator validates a argument and returns the recommendation,
+// or what would have been expected if the argument is invalid
+type argumentValidator func(string) (bool, ValueRecommendation, []string)
+
+func numberOrDateExpressionValidator(i string) (bool, ValueRecommendation, []string) {
+	if numericRegex.MatchString(i) {
+		return true, ValueRecommendationNumber, nil
+	}
+	//time or duration e.g. 2003-12-13T18:30:02Z or  -P1D12
+	if isDateValue(i) {
+		return true, ValueRecommendationDateTime, nil
+	}
+	if isDurationValue(i) {
+		return true, ValueRecommendationDuration, nil
+	}
+
+	return false, ValueRecommendationString, []string{"number", "date", "duration"}
+}
+
+func rangeValidator(i string) (bool, ValueRecommendation, []string) {
+	if _, _, ok := rangeBounds(i); ok {
+		return true, ValueRecommendationRange, nil
+	}
+	return false, ValueRecommendationString, []string{"range"}
+}
+
+func defaultValidator(i string) (bool, ValueRecommendation, []string) {
+	if _, _, ok := rangeBounds(i); ok {
+		return true, ValueRecommendationRange, nil
+	}
+	if isDateValue(i) {
+		return true, ValueRecommendationDateTime, nil
+	}
+	if isDurationValue(i) {
+		return true, ValueRecommendationDuration, nil
+	}
+	if numericRegex.MatchString(i) {
+		return true, ValueRecommendationNumber, nil
+	}
+	if i == "true" || i == "false" {
+		return true, ValueRecommendationBoolean, nil
+	}
+	if i == "null" {
+		return true, ValueRecommendationNull, nil
+	}
+	return true, ValueRecommendationString, nil
+}
+
+func (p *Parser) handleArgumentConstant(validator argumentValidator) (Node, error) {
+	p.lex.valueSpaces = p.spacesInValues
+	defer func() { p.lex.valueSpaces = false }()
+	t, err := p.lex.ConsumeToken()
+	if err != nil {
+		return nil, err
+	}
+	pos := p.lex.start
+	prefixWildcard := false
+	if t == tokenWildcard {
+		t, err = p.lex.ConsumeToken()
+		if err != nil {
+			return nil, err
+		}
+		prefixWildcard = true
+	}
+	if t == tokenValue && p.isFieldReference(prefixWildcard) {
+		return p.handleFieldReference(pos)
+	}
+	if t == tokenValue && p.isParameter(prefixWildcard) {
+		return p.handleParameter(pos)
+	}
+	if t == tokenValue {
+		ok, rec, expected := validator(p.lex.lastValue())
+		if !ok {
+			return nil, p.lex.errInvalidValue(p.lex.lastValue(), expected)
+		}
+		con := &constantExpression{prefixWildcard: prefixWildcard, value: p.lex.lastValue(), recommended: rec, quote: p.lex.lastQuote(), pos: pos}
+		p.lex.countNode(constantSize, con.value)
+		p.recommendDateTime(con)
+		p.recommendIdentifier(con)
+		n, _, err := p.lex.PeekNextToken()
+		if err != nil {
+			return nil, err
+		}
+		if n == tokenWildcard {
+			_, err = p.lex.ConsumeToken()
+			if err != nil {
+				return nil, err
+			}
+			con.suffixWildcard = true
+		}
+		con.plainLiteral()
+		return con, nil
+	}
+	return nil, p.lex.errExpectedValue(t)
+}
+
+func (p Parser) handleUnaryExpression(parent Node) (Node, error) {
+	unary := &constantExpression{value: p.lex.lastValue(), selector: true, recommended: ValueRecommendationString, unary: true, pos: p.lex.start}
+	p.lex.countNode(constantSize, unary.value)
+	if err := p.prepareSelector(unary); err != nil {
+		return unary, err
+	}
+	next, _, err := p.lex.PeekNextToken()
+	if err != nil {
+		return unary, err
+	}
+	if isLogicToken(next) {
+		t, err := p.lex.ConsumeToken()
+		if err != nil {
+			return unary, err
+		}
+		conj := &binaryExpression{nodes: [2]Node{nil, nil}}
+		p.lex.countNode(binarySize, "")
+		conj.operator = t.String()
+		conj.Add(unary)
+		rhs, err := p.build(conj)
+		if err != nil {
+			return conj, err
+		}
+		conj.Add(rhs)
+		return conj, nil
+	}
+	if isCompareToken(next) {
+		return unary, p.lex.errDanglingComparator(next).startingAt(p.lex.peeked)
+	}
+	if next == tokenBraceClose && parent.isRoot() {
+		return unary, p.lex.errInvalidClosingBrace().startingAt(p.lex.peeked)
+	}
+	return unary, nil
+}
+
+func (p *Parser) handleBinaryExpression(t tokenType, parent Node) (Node, error) {
+	bin := &binaryExpression{nodes: [2]Node{nil, nil}}
+	bin.operator = t.String()
+	sel := &constantExpression{value: p.lex.lastValue(), selector: true, recommended: ValueRecommendationString, pos: p.lex.start}
+	p.lex.countNode(binarySize, "")
+	p.lex.countNode(constantSize, sel.value)
+	bin.Add(sel)
+	if err := p.prepareSelector(sel); err != nil {
+		return bin, err
+	}
+	t, err := p.lex.ConsumeToken()
+	if err != nil {
+		return bin, err
+	}
+	if isCompareToken(t) {
+		bin.operator = t.String()
+	} else {
+		return bin, p.lex.errExpectedValue(t)
+	}
+
+	validator := defaultValidator
+	if isNumberOrDateComparision(t) && !p.validators.replacesBuiltin(t) {
+		validator = numberOrDateExpressionValidator
+	}
+	validator = p.dateTimeValidator(validator)
+	if p.dynamicArguments {
+		validator = dynamicValidator(validator)
+	}
+	var con Node
+	switch t {
+	case tokenCompareIn:
+		con, err = p.handleTupleArgument()
+	case tokenCompareBetween:
+		con, err = p.handleRangeArgument()
+	default:
+		con, err = p.handleArgumentConstant(validator)
+	}
+	if err != nil {
+		return bin, err
+	}
+	bin.Add(con)
+	if p.validators != nil {
+		if err := p.validators.validate(bin); err != nil {
+			return bin, p.lex.errRejectedValue(con, err)
+		}
+	}
+
+	next, _, err := p.lex.PeekNextToken()
+	if err != nil {
+		return bin, err
+	}
+	if isLogicToken(next) {
+		t, err = p.lex.ConsumeToken()
+		if err != nil {
+			return bin, err
+		}
+		conj := &binaryExpression{nodes: [2]Node{nil, nil}}
+		p.lex.countNode(binarySize, "")
+		conj.operator = t.String()
+		conj.Add(bin)
+		rhs, err := p.build(conj)
+		if err != nil {
+			return conj, err
+		}
+		conj.Add(rhs)
+		return conj, nil
+	}
+	if isCompareToken(next) {
+		return bin, p.lex.errDanglingComparator(next).startingAt(p.lex.peeked)
+	}
+	if next == tokenBraceClose && parent.isRoot() {
+		return bin, p.lex.errInvalidClosingBrace().startingAt(p.lex.peeked)
+	}
+	return bin, nil
+}
+
+func (p *Parser) checkDanglingChild(n Node) bool {
+	if n.NodeType() == NodeTypeBinary {
+		if len(n.Children()) != 2 {
+			return true
+		}
+	}
+	return false
+}
+
+// checkImpossibleTokensOnEnter checks for tokens that should not appear on enter `build`
+func (p *Parser) checkImpossibleTokensOnEnter(t tokenType) error {
+	if t == tokenBraceClose {
+		return p.lex.errInvalidClosingBrace()
+	}
+
+	if isLogicToken(t) {
+		return p.lex.errDanglingOperator(t)
+	}
+
+	if isCompareToken(t) {
+		return p.lex.errDanglingComparator(t)
+	}
+
+	if t == tokenWildcard {
+		return p.lex.errExpectedSelector(t)
+	}
+	return nil
+}
+
+// checkForEOF checks for correct end of file
+func (p *Parser) checkForEOF(t tokenType, node Node) (bool, error) {
+	if t == tokenEOF {
+		if p.checkDanglingChild(node) {
+			return true, p.lex.errDanglingOperator(t)
+
+		}
+		return true, nil
+	}
+	return false, nil
+}
+
+func (p *Parser) checkEndOrError(t tokenType, parent Node) (bool, error) {
+	if ok, err := p.checkForEOF(t, parent); ok {
+		return ok, err
+	}
+	if err := p.checkImpossibleTokensOnEnter(t); err != nil {
+		return true, err
+	}
+	return false, nil
+}
+
+func (p *Parser) mergeSubExpression(sub, parent Node) (Node, error) {
+	t, err := p.lex.ConsumeToken()
+	if err != nil {
+		return parent, err
+	}
+	conj := &binaryExpression{nodes: [2]Node{nil, nil}}
+	p.lex.countNode(binarySize, "")
+	conj.operator = t.String()
+	conj.Add(sub)
+
+	rhs, err := p.build(conj)
+	if err != nil {
+		return conj, err
+	}
+	conj.Add(rhs)
+	if parent.NodeType() == NodeTypeExpression {
+		parent.Add(conj)
+		return parent, nil
+	}
+	return conj, nil
+}
+
+func (p *Parser) build(parent Node) (Node, error) {
+	t, err := p.lex.ConsumeToken()
+	if err != nil {
+		return parent, err
+	}
+	if ok, err := p.checkEndOrError(t, parent); ok {
+		return parent, err
+	}
+	negated := t == tokenNot
+	if negated {
+		// the lexer only emits negations followed by a opening brace
+		t, err = p.lex.ConsumeToken()
+		if err != nil {
+			return parent, err
+		}
+	}
+	label := ""
+	if t == tokenLabel {
+		label = p.lex.lastValue()
+		// the lexer only emits labels followed by a opening brace
+		t, err = p.lex.ConsumeToken()
+		if err != nil {
+			return parent, err
+		}
+	}
+	if t == tokenBraceOpen {
+		sub, err := p.handleSubExpression(parent, label)
+		if err != nil {
+			return parent, err
+		}
+		t, err := p.lex.ConsumeToken()
+		if err != nil {
+			return parent, err
+		}
+		if t != tokenBraceClose {
+			return parent, p.lex.errUnclosedBrace(t)
+		}
+		if negated {
+			sub = &notExpression{node: sub}
+			p.lex.countNode(notSize, "")
+		}
+
+		next, _, err := p.lex.PeekNextToken()
+		if err != nil {
+			return parent, err
+		}
+		if isLogicToken(next) {
+			return p.mergeSubExpression(sub, parent)
+		}
+		if parent.NodeType() == NodeTypeExpression {
+			parent.Add(sub)
+			return parent, nil
+		}
+		return sub, nil
+	}
+
+	if t == tokenValue {
+		next, _, err := p.lex.PeekNextToken()
+		if err != nil {
+			return parent, err
+		}
+		var nextExpr Node
+		if isSeperatorUnary(next) {
+			nextExpr, err = p.handleUnaryExpression(parent)
+		} else {
+			nextExpr, err = p.handleBinaryExpression(t, parent)
+		}
+		if parent.isRoot() {
+			parent.Add(nextExpr)
+			return parent, err
+		}
+		return nextExpr, err
+	}
+	return parent, err
+
+}
+
+// WithSpacesInValues allows whitespace within unquoted arguments, e.g. `name==John Doe;age=gt=1`,
+// whitespace before a operator, a closing brace or the end of the input is not part of the argument
+func WithSpacesInValues() Option {
+	return func(p *Parser) {
+		p.spacesInValues = true
+	}
+}
+
+// checkTrailingInput fails if any input remains after the complete expression,
+// so e.g. `name==John Doe` is not silently truncated to `name==John`
+func (p *Parser) checkTrailingInput() error {
+	t, err := p.lex.ConsumeToken()
+	if err != nil || t == tokenEOF {
+		return err
+	}
+	return p.lex.errTrailingInput(t)
+}
+
+// Parse parses the supplied fiql and returns either a Expression or an error.
+// Parse never panics, any input (including invalid UTF-8) results in a Expression or a error,
+// this is verified by the fuzz target FuzzParse.
+// The context is checked while parsing, once it is done the parse fails with its error.
+func (p *Parser) Parse(ctx context.Context, input string) (Expression, error) {
+	if err := ctx.Err(); err != nil {
+		return Expression{root: true}, err
+	}
+	lex := acquireLexer(input)
+	defer releaseLexer(lex)
+	return p.withInput(ctx, lex).parse()
+}
+
+// ParseReader parses the fiql read from r, e.g. a request body. It does not stream:
+// the whole input is buffered in memory and only lexed once r is exhausted,
+// the buffer is kept once as read (UTF-8) and lexed in place without further conversion.
+// With WithMaxLength reading stops as soon as the limit is exceeded, which bounds the buffer,
+// so r may be a untrusted request body.
+// Reading stops once the context is done, the parse fails with its error.
+func (p *Parser) ParseReader(ctx context.Context, r io.Reader) (Expression, error) {
+	r = contextReader{ctx: ctx, r: r}
+	if p.maxLength > 0 {
+		r = io.LimitReader(r, int64(p.maxLength)+1)
+	}
+	var b strings.Builder
+	if _, err := io.Copy(&b, r); err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return Expression{root: true}, ctxErr
+		}
+		return Expression{root: true}, fmt.Errorf("unable to read input: %w", err)
+	}
+	if p.maxLength > 0 && b.Len() > p.maxLength {
+		return Expression{root: true}, (&lexer{ln: 1, start: Position{Line: 1}}).errReaderTooLong(p.maxLength)
+	}
+	return p.Parse(ctx, b.String())
+}
+
+// contextReader fails with the error of ctx once it is done
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (r contextReader) Read(b []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.r.Read(b)
+}
+
+// withInput returns a copy of the parser reading from lex, the state of a parse
+// is kept in the copy so the configured parser is never modified
+func (p *Parser) withInput(ctx context.Context, lex *lexer) *Parser {
+	c := *p
+	c.lex = lex
+	if ctx.Done() != nil {
+		lex.ctx = ctx
+	}
+	return &c
+}
+
+func (p *Parser) parse() (Expression, error) {
+	p.lex.metrics.Input = len(p.lex.input)
+	if p.metrics != nil {
+		defer func() { p.metrics(p.lex.metrics) }()
+	}
+	if !p.tokenTrace {
+		return p.parseExpression()
+	}
+	p.lex.trace = true
+	exp, err := p.parseExpression()
+	var perr *ParseError
+	if errors.As(err, &perr) {
+		perr.Tokens = p.lex.tokens
+	}
+	return exp, err
+}
+
+func (p *Parser) parseExpression() (Expression, error) {
+	exp := Expression{root: true}
+	if p.maxLength > 0 && len(p.lex.input) > p.maxLength {
+		return exp, p.lex.errInputTooLong(len(p.lex.input), p.maxLength)
+	}
+	p.lex.negation = p.negation
+	if p.strictSpec {
+		p.lex.literalQuotes = true
+		if err := p.lex.checkSpec(); err != nil {
+			return exp, err
+		}
+	}
+	p.lex.budget = p.budget
+	p.lex.countNode(expressionSize, "")
+	_, err := p.build(&exp)
+	if err == nil {
+		err = p.lex.checkBudget()
+	}
+	if err == nil {
+		err = p.checkTrailingInput()
+	}
+	if err == nil && !p.legacyPrecedence {
+		applyPrecedence(&exp)
+	}
+	if err == nil && p.logicalNodes {
+		exp = exp.CollapseLogical()
+	}
+	if err == nil && p.interner != nil {
+		p.interner.internNodes(&exp)
+	}
+	return exp, err
+}
+
+// New returns a new fiql parser configured by opts
+func New(opts ...Option) *Parser {
+	p := &Parser{}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// Parse instant parses the supplied fiql with a parser configured by opts
+func Parse(ctx context.Context, input string, opts ...Option) (Expression, error) {
+	return New(opts...).Parse(ctx, input)
+}
+
+// ParseReader instant parses the fiql read from r with a parser configured by opts,
+// the input is buffered like by Parser.ParseReader
+func ParseReader(ctx context.Context, r io.Reader, opts ...Option) (Expression, error) {
+	return New(opts...).ParseReader(ctx, r)
+}
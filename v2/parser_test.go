@@ -0,0 +1,802 @@
+package fiqlparser
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func ExampleParse() {
+	res, err := Parse(context.Background(), "column==value")
+	if err != nil {
+		return
+	}
+	json, _ := json.Marshal(&res)
+	fmt.Printf("%s", json)
+	// Output:
+	// {"Type":"Expr","Operator":"","Nodes":[{"Type":"Binary","Operator":"==","Nodes":[{"Type":"Const","Value":"column"},{"Type":"Const","Value":"value"}]}]}
+}
+
+type testVisitor struct {
+	sb strings.Builder
+}
+
+func (t *testVisitor) VisitExpressionEntered() { t.sb.WriteString("(") }
+func (t *testVisitor) VisitExpressionLeft()    { t.sb.WriteString(")") }
+func (t *testVisitor) VisitOperator(operatorCtx OperatorContext) {
+	t.sb.WriteString(string(operatorCtx.Operator()))
+}
+func (t *testVisitor) VisitSelector(selectorCtx SelectorContext) {
+	t.sb.WriteString(selectorCtx.Selector())
+}
+func (t *testVisitor) VisitComparison(comparisonCtx ComparisonContext) {
+	t.sb.WriteString(string(comparisonCtx.Comparison()))
+}
+func (t *testVisitor) VisitArgument(argumentCtx ArgumentContext) {
+	if argumentCtx.StartsWithWildcard() {
+		t.sb.WriteString("*")
+	}
+	t.sb.WriteString(argumentCtx.AsString())
+	if argumentCtx.EndsWithWildcard() {
+		t.sb.WriteString("*")
+	}
+}
+
+func (t *testVisitor) String() string { return t.sb.String() }
+
+func TestParse(t *testing.T) {
+	var values = []struct {
+		fiql        string
+		stringOuput string
+		errorOutput error
+	}{
+		{fiql: "(column==value)", stringOuput: "((column == value))", errorOutput: nil},
+		{fiql: "column==value", stringOuput: "(column == value)", errorOutput: nil},
+		{fiql: "column!=value", stringOuput: "(column <> value)", errorOutput: nil},
+		{fiql: "column=ge=1", stringOuput: "(column >= 1)", errorOutput: nil},
+		{fiql: "column=le=1", stringOuput: "(column <= 1)", errorOutput: nil},
+		{fiql: "column=gt=1.1", stringOuput: "(column > 1.1)", errorOutput: nil},
+		{fiql: "column=lt=100", stringOuput: "(column < 100)", errorOutput: nil},
+		{fiql: "column=lt=+100", stringOuput: "(column < +100)", errorOutput: nil},
+		{fiql: "column=lt=-100", stringOuput: "(column < -100)", errorOutput: nil},
+		{fiql: "column      ==        value", stringOuput: "(column == value)", errorOutput: nil},
+		{fiql: "updated=gt=2003-12-13T00:00:00Z", stringOuput: "(updated > 2003-12-13T00:00:00Z)", errorOutput: nil},
+		{fiql: "column==value,b==a", stringOuput: "(column == value OR b == a)", errorOutput: nil},
+		{fiql: "column==value  ,   b==a", stringOuput: "(column == value OR b == a)", errorOutput: nil},
+		{fiql: "     column==value  ,   b==a     ", stringOuput: "(column == value OR b == a)", errorOutput: nil},
+		{fiql: "     column  ==  value  ,   b  ==  a     ", stringOuput: "(column == value OR b == a)", errorOutput: nil},
+		{fiql: "(column==value)", stringOuput: "((column == value))", errorOutput: nil},
+		{fiql: "column==va\\,lue,b==a", stringOuput: "(column == va,lue OR b == a)", errorOutput: nil},
+		{fiql: "title==foo*;(updated=lt=-P1D,title==*bar)", stringOuput: "(title == foo* AND (updated < -P1D OR title == *bar))", errorOutput: nil},
+		{fiql: "(title==foo*);(fml==x,(xfs==a;f==fx))", stringOuput: "((title == foo*) AND (fml == x OR (xfs == a AND f == fx)))", errorOutput: nil},
+		{fiql: "(title==foo*,test==a,fx==fa);(fml==x)", stringOuput: "((title == foo* OR test == a OR fx == fa) AND (fml == x))", errorOutput: nil},
+		{fiql: "(title==foo*);(fml==x,(xfs==a;f==fx)", stringOuput: "", errorOutput: errors.New("ln:1:36 syntax error (unclosed brace `)` )")},
+		{fiql: "title=ffoo*", stringOuput: "", errorOutput: errors.New("ln:1:6 unexpected input (got `=f` but expected one of ==,!=,=gt=,=ge=,=lt=,=le=,=bt=,=between=,=in=,=q=)")},
+		{fiql: "title==fo,o*", stringOuput: "", errorOutput: errors.New("ln:1:12 syntax error (got `*` but expected a value)")},
+
+		{fiql: `a==value
+		; 
+		b==value`, stringOuput: "(a == value AND b == value)", errorOutput: nil},
+
+		{fiql: `
+		
+		)`, stringOuput: "", errorOutput: errors.New("ln:3:3 syntax error (invalid closing brace `)` )")},
+		{fiql: "column=ge=invalid", stringOuput: "", errorOutput: errors.New("ln:1:17 syntax error (got `invalid` but expected number or date or duration)")},
+		{fiql: "column=le=P1.4Y2M", stringOuput: "(column <= P1.4Y2M)", errorOutput: nil},
+		{fiql: "column=le=+P5W", stringOuput: "(column <= +P5W)", errorOutput: nil},
+		{fiql: "column=le=-P5W", stringOuput: "(column <= -P5W)", errorOutput: nil},
+		{fiql: "column=lt=P3DT4H59M", stringOuput: "(column < P3DT4H59M)", errorOutput: nil},
+	}
+	for _, v := range values {
+		res, err := Parse(context.Background(), v.fiql)
+		if v.errorOutput != nil {
+			assert.EqualError(t, err, v.errorOutput.Error())
+		} else {
+			if err != nil {
+				fmt.Printf(v.fiql)
+			}
+			assert.Nil(t, err)
+			assert.NotNil(t, res)
+			assert.Equal(t, v.stringOuput, res.String())
+		}
+
+	}
+}
+
+func TestDangling(t *testing.T) {
+	_, err := Parse(context.Background(), "a==b;")
+	assert.Error(t, err)
+	assert.EqualError(t, err, "ln:1:5 dangling operator")
+
+	_, err = Parse(context.Background(), "a==b,")
+	assert.Error(t, err)
+	assert.EqualError(t, err, "ln:1:5 dangling operator")
+
+	_, err = Parse(context.Background(), ",a==b")
+	assert.Error(t, err)
+	assert.EqualError(t, err, "ln:1:1 dangling operator")
+
+	_, err = Parse(context.Background(), ";a==b")
+	assert.Error(t, err)
+	assert.EqualError(t, err, "ln:1:1 dangling operator")
+
+	_, err = Parse(context.Background(), "==a==b")
+	assert.Error(t, err)
+	assert.EqualError(t, err, "ln:1:2 dangling comparator")
+
+	_, err = Parse(context.Background(), "a==b!=")
+	assert.Error(t, err)
+	assert.EqualError(t, err, "ln:1:4 dangling comparator")
+
+	_, err = Parse(context.Background(), "(a==b")
+	assert.Error(t, err)
+	assert.EqualError(t, err, "ln:1:5 syntax error (unclosed brace `)` )")
+
+	_, err = Parse(context.Background(), "a==b)")
+	assert.Error(t, err)
+	assert.EqualError(t, err, "ln:1:4 syntax error (invalid closing brace `)` )")
+
+	_, err = Parse(context.Background(), "()")
+	assert.Error(t, err)
+	assert.EqualError(t, err, "ln:1:2 syntax error (invalid closing brace `)` )")
+
+	_, err = Parse(context.Background(), "a==")
+	assert.Error(t, err)
+	assert.EqualError(t, err, "ln:1:3 syntax error (got `eof` but expected a value)")
+}
+func TestVisitor(t *testing.T) {
+	p := New()
+	tree, err := p.Parse(context.Background(), "(title==foo*);(fml==x,(xfs==a;f==fx))")
+	assert.NoError(t, err)
+	v := &testVisitor{}
+	tree.Accept(v)
+	assert.Equal(t, "((title==foo*)AND(fml==xOR(xfs==aANDf==fx)))", v.String())
+}
+
+type testTypeVisitor struct {
+	sb  strings.Builder
+	raw interface{}
+}
+
+func (t *testTypeVisitor) VisitExpressionEntered()                         {}
+func (t *testTypeVisitor) VisitExpressionLeft()                            {}
+func (t *testTypeVisitor) VisitOperator(operatorCtx OperatorContext)       {}
+func (t *testTypeVisitor) VisitSelector(selectorCtx SelectorContext)       {}
+func (t *testTypeVisitor) VisitComparison(comparisonCtx ComparisonContext) {}
+func (t *testTypeVisitor) VisitArgument(argumentCtx ArgumentContext) {
+	t.sb.WriteString(string(argumentCtx.ValueRecommendation()))
+	switch argumentCtx.ValueRecommendation() {
+	case ValueRecommendationDateTime:
+		t.raw, _ = argumentCtx.AsTime()
+	case ValueRecommendationDuration:
+		t.raw, _ = argumentCtx.AsDuration()
+	case ValueRecommendationNumber:
+		t.raw, _ = argumentCtx.AsFloat64()
+	case ValueRecommendationBoolean:
+		t.raw, _ = argumentCtx.AsBool()
+	case ValueRecommendationNull:
+		t.raw = nil
+	default:
+		t.raw = argumentCtx.AsString()
+	}
+}
+
+func (t *testTypeVisitor) String() string { return t.sb.String() }
+
+func TestRecommendedTypeString(t *testing.T) {
+	p := New()
+	tree, err := p.Parse(context.Background(), "title==foo*")
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+	v := &testTypeVisitor{}
+	tree.Accept(v)
+	assert.Equal(t, "string", v.String())
+	assert.Equal(t, "foo", v.raw)
+}
+
+func TestRecommendedTypeStrings(t *testing.T) {
+	p := New()
+	tree, err := p.Parse(context.Background(), "title==foo,blah==blub")
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+	v := &testTypeVisitor{}
+	tree.Accept(v)
+	assert.Equal(t, "stringstring", v.String())
+}
+
+func TestRecommendedTypeNumber(t *testing.T) {
+	p := New()
+	tree, err := p.Parse(context.Background(), "title=lt=-100.0")
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+	v := &testTypeVisitor{}
+	tree.Accept(v)
+	assert.Equal(t, "number", v.String())
+	assert.Equal(t, -100.0, v.raw)
+}
+
+func TestRecommendedTypeNumberEq(t *testing.T) {
+	p := New()
+	tree, err := p.Parse(context.Background(), "title==-100.0")
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+	v := &testTypeVisitor{}
+	tree.Accept(v)
+	assert.Equal(t, "number", v.String())
+	assert.Equal(t, -100.0, v.raw)
+}
+
+func TestRecommendedTypeNumberInvalid(t *testing.T) {
+	for _, fiql := range []string{"price=gt=10..20", "price=gt=1.2.3", "price=gt=1.", "price=gt=.5", "price=gt=1..", "price=gt=+"} {
+		_, err := Parse(context.Background(), fiql)
+		var parseErr *ParseError
+		if assert.ErrorAs(t, err, &parseErr, fiql) {
+			assert.Equal(t, ErrorCodeInvalidValue, parseErr.Code, fiql)
+		}
+	}
+	for _, fiql := range []string{"title==1.2.3", "title==1.", "title==.5", "title==-"} {
+		tree, err := Parse(context.Background(), fiql)
+		if assert.NoError(t, err, fiql) {
+			v := &testTypeVisitor{}
+			tree.Accept(v)
+			assert.Equal(t, "string", v.String(), fiql)
+		}
+	}
+}
+
+func TestRecommendedTypeDateTime(t *testing.T) {
+	p := New()
+	tree, err := p.Parse(context.Background(), "title=gt=2003-12-13T18:30:02Z")
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+	v := &testTypeVisitor{}
+	tree.Accept(v)
+	assert.Equal(t, "datetime", v.String())
+	assert.Equal(t, time.Date(2003, 12, 13, 18, 30, 2, 0, time.UTC), v.raw)
+}
+
+func TestRecommendedTypeDateTimeEq(t *testing.T) {
+	p := New()
+	tree, err := p.Parse(context.Background(), "title==2003-12-13T18:30:02Z")
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+	v := &testTypeVisitor{}
+	tree.Accept(v)
+	assert.Equal(t, "datetime", v.String())
+	assert.Equal(t, time.Date(2003, 12, 13, 18, 30, 2, 0, time.UTC), v.raw)
+}
+
+func TestRecommendedTypeDuration(t *testing.T) {
+	p := New()
+	tree, err := p.Parse(context.Background(), "title=gt=-P5Y")
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+	v := &testTypeVisitor{}
+	tree.Accept(v)
+	assert.Equal(t, "duration", v.String())
+	assert.Equal(t, ISO8601Duration{Negative: true, Years: 5, _string: "-P5Y"}, v.raw)
+}
+
+func TestRecommendedTypeDurationEq(t *testing.T) {
+	p := New()
+	tree, err := p.Parse(context.Background(), "title==-P5Y")
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+	v := &testTypeVisitor{}
+	tree.Accept(v)
+	assert.Equal(t, "duration", v.String())
+	assert.Equal(t, ISO8601Duration{Negative: true, Years: 5, _string: "-P5Y"}, v.raw)
+}
+
+func TestRecommendedTypeBooleanAndNull(t *testing.T) {
+	var values = []struct {
+		fiql string
+		rec  string
+		raw  interface{}
+	}{
+		{fiql: "active==true", rec: "boolean", raw: true},
+		{fiql: "active!=false", rec: "boolean", raw: false},
+		{fiql: "deleted==null", rec: "null", raw: nil},
+		{fiql: `deleted=="null"`, rec: "string", raw: "null"},
+		{fiql: "active=='true'", rec: "string", raw: "true"},
+		{fiql: "active==true*", rec: "string", raw: "true"},
+		{fiql: "active==True", rec: "string", raw: "True"},
+	}
+	for _, v := range values {
+		tree, err := Parse(context.Background(), v.fiql)
+		if !assert.NoError(t, err, v.fiql) {
+			continue
+		}
+		visitor := &testTypeVisitor{}
+		tree.Accept(visitor)
+		assert.Equal(t, v.rec, visitor.String(), v.fiql)
+		assert.Equal(t, v.raw, visitor.raw, v.fiql)
+	}
+	_, err := Parse(context.Background(), "active=gt=true")
+	assert.Error(t, err)
+}
+
+func TestJsonMarshall(t *testing.T) {
+	var values = []struct {
+		fiql        string
+		stringOuput string
+		errorOutput error
+	}{
+		{fiql: "column==value", stringOuput: `{"Type":"Expr","Operator":"","Nodes":[{"Type":"Binary","Operator":"==","Nodes":[{"Type":"Const","Value":"column"},{"Type":"Const","Value":"value"}]}]}`, errorOutput: nil},
+		{fiql: "column!=value", stringOuput: `{"Type":"Expr","Operator":"","Nodes":[{"Type":"Binary","Operator":"\u003c\u003e","Nodes":[{"Type":"Const","Value":"column"},{"Type":"Const","Value":"value"}]}]}`, errorOutput: nil},
+		{fiql: "title==foo*;(updated=lt=-P1D,title==*bar)", stringOuput: `{"Type":"Expr","Operator":"","Nodes":[{"Type":"Binary","Operator":"AND","Nodes":[{"Type":"Binary","Operator":"==","Nodes":[{"Type":"Const","Value":"title"},{"Type":"Const","Value":"foo*"}]},{"Type":"Expr","Operator":"","Nodes":[{"Type":"Binary","Operator":"OR","Nodes":[{"Type":"Binary","Operator":"\u003c","Nodes":[{"Type":"Const","Value":"updated"},{"Type":"Const","Value":"-P1D"}]},{"Type":"Binary","Operator":"==","Nodes":[{"Type":"Const","Value":"title"},{"Type":"Const","Value":"*bar"}]}]}]}]}]}`, errorOutput: nil},
+		{fiql: "(title==foo*);(fml==x,(xfs==a;f==fx))", stringOuput: `{"Type":"Expr","Operator":"","Nodes":[{"Type":"Binary","Operator":"AND","Nodes":[{"Type":"Expr","Operator":"","Nodes":[{"Type":"Binary","Operator":"==","Nodes":[{"Type":"Const","Value":"title"},{"Type":"Const","Value":"foo*"}]}]},{"Type":"Expr","Operator":"","Nodes":[{"Type":"Binary","Operator":"OR","Nodes":[{"Type":"Binary","Operator":"==","Nodes":[{"Type":"Const","Value":"fml"},{"Type":"Const","Value":"x"}]},{"Type":"Expr","Operator":"","Nodes":[{"Type":"Binary","Operator":"AND","Nodes":[{"Type":"Binary","Operator":"==","Nodes":[{"Type":"Const","Value":"xfs"},{"Type":"Const","Value":"a"}]},{"Type":"Binary","Operator":"==","Nodes":[{"Type":"Const","Value":"f"},{"Type":"Const","Value":"fx"}]}]}]}]}]}]}]}`, errorOutput: nil},
+		{fiql: "deleted;name==x", stringOuput: `{"Type":"Expr","Operator":"","Nodes":[{"Type":"Binary","Operator":"AND","Nodes":[{"Type":"Unary","Value":"deleted"},{"Type":"Binary","Operator":"==","Nodes":[{"Type":"Const","Value":"name"},{"Type":"Const","Value":"x"}]}]}]}`, errorOutput: nil},
+	}
+
+	for _, v := range values {
+		res, err := Parse(context.Background(), v.fiql)
+		assert.NoError(t, err)
+		assert.NotNil(t, res)
+		j, err := json.Marshal(&res)
+		assert.NoError(t, err)
+		assert.Equal(t, v.stringOuput, string(j))
+	}
+}
+
+func TestHandling(t *testing.T) {
+	p := New()
+	tree, err := p.Parse(context.Background(), "a==b;c==d,f==g")
+	assert.NoError(t, err)
+	j, err := json.Marshal(&tree)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"Type":"Expr","Operator":"","Nodes":[{"Type":"Binary","Operator":"OR","Nodes":[{"Type":"Binary","Operator":"AND","Nodes":[{"Type":"Binary","Operator":"==","Nodes":[{"Type":"Const","Value":"a"},{"Type":"Const","Value":"b"}]},{"Type":"Binary","Operator":"==","Nodes":[{"Type":"Const","Value":"c"},{"Type":"Const","Value":"d"}]}]},{"Type":"Binary","Operator":"==","Nodes":[{"Type":"Const","Value":"f"},{"Type":"Const","Value":"g"}]}]}]}`, string(j))
+
+	tree, err = New(WithLegacyPrecedence()).Parse(context.Background(), "a==b;c==d,f==g")
+	assert.NoError(t, err)
+	j, err = json.Marshal(&tree)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"Type":"Expr","Operator":"","Nodes":[{"Type":"Binary","Operator":"AND","Nodes":[{"Type":"Binary","Operator":"==","Nodes":[{"Type":"Const","Value":"a"},{"Type":"Const","Value":"b"}]},{"Type":"Binary","Operator":"OR","Nodes":[{"Type":"Binary","Operator":"==","Nodes":[{"Type":"Const","Value":"c"},{"Type":"Const","Value":"d"}]},{"Type":"Binary","Operator":"==","Nodes":[{"Type":"Const","Value":"f"},{"Type":"Const","Value":"g"}]}]}]}]}`, string(j))
+
+	tree, err = p.Parse(context.Background(), "(a==b;c==d),f==g")
+	assert.NoError(t, err)
+	j, err = json.Marshal(&tree)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"Type":"Expr","Operator":"","Nodes":[{"Type":"Binary","Operator":"OR","Nodes":[{"Type":"Expr","Operator":"","Nodes":[{"Type":"Binary","Operator":"AND","Nodes":[{"Type":"Binary","Operator":"==","Nodes":[{"Type":"Const","Value":"a"},{"Type":"Const","Value":"b"}]},{"Type":"Binary","Operator":"==","Nodes":[{"Type":"Const","Value":"c"},{"Type":"Const","Value":"d"}]}]}]},{"Type":"Binary","Operator":"==","Nodes":[{"Type":"Const","Value":"f"},{"Type":"Const","Value":"g"}]}]}]}`, string(j))
+
+}
+
+func TestChildren(t *testing.T) {
+	p := New()
+	tree, err := p.Parse(context.Background(), "a==b;c==d")
+	assert.NoError(t, err)
+	assert.NotNil(t, tree.Children()[0])
+	assert.NotNil(t, tree.Children()[0].Children()[0])
+	assert.NotNil(t, tree.Children()[0].Children()[1])
+
+	assert.NotNil(t, tree.Children()[0].Children()[0].Children()[0])
+	assert.NotNil(t, tree.Children()[0].Children()[0].Children()[1])
+	assert.NotNil(t, tree.Children()[0].Children()[1].Children()[0])
+	assert.NotNil(t, tree.Children()[0].Children()[1].Children()[1])
+}
+
+func TestParseUnary(t *testing.T) {
+	var values = []struct {
+		fiql        string
+		stringOuput string
+		errorOutput error
+	}{
+		{fiql: "column", stringOuput: "(column)", errorOutput: nil},
+		{fiql: "columnA,columnB==c", stringOuput: "(columnA OR columnB == c)", errorOutput: nil},
+		{fiql: "columnA,(columnB==c;columnC)", stringOuput: "(columnA OR (columnB == c AND columnC))", errorOutput: nil},
+	}
+	for _, v := range values {
+		res, err := Parse(context.Background(), v.fiql)
+		if v.errorOutput != nil {
+			assert.EqualError(t, err, v.errorOutput.Error())
+		} else {
+			if err != nil {
+				fmt.Printf(v.fiql)
+			}
+			assert.Nil(t, err)
+			assert.NotNil(t, res)
+			assert.Equal(t, v.stringOuput, res.String())
+		}
+
+	}
+}
+
+type labelVisitor struct {
+	testVisitor
+}
+
+func (t *labelVisitor) VisitLabel(label string) {
+	t.sb.WriteString(label)
+	t.sb.WriteString(":")
+}
+
+func TestLabels(t *testing.T) {
+	var values = []struct {
+		fiql        string
+		stringOuput string
+		visited     string
+	}{
+		{fiql: "urgent:(status==open;priority==high)", stringOuput: "(urgent:(status == open AND priority == high))", visited: "((urgent:status==openANDpriority==high))"},
+		{fiql: "a==b,urgent:(status==open)", stringOuput: "(a == b OR urgent:(status == open))", visited: "(a==bOR(urgent:status==open))"},
+		{fiql: "x:(a==b);y:(c==d,z:(e==f))", stringOuput: "(x:(a == b) AND y:(c == d OR z:(e == f)))", visited: "((x:a==b)AND(y:c==dOR(z:e==f)))"},
+		{fiql: "time==12:00:00", stringOuput: "(time == 12:00:00)", visited: "(time==12:00:00)"},
+	}
+	for _, v := range values {
+		res, err := Parse(context.Background(), v.fiql)
+		if !assert.NoError(t, err, v.fiql) {
+			continue
+		}
+		assert.Equal(t, v.stringOuput, res.String())
+		visitor := &labelVisitor{}
+		res.Accept(visitor)
+		assert.Equal(t, v.visited, visitor.String())
+	}
+}
+
+func TestLabelAccessorAndJSON(t *testing.T) {
+	res, err := Parse(context.Background(), "urgent:(status==open)")
+	assert.NoError(t, err)
+	sub, ok := res.Children()[0].(*Expression)
+	if assert.True(t, ok) {
+		assert.Equal(t, "urgent", sub.Label())
+	}
+	assert.Equal(t, "", res.Label())
+	j, err := json.Marshal(&res)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"Type":"Expr","Operator":"","Nodes":[{"Type":"Expr","Operator":"","Label":"urgent","Nodes":[{"Type":"Binary","Operator":"==","Nodes":[{"Type":"Const","Value":"status"},{"Type":"Const","Value":"open"}]}]}]}`, string(j))
+}
+
+func TestLabelErrors(t *testing.T) {
+	_, err := Parse(context.Background(), "urgent:(status==open")
+	assert.EqualError(t, err, "ln:1:20 syntax error (unclosed brace `)` )")
+
+	_, err = Parse(context.Background(), "urgent\\:(status==open)")
+	assert.Error(t, err)
+}
+
+func TestNestedSubExpressions(t *testing.T) {
+	var values = []struct {
+		fiql        string
+		stringOuput string
+	}{
+		{fiql: "((a==b))", stringOuput: "(((a == b)))"},
+		{fiql: "((a==b),c==d)", stringOuput: "(((a == b) OR c == d))"},
+		{fiql: "e==f;((a==b;x==y),c==d)", stringOuput: "(e == f AND ((a == b AND x == y) OR c == d))"},
+		{fiql: "x==1,(a==1),y==2", stringOuput: "(x == 1 OR (a == 1) OR y == 2)"},
+		{fiql: "(a==1),(b==1),c==1", stringOuput: "((a == 1) OR (b == 1) OR c == 1)"},
+		{fiql: "x==1;(a==1),y==2", stringOuput: "(x == 1 AND (a == 1) OR y == 2)"},
+	}
+	for _, v := range values {
+		res, err := Parse(context.Background(), v.fiql)
+		if assert.NoError(t, err, v.fiql) {
+			assert.Equal(t, v.stringOuput, res.String())
+		}
+	}
+}
+
+func TestTypedNodes(t *testing.T) {
+	tree, err := Parse(context.Background(), `a==*b;c`)
+	assert.NoError(t, err)
+	and, ok := tree.Children()[0].(BinaryNode)
+	if !assert.True(t, ok) {
+		return
+	}
+	assert.Equal(t, string(OperatorAND), and.Operator())
+	assert.True(t, and.IsLogical())
+
+	cmp, ok := and.Left().(BinaryNode)
+	if assert.True(t, ok) {
+		assert.Equal(t, string(ComparisonEq), cmp.Operator())
+		assert.False(t, cmp.IsLogical())
+		sel := cmp.Left().(ConstantNode)
+		assert.Equal(t, "a", sel.Value())
+		assert.True(t, sel.IsSelector())
+		assert.False(t, sel.IsUnary())
+		arg := cmp.Right().(ConstantNode)
+		assert.Equal(t, "b", arg.Value())
+		assert.False(t, arg.IsSelector())
+		pre, post := arg.Wildcards()
+		assert.True(t, pre)
+		assert.False(t, post)
+		assert.Equal(t, ValueRecommendationString, arg.ValueRecommendation())
+		assert.True(t, arg.Argument().StartsWithWildcard())
+	}
+	unary, ok := and.Right().(ConstantNode)
+	if assert.True(t, ok) {
+		assert.Equal(t, "c", unary.Value())
+		assert.True(t, unary.IsUnary())
+	}
+}
+
+func TestConstantPosition(t *testing.T) {
+	res, err := Parse(context.Background(), "a==1;\nname=in=[x+y]")
+	if !assert.NoError(t, err) {
+		return
+	}
+	positions := make(map[string]Position)
+	Walk(&res, func(n Node) bool {
+		if c, ok := n.(ConstantNode); ok {
+			positions[c.Value()] = c.Position()
+		}
+		return true
+	})
+	assert.Equal(t, Position{Line: 1, Column: 0, Offset: 0, ByteOffset: 0}, positions["a"])
+	assert.Equal(t, Position{Line: 1, Column: 3, Offset: 3, ByteOffset: 3}, positions["1"])
+	assert.Equal(t, Position{Line: 2, Column: 0, Offset: 6, ByteOffset: 6}, positions["name"])
+	assert.Equal(t, Position{Line: 2, Column: 8, Offset: 14, ByteOffset: 14}, positions["[x+y]"])
+}
+
+func TestParserConcurrent(t *testing.T) {
+	parser := New(WithTupleDelimiters('(', ',', ')'))
+	inputs := []string{"a==1;b=in=(x,y)", "title==foo*,(updated=lt=-P1D;title==*bar)", "c=gt=2003-12-13T00:00:00Z"}
+	expected := make([]string, len(inputs))
+	for i, in := range inputs {
+		res, err := parser.Parse(context.Background(), in)
+		assert.NoError(t, err)
+		expected[i] = res.String()
+	}
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				in := i % len(inputs)
+				res, err := parser.Parse(context.Background(), inputs[in])
+				assert.NoError(t, err)
+				assert.Equal(t, expected[in], res.String())
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+const benchmarkFIQL = "title==foo*;(updated=lt=-P1D,title==*bar);status=in=[open+closed]"
+
+func BenchmarkParse(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		p := New()
+		if _, err := p.Parse(context.Background(), benchmarkFIQL); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkParseShared(b *testing.B) {
+	p := New()
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if _, err := p.Parse(context.Background(), benchmarkFIQL); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) {
+	return 0, errors.New("broken")
+}
+
+func TestParseReader(t *testing.T) {
+	input := strings.Repeat("name==Jürgen;", 1000) + "age=gt=1"
+	res, err := ParseReader(context.Background(), strings.NewReader(input))
+	if assert.NoError(t, err) {
+		expected, _ := Parse(context.Background(), input)
+		assert.Equal(t, expected.String(), res.String())
+	}
+
+	_, err = New().ParseReader(context.Background(), strings.NewReader("a==b;"))
+	var perr *ParseError
+	assert.True(t, errors.As(err, &perr))
+
+	_, err = ParseReader(context.Background(), failingReader{})
+	assert.EqualError(t, err, "unable to read input: broken")
+}
+
+// endlessReader returns `a==1;` forever and counts the bytes read
+type endlessReader struct {
+	read int
+}
+
+func (r *endlessReader) Read(b []byte) (int, error) {
+	for i := range b {
+		b[i] = "a==1;"[(r.read+i)%5]
+	}
+	r.read += len(b)
+	return len(b), nil
+}
+
+func TestParseReaderMaxLength(t *testing.T) {
+	r := &endlessReader{}
+	_, err := New(WithMaxLength(64)).ParseReader(context.Background(), r)
+	var perr *ParseError
+	if assert.True(t, errors.As(err, &perr)) {
+		assert.Equal(t, ErrorCodeInputTooLong, perr.Code)
+		assert.EqualError(t, err, "ln:1:0 input too long (more than 64 bytes allowed)")
+	}
+	assert.Equal(t, 65, r.read)
+
+	res, err := New(WithMaxLength(9)).ParseReader(context.Background(), strings.NewReader("a==1;b==2"))
+	if assert.NoError(t, err) {
+		assert.Equal(t, "(a == 1 AND b == 2)", res.String())
+	}
+	_, err = New(WithMaxLength(8)).ParseReader(context.Background(), strings.NewReader("a==1;b==2"))
+	assert.True(t, errors.As(err, &perr))
+}
+
+func TestUnaryNodeType(t *testing.T) {
+	unaries := func(e Expression) []string {
+		res := make([]string, 0)
+		Walk(&e, func(n Node) bool {
+			if n.NodeType() == NodeTypeUnary {
+				res = append(res, n.(ConstantNode).Value())
+			}
+			return true
+		})
+		return res
+	}
+	res, err := Parse(context.Background(), "a,(b==c;d)")
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, []string{"a", "d"}, unaries(res))
+	again, err := Parse(context.Background(), res.ToFIQL())
+	if assert.NoError(t, err) {
+		assert.Equal(t, []string{"a", "d"}, unaries(again))
+	}
+}
+
+func TestTrailingInput(t *testing.T) {
+	var values = []struct {
+		fiql   string
+		token  string
+		column int
+		msg    string
+	}{
+		{fiql: "name==John Doe", token: "Doe", column: 12, msg: "ln:1:12 syntax error (unexpected `Doe` after complete expression, quote values containing whitespace)"},
+		{fiql: "name==John Doe;a==b", token: "Doe", column: 12},
+		{fiql: "(a==b) c", token: "c", column: 8},
+		{fiql: "(a==b) (c==d)", token: "(", column: 8, msg: "ln:1:8 syntax error (unexpected `(` after complete expression)"},
+	}
+	for _, v := range values {
+		_, err := Parse(context.Background(), v.fiql)
+		var perr *ParseError
+		if !assert.True(t, errors.As(err, &perr), "expected ParseError for `%s`", v.fiql) {
+			continue
+		}
+		assert.Equal(t, ErrorCodeTrailingInput, perr.Code, v.fiql)
+		assert.Equal(t, v.token, perr.Token, v.fiql)
+		assert.Equal(t, v.column, perr.Column, v.fiql)
+		if v.msg != "" {
+			assert.EqualError(t, err, v.msg)
+		}
+	}
+}
+
+func TestTrailingInputRejected(t *testing.T) {
+	// all of these were silently truncated to the first complete expression
+	var values = []struct {
+		fiql   string
+		column int
+	}{
+		{fiql: "a==b c==d", column: 6},
+		{fiql: "a==b )x", column: 4},
+		{fiql: "a==b)", column: 4},
+		{fiql: "(a==b))", column: 7},
+		{fiql: "(a==b);c==d)", column: 12},
+		{fiql: "a==b;(c==d) e", column: 13},
+		{fiql: "a==b\tx", column: 6},
+		{fiql: "(a==b)x", column: 7},
+	}
+	entryPoints := []struct {
+		name  string
+		parse func(fiql string) (interface{}, error)
+	}{
+		{name: "ParseMulti", parse: func(fiql string) (interface{}, error) { return ParseMulti(context.Background(), "a==b\n"+fiql) }},
+		{name: "ParseFile", parse: func(fiql string) (interface{}, error) {
+			return ParseFile(context.Background(), "main.fiql", mapResolver(map[string]string{"main.fiql": "a==b\n@include \"a.fiql\"", "a.fiql": fiql}))
+		}},
+		{name: "ParseReader", parse: func(fiql string) (interface{}, error) {
+			return ParseReader(context.Background(), strings.NewReader(fiql))
+		}},
+		{name: "ParseRequest", parse: func(fiql string) (interface{}, error) {
+			return ParseRequest(httptest.NewRequest(http.MethodGet, "/items?filter="+url.PathEscape(fiql), nil), "filter")
+		}},
+	}
+	for _, v := range values {
+		for _, parser := range []*Parser{New(), New(WithLogicalNodes()), New(WithLegacyPrecedence())} {
+			res, err := parser.Parse(context.Background(), v.fiql)
+			var perr *ParseError
+			if !assert.True(t, errors.As(err, &perr), "`%s` should not parse, got `%v`", v.fiql, res) {
+				continue
+			}
+			assert.Equal(t, v.column, perr.Column, v.fiql)
+		}
+		for _, entry := range entryPoints {
+			res, err := entry.parse(v.fiql)
+			var perr *ParseError
+			if !assert.True(t, errors.As(err, &perr), "%s: `%s` should not parse, got `%v`", entry.name, v.fiql, res) {
+				continue
+			}
+			assert.Equal(t, v.column, perr.Column, "%s: %s", entry.name, v.fiql)
+		}
+	}
+}
+
+func TestSpacesInValues(t *testing.T) {
+	parser := New(WithSpacesInValues())
+	var values = []struct {
+		fiql  string
+		value string
+	}{
+		{fiql: "name==John Doe", value: "John Doe"},
+		{fiql: "name==John  Doe  ;a==b", value: "John  Doe"},
+		{fiql: "(name== John Doe ),a==b", value: "John Doe"},
+		{fiql: "name==John Doe*", value: "John Doe"},
+		{fiql: `name=="John Doe"`, value: "John Doe"},
+	}
+	for _, v := range values {
+		res, err := parser.Parse(context.Background(), v.fiql)
+		if !assert.NoError(t, err, v.fiql) {
+			continue
+		}
+		visitor := &quotedVisitor{}
+		res.Accept(visitor)
+		if assert.NotEmpty(t, visitor.args, v.fiql) {
+			assert.Equal(t, v.value, visitor.args[0].AsString(), v.fiql)
+		}
+	}
+
+	// selectors are still terminated by whitespace
+	_, err := parser.Parse(context.Background(), "first name==John")
+	assert.Error(t, err)
+}
+
+func TestAllComparisonsInSync(t *testing.T) {
+	all := AllComparisons()
+	lex := &lexer{}
+	produced := make(map[Comparison]bool)
+	for _, c := range comparators {
+		tok, err := lex.toCompareToken(c)
+		if assert.NoError(t, err, c) {
+			assert.Contains(t, all, Comparison(tok.String()), "comparator `%s` is missing in AllComparisons", c)
+			produced[Comparison(tok.String())] = true
+		}
+	}
+	for _, c := range all {
+		assert.True(t, produced[c], "`%s` is not produced by any comparator", c)
+		assert.Contains(t, fiqlOperators, string(c), "`%s` has no FIQL representation", c)
+		assert.Contains(t, frozenOperators, string(c), "`%s` can not be frozen", c)
+	}
+	assert.Len(t, fiqlOperators, len(all)+2)
+}
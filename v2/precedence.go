@@ -0,0 +1,61 @@
+package fiqlparser
+
+// WithLegacyPrecedence disables operator precedence, logical operators are then
+// grouped right to left as they are encountered (`a;b,c` is `a;(b,c)`) like in previous versions
+func WithLegacyPrecedence() Option {
+	return func(p *Parser) {
+		p.legacyPrecedence = true
+	}
+}
+
+// applyPrecedence regroups the logical chains of all (sub) expressions so AND binds tighter than OR,
+// e.g. `a,b;c` is grouped as `a,(b;c)`
+func applyPrecedence(n Node) {
+	if e, ok := n.(*Expression); ok && e.node != nil {
+		e.node = regroupChain(e.node)
+	}
+	for _, c := range n.Children() {
+		if c != nil {
+			applyPrecedence(c)
+		}
+	}
+}
+
+// regroupChain regroups a chain of logical operations as produced by the parser,
+// the parser nests every following operation as right operand: `a;b,c` is AND(a, OR(b, c))
+func regroupChain(n Node) Node {
+	operands := make([]Node, 0)
+	operators := make([]string, 0)
+	for {
+		bin, ok := n.(*binaryExpression)
+		if !ok || !isLogicalOperator(bin.operator) || bin.nodes[0] == nil || bin.nodes[1] == nil {
+			operands = append(operands, n)
+			break
+		}
+		operands = append(operands, bin.nodes[0])
+		operators = append(operators, bin.operator)
+		n = bin.nodes[1]
+	}
+	if len(operators) == 0 {
+		return n
+	}
+	// split at OR into groups of AND operations
+	groups := make([]Node, 0)
+	start := 0
+	for i := 0; i <= len(operators); i++ {
+		if i < len(operators) && operators[i] != string(OperatorOR) {
+			continue
+		}
+		groups = append(groups, nestRight(string(OperatorAND), operands[start:i+1]))
+		start = i + 1
+	}
+	return nestRight(string(OperatorOR), groups)
+}
+
+// nestRight combines the operands with the operator, nesting to the right
+func nestRight(operator string, operands []Node) Node {
+	if len(operands) == 1 {
+		return operands[0]
+	}
+	return newBinary(operator, operands[0], nestRight(operator, operands[1:]))
+}
@@ -0,0 +1,67 @@
+package fiqlparser
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// groupedString renders the tree with explicit grouping of every logical operation
+func groupedString(n Node) string {
+	switch node := n.(type) {
+	case *Expression:
+		return groupedString(node.node)
+	case *binaryExpression:
+		if isLogicalOperator(node.operator) {
+			return node.operator + "(" + groupedString(node.nodes[0]) + ", " + groupedString(node.nodes[1]) + ")"
+		}
+	}
+	return n.String()
+}
+
+func TestPrecedence(t *testing.T) {
+	var values = []struct {
+		fiql    string
+		grouped string
+	}{
+		{fiql: "a==1,b==2;c==3", grouped: "OR(a == 1, AND(b == 2, c == 3))"},
+		{fiql: "a==1;b==2,c==3", grouped: "OR(AND(a == 1, b == 2), c == 3)"},
+		{fiql: "a==1;b==2,c==3;d==4", grouped: "OR(AND(a == 1, b == 2), AND(c == 3, d == 4))"},
+		{fiql: "a==1,b==2,c==3", grouped: "OR(a == 1, OR(b == 2, c == 3))"},
+		{fiql: "a==1;b==2;c==3", grouped: "AND(a == 1, AND(b == 2, c == 3))"},
+		{fiql: "a==1;(b==2,c==3)", grouped: "AND(a == 1, OR(b == 2, c == 3))"},
+		{fiql: "(a==1,b==2;c==3);d==4", grouped: "AND(OR(a == 1, AND(b == 2, c == 3)), d == 4)"},
+		{fiql: "a,b;c", grouped: "OR(a, AND(b, c))"},
+	}
+	for _, v := range values {
+		res, err := Parse(context.Background(), v.fiql)
+		if !assert.NoError(t, err, v.fiql) {
+			continue
+		}
+		assert.Equal(t, v.grouped, groupedString(&res), v.fiql)
+		// the FIQL representation keeps the meaning
+		again, err := Parse(context.Background(), res.ToFIQL())
+		if assert.NoError(t, err, v.fiql) {
+			assert.Equal(t, v.grouped, groupedString(&again), v.fiql)
+		}
+	}
+}
+
+func TestLegacyPrecedence(t *testing.T) {
+	res, err := New(WithLegacyPrecedence()).Parse(context.Background(), "a==1;b==2,c==3")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "AND(a == 1, OR(b == 2, c == 3))", groupedString(&res))
+	}
+}
+
+func TestToFIQLGroupsSynthesizedOr(t *testing.T) {
+	or := newBinary(string(OperatorOR), newBinary(string(ComparisonEq), &constantExpression{value: "a", selector: true}, &constantExpression{value: "1"}),
+		newBinary(string(ComparisonEq), &constantExpression{value: "b", selector: true}, &constantExpression{value: "2"}))
+	and := newBinary(string(OperatorAND), or, newBinary(string(ComparisonEq), &constantExpression{value: "c", selector: true}, &constantExpression{value: "3"}))
+	e := Expression{root: true, node: and}
+	assert.Equal(t, "(a==1,b==2);c==3", e.ToFIQL())
+
+	collapsed := e.CollapseLogical()
+	assert.Equal(t, "(a==1,b==2);c==3", collapsed.ToFIQL())
+}
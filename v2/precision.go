@@ -0,0 +1,174 @@
+package fiqlparser
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// DatePeriod is a set of coarse date literal kinds
+type DatePeriod int
+
+// DatePeriodMonth is a month literal, e.g. `2024-05`
+const DatePeriodMonth DatePeriod = 1
+
+// DatePeriodDay is a day literal, e.g. `2024-05-13`
+const DatePeriodDay DatePeriod = 2
+
+// DatePeriodWeek is a ISO week literal, e.g. `2024-W05`
+const DatePeriodWeek DatePeriod = 4
+
+// DatePeriodQuarter is a quarter literal, e.g. `2024-Q2`
+const DatePeriodQuarter DatePeriod = 8
+
+// DatePeriodAll contains all coarse date literal kinds
+const DatePeriodAll = DatePeriodMonth | DatePeriodDay | DatePeriodWeek | DatePeriodQuarter
+
+// coarseDateLayouts are the supported date literals with a precision coarser than RFC3339,
+// each with the size of the period it covers
+var coarseDateLayouts = []struct {
+	period DatePeriod
+	layout string
+	years  int
+	months int
+	days   int
+}{
+	{period: DatePeriodMonth, layout: "2006-01", months: 1},
+	{period: DatePeriodDay, layout: "2006-01-02", days: 1},
+}
+
+var weekRegex = regexp.MustCompile(`^(\d{4})-W(\d{2})$`)
+var quarterRegex = regexp.MustCompile(`^(\d{4})-Q([1-4])$`)
+
+// isoWeekStart returns the monday of the given ISO week
+func isoWeekStart(year int, week int, loc *time.Location) (time.Time, bool) {
+	if week < 1 || week > 53 {
+		return time.Time{}, false
+	}
+	// the 4th of january is always in the first week
+	jan4 := time.Date(year, time.January, 4, 0, 0, 0, 0, loc)
+	start := jan4.AddDate(0, 0, -((int(jan4.Weekday())+6)%7)+(week-1)*7)
+	if y, w := start.ISOWeek(); y != year || w != week {
+		return time.Time{}, false
+	}
+	return start, true
+}
+
+// coarseDateRange returns the period covered by a coarse date literal (e.g. `2024-05`), in UTC
+func coarseDateRange(value string) (time.Time, time.Time, bool) {
+	start, end, _, ok := coarseDatePeriod(value, time.UTC)
+	return start, end, ok
+}
+
+// coarseDatePeriod returns the period covered by a coarse date literal in the given location
+// and the kind of literal
+func coarseDatePeriod(value string, loc *time.Location) (time.Time, time.Time, DatePeriod, bool) {
+	for _, l := range coarseDateLayouts {
+		if len(value) != len(l.layout) {
+			continue
+		}
+		if start, err := time.ParseInLocation(l.layout, value, loc); err == nil {
+			return start, start.AddDate(l.years, l.months, l.days), l.period, true
+		}
+	}
+	if m := weekRegex.FindStringSubmatch(value); m != nil {
+		year, _ := strconv.Atoi(m[1])
+		week, _ := strconv.Atoi(m[2])
+		if start, ok := isoWeekStart(year, week, loc); ok {
+			return start, start.AddDate(0, 0, 7), DatePeriodWeek, true
+		}
+	}
+	if m := quarterRegex.FindStringSubmatch(value); m != nil {
+		year, _ := strconv.Atoi(m[1])
+		quarter, _ := strconv.Atoi(m[2])
+		start := time.Date(year, time.Month((quarter-1)*3+1), 1, 0, 0, 0, 0, loc)
+		return start, start.AddDate(0, 3, 0), DatePeriodQuarter, true
+	}
+	return time.Time{}, time.Time{}, 0, false
+}
+
+// ErrNoTimeRange is generated if a argument is not a date
+var ErrNoTimeRange = errors.New("argument is not a date")
+
+// AsTimeRange returns the period covered by the argument, start is inclusive and end exclusive.
+// Coarse dates (`2024-05`, `2024-05-13`, `2024-W05`, `2024-Q2`) cover the whole month, day,
+// ISO week or quarter,
+// a RFC3339 timestamp is returned as start and end. Coarse dates are in the location set by WithDefaultLocation.
+func (c ArgumentContext) AsTimeRange() (time.Time, time.Time, error) {
+	if start, end, _, ok := coarseDatePeriod(c.val, c.location()); ok {
+		return start, end, nil
+	}
+	t, err := time.Parse(time.RFC3339, c.val)
+	if err != nil {
+		return t, t, ErrNoTimeRange
+	}
+	return t, t, nil
+}
+
+// DateExpansion configures how coarse dates are expanded into ranges
+type DateExpansion struct {
+	// Periods are the literal kinds that are expanded, if empty all are expanded
+	Periods DatePeriod
+	// Location the periods are interpreted in, defaults to the location of the argument
+	// (see WithDefaultLocation) or UTC
+	Location *time.Location
+}
+
+// ExpandDatePrecision returns a copy of the expression where comparisons with coarse dates
+// (`2024-05`, `2024-05-13`, `2024-W05` or `2024-Q2`) are replaced by comparisons with the covered period, e.g.
+// `created==2024-05` becomes `(created=ge=2024-05-01T00:00:00Z;created=lt=2024-06-01T00:00:00Z)`
+// and `created=gt=2024-05` becomes `created=ge=2024-06-01T00:00:00Z`. Quoted arguments and arguments
+// with wildcards are left untouched.
+func (e *Expression) ExpandDatePrecision() Expression {
+	return DateExpansion{}.Expand(*e)
+}
+
+// Expand returns a copy of the expression where comparisons with coarse dates of the configured
+// kinds are replaced by comparisons with the covered period, see ExpandDatePrecision
+func (d DateExpansion) Expand(e Expression) Expression {
+	periods := d.Periods
+	if periods == 0 {
+		periods = DatePeriodAll
+	}
+	return rewriteExpression(e, func(n Node) Node {
+		bin, ok := n.(*binaryExpression)
+		if !ok || isLogicalOperator(bin.operator) {
+			return n
+		}
+		sel, arg, ok := predicateOperands(bin)
+		if !ok || arg.quote != 0 || arg.prefixWildcard || arg.suffixWildcard {
+			return n
+		}
+		loc := d.Location
+		if loc == nil {
+			loc = arg.loc
+		}
+		if loc == nil {
+			loc = time.UTC
+		}
+		start, end, period, ok := coarseDatePeriod(arg.value, loc)
+		if !ok || periods&period == 0 {
+			return n
+		}
+		bound := func(op Comparison, t time.Time) Node {
+			return newBinary(string(op), &constantExpression{selector: true, value: sel.value, recommended: ValueRecommendationString},
+				&constantExpression{value: t.Format(time.RFC3339), recommended: ValueRecommendationDateTime})
+		}
+		switch Comparison(bin.operator) {
+		case ComparisonEq:
+			return newSubExpression(newBinary(string(OperatorAND), bound(ComparisonGte, start), bound(ComparisonLt, end)))
+		case ComparisonNeq:
+			return newSubExpression(newBinary(string(OperatorOR), bound(ComparisonLt, start), bound(ComparisonGte, end)))
+		case ComparisonGt:
+			return bound(ComparisonGte, end)
+		case ComparisonGte:
+			return bound(ComparisonGte, start)
+		case ComparisonLt:
+			return bound(ComparisonLt, start)
+		case ComparisonLte:
+			return bound(ComparisonLt, end)
+		}
+		return n
+	})
+}
@@ -0,0 +1,125 @@
+package fiqlparser
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpandDatePrecision(t *testing.T) {
+	var values = []struct {
+		fiql     string
+		expanded string
+	}{
+		{fiql: "created==2024-05", expanded: "(created=ge=2024-05-01T00:00:00Z;created=lt=2024-06-01T00:00:00Z)"},
+		{fiql: "created==2024-12", expanded: "(created=ge=2024-12-01T00:00:00Z;created=lt=2025-01-01T00:00:00Z)"},
+		{fiql: "created==2024-02-29", expanded: "(created=ge=2024-02-29T00:00:00Z;created=lt=2024-03-01T00:00:00Z)"},
+		{fiql: "created!=2024-05", expanded: "(created=lt=2024-05-01T00:00:00Z,created=ge=2024-06-01T00:00:00Z)"},
+		{fiql: "created=gt=2024-05", expanded: "created=ge=2024-06-01T00:00:00Z"},
+		{fiql: "created=ge=2024-05", expanded: "created=ge=2024-05-01T00:00:00Z"},
+		{fiql: "created=lt=2024-05-13", expanded: "created=lt=2024-05-13T00:00:00Z"},
+		{fiql: "created=le=2024-05-13", expanded: "created=lt=2024-05-14T00:00:00Z"},
+		{fiql: "a==1,created==2024-05;b==2", expanded: "a==1,(created=ge=2024-05-01T00:00:00Z;created=lt=2024-06-01T00:00:00Z);b==2"},
+		{fiql: "created==2024-05-01T10:00:00Z", expanded: "created==2024-05-01T10:00:00Z"},
+		{fiql: "created==\"2024-05\"", expanded: "created==\"2024-05\""},
+		{fiql: "created==2024-05*", expanded: "created==2024-05*"},
+		{fiql: "created==2024-13", expanded: "created==2024-13"},
+		{fiql: "created==2024", expanded: "created==2024"},
+		{fiql: "created==2024-W05", expanded: "(created=ge=2024-01-29T00:00:00Z;created=lt=2024-02-05T00:00:00Z)"},
+		{fiql: "created==2026-W01", expanded: "(created=ge=2025-12-29T00:00:00Z;created=lt=2026-01-05T00:00:00Z)"},
+		{fiql: "created==2020-W53", expanded: "(created=ge=2020-12-28T00:00:00Z;created=lt=2021-01-04T00:00:00Z)"},
+		{fiql: "created==2024-W53", expanded: "created==2024-W53"},
+		{fiql: "created=le=2024-Q2", expanded: "created=lt=2024-07-01T00:00:00Z"},
+		{fiql: "created==2024-Q4", expanded: "(created=ge=2024-10-01T00:00:00Z;created=lt=2025-01-01T00:00:00Z)"},
+		{fiql: "created==2024-Q5", expanded: "created==2024-Q5"},
+	}
+	for _, v := range values {
+		res, err := Parse(context.Background(), v.fiql)
+		if !assert.NoError(t, err, v.fiql) {
+			continue
+		}
+		expanded := res.ExpandDatePrecision()
+		assert.Equal(t, v.expanded, expanded.ToFIQL())
+	}
+}
+
+func TestDateExpansion(t *testing.T) {
+	res, err := Parse(context.Background(), "a==2024-Q2;b==2024-05")
+	assert.NoError(t, err)
+	expanded := DateExpansion{Periods: DatePeriodQuarter | DatePeriodWeek}.Expand(res)
+	assert.Equal(t, "(a=ge=2024-04-01T00:00:00Z;a=lt=2024-07-01T00:00:00Z);b==2024-05", expanded.ToFIQL())
+
+	loc := time.FixedZone("CET", 3600)
+	res, err = Parse(context.Background(), "a=ge=2024-W05")
+	assert.NoError(t, err)
+	expanded = DateExpansion{Location: loc}.Expand(res)
+	assert.Equal(t, "a=ge=2024-01-29T00:00:00+01:00", expanded.ToFIQL())
+
+	// the default location of the parser applies unless a location is configured
+	res, err = Parse(context.Background(), "a==2024-05", WithDefaultLocation(loc))
+	assert.NoError(t, err)
+	expanded = res.ExpandDatePrecision()
+	assert.Equal(t, "(a=ge=2024-05-01T00:00:00+01:00;a=lt=2024-06-01T00:00:00+01:00)", expanded.ToFIQL())
+	expanded = DateExpansion{Location: time.UTC}.Expand(res)
+	assert.Equal(t, "(a=ge=2024-05-01T00:00:00Z;a=lt=2024-06-01T00:00:00Z)", expanded.ToFIQL())
+}
+
+func TestExpandDatePrecisionKeepsOriginal(t *testing.T) {
+	res, err := Parse(context.Background(), "created==2024-05")
+	assert.NoError(t, err)
+	_ = res.ExpandDatePrecision()
+	assert.Equal(t, "created==2024-05", res.ToFIQL())
+}
+
+func TestCoarseDateRecommendation(t *testing.T) {
+	res, err := Parse(context.Background(), "created=gt=2024-05")
+	assert.NoError(t, err)
+	v := &testTypeVisitor{}
+	res.Accept(v)
+	assert.Equal(t, "datetime", v.String())
+	assert.Equal(t, time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), v.raw)
+}
+
+type timeRangeVisitor struct {
+	testTypeVisitor
+	start time.Time
+	end   time.Time
+	err   error
+}
+
+func (t *timeRangeVisitor) VisitArgument(argumentCtx ArgumentContext) {
+	t.start, t.end, t.err = argumentCtx.AsTimeRange()
+}
+
+func TestAsTimeRange(t *testing.T) {
+	var values = []struct {
+		fiql  string
+		start time.Time
+		end   time.Time
+		err   error
+	}{
+		{fiql: "a==2024-05", start: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), end: time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)},
+		{fiql: "a==2024-05-31", start: time.Date(2024, 5, 31, 0, 0, 0, 0, time.UTC), end: time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)},
+		{fiql: "a==2024-05-31T10:00:00Z", start: time.Date(2024, 5, 31, 10, 0, 0, 0, time.UTC), end: time.Date(2024, 5, 31, 10, 0, 0, 0, time.UTC)},
+		{fiql: "a==2024-W05", start: time.Date(2024, 1, 29, 0, 0, 0, 0, time.UTC), end: time.Date(2024, 2, 5, 0, 0, 0, 0, time.UTC)},
+		{fiql: "a==2024-Q1", start: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), end: time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)},
+		{fiql: "a==foo", err: ErrNoTimeRange},
+	}
+	for _, v := range values {
+		res, err := Parse(context.Background(), v.fiql)
+		if !assert.NoError(t, err) {
+			continue
+		}
+		visitor := &timeRangeVisitor{}
+		res.Accept(visitor)
+		if v.err != nil {
+			assert.ErrorIs(t, visitor.err, v.err)
+			continue
+		}
+		assert.NoError(t, visitor.err)
+		assert.Equal(t, v.start, visitor.start, v.fiql)
+		assert.Equal(t, v.end, visitor.end, v.fiql)
+	}
+}
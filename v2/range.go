@@ -0,0 +1,101 @@
+package fiqlparser
+
+import (
+	"errors"
+	"strings"
+)
+
+// rangeSeparator separates the bounds of a range literal
+const rangeSeparator = ".."
+
+// ErrNoRange is generated if a argument is not a range
+var ErrNoRange = errors.New("argument is not a range")
+
+// rangeBounds splits a range literal (e.g. `10..20`) into its bounds,
+// both bounds have to be numbers, dates or durations of the same kind
+func rangeBounds(value string) (*constantExpression, *constantExpression, bool) {
+	i := strings.Index(value, rangeSeparator)
+	if i <= 0 {
+		return nil, nil, false
+	}
+	low, high := value[:i], value[i+len(rangeSeparator):]
+	// e.g. `1...2` or `1..2..3`, the remaining dots belong to neither bound
+	if strings.HasPrefix(high, ".") || strings.Contains(high, rangeSeparator) {
+		return nil, nil, false
+	}
+	ok, lowRec, _ := numberOrDateExpressionValidator(low)
+	if !ok {
+		return nil, nil, false
+	}
+	ok, highRec, _ := numberOrDateExpressionValidator(high)
+	if !ok || lowRec != highRec {
+		return nil, nil, false
+	}
+	return &constantExpression{value: low, recommended: lowRec}, &constantExpression{value: high, recommended: highRec}, true
+}
+
+// tupleRangeBounds returns the bounds of a tuple with two elements (e.g. `[10+20]`),
+// both bounds have to be unquoted numbers, dates or durations of the same kind
+func tupleRangeBounds(t *tupleArgument) (*constantExpression, *constantExpression, bool) {
+	if len(t.elements) != 2 {
+		return nil, nil, false
+	}
+	low, high := t.elements[0], t.elements[1]
+	for _, el := range t.elements {
+		if el.quote != 0 || el.prefixWildcard || el.suffixWildcard {
+			return nil, nil, false
+		}
+		if ok, _, _ := numberOrDateExpressionValidator(el.value); !ok {
+			return nil, nil, false
+		}
+	}
+	if low.recommended != high.recommended {
+		return nil, nil, false
+	}
+	return low, high, true
+}
+
+// argumentRangeBounds returns the bounds of a range literal or a range tuple
+func argumentRangeBounds(value string, t *tupleArgument) (*constantExpression, *constantExpression, bool) {
+	if t != nil {
+		return tupleRangeBounds(t)
+	}
+	return rangeBounds(value)
+}
+
+func (p *Parser) handleRangeArgument() (Node, error) {
+	elements, ok, err := p.lex.readTuple(p.tupleDelimiters())
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		if p.dynamicArguments {
+			return p.handleArgumentConstant(dynamicValidator(rangeValidator))
+		}
+		return p.handleArgumentConstant(rangeValidator)
+	}
+	for _, el := range elements {
+		p.lex.countNode(constantSize, el.value)
+		_, el.recommended, _ = defaultValidator(el.value)
+		el.plainLiteral()
+	}
+	tuple := &tupleArgument{delimiters: p.tupleDelimiters(), elements: elements}
+	if _, _, ok := tupleRangeBounds(tuple); !ok {
+		return nil, p.lex.errInvalidValue(p.lex.lastValue(), []string{"range"})
+	}
+	p.lex.countNode(constantSize+tupleSize+len(elements)*pointerSize, p.lex.lastValue())
+	return &constantExpression{value: p.lex.lastValue(), recommended: ValueRecommendationRange, tuple: tuple, pos: p.lex.start}, nil
+}
+
+// AsRange returns the inclusive bounds of a range literal (e.g. `10..20`)
+// or a range tuple (e.g. `[10+20]`), each with its own value recommendation
+func (c ArgumentContext) AsRange() (ArgumentContext, ArgumentContext, error) {
+	if c.r != ValueRecommendationRange {
+		return ArgumentContext{}, ArgumentContext{}, ErrNoRange
+	}
+	low, high, ok := argumentRangeBounds(c.val, c.tuple)
+	if !ok {
+		return ArgumentContext{}, ArgumentContext{}, ErrNoRange
+	}
+	return low.Argument(), high.Argument(), nil
+}
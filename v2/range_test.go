@@ -0,0 +1,92 @@
+package fiqlparser
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type rangeVisitor struct {
+	testTypeVisitor
+	low  ArgumentContext
+	high ArgumentContext
+	err  error
+}
+
+func (r *rangeVisitor) VisitArgument(argumentCtx ArgumentContext) {
+	r.testTypeVisitor.VisitArgument(argumentCtx)
+	r.low, r.high, r.err = argumentCtx.AsRange()
+}
+
+func TestRangeLiteral(t *testing.T) {
+	var values = []struct {
+		fiql string
+		rec  string
+		low  string
+		high string
+		kind ValueRecommendation
+	}{
+		{fiql: "price==10..20", rec: "range", low: "10", high: "20", kind: ValueRecommendationNumber},
+		{fiql: "price=bt=-1.5..2.5", rec: "range", low: "-1.5", high: "2.5", kind: ValueRecommendationNumber},
+		{fiql: "created!=2021-01-01T00:00:00Z..2021-02-01T00:00:00Z", rec: "range", low: "2021-01-01T00:00:00Z", high: "2021-02-01T00:00:00Z", kind: ValueRecommendationDateTime},
+		{fiql: "created=bt=2024-01..2024-03", rec: "range", low: "2024-01", high: "2024-03", kind: ValueRecommendationDateTime},
+		{fiql: "age=BT=-P10Y..-P5Y", rec: "range", low: "-P10Y", high: "-P5Y", kind: ValueRecommendationDuration},
+		{fiql: "created=between=2021-01-01T00:00:00Z..2021-02-01T00:00:00Z", rec: "range", low: "2021-01-01T00:00:00Z", high: "2021-02-01T00:00:00Z", kind: ValueRecommendationDateTime},
+		{fiql: "price=bt=[10+20]", rec: "range", low: "10", high: "20", kind: ValueRecommendationNumber},
+		{fiql: "name==a..b", rec: "string"},
+		{fiql: "name==x..20", rec: "string"},
+		{fiql: "name==10..2024-01", rec: "string"},
+		{fiql: `price=="10..20"`, rec: "string"},
+		{fiql: "price==10..20*", rec: "string"},
+		{fiql: "price==1...2", rec: "string"},
+		{fiql: "price==1..2..3", rec: "string"},
+	}
+	for _, v := range values {
+		res, err := Parse(context.Background(), v.fiql)
+		if !assert.NoError(t, err, v.fiql) {
+			continue
+		}
+		visitor := &rangeVisitor{}
+		res.Accept(visitor)
+		assert.Equal(t, v.rec, visitor.String(), v.fiql)
+		if v.rec != "range" {
+			assert.ErrorIs(t, visitor.err, ErrNoRange, v.fiql)
+			continue
+		}
+		assert.NoError(t, visitor.err, v.fiql)
+		assert.Equal(t, v.low, visitor.low.AsString(), v.fiql)
+		assert.Equal(t, v.high, visitor.high.AsString(), v.fiql)
+		assert.Equal(t, v.kind, visitor.low.ValueRecommendation(), v.fiql)
+		assert.Equal(t, v.kind, visitor.high.ValueRecommendation(), v.fiql)
+	}
+}
+
+func TestRangeComparison(t *testing.T) {
+	res, err := Parse(context.Background(), "price=bt=10..20")
+	assert.NoError(t, err)
+	assert.Equal(t, "(price BETWEEN 10..20)", res.String())
+	assert.Equal(t, "price=bt=10..20", res.ToFIQL())
+
+	res, err = Parse(context.Background(), "price=between=[10+20]")
+	assert.NoError(t, err)
+	assert.Equal(t, "price=bt=[10+20]", res.ToFIQL())
+
+	res, err = New(WithTupleDelimiters('(', ',', ')')).Parse(context.Background(), "price=bt=(10,20);a==1")
+	if assert.NoError(t, err) {
+		sql, args, err := (&SQLTranslator{Columns: map[string]string{"price": "price", "a": "a"}}).Translate(res)
+		assert.NoError(t, err)
+		assert.Equal(t, "price BETWEEN ? AND ? AND a = ?", sql)
+		assert.Equal(t, []interface{}{int64(10), int64(20), int64(1)}, args)
+	}
+
+	for _, fiql := range []string{"price=bt=10", "price=bt=[10]", "price=bt=[1+2+3]", "price=bt=[1+a]", `price=bt=["1"+2]`, "price=bt=[1+2003-12-13T00:00:00Z]",
+		"price=bt=1...2", "price=bt=1..2..3", "price=bt=1..2.", "price=bt=.1..2"} {
+		_, err = Parse(context.Background(), fiql)
+		var parseErr *ParseError
+		if assert.ErrorAs(t, err, &parseErr, fiql) {
+			assert.Equal(t, ErrorCodeInvalidValue, parseErr.Code, fiql)
+			assert.Equal(t, []string{"range"}, parseErr.Expected, fiql)
+		}
+	}
+}
@@ -0,0 +1,287 @@
+package fiqlparser
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxRecoveries bounds the repairs of ParseLenient per byte of input
+const maxRecoveries = 4
+
+// ParseLenient parses the supplied fiql on a best effort basis, e.g. for interactive query builders.
+// Instead of stopping at the first syntax error the input is repaired and parsed again until it is valid:
+// missing arguments are replaced by placeholders (see ConstantNode.IsPlaceholder),
+// invalid arguments by placeholders of the expected type, unclosed braces and quotes are closed
+// and any other offending token (e.g. a dangling operator) is dropped.
+// All errors met are returned positioned within input, so are the positions of the nodes.
+// Errors caused by earlier repairs are not reported.
+// Inputs exceeding WithMaxLength or the budget as well as violations of WithStrictSpec are not repaired,
+// the empty expression is returned with the error.
+// Once the context is done the empty expression is returned with the errors met so far,
+// the last of which wraps the error of the context.
+func (p *Parser) ParseLenient(ctx context.Context, input string) (Expression, []*ParseError) {
+	r := newRecovery(input)
+	errs := make([]*ParseError, 0)
+	var last *ParseError
+	for attempt := 0; attempt <= maxRecoveries*len(input)+8; attempt++ {
+		e, err := p.Parse(ctx, r.src)
+		if err == nil {
+			return r.result(e), errs
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return Expression{root: true}, append(errs, &ParseError{Line: 1, Code: ErrorCodeUnexpectedInput, msg: ctxErr.Error(), err: ctxErr, start: Position{Line: 1}})
+		}
+		var perr *ParseError
+		if !errors.As(err, &perr) {
+			perr = &ParseError{Line: 1, Code: ErrorCodeUnexpectedInput, msg: err.Error(), err: err, start: Position{Line: 1}}
+		}
+		start, end := locateToken(r.src, perr.ByteOffset, perr.Token)
+		if !r.placeholderAt(start, end) {
+			errs = append(errs, r.original(perr))
+		}
+		repeated := last != nil && last.Code == perr.Code && last.ByteOffset == perr.ByteOffset && last.Token == perr.Token
+		last = perr
+		if repeated || !r.repair(p, perr, start, end) {
+			if perr.Code == ErrorCodeInputTooLong || perr.Code == ErrorCodeBudgetExceeded || perr.Code == ErrorCodeSpecViolation {
+				return Expression{root: true}, errs
+			}
+			return r.result(e), errs
+		}
+	}
+	return Expression{root: true}, errs
+}
+
+// ParseAll parses the supplied fiql like Parse, but does not stop at the first syntax error:
+// the error is of type ParseErrors and reports every problem found with its position, e.g. to underline all of them at once.
+// The errors are collected as by ParseLenient, the expression is only valid if the error is nil.
+// Once the context is done the parse fails with its error.
+func (p *Parser) ParseAll(ctx context.Context, input string) (Expression, error) {
+	e, errs := p.ParseLenient(ctx, input)
+	if err := ctx.Err(); err != nil {
+		return Expression{root: true}, err
+	}
+	if len(errs) == 0 {
+		return e, nil
+	}
+	sort.SliceStable(errs, func(i, j int) bool { return errs[i].ByteOffset < errs[j].ByteOffset })
+	return Expression{root: true}, ParseErrors(errs)
+}
+
+// ParseAll instant parses the supplied fiql with a parser configured by opts
+// and reports all syntax errors, see Parser.ParseAll
+func ParseAll(ctx context.Context, input string, opts ...Option) (Expression, error) {
+	return New(opts...).ParseAll(ctx, input)
+}
+
+// ParseLenient instant parses the supplied fiql with a parser configured by opts
+// on a best effort basis, see Parser.ParseLenient
+func ParseLenient(ctx context.Context, input string, opts ...Option) (Expression, []*ParseError) {
+	return New(opts...).ParseLenient(ctx, input)
+}
+
+// recovery is the state of a lenient parse
+type recovery struct {
+	input string
+	// src is the repaired input
+	src string
+	// offsets maps the byte offsets of src to the byte offsets of input, inserted text to its insertion point
+	offsets []int
+	// placeholders are the byte ranges of the placeholders within src
+	placeholders [][2]int
+}
+
+func newRecovery(input string) *recovery {
+	offsets := make([]int, len(input)+1)
+	for i := range offsets {
+		offsets[i] = i
+	}
+	return &recovery{input: input, src: input, offsets: offsets}
+}
+
+// repair fixes the error, false if it can not be repaired
+func (r *recovery) repair(p *Parser, perr *ParseError, start int, end int) bool {
+	switch perr.Code {
+	case ErrorCodeInputTooLong, ErrorCodeBudgetExceeded, ErrorCodeSpecViolation:
+		return false
+	case ErrorCodeUnexpectedToken:
+		if containsString(perr.Expected, "value") {
+			r.edit(start, start, `""`, true)
+			return true
+		}
+	case ErrorCodeInvalidValue:
+		r.edit(start, end, placeholderValue(p, perr.Expected), true)
+		return true
+	case ErrorCodeUnclosedBrace:
+		if start == len(r.src) {
+			r.edit(start, start, ")", false)
+			return true
+		}
+	case ErrorCodeDanglingComparator:
+		// the comparison misses its selector, it is dropped up to the next operator
+		if i := strings.IndexAny(r.src[end:], ";,)"); i >= 0 {
+			end += i
+		} else {
+			end = len(r.src)
+		}
+	case ErrorCodeUnexpectedEOF:
+		if len(perr.Expected) == 1 && (perr.Expected[0] == `"` || perr.Expected[0] == "'") {
+			r.edit(len(r.src), len(r.src), perr.Expected[0], false)
+			return true
+		}
+	case ErrorCodeDanglingOperator:
+		if start == end {
+			// the operator precedes the end of the input or a closing brace
+			i := len(strings.TrimRight(r.src[:start], " \t\r\n")) - 1
+			if i < 0 || (r.src[i] != ';' && r.src[i] != ',') {
+				return false
+			}
+			start, end = i, i+1
+		}
+	}
+	if start == end {
+		if start >= len(r.src) {
+			return false
+		}
+		_, size := utf8.DecodeRuneInString(r.src[start:])
+		end = start + size
+	}
+	r.edit(start, end, "", false)
+	return true
+}
+
+// edit replaces src[start:end] with s, placeholders overlapping the range are dropped
+func (r *recovery) edit(start int, end int, s string, placeholder bool) {
+	r.src = r.src[:start] + s + r.src[end:]
+	offsets := make([]int, 0, len(r.offsets)-(end-start)+len(s))
+	offsets = append(offsets, r.offsets[:start]...)
+	for i := 0; i < len(s); i++ {
+		offsets = append(offsets, r.offsets[start])
+	}
+	r.offsets = append(offsets, r.offsets[end:]...)
+	placeholders := r.placeholders[:0]
+	for _, ph := range r.placeholders {
+		switch {
+		case ph[1] <= start:
+		case ph[0] >= end:
+			ph[0] += len(s) - (end - start)
+			ph[1] += len(s) - (end - start)
+		default:
+			continue
+		}
+		placeholders = append(placeholders, ph)
+	}
+	r.placeholders = placeholders
+	if placeholder {
+		r.placeholders = append(r.placeholders, [2]int{start, start + len(s)})
+	}
+}
+
+// placeholderAt indicates whether the range of src overlaps a placeholder
+func (r *recovery) placeholderAt(start int, end int) bool {
+	for _, ph := range r.placeholders {
+		if start < ph[1] && end > ph[0] {
+			return true
+		}
+	}
+	return false
+}
+
+// original returns the error positioned within the input
+func (r *recovery) original(perr *ParseError) *ParseError {
+	e := *perr
+	pos := r.position(perr.ByteOffset)
+	// the column of a error is not necessarily the column of its offset (see ParseError.Column), keep the difference
+	offset := perr.ByteOffset
+	if offset < 0 {
+		offset = 0
+	}
+	if offset > len(r.src) {
+		offset = len(r.src)
+	}
+	before := r.src[:offset]
+	shift := perr.Column - utf8.RuneCountInString(before[strings.LastIndexByte(before, '\n')+1:])
+	e.Line, e.Column, e.Offset, e.ByteOffset, e.start = pos.Line, pos.Column+shift, pos.Offset, pos.ByteOffset, pos
+	return &e
+}
+
+// position converts a byte offset of src to a position within the input
+func (r *recovery) position(offset int) Position {
+	if offset < 0 {
+		offset = 0
+	}
+	if offset >= len(r.offsets) {
+		offset = len(r.offsets) - 1
+	}
+	b := r.offsets[offset]
+	before := r.input[:b]
+	pos := Position{Line: strings.Count(before, "\n") + 1, Offset: utf8.RuneCountInString(before), ByteOffset: b}
+	pos.Column = utf8.RuneCountInString(before[strings.LastIndexByte(before, '\n')+1:])
+	return pos
+}
+
+// result marks the placeholders of the expression and positions its constants within the input
+func (r *recovery) result(e Expression) Expression {
+	Walk(&e, func(n Node) bool {
+		if c, ok := n.(*constantExpression); ok {
+			c.placeholder = r.inPlaceholder(c.pos.ByteOffset)
+			c.pos = r.position(c.pos.ByteOffset)
+		}
+		return true
+	})
+	return e
+}
+
+// inPlaceholder indicates whether the byte offset of src lies within a placeholder
+func (r *recovery) inPlaceholder(offset int) bool {
+	for _, ph := range r.placeholders {
+		if offset >= ph[0] && offset < ph[1] {
+			return true
+		}
+	}
+	return false
+}
+
+// placeholderValue returns a argument satisfying the expectation of a invalid value error
+func placeholderValue(p *Parser, expected []string) string {
+	switch {
+	case containsString(expected, "number"):
+		return "0"
+	case containsString(expected, "range"):
+		return "0..0"
+	case containsString(expected, "tuple"), containsString(expected, "value"):
+		d := p.tupleDelimiters()
+		return string(d.Open) + `""` + string(d.Close)
+	}
+	return `""`
+}
+
+// locateToken returns the byte range of the offending token of a error starting at offset,
+// a quoted value is located with its quotes
+func locateToken(src string, offset int, token string) (int, int) {
+	if offset > len(src) {
+		offset = len(src)
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	n := len(token)
+	if offset+n+2 <= len(src) && (src[offset] == '"' || src[offset] == '\'') && src[offset+n+1] == src[offset] && src[offset+1:offset+n+1] == token {
+		return offset, offset + n + 2
+	}
+	if strings.HasPrefix(src[offset:], token) {
+		return offset, offset + n
+	}
+	return offset, offset
+}
+
+func containsString(values []string, v string) bool {
+	for _, c := range values {
+		if c == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,116 @@
+package fiqlparser
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseLenient(t *testing.T) {
+	var values = []struct {
+		input    string
+		expected string
+		codes    []ErrorCode
+	}{
+		{input: "name==John;age=gt=18", expected: "(name == John AND age > 18)"},
+		{input: "name==", expected: `(name == "")`, codes: []ErrorCode{ErrorCodeUnexpectedToken}},
+		{input: "name==;a==1", expected: `(name == "" AND a == 1)`, codes: []ErrorCode{ErrorCodeUnexpectedToken}},
+		{input: "a==1;", expected: "(a == 1)", codes: []ErrorCode{ErrorCodeDanglingOperator}},
+		{input: ";a==1", expected: "(a == 1)", codes: []ErrorCode{ErrorCodeDanglingOperator}},
+		{input: "a==1==2", expected: "(a == 1)", codes: []ErrorCode{ErrorCodeDanglingComparator}},
+		{input: "a==1)", expected: "(a == 1)", codes: []ErrorCode{ErrorCodeInvalidClosingBrace}},
+		{input: "(a==1;b==2", expected: "((a == 1 AND b == 2))", codes: []ErrorCode{ErrorCodeUnclosedBrace}},
+		{input: "a==John Doe", expected: "(a == John)", codes: []ErrorCode{ErrorCodeTrailingInput}},
+		{input: `a=="John`, expected: `(a == "John")`, codes: []ErrorCode{ErrorCodeUnexpectedEOF}},
+		{input: "a=gt=abc", expected: "(a > 0)", codes: []ErrorCode{ErrorCodeInvalidValue}},
+		{input: "a=bt=x", expected: "(a BETWEEN 0..0)", codes: []ErrorCode{ErrorCodeInvalidValue}},
+		{input: "a=gt=;b=in=;c=bt=", expected: `(a > 0 AND b IN [""] AND c BETWEEN 0..0)`,
+			codes: []ErrorCode{ErrorCodeUnexpectedToken, ErrorCodeUnexpectedToken, ErrorCodeUnexpectedToken}},
+		{input: "a==1;;b=gt=x,", expected: "(a == 1 AND b > 0)",
+			codes: []ErrorCode{ErrorCodeDanglingOperator, ErrorCodeInvalidValue, ErrorCodeDanglingOperator}},
+	}
+	for _, v := range values {
+		e, errs := ParseLenient(context.Background(), v.input)
+		assert.Equal(t, v.expected, e.String(), v.input)
+		codes := make([]ErrorCode, 0, len(errs))
+		for _, err := range errs {
+			codes = append(codes, err.Code)
+		}
+		assert.Equal(t, append([]ErrorCode{}, v.codes...), codes, v.input)
+	}
+}
+
+func TestParseLenientPositions(t *testing.T) {
+	e, errs := ParseLenient(context.Background(), "a==1\n;;ä=gt=x;b==2")
+	if assert.Len(t, errs, 2) {
+		assert.Equal(t, [3]int{2, 2, 6}, [3]int{errs[0].Line, errs[0].Column, errs[0].ByteOffset})
+		assert.Equal(t, [3]int{2, 8, 13}, [3]int{errs[1].Line, errs[1].Column, errs[1].ByteOffset})
+	}
+	positions := make(map[string]Position)
+	placeholders := make([]string, 0)
+	Walk(&e, func(n Node) bool {
+		if c, ok := n.(ConstantNode); ok {
+			positions[c.Value()] = c.Position()
+			if c.IsPlaceholder() {
+				placeholders = append(placeholders, c.Value())
+			}
+		}
+		return true
+	})
+	assert.Equal(t, Position{Line: 2, Column: 2, Offset: 7, ByteOffset: 7}, positions["ä"])
+	assert.Equal(t, Position{Line: 2, Column: 9, Offset: 14, ByteOffset: 15}, positions["b"])
+	assert.Equal(t, []string{"0"}, placeholders)
+}
+
+func TestParseLenientPlaceholders(t *testing.T) {
+	e, _ := New(WithTupleDelimiters('(', ',', ')')).ParseLenient(context.Background(), "a=in=;b==")
+	placeholders := 0
+	Walk(&e, func(n Node) bool {
+		if c, ok := n.(ConstantNode); ok && c.IsPlaceholder() {
+			placeholders++
+		}
+		return true
+	})
+	assert.Equal(t, `(a IN ("") AND b == "")`, e.String())
+	// the tuple, its element and the argument of b
+	assert.Equal(t, 3, placeholders)
+
+	e, errs := ParseLenient(context.Background(), `a==""`)
+	assert.Empty(t, errs)
+	Walk(&e, func(n Node) bool {
+		if c, ok := n.(ConstantNode); ok {
+			assert.False(t, c.IsPlaceholder())
+		}
+		return true
+	})
+}
+
+func TestParseLenientUnrecoverable(t *testing.T) {
+	e, errs := New(WithMaxLength(4)).ParseLenient(context.Background(), "a==1;b==2")
+	assert.Equal(t, "()", e.String())
+	if assert.Len(t, errs, 1) {
+		assert.Equal(t, ErrorCodeInputTooLong, errs[0].Code)
+	}
+}
+
+func TestParseAll(t *testing.T) {
+	e, err := ParseAll(context.Background(), "a==1;b==2")
+	assert.NoError(t, err)
+	assert.Equal(t, "(a == 1 AND b == 2)", e.String())
+
+	_, err = ParseAll(context.Background(), "a=gt=x;;b==\"c")
+	var errs ParseErrors
+	if assert.ErrorAs(t, err, &errs) && assert.Len(t, errs, 3) {
+		assert.Equal(t, []int{5, 7, 11}, []int{errs[0].ByteOffset, errs[1].ByteOffset, errs[2].ByteOffset})
+		assert.Equal(t, errs[0].Error()+"; "+errs[1].Error()+"; "+errs[2].Error(), err.Error())
+	}
+	var perr *ParseError
+	if assert.ErrorAs(t, err, &perr) {
+		assert.Equal(t, ErrorCodeInvalidValue, perr.Code)
+	}
+	assert.ErrorIs(t, err, perr)
+
+	_, err = ParseAll(context.Background(), "a==\"b")
+	assert.ErrorIs(t, err, ErrUnexpectedEOF)
+}
@@ -0,0 +1,122 @@
+package fiqlparser
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrUnresolvedArgument is generated for dynamic arguments without resolver, see DynamicArguments
+var ErrUnresolvedArgument = errors.New("unresolved argument")
+
+// ArgumentResolver returns the value of a dynamic argument from the context, e.g. the id of the authenticated user.
+// The value is typed like a parsed argument, e.g. `42` is a number and `2024-05-01` a datetime,
+// with the datetime layouts and the default location of the parser (see WithDateTimeLayouts and WithDefaultLocation).
+type ArgumentResolver func(ctx context.Context) (string, error)
+
+// DynamicArguments binds arguments which are only known when a expression is translated or evaluated,
+// e.g. `owner==@me` where `@me` is the authenticated user.
+// It is safe for concurrent use as long as its fields are not modified.
+type DynamicArguments struct {
+	// Resolvers maps the names of the dynamic arguments (without `@`) to their resolver
+	Resolvers map[string]ArgumentResolver
+	// Strict rejects `@name` arguments without resolver instead of keeping them as literals
+	Strict bool
+}
+
+// WithDynamicArguments accepts unquoted `@name` arguments for the comparisons validating their argument,
+// e.g. `age=gt=@limit` or `created=bt=@period`, the resolved values are typed by DynamicArguments.Resolve.
+// Equality comparisons and tuples accept them without this option. The bounds of range tuples
+// (`=bt=[@a+@b]`) can not be dynamic.
+func WithDynamicArguments() Option {
+	return func(p *Parser) {
+		p.dynamicArguments = true
+	}
+}
+
+// Resolve returns a copy of the expression where unquoted `@name` arguments, also within tuples,
+// are replaced by the value of the resolver registered for name, e.g. `owner==@me` becomes `owner==42`.
+// Every resolver is called at most once per call. Quoted arguments (`"@me"`) and arguments with wildcards
+// remain literals. Field references (see WithFieldReferences) to a registered name are resolved as well,
+// so dynamic arguments keep their meaning if field references are enabled.
+func (d DynamicArguments) Resolve(ctx context.Context, e Expression) (Expression, error) {
+	values := make(map[string]string)
+	var err error
+	res := rewriteExpression(e, func(n Node) Node {
+		c, ok := n.(*constantExpression)
+		if !ok || c.selector || err != nil {
+			return n
+		}
+		if c.tuple != nil {
+			tuple := &tupleArgument{delimiters: c.tuple.delimiters, elements: make([]*constantExpression, 0, len(c.tuple.elements))}
+			for _, el := range c.tuple.elements {
+				resolved, rerr := d.resolve(ctx, el, values)
+				if rerr != nil {
+					err = rerr
+					return n
+				}
+				tuple.elements = append(tuple.elements, resolved)
+			}
+			var b strings.Builder
+			writeTuple(&b, tuple)
+			c.tuple, c.value = tuple, b.String()
+			return c
+		}
+		resolved, rerr := d.resolve(ctx, c, values)
+		if rerr != nil {
+			err = rerr
+			return n
+		}
+		return resolved
+	})
+	if err != nil {
+		return e, err
+	}
+	return res, nil
+}
+
+// resolve returns the argument with its dynamic value, the argument itself if it is no dynamic argument
+func (d DynamicArguments) resolve(ctx context.Context, c *constantExpression, values map[string]string) (*constantExpression, error) {
+	name, ok := dynamicArgument(c)
+	if !ok {
+		return c, nil
+	}
+	resolver, registered := d.Resolvers[name]
+	if !registered {
+		if d.Strict && c.recommended != ValueRecommendationField {
+			return nil, fmt.Errorf("%w `@%s`", ErrUnresolvedArgument, name)
+		}
+		return c, nil
+	}
+	v, cached := values[name]
+	if !cached {
+		var err error
+		if v, err = resolver(ctx); err != nil {
+			return nil, fmt.Errorf("unable to resolve `@%s`: %w", name, err)
+		}
+		values[name] = v
+	}
+	return c.bind(v), nil
+}
+
+// dynamicValidator accepts `@name` arguments before validator
+func dynamicValidator(validator argumentValidator) argumentValidator {
+	return func(i string) (bool, ValueRecommendation, []string) {
+		if len(i) > 1 && strings.HasPrefix(i, "@") {
+			return true, ValueRecommendationString, nil
+		}
+		return validator(i)
+	}
+}
+
+// dynamicArgument returns the name of a `@name` argument or field reference
+func dynamicArgument(c *constantExpression) (string, bool) {
+	if c.recommended == ValueRecommendationField {
+		return c.value, true
+	}
+	if c.quote != 0 || c.prefixWildcard || c.suffixWildcard || len(c.value) < 2 || !strings.HasPrefix(c.value, "@") {
+		return "", false
+	}
+	return c.value[1:], true
+}
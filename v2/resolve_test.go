@@ -0,0 +1,134 @@
+package fiqlparser
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type userKey struct{}
+
+func TestDynamicArgumentsResolve(t *testing.T) {
+	calls := 0
+	d := DynamicArguments{Resolvers: map[string]ArgumentResolver{
+		"me": func(ctx context.Context) (string, error) {
+			calls++
+			return ctx.Value(userKey{}).(string), nil
+		},
+		"team": func(ctx context.Context) (string, error) { return "core", nil },
+	}}
+	ctx := context.WithValue(context.Background(), userKey{}, "42")
+	var values = []struct {
+		fiql     string
+		opts     []Option
+		expected string
+	}{
+		{fiql: "owner==@me", expected: "(owner == 42)"},
+		{fiql: "owner==@me,reviewer==@me", expected: "(owner == 42 OR reviewer == 42)"},
+		{fiql: "team=in=[@team+ops]", expected: "(team IN [core+ops])"},
+		{fiql: `owner=="@me"`, expected: `(owner == "@me")`},
+		{fiql: "owner==@me*", expected: "(owner == @me*)"},
+		{fiql: "owner==@other", expected: "(owner == @other)"},
+		{fiql: "owner==@me;end=gt=@start", opts: []Option{WithFieldReferences()}, expected: "(owner == 42 AND end > @start)"},
+	}
+	for _, v := range values {
+		calls = 0
+		e, err := New(v.opts...).Parse(context.Background(), v.fiql)
+		if !assert.NoError(t, err, v.fiql) {
+			continue
+		}
+		res, err := d.Resolve(ctx, e)
+		if !assert.NoError(t, err, v.fiql) {
+			continue
+		}
+		assert.Equal(t, v.expected, res.String(), v.fiql)
+		assert.LessOrEqual(t, calls, 1, v.fiql)
+		// the original expression is not modified
+		original := mustParse(t, v.fiql, v.opts...)
+		assert.Equal(t, original.String(), e.String(), v.fiql)
+	}
+}
+
+func TestDynamicArgumentsTyped(t *testing.T) {
+	d := DynamicArguments{Resolvers: map[string]ArgumentResolver{"limit": func(context.Context) (string, error) { return "10", nil }}}
+	_, err := Parse(context.Background(), "age=gt=@limit")
+	assert.Error(t, err)
+	e, err := d.Resolve(context.Background(), mustParse(t, "age=gt=@limit", WithDynamicArguments()))
+	assert.NoError(t, err)
+	_, arg, ok := predicateOperands(e.node.(*binaryExpression))
+	if assert.True(t, ok) {
+		assert.Equal(t, ValueRecommendationNumber, arg.recommended)
+	}
+
+	d.Resolvers["period"] = func(context.Context) (string, error) { return "1..5", nil }
+	e, err = d.Resolve(context.Background(), mustParse(t, "age=bt=@period", WithDynamicArguments()))
+	assert.NoError(t, err)
+	_, arg, ok = predicateOperands(e.node.(*binaryExpression))
+	if assert.True(t, ok) {
+		assert.Equal(t, ValueRecommendationRange, arg.recommended)
+	}
+}
+
+func TestDynamicArgumentsDateTime(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip("time zone data not available")
+	}
+	opts := []Option{WithDynamicArguments(), WithDefaultLocation(berlin), WithDateTimeLayouts(DateTimeLayoutOf("02.01.2006"))}
+	var values = []struct {
+		value    string
+		expected time.Time
+	}{
+		{value: "2024-05-01", expected: time.Date(2024, 5, 1, 0, 0, 0, 0, berlin)},
+		{value: "15.01.2024", expected: time.Date(2024, 1, 15, 0, 0, 0, 0, berlin)},
+		{value: "2024-05-01T10:00:00Z", expected: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)},
+	}
+	for _, v := range values {
+		value := v.value
+		d := DynamicArguments{Resolvers: map[string]ArgumentResolver{"since": func(context.Context) (string, error) { return value, nil }}}
+		for _, fiql := range []string{"created=gt=@since", "created=in=[@since]"} {
+			e, err := d.Resolve(context.Background(), mustParse(t, fiql, opts...))
+			if !assert.NoError(t, err, fiql) {
+				continue
+			}
+			arg := firstArgument(&e)
+			if arg.ValueRecommendation() == ValueRecommendationTuple {
+				elements, err := arg.AsTuple()
+				if !assert.NoError(t, err, fiql) {
+					continue
+				}
+				arg = elements[0]
+			}
+			assert.Equal(t, ValueRecommendationDateTime, arg.ValueRecommendation(), v.value)
+			tm, err := arg.AsTime()
+			if assert.NoError(t, err, v.value) {
+				assert.True(t, v.expected.Equal(tm), "%s: expected %s got %s", v.value, v.expected, tm)
+			}
+		}
+	}
+}
+
+func TestDynamicArgumentsErrors(t *testing.T) {
+	failing := errors.New("no user")
+	d := DynamicArguments{Strict: true, Resolvers: map[string]ArgumentResolver{
+		"me": func(context.Context) (string, error) { return "", failing },
+	}}
+	_, err := d.Resolve(context.Background(), mustParse(t, "owner==@me"))
+	assert.ErrorIs(t, err, failing)
+	_, err = d.Resolve(context.Background(), mustParse(t, "owner=in=[a+@mee]"))
+	assert.ErrorIs(t, err, ErrUnresolvedArgument)
+	_, err = d.Resolve(context.Background(), mustParse(t, "end=gt=@start", WithFieldReferences()))
+	assert.NoError(t, err)
+}
+
+func mustParse(t *testing.T, fiql string, opts ...Option) Expression {
+	t.Helper()
+	e, err := New(opts...).Parse(context.Background(), fiql)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return e
+}
@@ -0,0 +1,56 @@
+package fiqlparser
+
+// rewriteNode copies the tree bottom up, fn is called for every copied node
+// and may return a replacement, the original tree is not modified
+func rewriteNode(n Node, fn func(Node) Node) Node {
+	switch node := n.(type) {
+	case *Expression:
+		c := *node
+		if node.node != nil {
+			c.node = rewriteNode(node.node, fn)
+		}
+		return fn(&c)
+	case *binaryExpression:
+		c := *node
+		for i, child := range node.nodes {
+			if child != nil {
+				c.nodes[i] = rewriteNode(child, fn)
+			}
+		}
+		return fn(&c)
+	case *logicalExpression:
+		c := *node
+		c.nodes = make([]Node, 0, len(node.nodes))
+		for _, child := range node.nodes {
+			c.nodes = append(c.nodes, rewriteNode(child, fn))
+		}
+		return fn(&c)
+	case *notExpression:
+		c := *node
+		if node.node != nil {
+			c.node = rewriteNode(node.node, fn)
+		}
+		return fn(&c)
+	case *constantExpression:
+		c := *node
+		return fn(&c)
+	}
+	return n
+}
+
+// rewriteExpression applies rewriteNode to the whole expression
+func rewriteExpression(e Expression, fn func(Node) Node) Expression {
+	res := rewriteNode(&e, fn)
+	if exp, ok := res.(*Expression); ok {
+		return *exp
+	}
+	return Expression{root: true, node: res}
+}
+
+func newBinary(operator string, lhs Node, rhs Node) *binaryExpression {
+	return &binaryExpression{operator: operator, nodes: [2]Node{lhs, rhs}}
+}
+
+func newSubExpression(n Node) *Expression {
+	return &Expression{node: n}
+}
@@ -0,0 +1,232 @@
+package fiqlparser
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrComparisonNotAllowed is generated by Schema.Validate if a selector is used with a comparison it does not allow
+var ErrComparisonNotAllowed = errors.New("comparison not allowed")
+
+// ErrInvalidArgumentType is generated by Schema.Validate if a argument does not match the type of the selector
+var ErrInvalidArgumentType = errors.New("invalid argument type")
+
+// SelectorSchema describes a selector accepted by a endpoint
+type SelectorSchema struct {
+	// Name is the selector
+	Name string
+	// Description is used by the generated documentation
+	Description string
+	// Type is the required value recommendation of arguments (the elements of tuples, the bounds of ranges),
+	// empty or ValueRecommendationString accept every argument, `null` is always accepted
+	Type ValueRecommendation
+	// Comparisons are the allowed comparisons, all comparisons are allowed if empty
+	Comparisons []Comparison
+	// Unary allows the selector without constraint (e.g. `active`)
+	Unary bool
+	// Example is a argument used in the examples of the generated documentation (see Markdown),
+	// a value matching Type is used if empty
+	Example string
+}
+
+// Schema is the set of selectors a endpoint accepts, it validates expressions (see Validate)
+// and generates the documentation of the filter parameter (see JSONSchema, OpenAPIParameter, Markdown and HTML),
+// so both are derived from the same definition
+type Schema struct {
+	Selectors []SelectorSchema
+}
+
+// Names returns the names of all selectors, e.g. for WithSelectorCase
+func (s Schema) Names() []string {
+	names := make([]string, 0, len(s.Selectors))
+	for _, sel := range s.Selectors {
+		names = append(names, sel.Name)
+	}
+	return names
+}
+
+func (s Schema) selector(name string) (SelectorSchema, bool) {
+	for _, sel := range s.Selectors {
+		if sel.Name == name {
+			return sel, true
+		}
+	}
+	return SelectorSchema{}, false
+}
+
+// Validate checks all predicates of the expression against the schema,
+// the error wraps ErrUnknownSelector, ErrComparisonNotAllowed or ErrInvalidArgumentType
+func (s Schema) Validate(e Expression) error {
+	var err error
+	Walk(&e, func(n Node) bool {
+		if p, ok := predicateOf(n); ok {
+			err = s.validatePredicate(p)
+		}
+		return err == nil
+	})
+	return err
+}
+
+func (s Schema) validatePredicate(p Predicate) error {
+	sel, ok := s.selector(p.Selector)
+	if !ok {
+		return fmt.Errorf("%w `%s`", ErrUnknownSelector, p.Selector)
+	}
+	if p.IsUnary() {
+		if !sel.Unary {
+			return fmt.Errorf("%w: `%s` requires a comparison", ErrComparisonNotAllowed, p.Selector)
+		}
+		return nil
+	}
+	if !sel.allows(p.Comparison) {
+		return fmt.Errorf("%w: `%s` on `%s`", ErrComparisonNotAllowed, fiqlOperators[string(p.Comparison)], p.Selector)
+	}
+	if ref, ok := p.Argument.Field(); ok {
+		other, ok := s.selector(ref)
+		if !ok {
+			return fmt.Errorf("%w `%s`", ErrUnknownSelector, ref)
+		}
+		if sel.Type != "" && other.Type != "" && sel.Type != other.Type {
+			return fmt.Errorf("%w: `%s` expects %s but `%s` is %s", ErrInvalidArgumentType, p.Selector, sel.Type, ref, other.Type)
+		}
+		return nil
+	}
+	// the type of named parameters is checked once they are bound, see Template
+	if _, ok := p.Argument.Parameter(); ok {
+		return nil
+	}
+	if !sel.accepts(p.Argument) {
+		return fmt.Errorf("%w: `%s` expects %s", ErrInvalidArgumentType, p.Selector, sel.Type)
+	}
+	return nil
+}
+
+func (s SelectorSchema) allows(c Comparison) bool {
+	if len(s.Comparisons) == 0 {
+		return true
+	}
+	for _, allowed := range s.Comparisons {
+		if allowed == c {
+			return true
+		}
+	}
+	return false
+}
+
+// accepts checks the argument against the type, tuples and ranges are checked element wise
+func (s SelectorSchema) accepts(arg ArgumentContext) bool {
+	if s.Type == "" || s.Type == ValueRecommendationString || arg.IsNull() {
+		return true
+	}
+	switch arg.ValueRecommendation() {
+	case ValueRecommendationTuple:
+		elements, err := arg.AsTuple()
+		if err != nil {
+			return false
+		}
+		for _, el := range elements {
+			if !s.accepts(el) {
+				return false
+			}
+		}
+		return true
+	case ValueRecommendationRange:
+		low, high, err := arg.AsRange()
+		return err == nil && s.accepts(low) && s.accepts(high)
+	}
+	// identifiers are detected regardless of WithIdentifierRecommendations
+	if isIdentifierRecommendation(s.Type) && !arg.pre && !arg.post {
+		rec, ok := identifierRecommendation(arg.val)
+		return ok && rec == s.Type
+	}
+	return arg.ValueRecommendation() == s.Type
+}
+
+// comparisons returns the allowed comparisons in FIQL notation
+func (s SelectorSchema) comparisons() []string {
+	allowed := s.Comparisons
+	if len(allowed) == 0 {
+		allowed = AllComparisons()
+	}
+	res := make([]string, 0, len(allowed))
+	for _, c := range allowed {
+		res = append(res, fiqlOperators[string(c)])
+	}
+	return res
+}
+
+// Description returns a human readable description of the filter grammar and the allowed selectors
+func (s Schema) Description() string {
+	var b strings.Builder
+	b.WriteString(schemaDocIntro)
+	b.WriteString("\n")
+	b.WriteString("Allowed selectors:\n")
+	for _, sel := range s.Selectors {
+		b.WriteString("- `")
+		b.WriteString(sel.Name)
+		b.WriteString("`")
+		if sel.Type != "" {
+			b.WriteString(" (")
+			b.WriteString(string(sel.Type))
+			b.WriteString(")")
+		}
+		b.WriteString(": ")
+		for i, c := range sel.comparisons() {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			b.WriteString("`")
+			b.WriteString(c)
+			b.WriteString("`")
+		}
+		if sel.Unary {
+			b.WriteString(", without constraint")
+		}
+		if sel.Description != "" {
+			b.WriteString(" - ")
+			b.WriteString(sel.Description)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// JSONSchema returns a JSON Schema of the filter parameter, the selectors are listed
+// in the `x-fiql-selectors` extension as objects with `name`, `type`, `operators`, `unary` and `description`
+func (s Schema) JSONSchema() map[string]interface{} {
+	selectors := make([]interface{}, 0, len(s.Selectors))
+	for _, sel := range s.Selectors {
+		typ := sel.Type
+		if typ == "" {
+			typ = ValueRecommendationString
+		}
+		entry := map[string]interface{}{
+			"name":      sel.Name,
+			"type":      string(typ),
+			"operators": sel.comparisons(),
+			"unary":     sel.Unary,
+		}
+		if sel.Description != "" {
+			entry["description"] = sel.Description
+		}
+		selectors = append(selectors, entry)
+	}
+	return map[string]interface{}{
+		"type":             "string",
+		"format":           "fiql",
+		"description":      s.Description(),
+		"x-fiql-selectors": selectors,
+	}
+}
+
+// OpenAPIParameter returns a OpenAPI 3 query parameter object describing the filter parameter
+func (s Schema) OpenAPIParameter(name string) map[string]interface{} {
+	return map[string]interface{}{
+		"name":        name,
+		"in":          "query",
+		"required":    false,
+		"description": s.Description(),
+		"schema":      s.JSONSchema(),
+	}
+}
@@ -0,0 +1,87 @@
+package fiqlparser
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testSchema = Schema{Selectors: []SelectorSchema{
+	{Name: "status", Description: "ticket status", Comparisons: []Comparison{ComparisonEq, ComparisonNeq, ComparisonIn}},
+	{Name: "priority", Type: ValueRecommendationNumber},
+	{Name: "created", Type: ValueRecommendationDateTime, Comparisons: []Comparison{ComparisonGt, ComparisonLt, ComparisonBetween}},
+	{Name: "archived", Comparisons: []Comparison{ComparisonEq}, Unary: true},
+}}
+
+func TestSchemaValidate(t *testing.T) {
+	var values = []struct {
+		fiql string
+		err  error
+	}{
+		{fiql: "status==open;priority=gt=2", err: nil},
+		{fiql: "status=in=[open+closed],archived", err: nil},
+		{fiql: "created=bt=[2024-01-01+2024-02-01];priority==null", err: nil},
+		{fiql: "owner==me", err: ErrUnknownSelector},
+		{fiql: "status=gt=5", err: ErrComparisonNotAllowed},
+		{fiql: "priority", err: ErrComparisonNotAllowed},
+		{fiql: "priority==high", err: ErrInvalidArgumentType},
+		{fiql: "priority=in=[1+high]", err: ErrInvalidArgumentType},
+		{fiql: "created=bt=[1+10]", err: ErrInvalidArgumentType},
+	}
+	for _, v := range values {
+		res, err := Parse(context.Background(), v.fiql)
+		if !assert.NoError(t, err, v.fiql) {
+			continue
+		}
+		err = testSchema.Validate(res)
+		if v.err == nil {
+			assert.NoError(t, err, v.fiql)
+			continue
+		}
+		assert.True(t, errors.Is(err, v.err), "%s: %v", v.fiql, err)
+	}
+	res, _ := Parse(context.Background(), "status=gt=5")
+	assert.EqualError(t, testSchema.Validate(res), "comparison not allowed: `=gt=` on `status`")
+}
+
+func TestSchemaDocumentation(t *testing.T) {
+	assert.Equal(t, "FIQL filter, constraints are combined with `;` (AND) and `,` (OR) and grouped with parentheses.\n"+
+		"Allowed selectors:\n"+
+		"- `status`: `==`, `!=`, `=in=` - ticket status\n"+
+		"- `priority` (number): `==`, `!=`, `=gt=`, `=lt=`, `=ge=`, `=le=`, `=bt=`, `=in=`, `=q=`\n"+
+		"- `created` (datetime): `=gt=`, `=lt=`, `=bt=`\n"+
+		"- `archived`: `==`, without constraint\n", testSchema.Description())
+
+	param, err := json.Marshal(testSchema.OpenAPIParameter("filter"))
+	if !assert.NoError(t, err) {
+		return
+	}
+	var decoded struct {
+		Name     string
+		In       string
+		Required bool
+		Schema   struct {
+			Type      string
+			Selectors []struct {
+				Name      string
+				Type      string
+				Operators []string
+				Unary     bool
+			} `json:"x-fiql-selectors"`
+		}
+	}
+	if assert.NoError(t, json.Unmarshal(param, &decoded)) {
+		assert.Equal(t, "filter", decoded.Name)
+		assert.Equal(t, "query", decoded.In)
+		assert.Equal(t, "string", decoded.Schema.Type)
+		if assert.Len(t, decoded.Schema.Selectors, 4) {
+			assert.Equal(t, "string", decoded.Schema.Selectors[0].Type)
+			assert.Equal(t, []string{"==", "!=", "=in="}, decoded.Schema.Selectors[0].Operators)
+			assert.Equal(t, "datetime", decoded.Schema.Selectors[2].Type)
+			assert.True(t, decoded.Schema.Selectors[3].Unary)
+		}
+	}
+}
@@ -0,0 +1,246 @@
+package fiqlparser
+
+import (
+	"context"
+	"html"
+	"strings"
+)
+
+// schemaDocIntro introduces the grammar in the generated documentation
+const schemaDocIntro = "FIQL filter, constraints are combined with `;` (AND) and `,` (OR) and grouped with parentheses."
+
+// valueFormats describes the format of arguments per type, in order of appearance in the documentation
+var valueFormats = []struct {
+	typ         ValueRecommendation
+	description string
+}{
+	{ValueRecommendationString, "any text, values containing whitespace or reserved characters are quoted (`\"John Doe\"`), `*` at the start or end matches any text"},
+	{ValueRecommendationNumber, "integer or decimal number with optional sign, e.g. `42` or `-1.5`"},
+	{ValueRecommendationDateTime, "RFC 3339 date or date and time, e.g. `2024-05-01` or `2024-05-01T10:30:00Z`"},
+	{ValueRecommendationDuration, "ISO 8601 duration relative to now, e.g. `-P1D` (one day ago) or `PT2H`"},
+	{ValueRecommendationBoolean, "`true` or `false`"},
+	{ValueRecommendationUUID, "UUID, e.g. `123e4567-e89b-12d3-a456-426614174000`"},
+	{ValueRecommendationEmail, "email address, e.g. `jane@example.com`"},
+	{ValueRecommendationIP, "IPv4 or IPv6 address or CIDR prefix, e.g. `192.0.2.1` or `10.0.0.0/8`"},
+}
+
+// exampleValues are the example arguments per type, used if a selector has no Example
+var exampleValues = map[ValueRecommendation][2]string{
+	ValueRecommendationString:   {"example", "other"},
+	ValueRecommendationNumber:   {"42", "100"},
+	ValueRecommendationDateTime: {"2024-05-01T00:00:00Z", "2024-06-01T00:00:00Z"},
+	ValueRecommendationDuration: {"-P1D", "P1D"},
+	ValueRecommendationBoolean:  {"true", "false"},
+	ValueRecommendationUUID:     {"123e4567-e89b-12d3-a456-426614174000", "00000000-0000-0000-0000-000000000000"},
+	ValueRecommendationEmail:    {"jane@example.com", "john@example.com"},
+	ValueRecommendationIP:       {"192.0.2.1", "10.0.0.0/8"},
+}
+
+// schemaDoc is the content of the generated documentation, rendered as Markdown or HTML
+type schemaDoc struct {
+	rows     []schemaDocRow
+	formats  []int
+	examples []string
+}
+
+type schemaDocRow struct {
+	name        string
+	typ         string
+	operators   []string
+	unary       bool
+	description string
+}
+
+// Markdown returns the documentation of the filter parameter as Markdown:
+// a table of the selectors with their type and operators, the value formats and example expressions.
+// The examples are generated from the schema and always pass Validate.
+// Sections use level 3 headings so the output can be embedded into API documentation.
+func (s Schema) Markdown() string {
+	d := s.documentation()
+	var b strings.Builder
+	b.WriteString(schemaDocIntro)
+	b.WriteString("\n\n### Selectors\n\n| Selector | Type | Operators | Description |\n| --- | --- | --- | --- |\n")
+	for _, r := range d.rows {
+		b.WriteString("| `")
+		b.WriteString(markdownCell(r.name))
+		b.WriteString("` | ")
+		b.WriteString(r.typ)
+		b.WriteString(" | ")
+		for i, op := range r.operators {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			b.WriteString("`")
+			b.WriteString(op)
+			b.WriteString("`")
+		}
+		if r.unary {
+			b.WriteString(", without constraint")
+		}
+		b.WriteString(" | ")
+		b.WriteString(markdownCell(r.description))
+		b.WriteString(" |\n")
+	}
+	b.WriteString("\n### Value formats\n\n")
+	for _, i := range d.formats {
+		b.WriteString("- **")
+		b.WriteString(string(valueFormats[i].typ))
+		b.WriteString("**: ")
+		b.WriteString(valueFormats[i].description)
+		b.WriteString("\n")
+	}
+	b.WriteString("- **null**: `null` matches missing values, `\"null\"` the text\n")
+	if len(d.examples) > 0 {
+		b.WriteString("\n### Examples\n\n")
+		for _, e := range d.examples {
+			b.WriteString("- `")
+			b.WriteString(e)
+			b.WriteString("`\n")
+		}
+	}
+	return b.String()
+}
+
+// HTML returns the documentation of the filter parameter as HTML fragment with the content of Markdown
+func (s Schema) HTML() string {
+	d := s.documentation()
+	var b strings.Builder
+	b.WriteString("<p>")
+	b.WriteString(htmlCode(schemaDocIntro))
+	b.WriteString("</p>\n<h3>Selectors</h3>\n<table>\n<thead><tr><th>Selector</th><th>Type</th><th>Operators</th><th>Description</th></tr></thead>\n<tbody>\n")
+	for _, r := range d.rows {
+		b.WriteString("<tr><td><code>")
+		b.WriteString(html.EscapeString(r.name))
+		b.WriteString("</code></td><td>")
+		b.WriteString(r.typ)
+		b.WriteString("</td><td>")
+		for i, op := range r.operators {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			b.WriteString("<code>")
+			b.WriteString(html.EscapeString(op))
+			b.WriteString("</code>")
+		}
+		if r.unary {
+			b.WriteString(", without constraint")
+		}
+		b.WriteString("</td><td>")
+		b.WriteString(html.EscapeString(r.description))
+		b.WriteString("</td></tr>\n")
+	}
+	b.WriteString("</tbody>\n</table>\n<h3>Value formats</h3>\n<ul>\n")
+	for _, i := range d.formats {
+		b.WriteString("<li><strong>")
+		b.WriteString(string(valueFormats[i].typ))
+		b.WriteString("</strong>: ")
+		b.WriteString(htmlCode(valueFormats[i].description))
+		b.WriteString("</li>\n")
+	}
+	b.WriteString("<li><strong>null</strong>: ")
+	b.WriteString(htmlCode("`null` matches missing values, `\"null\"` the text"))
+	b.WriteString("</li>\n</ul>\n")
+	if len(d.examples) > 0 {
+		b.WriteString("<h3>Examples</h3>\n<ul>\n")
+		for _, e := range d.examples {
+			b.WriteString("<li><code>")
+			b.WriteString(html.EscapeString(e))
+			b.WriteString("</code></li>\n")
+		}
+		b.WriteString("</ul>\n")
+	}
+	return b.String()
+}
+
+func (s Schema) documentation() schemaDoc {
+	d := schemaDoc{}
+	used := make(map[ValueRecommendation]bool)
+	valid := make([]string, 0, len(s.Selectors))
+	for _, sel := range s.Selectors {
+		typ := sel.Type
+		if typ == "" {
+			typ = ValueRecommendationString
+		}
+		used[typ] = true
+		d.rows = append(d.rows, schemaDocRow{name: sel.Name, typ: string(typ), operators: sel.comparisons(), unary: sel.Unary, description: sel.Description})
+		if example, ok := s.example(sel, typ); ok {
+			valid = append(valid, example)
+		}
+	}
+	used[ValueRecommendationString] = true
+	for i, f := range valueFormats {
+		if used[f.typ] {
+			d.formats = append(d.formats, i)
+		}
+	}
+	d.examples = valid
+	if len(valid) >= 2 {
+		d.examples = append(d.examples, valid[0]+";"+valid[1])
+		if len(valid) >= 3 {
+			d.examples = append(d.examples, valid[0]+";("+valid[1]+","+valid[2]+")")
+		}
+	}
+	return d
+}
+
+// example returns a valid expression constraining the selector, `==` is preferred over other comparisons
+func (s Schema) example(sel SelectorSchema, typ ValueRecommendation) (string, bool) {
+	values, ok := exampleValues[typ]
+	if !ok {
+		values = exampleValues[ValueRecommendationString]
+	}
+	if sel.Example != "" {
+		values[0] = sel.Example
+	}
+	candidates := make([]string, 0, len(sel.comparisons())+1)
+	for _, op := range sel.comparisons() {
+		var fiql string
+		switch op {
+		case fiqlOperators[string(ComparisonBetween)]:
+			fiql = sel.Name + op + values[0] + ".." + values[1]
+		case fiqlOperators[string(ComparisonIn)]:
+			fiql = sel.Name + op + "[" + values[0] + "+" + values[1] + "]"
+		default:
+			fiql = sel.Name + op + values[0]
+		}
+		if op == fiqlOperators[string(ComparisonEq)] {
+			candidates = append([]string{fiql}, candidates...)
+			continue
+		}
+		candidates = append(candidates, fiql)
+	}
+	if sel.Unary {
+		candidates = append(candidates, sel.Name)
+	}
+	for _, fiql := range candidates {
+		e, err := Parse(context.Background(), fiql)
+		if err == nil && s.Validate(e) == nil {
+			return fiql, true
+		}
+	}
+	return "", false
+}
+
+// markdownCell escapes the pipes within a table cell
+func markdownCell(s string) string {
+	return strings.ReplaceAll(s, "|", `\|`)
+}
+
+// htmlCode escapes text and converts Markdown code spans (`x`) to code elements
+func htmlCode(s string) string {
+	parts := strings.Split(s, "`")
+	var b strings.Builder
+	for i, p := range parts {
+		if i%2 == 1 && i < len(parts)-1 {
+			b.WriteString("<code>")
+			b.WriteString(html.EscapeString(p))
+			b.WriteString("</code>")
+			continue
+		}
+		if i%2 == 1 {
+			b.WriteString("`")
+		}
+		b.WriteString(html.EscapeString(p))
+	}
+	return b.String()
+}
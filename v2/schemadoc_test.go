@@ -0,0 +1,66 @@
+package fiqlparser
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchemaMarkdown(t *testing.T) {
+	schema := Schema{Selectors: []SelectorSchema{
+		{Name: "status", Description: "open | closed", Comparisons: []Comparison{ComparisonEq, ComparisonIn}, Example: "open"},
+		{Name: "priority", Type: ValueRecommendationNumber, Comparisons: []Comparison{ComparisonGt, ComparisonBetween}},
+	}}
+	assert.Equal(t, "FIQL filter, constraints are combined with `;` (AND) and `,` (OR) and grouped with parentheses.\n\n"+
+		"### Selectors\n\n"+
+		"| Selector | Type | Operators | Description |\n"+
+		"| --- | --- | --- | --- |\n"+
+		"| `status` | string | `==`, `=in=` | open \\| closed |\n"+
+		"| `priority` | number | `=gt=`, `=bt=` |  |\n\n"+
+		"### Value formats\n\n"+
+		"- **string**: any text, values containing whitespace or reserved characters are quoted (`\"John Doe\"`), `*` at the start or end matches any text\n"+
+		"- **number**: integer or decimal number with optional sign, e.g. `42` or `-1.5`\n"+
+		"- **null**: `null` matches missing values, `\"null\"` the text\n\n"+
+		"### Examples\n\n"+
+		"- `status==open`\n"+
+		"- `priority=gt=42`\n"+
+		"- `status==open;priority=gt=42`\n", schema.Markdown())
+}
+
+func TestSchemaHTML(t *testing.T) {
+	schema := Schema{Selectors: []SelectorSchema{
+		{Name: "name", Description: "<b>name</b>"},
+	}}
+	doc := schema.HTML()
+	assert.True(t, strings.HasPrefix(doc, "<p>FIQL filter, constraints are combined with <code>;</code> (AND)"), doc)
+	assert.Contains(t, doc, "<tr><td><code>name</code></td><td>string</td><td><code>==</code>, <code>!=</code>")
+	assert.Contains(t, doc, "<td>&lt;b&gt;name&lt;/b&gt;</td>")
+	assert.Contains(t, doc, "<li><code>name==example</code></li>")
+}
+
+func TestSchemaDocumentationExamplesValidate(t *testing.T) {
+	schema := Schema{Selectors: []SelectorSchema{
+		{Name: "status", Comparisons: []Comparison{ComparisonNeq}},
+		{Name: "created", Type: ValueRecommendationDateTime, Comparisons: []Comparison{ComparisonBetween}},
+		{Name: "age", Type: ValueRecommendationDuration},
+		{Name: "active", Type: ValueRecommendationBoolean, Comparisons: []Comparison{ComparisonGt}, Unary: true},
+		{Name: "title", Comparisons: []Comparison{ComparisonLt}},
+	}}
+	examples := schema.documentation().examples
+	assert.Equal(t, []string{
+		"status!=example",
+		"created=bt=2024-05-01T00:00:00Z..2024-06-01T00:00:00Z",
+		"age==-P1D",
+		"active",
+		"status!=example;created=bt=2024-05-01T00:00:00Z..2024-06-01T00:00:00Z",
+		"status!=example;(created=bt=2024-05-01T00:00:00Z..2024-06-01T00:00:00Z,age==-P1D)",
+	}, examples)
+	for _, e := range examples {
+		res, err := Parse(context.Background(), e)
+		if assert.NoError(t, err, e) {
+			assert.NoError(t, schema.Validate(res), e)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package fiqlparser
+
+import (
+	"strconv"
+	"strings"
+)
+
+// DefaultSelectorPathSeparator separates the segments of nested selectors unless configured otherwise, e.g. `author.name`
+const DefaultSelectorPathSeparator = '.'
+
+// PathSegment is a segment of a selector path, either a field name or a array index (`[0]`)
+type PathSegment struct {
+	// Name is the field name, empty for array indices
+	Name string
+	// Index is the array index, only valid if IsIndex is set
+	Index   int
+	IsIndex bool
+}
+
+// String returns the name or the index of the segment
+func (s PathSegment) String() string {
+	if s.IsIndex {
+		return strconv.Itoa(s.Index)
+	}
+	return s.Name
+}
+
+// WithSelectorPathSeparator configures the separator of nested selectors (see SelectorContext.Path),
+// e.g. WithSelectorPathSeparator('/') for `author/name`, DefaultSelectorPathSeparator is used otherwise
+func WithSelectorPathSeparator(separator rune) Option {
+	return func(p *Parser) {
+		p.pathSeparator = separator
+	}
+}
+
+// Path returns the segments of the selector split by the configured separator, array indices
+// are segments of their own, e.g. `items[0].sku` results in `items`, `0` and `sku`
+func (s SelectorContext) Path() []string {
+	segments := s.Segments()
+	path := make([]string, 0, len(segments))
+	for _, seg := range segments {
+		path = append(path, seg.String())
+	}
+	return path
+}
+
+// Segments returns the segments of the selector like Path, distinguishing field names from array indices
+func (s SelectorContext) Segments() []PathSegment {
+	sep := s.separator
+	if sep == 0 {
+		sep = DefaultSelectorPathSeparator
+	}
+	return SplitSelectorPath(s.selector, sep)
+}
+
+// IsNested indicates a selector with more than one segment, e.g. `author.name` or `items[0]`
+func (s SelectorContext) IsNested() bool {
+	return len(s.Segments()) > 1
+}
+
+// SplitSelectorPath splits the selector into its segments, e.g. for translators working on Predicate.Selector.
+// Trailing `[n]` of a segment are array indices if n is a non-negative integer, otherwise they are part of the name.
+func SplitSelectorPath(selector string, separator rune) []PathSegment {
+	segments := make([]PathSegment, 0)
+	for _, part := range strings.Split(selector, string(separator)) {
+		indices := make([]PathSegment, 0)
+		for strings.HasSuffix(part, "]") {
+			open := strings.LastIndexByte(part, '[')
+			if open < 0 {
+				break
+			}
+			digits := part[open+1 : len(part)-1]
+			if digits == "" || strings.Trim(digits, "0123456789") != "" {
+				break
+			}
+			index, err := strconv.Atoi(digits)
+			if err != nil {
+				break
+			}
+			indices = append(indices, PathSegment{Index: index, IsIndex: true})
+			part = part[:open]
+		}
+		if part != "" || len(indices) == 0 {
+			segments = append(segments, PathSegment{Name: part})
+		}
+		for i := len(indices) - 1; i >= 0; i-- {
+			segments = append(segments, indices[i])
+		}
+	}
+	return segments
+}
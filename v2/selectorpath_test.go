@@ -0,0 +1,55 @@
+package fiqlparser
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitSelectorPath(t *testing.T) {
+	var values = []struct {
+		selector string
+		expected []PathSegment
+	}{
+		{selector: "name", expected: []PathSegment{{Name: "name"}}},
+		{selector: "author.name", expected: []PathSegment{{Name: "author"}, {Name: "name"}}},
+		{selector: "items[0].sku", expected: []PathSegment{{Name: "items"}, {Index: 0, IsIndex: true}, {Name: "sku"}}},
+		{selector: "m[1][20]", expected: []PathSegment{{Name: "m"}, {Index: 1, IsIndex: true}, {Index: 20, IsIndex: true}}},
+		{selector: "items.0", expected: []PathSegment{{Name: "items"}, {Name: "0"}}},
+		{selector: "a[x].b[-1]", expected: []PathSegment{{Name: "a[x]"}, {Name: "b[-1]"}}},
+		{selector: "a[]", expected: []PathSegment{{Name: "a[]"}}},
+		{selector: "[3]", expected: []PathSegment{{Index: 3, IsIndex: true}}},
+	}
+	for _, v := range values {
+		assert.Equal(t, v.expected, SplitSelectorPath(v.selector, DefaultSelectorPathSeparator), v.selector)
+	}
+	assert.Equal(t, []PathSegment{{Name: "a.b"}, {Name: "c"}}, SplitSelectorPath("a.b/c", '/'))
+}
+
+type pathVisitor struct {
+	BaseVisitor
+	paths  [][]string
+	nested []bool
+}
+
+func (v *pathVisitor) VisitSelector(ctx SelectorContext) error {
+	v.paths = append(v.paths, ctx.Path())
+	v.nested = append(v.nested, ctx.IsNested())
+	return nil
+}
+
+func TestSelectorContextPath(t *testing.T) {
+	e := mustParse(t, "items[0].sku==a;author.name==b;deleted")
+	v := &pathVisitor{}
+	assert.NoError(t, e.Visit(v))
+	assert.Equal(t, [][]string{{"items", "0", "sku"}, {"author", "name"}, {"deleted"}}, v.paths)
+	assert.Equal(t, []bool{true, true, false}, v.nested)
+
+	e, err := New(WithSelectorPathSeparator('/')).Parse(context.Background(), "author/name==b;a.b==c")
+	if assert.NoError(t, err) {
+		v = &pathVisitor{}
+		assert.NoError(t, e.Visit(v))
+		assert.Equal(t, [][]string{{"author", "name"}, {"a.b"}}, v.paths)
+	}
+}
@@ -0,0 +1,163 @@
+package fiqlparser
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// WithSelectorPattern enforces a naming convention for selectors, e.g. `^[a-z][a-z0-9_.]{0,63}$`,
+// selectors not matching the pattern fail with ErrorCodeInvalidSelector
+func WithSelectorPattern(pattern *regexp.Regexp) Option {
+	return func(p *Parser) {
+		p.selectorPattern = pattern
+	}
+}
+
+// SelectorCase is the policy for the case of selectors
+type SelectorCase int
+
+const (
+	// SelectorCasePreserve keeps selectors as written
+	SelectorCasePreserve SelectorCase = iota
+	// SelectorCaseLower lower cases all selectors, e.g. `Name` and `NAME` both result in `name`
+	SelectorCaseLower
+	// SelectorCaseInsensitive matches selectors case insensitively against the known selectors
+	// and replaces them by the known spelling, e.g. `CREATEDAT` results in `createdAt`.
+	// Unknown selectors and selectors matching multiple known selectors are kept as written.
+	SelectorCaseInsensitive
+)
+
+// WithSelectorCase sets the case policy for selectors, known are the selectors of the schema
+// (e.g. the keys of SQLTranslator.Columns) matched by SelectorCaseInsensitive.
+// The policy is applied before the selector pattern check and the selector mapper,
+// so validation, rewriting and serialization all work on the same selector.
+func WithSelectorCase(policy SelectorCase, known ...string) Option {
+	return func(p *Parser) {
+		p.selectorCase = policy
+		p.knownSelectors = nil
+		if policy != SelectorCaseInsensitive {
+			return
+		}
+		p.knownSelectors = make(map[string]string, len(known))
+		for _, k := range known {
+			lower := strings.ToLower(k)
+			if spelling, ok := p.knownSelectors[lower]; ok && spelling != k {
+				p.knownSelectors[lower] = ""
+				continue
+			}
+			p.knownSelectors[lower] = k
+		}
+	}
+}
+
+// WithCaseInsensitiveSelectors lower cases all selectors, e.g. `Name` and `name` both result in `name`,
+// it is a shorthand for WithSelectorCase(SelectorCaseLower)
+func WithCaseInsensitiveSelectors() Option {
+	return WithSelectorCase(SelectorCaseLower)
+}
+
+// WithSelectorMapper maps all selectors at parse time, e.g. to field names (see SelectorSnakeCase).
+// The mapper is applied after the case policy and the selector pattern check.
+func WithSelectorMapper(mapper func(selector string) string) Option {
+	return func(p *Parser) {
+		p.selectorMapper = mapper
+	}
+}
+
+// SelectorSnakeCase converts camel case selectors to snake case, e.g. `user.createdAt` to `user.created_at`
+func SelectorSnakeCase(selector string) string {
+	var b strings.Builder
+	runes := []rune(selector)
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			prevLower := i > 0 && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]))
+			nextLower := i > 0 && i+1 < len(runes) && unicode.IsUpper(runes[i-1]) && unicode.IsLower(runes[i+1])
+			if prevLower || nextLower {
+				b.WriteRune('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// prepareSelector applies the case policy, checks the selector against the pattern, resolves aliases and canonicalizes it
+func (p Parser) prepareSelector(sel *constantExpression) error {
+	sel.value = p.caseSelector(sel.value)
+	if p.selectorPattern != nil && !p.selectorPattern.MatchString(sel.value) {
+		return p.lex.errInvalidSelector(sel.value, sel.pos, p.selectorPattern.String())
+	}
+	if p.aliases != nil {
+		internal, ok := p.aliases.Aliases[sel.value]
+		if !ok && p.aliases.Strict {
+			return p.lex.errUnknownSelector(sel.value, sel.pos, p.aliases.known())
+		}
+		if ok {
+			sel.value = internal
+		}
+	}
+	if p.selectorMapper != nil {
+		sel.value = p.selectorMapper(sel.value)
+	}
+	sel.pathSeparator = p.pathSeparator
+	return nil
+}
+
+// caseSelector applies the case policy to the selector
+func (p Parser) caseSelector(selector string) string {
+	switch p.selectorCase {
+	case SelectorCaseLower:
+		return strings.ToLower(selector)
+	case SelectorCaseInsensitive:
+		if spelling := p.knownSelectors[strings.ToLower(selector)]; spelling != "" {
+			return spelling
+		}
+	}
+	return selector
+}
+
+// Selectors returns all selectors referenced by the expression, deduplicated and in order of appearance,
+// including selectors referenced by arguments (see WithFieldReferences)
+func (e *Expression) Selectors() []string {
+	res := make([]string, 0)
+	seen := make(map[string]bool)
+	Walk(e, func(n Node) bool {
+		if c, ok := n.(*constantExpression); ok && (c.selector || c.recommended == ValueRecommendationField) && !seen[c.value] {
+			seen[c.value] = true
+			res = append(res, c.value)
+		}
+		return true
+	})
+	return res
+}
+
+// SelectorsWithComparisons returns all selectors referenced by the expression with the comparisons
+// they are used with, deduplicated and in order of appearance.
+// Unary selectors (without constraint) are contained with an empty list of comparisons.
+func (e *Expression) SelectorsWithComparisons() map[string][]Comparison {
+	res := make(map[string][]Comparison)
+	Walk(e, func(n Node) bool {
+		switch node := n.(type) {
+		case *constantExpression:
+			if node.selector && res[node.value] == nil {
+				res[node.value] = make([]Comparison, 0)
+			}
+		case *binaryExpression:
+			sel, _, ok := predicateOperands(node)
+			if !ok || !sel.selector || isLogicalOperator(node.operator) {
+				return true
+			}
+			comparison := Comparison(node.operator)
+			for _, c := range res[sel.value] {
+				if c == comparison {
+					return true
+				}
+			}
+			res[sel.value] = append(res[sel.value], comparison)
+		}
+		return true
+	})
+	return res
+}
@@ -0,0 +1,134 @@
+package fiqlparser
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSelectors(t *testing.T) {
+	var values = []struct {
+		fiql        string
+		selectors   []string
+		comparisons map[string][]Comparison
+	}{
+		{fiql: "a==1", selectors: []string{"a"},
+			comparisons: map[string][]Comparison{"a": {ComparisonEq}}},
+		{fiql: "b==1;a=gt=2,b!=3;b==4", selectors: []string{"b", "a"},
+			comparisons: map[string][]Comparison{"b": {ComparisonEq, ComparisonNeq}, "a": {ComparisonGt}}},
+		{fiql: "active;(name=in=[x+y],name=q=fox)", selectors: []string{"active", "name"},
+			comparisons: map[string][]Comparison{"active": {}, "name": {ComparisonIn, ComparisonQuery}}},
+	}
+	for _, v := range values {
+		res, err := Parse(context.Background(), v.fiql)
+		if !assert.NoError(t, err, v.fiql) {
+			continue
+		}
+		assert.Equal(t, v.selectors, res.Selectors(), v.fiql)
+		assert.Equal(t, v.comparisons, res.SelectorsWithComparisons(), v.fiql)
+	}
+}
+
+func TestSelectorPattern(t *testing.T) {
+	parser := New(WithSelectorPattern(regexp.MustCompile(`^[a-z][a-z0-9_.]{0,63}$`)))
+	var values = []struct {
+		fiql   string
+		valid  bool
+		token  string
+		line   int
+		column int
+		offset int
+	}{
+		{fiql: "name==x;user.age=gt=18,active", valid: true},
+		{fiql: "Name==x", token: "Name", line: 1, column: 1, offset: 0},
+		{fiql: "a==1;\n _id==x", token: "_id", line: 2, column: 2, offset: 7},
+		{fiql: "a==1,(Active)", token: "Active", line: 1, column: 7, offset: 6},
+	}
+	for _, v := range values {
+		_, err := parser.Parse(context.Background(), v.fiql)
+		if v.valid {
+			assert.NoError(t, err, v.fiql)
+			continue
+		}
+		var perr *ParseError
+		if !assert.True(t, errors.As(err, &perr), "expected ParseError for `%s`", v.fiql) {
+			continue
+		}
+		assert.Equal(t, ErrorCodeInvalidSelector, perr.Code, v.fiql)
+		assert.Equal(t, v.token, perr.Token, v.fiql)
+		assert.Equal(t, v.line, perr.Line, v.fiql)
+		assert.Equal(t, v.column, perr.Column, v.fiql)
+		assert.Equal(t, v.offset, perr.Offset, v.fiql)
+	}
+	_, err := parser.Parse(context.Background(), "Name==x")
+	assert.EqualError(t, err, "ln:1:1 invalid selector (`Name` does not match `^[a-z][a-z0-9_.]{0,63}$`)")
+}
+
+func TestSelectorCanonicalization(t *testing.T) {
+	var values = []struct {
+		opts   []Option
+		fiql   string
+		output string
+	}{
+		{opts: []Option{WithCaseInsensitiveSelectors()}, fiql: "Name==John;NAME!=Jane,age", output: "name==John;name!=Jane,age"},
+		{opts: []Option{WithSelectorMapper(SelectorSnakeCase)}, fiql: "createdAt=gt=1;user.lastName==x;HTTPStatus==200", output: "created_at=gt=1;user.last_name==x;http_status==200"},
+		{opts: []Option{WithCaseInsensitiveSelectors(), WithSelectorMapper(func(s string) string { return "t." + s })}, fiql: "Name==John", output: "t.name==John"},
+	}
+	for _, v := range values {
+		res, err := New(v.opts...).Parse(context.Background(), v.fiql)
+		if assert.NoError(t, err, v.fiql) {
+			assert.Equal(t, v.output, res.ToFIQL(), v.fiql)
+		}
+	}
+}
+
+func TestSelectorSnakeCase(t *testing.T) {
+	assert.Equal(t, "created_at", SelectorSnakeCase("createdAt"))
+	assert.Equal(t, "user.created_at", SelectorSnakeCase("user.createdAt"))
+	assert.Equal(t, "http_status", SelectorSnakeCase("HTTPStatus"))
+	assert.Equal(t, "address2_line", SelectorSnakeCase("address2Line"))
+	assert.Equal(t, "already_snake", SelectorSnakeCase("already_snake"))
+}
+
+func TestSelectorCase(t *testing.T) {
+	known := []string{"createdAt", "name", "id", "ID"}
+	var values = []struct {
+		opts   []Option
+		fiql   string
+		output string
+	}{
+		{opts: []Option{WithSelectorCase(SelectorCasePreserve)}, fiql: "Name==x;CREATEDAT=gt=1", output: "Name==x;CREATEDAT=gt=1"},
+		{opts: []Option{WithSelectorCase(SelectorCaseLower)}, fiql: "Name==x;CREATEDAT=gt=1", output: "name==x;createdat=gt=1"},
+		{opts: []Option{WithSelectorCase(SelectorCaseInsensitive, known...)}, fiql: "Name==x;CREATEDAT=gt=1;createdAt", output: "name==x;createdAt=gt=1;createdAt"},
+		// unknown and ambiguous selectors are kept as written
+		{opts: []Option{WithSelectorCase(SelectorCaseInsensitive, known...)}, fiql: "Other==x;Id==1;ID==2", output: "Other==x;Id==1;ID==2"},
+		{opts: []Option{WithSelectorCase(SelectorCaseInsensitive, known...), WithSelectorMapper(SelectorSnakeCase)}, fiql: "CreatedAt=gt=1", output: "created_at=gt=1"},
+		// the last case option wins
+		{opts: []Option{WithSelectorCase(SelectorCaseInsensitive, known...), WithCaseInsensitiveSelectors()}, fiql: "CreatedAt=gt=1", output: "createdat=gt=1"},
+	}
+	for _, v := range values {
+		res, err := New(v.opts...).Parse(context.Background(), v.fiql)
+		if assert.NoError(t, err, v.fiql) {
+			assert.Equal(t, v.output, res.ToFIQL(), v.fiql)
+		}
+	}
+}
+
+func TestSelectorCaseValidation(t *testing.T) {
+	pattern := regexp.MustCompile(`^[a-z][a-zA-Z0-9_.]{0,63}$`)
+	_, err := New(WithSelectorPattern(pattern), WithSelectorCase(SelectorCaseLower)).Parse(context.Background(), "Name==x")
+	assert.NoError(t, err)
+	_, err = New(WithSelectorPattern(pattern), WithSelectorCase(SelectorCaseInsensitive, "createdAt")).Parse(context.Background(), "CreatedAt==x;Other==y")
+	assert.EqualError(t, err, "ln:1:14 invalid selector (`Other` does not match `^[a-z][a-zA-Z0-9_.]{0,63}$`)")
+
+	translator := &SQLTranslator{Columns: map[string]string{"createdAt": "created_at"}}
+	res, err := New(WithSelectorCase(SelectorCaseInsensitive, "createdAt")).Parse(context.Background(), "CREATEDAT=gt=1")
+	if assert.NoError(t, err) {
+		cond, _, err := translator.Translate(res)
+		assert.NoError(t, err)
+		assert.Equal(t, "created_at > ?", cond)
+	}
+}
@@ -0,0 +1,236 @@
+package fiqlparser
+
+import (
+	"sort"
+	"strings"
+)
+
+// SharedSubexpression is a sub filter contained in multiple expressions
+type SharedSubexpression struct {
+	// Filter is the canonical FIQL of the sub filter
+	Filter string
+	// Expressions contains the indexes of all expressions containing the sub filter
+	Expressions []int
+}
+
+// subexpressionIndex collects the canonical sub filters of a set of expressions
+type subexpressionIndex struct {
+	// occurrences maps each sub filter to the expressions containing it
+	occurrences map[string]map[int]bool
+	// parts maps each sub filter to all sub filters it consists of
+	parts map[string]map[string]bool
+	// conjunctions contains the operands of every AND per expression
+	conjunctions []indexedConjunction
+}
+
+type indexedConjunction struct {
+	expression int
+	operands   map[string]canonicalOperand
+}
+
+func newSubexpressionIndex() *subexpressionIndex {
+	return &subexpressionIndex{
+		occurrences: make(map[string]map[int]bool),
+		parts:       make(map[string]map[string]bool),
+	}
+}
+
+func (idx *subexpressionIndex) add(key string, expression int, parts map[string]bool) {
+	if idx.occurrences[key] == nil {
+		idx.occurrences[key] = make(map[int]bool)
+		idx.parts[key] = make(map[string]bool)
+	}
+	idx.occurrences[key][expression] = true
+	for p := range parts {
+		idx.parts[key][p] = true
+	}
+}
+
+// walk indexes the node and all its sub filters and returns the keys of all of them
+func (idx *subexpressionIndex) walk(n Node, expression int) map[string]bool {
+	key := canonicalFIQL(n)
+	parts := make(map[string]bool)
+	if op, operands, ok := logicalOperands(n); ok {
+		conj := indexedConjunction{expression: expression, operands: make(map[string]canonicalOperand)}
+		for _, o := range operands {
+			for p := range idx.walk(o, expression) {
+				parts[p] = true
+			}
+			c, compound := canonicalNode(o)
+			conj.operands[c] = canonicalOperand{fiql: c, compound: compound}
+		}
+		if op == string(OperatorAND) {
+			idx.conjunctions = append(idx.conjunctions, conj)
+		}
+	}
+	idx.add(key, expression, parts)
+	parts[key] = true
+	return parts
+}
+
+// addSharedConjunctions adds the common operands of each pair of conjunctions as sub filter
+func (idx *subexpressionIndex) addSharedConjunctions() {
+	for i, a := range idx.conjunctions {
+		for _, b := range idx.conjunctions[i+1:] {
+			common := make([]canonicalOperand, 0)
+			for k, o := range a.operands {
+				if _, ok := b.operands[k]; ok {
+					common = append(common, o)
+				}
+			}
+			if len(common) < 2 {
+				continue
+			}
+			key, _ := joinCanonicalOperands(string(OperatorAND), common)
+			parts := make(map[string]bool)
+			for _, o := range common {
+				parts[o.fiql] = true
+				for p := range idx.parts[o.fiql] {
+					parts[p] = true
+				}
+			}
+			for _, c := range idx.conjunctions {
+				if containsAllOperands(c.operands, common) {
+					idx.add(key, c.expression, parts)
+				}
+			}
+		}
+	}
+}
+
+func containsAllOperands(operands map[string]canonicalOperand, required []canonicalOperand) bool {
+	for _, r := range required {
+		if _, ok := operands[r.fiql]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func sameExpressions(a, b map[int]bool) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k := range a {
+		if !b[k] {
+			return false
+		}
+	}
+	return true
+}
+
+// SharedSubexpressions computes the sub filters contained in at least minShared of the supplied expressions
+// (minShared is at least 2). This includes common predicates, common sub expressions and common
+// subsets of the operands of AND operations (e.g. `a==1;b==2` for `a==1;b==2;c==3` and `b==2;d==4;a==1`).
+// The result is minimal, a sub filter is omitted if a larger sub filter containing it is shared by the same expressions.
+// It is intended to find candidates for materialized views or cached segments.
+// The result is sorted by the number of expressions sharing the sub filter, then by the sub filter.
+func SharedSubexpressions(exprs []Expression, minShared int) []SharedSubexpression {
+	if minShared < 2 {
+		minShared = 2
+	}
+	idx := newSubexpressionIndex()
+	for i := range exprs {
+		if exprs[i].node != nil {
+			idx.walk(&exprs[i], i)
+		}
+	}
+	idx.addSharedConjunctions()
+
+	candidates := make([]string, 0)
+	for key, occ := range idx.occurrences {
+		if len(occ) >= minShared {
+			candidates = append(candidates, key)
+		}
+	}
+	res := make([]SharedSubexpression, 0)
+	for _, c := range candidates {
+		redundant := false
+		for _, other := range candidates {
+			if other != c && idx.parts[other][c] && sameExpressions(idx.occurrences[c], idx.occurrences[other]) {
+				redundant = true
+				break
+			}
+		}
+		if redundant {
+			continue
+		}
+		shared := SharedSubexpression{Filter: c, Expressions: make([]int, 0, len(idx.occurrences[c]))}
+		for i := range idx.occurrences[c] {
+			shared.Expressions = append(shared.Expressions, i)
+		}
+		sort.Ints(shared.Expressions)
+		res = append(res, shared)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if len(res[i].Expressions) != len(res[j].Expressions) {
+			return len(res[i].Expressions) > len(res[j].Expressions)
+		}
+		return strings.Compare(res[i].Filter, res[j].Filter) < 0
+	})
+	return res
+}
+
+// RepeatedSubexpression is a sub filter occurring multiple times within one expression
+type RepeatedSubexpression struct {
+	// Filter is the canonical FIQL of the sub filter
+	Filter string
+	// Nodes contains every occurrence of the sub filter
+	Nodes []Node
+}
+
+// RepeatedSubexpressions returns all sub filters (predicates and logical operations) which occur
+// more than once within the expression, compared by their canonical form (e.g. `a==1;b==2` equals `(b==2;a==1)`).
+// A sub filter is omitted if it only repeats as part of a larger repeated sub filter.
+// The result is sorted by the number of occurrences, then by the sub filter.
+func (e *Expression) RepeatedSubexpressions() []RepeatedSubexpression {
+	nodes := make(map[string][]Node)
+	parts := make(map[string]map[string]bool)
+	var walk func(n Node) map[string]bool
+	walk = func(n Node) map[string]bool {
+		key := canonicalFIQL(n)
+		p := make(map[string]bool)
+		if _, operands, ok := logicalOperands(n); ok {
+			for _, o := range operands {
+				for k := range walk(o) {
+					p[k] = true
+				}
+			}
+		}
+		nodes[key] = append(nodes[key], unwrapExpression(n))
+		if parts[key] == nil {
+			parts[key] = make(map[string]bool)
+		}
+		for k := range p {
+			parts[key][k] = true
+		}
+		p[key] = true
+		return p
+	}
+	if e.node != nil {
+		walk(e)
+	}
+	res := make([]RepeatedSubexpression, 0)
+	for key, occ := range nodes {
+		if len(occ) < 2 {
+			continue
+		}
+		redundant := false
+		for other, otherOcc := range nodes {
+			if other != key && len(otherOcc) == len(occ) && parts[other][key] {
+				redundant = true
+				break
+			}
+		}
+		if !redundant {
+			res = append(res, RepeatedSubexpression{Filter: key, Nodes: occ})
+		}
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if len(res[i].Nodes) != len(res[j].Nodes) {
+			return len(res[i].Nodes) > len(res[j].Nodes)
+		}
+		return res[i].Filter < res[j].Filter
+	})
+	return res
+}
@@ -0,0 +1,83 @@
+package fiqlparser
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCanonicalFIQL(t *testing.T) {
+	var values = []struct {
+		fiql      string
+		canonical string
+	}{
+		{fiql: "b==2;a==1", canonical: "a==1;b==2"},
+		{fiql: "(b==2;(a==1))", canonical: "a==1;b==2"},
+		{fiql: "b==2;a==1;b==2", canonical: "a==1;b==2"},
+		{fiql: "c==3,(b==2;a==1)", canonical: "(a==1;b==2),c==3"},
+		{fiql: "(a==1;a==1),c==3", canonical: "a==1,c==3"},
+		{fiql: `name=="John Doe"`, canonical: `name=="John Doe"`},
+		{fiql: `name=="John"`, canonical: `name==John`},
+		{fiql: "x:(a==1)", canonical: "a==1"},
+	}
+	for _, v := range values {
+		res, err := Parse(context.Background(), v.fiql)
+		if assert.NoError(t, err) {
+			assert.Equal(t, v.canonical, canonicalFIQL(&res), v.fiql)
+		}
+	}
+}
+
+func TestSharedSubexpressions(t *testing.T) {
+	filters := []string{
+		"a==1;b==2;c==3",
+		"b==2;d==4;a==1",
+		"e==5,(a==1;b==2)",
+		"x==1,(y==2;z==3)",
+		"w==0;((z==3;y==2),x==1)",
+	}
+	exprs := make([]Expression, 0)
+	for _, f := range filters {
+		res, err := Parse(context.Background(), f)
+		assert.NoError(t, err)
+		exprs = append(exprs, res)
+	}
+	assert.Equal(t, []SharedSubexpression{
+		{Filter: "a==1;b==2", Expressions: []int{0, 1, 2}},
+		{Filter: "(y==2;z==3),x==1", Expressions: []int{3, 4}},
+	}, SharedSubexpressions(exprs, 2))
+
+	assert.Equal(t, []SharedSubexpression{
+		{Filter: "a==1;b==2", Expressions: []int{0, 1, 2}},
+	}, SharedSubexpressions(exprs, 3))
+
+	assert.Empty(t, SharedSubexpressions(exprs, 4))
+}
+
+func TestRepeatedSubexpressions(t *testing.T) {
+	var values = []struct {
+		fiql     string
+		repeated map[string]int
+	}{
+		{fiql: "a==1;b==2", repeated: map[string]int{}},
+		{fiql: "a==1;b==2;a==1", repeated: map[string]int{"a==1": 2}},
+		{fiql: "(a==1;b==2),(c==3,(b==2;a==1))", repeated: map[string]int{"a==1;b==2": 2}},
+		{fiql: "(a==1;b==2),(c==3;b==2;a==1)", repeated: map[string]int{"a==1": 2, "b==2": 2}},
+		{fiql: "(x==1,(a==1;b==2)),(y==2,(a==1;b==2));a==1", repeated: map[string]int{"a==1": 3, "a==1;b==2": 2}},
+	}
+	for _, v := range values {
+		res, err := Parse(context.Background(), v.fiql)
+		if !assert.NoError(t, err) {
+			continue
+		}
+		repeated := make(map[string]int)
+		for _, r := range res.RepeatedSubexpressions() {
+			repeated[r.Filter] = len(r.Nodes)
+			for _, n := range r.Nodes {
+				assert.Equal(t, r.Filter, canonicalFIQL(n))
+			}
+		}
+		assert.Equal(t, v.repeated, repeated, v.fiql)
+	}
+}
@@ -0,0 +1,527 @@
+package fiqlparser
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SQLPlaceholderStyle defines how parameters are referenced in the generated SQL
+type SQLPlaceholderStyle int
+
+// SQLPlaceholderQuestion uses `?` placeholders (MySQL, SQLite, sqlx before Rebind, GORM)
+const SQLPlaceholderQuestion SQLPlaceholderStyle = 0
+
+// SQLPlaceholderDollar uses numbered `$1` placeholders (PostgreSQL)
+const SQLPlaceholderDollar SQLPlaceholderStyle = 1
+
+// SQLPlaceholderNamed uses named `:fiql_arg_1` placeholders (sqlx named queries, database/sql named args),
+// see TranslateNamed for the bindings
+const SQLPlaceholderNamed SQLPlaceholderStyle = 2
+
+// SQLFullTextFunc translates a full text search (`=q=`) on the column,
+// param is the placeholder of the search term
+type SQLFullTextFunc func(column string, param string) string
+
+// SQLFullTextPostgres translates full text searches using tsvector and plainto_tsquery,
+// config is the text search configuration (e.g. `english`), empty for the default configuration
+func SQLFullTextPostgres(config string) SQLFullTextFunc {
+	return func(column string, param string) string {
+		if config == "" {
+			return "to_tsvector(" + column + ") @@ plainto_tsquery(" + param + ")"
+		}
+		cfg := "'" + strings.ReplaceAll(config, "'", "''") + "'"
+		return "to_tsvector(" + cfg + ", " + column + ") @@ plainto_tsquery(" + cfg + ", " + param + ")"
+	}
+}
+
+// SQLFullTextMySQL translates full text searches using MATCH AGAINST in natural language mode,
+// the column needs a FULLTEXT index
+func SQLFullTextMySQL(column string, param string) string {
+	return "MATCH (" + column + ") AGAINST (" + param + " IN NATURAL LANGUAGE MODE)"
+}
+
+// defaultSQLPlaceholderPrefix is the prefix of named placeholders
+const defaultSQLPlaceholderPrefix = "fiql_arg_"
+
+// sqlLikeEscape is the escape character used for LIKE patterns,
+// it is supported by all common databases without further quoting issues
+const sqlLikeEscape = '!'
+
+// SQLTranslator translates a expression to a SQL condition.
+// Values are never inlined, they are always passed as parameters.
+// Selectors are only accepted if they are mapped to a column, so user input never ends up in the SQL.
+//
+// The result can be passed to GORM (db.Where(cond, args...)) or sqlx.
+type SQLTranslator struct {
+	// Columns maps selectors to columns, the columns are used as is in the generated SQL
+	Columns map[string]string
+	// Resolver resolves selectors which are not mapped in Columns,
+	// the returned identifier is used as is in the generated SQL
+	Resolver SelectorResolver
+	// Relations maps selector paths to related tables, e.g. `customer` for `customer.name`,
+	// see Joins for the join clauses of to-one relations
+	Relations map[string]SQLRelation
+	// Placeholder is the placeholder style for parameters
+	Placeholder SQLPlaceholderStyle
+	// PlaceholderPrefix is the prefix of named placeholders, defaults to `fiql_arg_`
+	PlaceholderPrefix string
+	// FullText translates full text searches (`=q=`), they are rejected if not set
+	FullText SQLFullTextFunc
+	// Provenance prepends the comment of the filter (see Expression.SQLComment),
+	// so slow queries can be correlated with the filters causing them
+	Provenance bool
+	// Nulls defines whether `!=` matches null columns, with NullSemanticsInclude
+	// `status!=open` is translated to `(status <> ? OR status IS NULL)`
+	Nulls NullSemantics
+	// Scopes are default conditions always added to the condition (e.g. `users.deleted_at IS NULL`),
+	// they are used as is in the generated SQL, see Unscoped
+	Scopes []string
+
+	unscoped bool
+}
+
+// Unscoped returns a copy of the translator which omits the default scopes of the table and all relations
+func (t *SQLTranslator) Unscoped() *SQLTranslator {
+	c := *t
+	c.unscoped = true
+	return &c
+}
+
+// scopes returns the scopes unless disabled
+func (t *SQLTranslator) scopes(scopes []string) []string {
+	if t.unscoped {
+		return nil
+	}
+	return scopes
+}
+
+type sqlBuilder struct {
+	t    *SQLTranslator
+	b    bytes.Buffer
+	args []interface{}
+}
+
+// maxPooledSQLBuffer is the buffer capacity up to which builders are reused
+const maxPooledSQLBuffer = 4096
+
+// sqlBuilderPool reuses the buffers of builders between translations,
+// the arguments are returned to the caller and are therefore never reused
+var sqlBuilderPool = sync.Pool{New: func() interface{} { return &sqlBuilder{} }}
+
+func newSQLBuilder(t *SQLTranslator) *sqlBuilder {
+	s := sqlBuilderPool.Get().(*sqlBuilder)
+	s.t = t
+	s.args = make([]interface{}, 0)
+	s.b.Reset()
+	return s
+}
+
+func (s *sqlBuilder) release() {
+	s.t = nil
+	s.args = nil
+	if s.b.Cap() <= maxPooledSQLBuffer {
+		sqlBuilderPool.Put(s)
+	}
+}
+
+// Translate returns the condition of the expression and its arguments combined with the scopes,
+// a empty expression without scopes returns a empty condition
+func (t *SQLTranslator) Translate(e Expression) (string, []interface{}, error) {
+	s := newSQLBuilder(t)
+	defer s.release()
+	if err := s.translate(&e); err != nil {
+		return "", nil, err
+	}
+	return s.b.String(), s.args, nil
+}
+
+// AppendSQL appends the condition of the expression (see Translate) to dst
+// and returns the extended buffer and the arguments
+func (t *SQLTranslator) AppendSQL(dst []byte, e Expression) ([]byte, []interface{}, error) {
+	s := newSQLBuilder(t)
+	defer s.release()
+	if err := s.translate(&e); err != nil {
+		return dst, nil, err
+	}
+	return append(dst, s.b.Bytes()...), s.args, nil
+}
+
+// WriteSQLTo writes the condition of the expression (see Translate) to w and returns the arguments,
+// nothing is written if the translation fails
+func (t *SQLTranslator) WriteSQLTo(w io.Writer, e Expression) (int64, []interface{}, error) {
+	s := newSQLBuilder(t)
+	defer s.release()
+	if err := s.translate(&e); err != nil {
+		return 0, nil, err
+	}
+	n, err := w.Write(s.b.Bytes())
+	if err != nil {
+		return int64(n), nil, err
+	}
+	return int64(n), s.args, nil
+}
+
+// translate writes the condition of the expression combined with the scopes
+func (s *sqlBuilder) translate(e *Expression) error {
+	scopes := s.t.scopes(s.t.Scopes)
+	if e.node == nil {
+		s.b.WriteString(strings.Join(scopes, " AND "))
+		return nil
+	}
+	if s.t.Provenance {
+		s.b.WriteString(e.SQLComment())
+		s.b.WriteRune(' ')
+	}
+	op, _, logical := logicalOperands(e)
+	nested := logical && op == string(OperatorOR) && len(scopes) > 0
+	if nested {
+		s.b.WriteRune('(')
+	}
+	if err := s.write(e); err != nil {
+		return err
+	}
+	if nested {
+		s.b.WriteRune(')')
+	}
+	for _, scope := range scopes {
+		s.b.WriteString(" AND ")
+		s.b.WriteString(scope)
+	}
+	return nil
+}
+
+// Where returns the condition prefixed by `WHERE` and its arguments,
+// a empty condition returns a empty string
+func (t *SQLTranslator) Where(e Expression) (string, []interface{}, error) {
+	cond, args, err := t.Translate(e)
+	if err != nil || cond == "" {
+		return cond, args, err
+	}
+	return "WHERE " + cond, args, nil
+}
+
+// placeholderName returns the name of the nth (starting at 1) named placeholder
+func (t *SQLTranslator) placeholderName(n int) string {
+	prefix := t.PlaceholderPrefix
+	if prefix == "" {
+		prefix = defaultSQLPlaceholderPrefix
+	}
+	return prefix + strconv.Itoa(n)
+}
+
+// TranslateNamed returns the condition with named placeholders (e.g. `:fiql_arg_1`) regardless
+// of the configured placeholder style and the bindings of the placeholders by name (without colon)
+func (t *SQLTranslator) TranslateNamed(e Expression) (string, map[string]interface{}, error) {
+	c := *t
+	c.Placeholder = SQLPlaceholderNamed
+	cond, args, err := c.Translate(e)
+	if err != nil {
+		return "", nil, err
+	}
+	bindings := make(map[string]interface{}, len(args))
+	for i, arg := range args {
+		bindings[c.placeholderName(i+1)] = arg
+	}
+	return cond, bindings, nil
+}
+
+// SQLStatement is a translated condition with a key identifying its shape
+type SQLStatement struct {
+	// Key is derived from SQL only, filters differing in their values share the key
+	// and it can be used to cache prepared statements
+	Key string
+	// SQL is the condition
+	SQL string
+	// Args are the arguments of the condition
+	Args []interface{}
+}
+
+// Statement returns the condition, its arguments and a stable key of the condition,
+// e.g. `name==x` and `name==y` result in the same key while `name==x*` does not.
+// The provenance comment is not part of the key.
+func (t *SQLTranslator) Statement(e Expression) (SQLStatement, error) {
+	cond, args, err := t.Translate(e)
+	if err != nil {
+		return SQLStatement{}, err
+	}
+	key := cond
+	if t.Provenance {
+		key = strings.TrimPrefix(cond, e.SQLComment()+" ")
+	}
+	return SQLStatement{Key: sqlStatementKey(key), SQL: cond, Args: args}, nil
+}
+
+// sqlStatementKey hashes the condition, as values are never inlined it only depends on the shape of the filter
+func sqlStatementKey(cond string) string {
+	sum := sha256.Sum256([]byte(cond))
+	return hex.EncodeToString(sum[:16])
+}
+
+func (s *sqlBuilder) column(selector string) (string, error) {
+	c, ok := s.t.Columns[selector]
+	if (!ok || c == "") && s.t.Resolver != nil {
+		return s.t.Resolver(selector)
+	}
+	if !ok || c == "" {
+		return "", fmt.Errorf("%w `%s`", ErrUnknownSelector, selector)
+	}
+	return c, nil
+}
+
+func (s *sqlBuilder) placeholder(arg interface{}) {
+	s.b.WriteString(s.param(arg))
+}
+
+// param adds the argument and returns its placeholder
+func (s *sqlBuilder) param(arg interface{}) string {
+	s.args = append(s.args, arg)
+	switch s.t.Placeholder {
+	case SQLPlaceholderDollar:
+		return "$" + strconv.Itoa(len(s.args))
+	case SQLPlaceholderNamed:
+		return ":" + s.t.placeholderName(len(s.args))
+	}
+	return "?"
+}
+
+func (s *sqlBuilder) write(n Node) error {
+	n = unwrapExpression(n)
+	if op, operands, ok := logicalOperands(n); ok {
+		for i, o := range operands {
+			if i > 0 {
+				s.b.WriteRune(' ')
+				s.b.WriteString(op)
+				s.b.WriteRune(' ')
+			}
+			_, _, nested := logicalOperands(o)
+			if nested {
+				s.b.WriteRune('(')
+			}
+			if err := s.write(o); err != nil {
+				return err
+			}
+			if nested {
+				s.b.WriteRune(')')
+			}
+		}
+		return nil
+	}
+	switch node := n.(type) {
+	case *binaryExpression:
+		sel, _, ok := predicateOperands(node)
+		if !ok {
+			return fmt.Errorf("incomplete comparison `%s`", node.String())
+		}
+		return s.writeExists(sel.value, func() error { return s.writePredicate(node) })
+	case *notExpression:
+		s.b.WriteString("NOT (")
+		if err := s.write(node.node); err != nil {
+			return err
+		}
+		s.b.WriteRune(')')
+		return nil
+	case *constantExpression:
+		return s.writeExists(node.value, func() error {
+			col, err := s.column(node.value)
+			if err != nil {
+				return err
+			}
+			s.b.WriteString(col)
+			s.b.WriteString(" IS NOT NULL")
+			return nil
+		})
+	}
+	return fmt.Errorf("unsupported node `%v`", n)
+}
+
+var sqlComparisons = map[string]string{
+	string(ComparisonEq):  "=",
+	string(ComparisonNeq): "<>",
+	string(ComparisonGt):  ">",
+	string(ComparisonGte): ">=",
+	string(ComparisonLt):  "<",
+	string(ComparisonLte): "<=",
+}
+
+func (s *sqlBuilder) writePredicate(node *binaryExpression) error {
+	sel, arg, ok := predicateOperands(node)
+	if !ok {
+		return fmt.Errorf("incomplete comparison `%s`", node.String())
+	}
+	col, err := s.column(sel.value)
+	if err != nil {
+		return err
+	}
+	if s.t.Nulls == NullSemanticsInclude && isNullSensitive(node.operator, arg) {
+		s.b.WriteRune('(')
+		if err := s.writeComparison(col, node.operator, arg); err != nil {
+			return err
+		}
+		s.b.WriteString(" OR ")
+		s.b.WriteString(col)
+		s.b.WriteString(" IS NULL)")
+		return nil
+	}
+	return s.writeComparison(col, node.operator, arg)
+}
+
+func (s *sqlBuilder) writeComparison(col string, operator string, arg *constantExpression) error {
+	if arg.recommended == ValueRecommendationRange {
+		return s.writeBetween(col, operator, arg)
+	}
+	if arg.tuple != nil {
+		return s.writeIn(col, arg.tuple)
+	}
+	if ref, ok := arg.Argument().Field(); ok {
+		return s.writeFieldComparison(col, operator, ref)
+	}
+	if operator == string(ComparisonQuery) {
+		if s.t.FullText == nil {
+			return fmt.Errorf("unsupported comparison `%s`, no full text function configured", operator)
+		}
+		if arg.prefixWildcard || arg.suffixWildcard {
+			return fmt.Errorf("wildcards are not supported within full text searches `%s`", arg.value)
+		}
+		s.b.WriteString(s.t.FullText(col, s.param(arg.value)))
+		return nil
+	}
+	cmp, ok := sqlComparisons[operator]
+	if !ok {
+		return fmt.Errorf("unsupported comparison `%s`", operator)
+	}
+	s.b.WriteString(col)
+	if arg.recommended == ValueRecommendationNull && (operator == string(ComparisonEq) || operator == string(ComparisonNeq)) {
+		if operator == string(ComparisonNeq) {
+			s.b.WriteString(" IS NOT NULL")
+		} else {
+			s.b.WriteString(" IS NULL")
+		}
+		return nil
+	}
+	if (arg.prefixWildcard || arg.suffixWildcard) && (operator == string(ComparisonEq) || operator == string(ComparisonNeq)) {
+		if operator == string(ComparisonNeq) {
+			s.b.WriteString(" NOT")
+		}
+		s.b.WriteString(" LIKE ")
+		s.placeholder(sqlLikePattern(arg))
+		s.b.WriteString(" ESCAPE '")
+		s.b.WriteRune(sqlLikeEscape)
+		s.b.WriteRune('\'')
+		return nil
+	}
+	s.b.WriteRune(' ')
+	s.b.WriteString(cmp)
+	s.b.WriteRune(' ')
+	s.placeholder(sqlArgument(arg))
+	return nil
+}
+
+// writeBetween writes a range comparison, `==` and `=bt=` match the range and `!=` excludes it
+func (s *sqlBuilder) writeBetween(col string, operator string, arg *constantExpression) error {
+	low, high, ok := argumentRangeBounds(arg.value, arg.tuple)
+	if !ok {
+		return fmt.Errorf("%w `%s`", ErrNoRange, arg.value)
+	}
+	s.b.WriteString(col)
+	switch operator {
+	case string(ComparisonEq), string(ComparisonBetween):
+	case string(ComparisonNeq):
+		s.b.WriteString(" NOT")
+	default:
+		return fmt.Errorf("unsupported range comparison `%s`", operator)
+	}
+	s.b.WriteString(" BETWEEN ")
+	s.placeholder(sqlArgument(low))
+	s.b.WriteString(" AND ")
+	s.placeholder(sqlArgument(high))
+	return nil
+}
+
+// writeFieldComparison writes a column to column comparison (see WithFieldReferences),
+// the referenced selector may not pass a to-many relation
+func (s *sqlBuilder) writeFieldComparison(col string, operator string, ref string) error {
+	cmp, ok := sqlComparisons[operator]
+	if !ok {
+		return fmt.Errorf("unsupported field comparison `%s`", operator)
+	}
+	if _, many := s.t.splitRelationChain(s.t.relationChain(ref)); len(many) > 0 {
+		return fmt.Errorf("field references into to-many relations are not supported `%s`", ref)
+	}
+	other, err := s.column(ref)
+	if err != nil {
+		return err
+	}
+	s.b.WriteString(col)
+	s.b.WriteRune(' ')
+	s.b.WriteString(cmp)
+	s.b.WriteRune(' ')
+	s.b.WriteString(other)
+	return nil
+}
+
+// writeIn writes a membership comparison with a parameter per element
+func (s *sqlBuilder) writeIn(col string, t *tupleArgument) error {
+	s.b.WriteString(col)
+	s.b.WriteString(" IN (")
+	for i, el := range t.elements {
+		if el.prefixWildcard || el.suffixWildcard {
+			return fmt.Errorf("wildcards are not supported within tuples `%s`", el.value)
+		}
+		if i > 0 {
+			s.b.WriteString(", ")
+		}
+		s.placeholder(sqlArgument(el))
+	}
+	s.b.WriteRune(')')
+	return nil
+}
+
+// sqlLikePattern converts the argument to a LIKE pattern
+func sqlLikePattern(arg *constantExpression) string {
+	var b strings.Builder
+	if arg.prefixWildcard {
+		b.WriteRune('%')
+	}
+	for _, r := range arg.value {
+		if r == '%' || r == '_' || r == sqlLikeEscape {
+			b.WriteRune(sqlLikeEscape)
+		}
+		b.WriteRune(r)
+	}
+	if arg.suffixWildcard {
+		b.WriteRune('%')
+	}
+	return b.String()
+}
+
+// sqlArgument converts the argument to a typed parameter according to its recommendation
+func sqlArgument(arg *constantExpression) interface{} {
+	switch arg.recommended {
+	case ValueRecommendationNumber:
+		if i, err := strconv.ParseInt(arg.value, 10, 64); err == nil {
+			return i
+		}
+		if f, err := strconv.ParseFloat(arg.value, 64); err == nil {
+			return f
+		}
+	case ValueRecommendationDateTime:
+		if t, err := time.Parse(time.RFC3339, arg.value); err == nil {
+			return t
+		}
+		if arg.layout != nil {
+			if t, ok := arg.layout(arg.value, arg.Argument().location()); ok {
+				return t
+			}
+		}
+	case ValueRecommendationBoolean:
+		return arg.value == "true"
+	}
+	return arg.value
+}
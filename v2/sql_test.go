@@ -0,0 +1,294 @@
+package fiqlparser
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testSQLColumns = map[string]string{
+	"name":    "users.name",
+	"age":     "users.age",
+	"created": "users.created_at",
+	"deleted": "users.deleted_at",
+	"status":  "users.status",
+}
+
+func TestSQLTranslator(t *testing.T) {
+	translator := &SQLTranslator{Columns: testSQLColumns}
+	var values = []struct {
+		fiql string
+		sql  string
+		args []interface{}
+	}{
+		{fiql: "name==John", sql: "users.name = ?", args: []interface{}{"John"}},
+		{fiql: "name!=John", sql: "users.name <> ?", args: []interface{}{"John"}},
+		{fiql: "age=gt=18", sql: "users.age > ?", args: []interface{}{int64(18)}},
+		{fiql: "age=le=18.5", sql: "users.age <= ?", args: []interface{}{18.5}},
+		{fiql: "created=ge=2003-12-13T00:00:00Z", sql: "users.created_at >= ?", args: []interface{}{time.Date(2003, 12, 13, 0, 0, 0, 0, time.UTC)}},
+		{fiql: "name==Jo*", sql: "users.name LIKE ? ESCAPE '!'", args: []interface{}{"Jo%"}},
+		{fiql: `name!=*5%_\!*`, sql: "users.name NOT LIKE ? ESCAPE '!'", args: []interface{}{"%5!%!_!!%"}},
+		{fiql: "deleted", sql: "users.deleted_at IS NOT NULL", args: []interface{}{}},
+		{fiql: "deleted==null", sql: "users.deleted_at IS NULL", args: []interface{}{}},
+		{fiql: "deleted!=null", sql: "users.deleted_at IS NOT NULL", args: []interface{}{}},
+		{fiql: "status==true", sql: "users.status = ?", args: []interface{}{true}},
+		{fiql: `status=="null"`, sql: "users.status = ?", args: []interface{}{"null"}},
+		{fiql: "age=in=[18+21+65]", sql: "users.age IN (?, ?, ?)", args: []interface{}{int64(18), int64(21), int64(65)}},
+		{fiql: "age==18..65", sql: "users.age BETWEEN ? AND ?", args: []interface{}{int64(18), int64(65)}},
+		{fiql: "age!=18..65", sql: "users.age NOT BETWEEN ? AND ?", args: []interface{}{int64(18), int64(65)}},
+		{fiql: "created=bt=2003-12-13T00:00:00Z..2003-12-14T00:00:00Z", sql: "users.created_at BETWEEN ? AND ?", args: []interface{}{time.Date(2003, 12, 13, 0, 0, 0, 0, time.UTC), time.Date(2003, 12, 14, 0, 0, 0, 0, time.UTC)}},
+		{fiql: "name==\"x' OR 1=1 --\"", sql: "users.name = ?", args: []interface{}{"x' OR 1=1 --"}},
+		{fiql: "name==a;age==1;status==b", sql: "users.name = ? AND users.age = ? AND users.status = ?", args: []interface{}{"a", int64(1), "b"}},
+		{fiql: "name==a;(age==1,status==b)", sql: "users.name = ? AND (users.age = ? OR users.status = ?)", args: []interface{}{"a", int64(1), "b"}},
+		{fiql: "(name==a;age==1),status==b", sql: "(users.name = ? AND users.age = ?) OR users.status = ?", args: []interface{}{"a", int64(1), "b"}},
+	}
+	for _, v := range values {
+		res, err := Parse(context.Background(), v.fiql)
+		if !assert.NoError(t, err, v.fiql) {
+			continue
+		}
+		sql, args, err := translator.Translate(res)
+		if assert.NoError(t, err, v.fiql) {
+			assert.Equal(t, v.sql, sql, v.fiql)
+			assert.Equal(t, v.args, args, v.fiql)
+		}
+	}
+}
+
+func TestSQLTranslatorWhere(t *testing.T) {
+	translator := &SQLTranslator{Columns: testSQLColumns, Placeholder: SQLPlaceholderDollar}
+	res, err := Parse(context.Background(), "name==a,age=gt=1")
+	assert.NoError(t, err)
+	sql, args, err := translator.Where(res)
+	assert.NoError(t, err)
+	assert.Equal(t, "WHERE users.name = $1 OR users.age > $2", sql)
+	assert.Equal(t, []interface{}{"a", int64(1)}, args)
+
+	sql, args, err = translator.Where(Expression{root: true})
+	assert.NoError(t, err)
+	assert.Equal(t, "", sql)
+	assert.Empty(t, args)
+}
+
+func TestSQLTranslatorNullSemantics(t *testing.T) {
+	translator := &SQLTranslator{Columns: testSQLColumns, Nulls: NullSemanticsInclude}
+	var values = []struct {
+		fiql string
+		sql  string
+	}{
+		{fiql: "status!=open", sql: "(users.status <> ? OR users.status IS NULL)"},
+		{fiql: "name!=Jo*", sql: "(users.name NOT LIKE ? ESCAPE '!' OR users.name IS NULL)"},
+		{fiql: "age!=18..65", sql: "(users.age NOT BETWEEN ? AND ? OR users.age IS NULL)"},
+		{fiql: "name==a;status!=open", sql: "users.name = ? AND (users.status <> ? OR users.status IS NULL)"},
+		{fiql: "status!=null", sql: "users.status IS NOT NULL"},
+		{fiql: "status==open", sql: "users.status = ?"},
+		{fiql: "age=gt=1", sql: "users.age > ?"},
+	}
+	for _, v := range values {
+		res, err := Parse(context.Background(), v.fiql)
+		if !assert.NoError(t, err, v.fiql) {
+			continue
+		}
+		sql, _, err := translator.Translate(res)
+		if assert.NoError(t, err, v.fiql) {
+			assert.Equal(t, v.sql, sql, v.fiql)
+		}
+	}
+}
+
+func TestSQLTranslatorResolver(t *testing.T) {
+	translator := &SQLTranslator{
+		Columns: map[string]string{"age": "users.age"},
+		Resolver: func(selector string) (string, error) {
+			switch selector {
+			case "name":
+				return "users.first_name || ' ' || users.last_name", nil
+			case "customer.city":
+				return "customers.city", nil
+			}
+			return "", fmt.Errorf("%w `%s`", ErrUnknownSelector, selector)
+		},
+	}
+	res, err := Parse(context.Background(), "name==John*;customer.city==Vienna;age=gt=18")
+	assert.NoError(t, err)
+	sql, args, err := translator.Translate(res)
+	assert.NoError(t, err)
+	assert.Equal(t, "users.first_name || ' ' || users.last_name LIKE ? ESCAPE '!' AND customers.city = ? AND users.age > ?", sql)
+	assert.Equal(t, []interface{}{"John%", "Vienna", int64(18)}, args)
+
+	res, err = Parse(context.Background(), "password==x")
+	assert.NoError(t, err)
+	_, _, err = translator.Translate(res)
+	assert.ErrorIs(t, err, ErrUnknownSelector)
+}
+
+func TestSQLTranslatorScopes(t *testing.T) {
+	translator := &SQLTranslator{Columns: testSQLColumns, Scopes: []string{"users.deleted_at IS NULL", "users.tenant_id = 1"}}
+	var values = []struct {
+		fiql string
+		sql  string
+	}{
+		{fiql: "name==a", sql: "users.name = ? AND users.deleted_at IS NULL AND users.tenant_id = 1"},
+		{fiql: "name==a;age==1", sql: "users.name = ? AND users.age = ? AND users.deleted_at IS NULL AND users.tenant_id = 1"},
+		{fiql: "name==a,age==1", sql: "(users.name = ? OR users.age = ?) AND users.deleted_at IS NULL AND users.tenant_id = 1"},
+	}
+	for _, v := range values {
+		res, err := Parse(context.Background(), v.fiql)
+		if !assert.NoError(t, err, v.fiql) {
+			continue
+		}
+		sql, _, err := translator.Translate(res)
+		if assert.NoError(t, err, v.fiql) {
+			assert.Equal(t, v.sql, sql, v.fiql)
+		}
+	}
+
+	sql, args, err := translator.Where(Expression{root: true})
+	assert.NoError(t, err)
+	assert.Equal(t, "WHERE users.deleted_at IS NULL AND users.tenant_id = 1", sql)
+	assert.Empty(t, args)
+
+	res, err := Parse(context.Background(), "name==a,age==1")
+	assert.NoError(t, err)
+	sql, _, err = translator.Unscoped().Translate(res)
+	assert.NoError(t, err)
+	assert.Equal(t, "users.name = ? OR users.age = ?", sql)
+	// the original translator keeps its scopes
+	sql, _, err = translator.Translate(res)
+	assert.NoError(t, err)
+	assert.Contains(t, sql, "users.deleted_at IS NULL")
+
+	// the provenance comment precedes the condition
+	translator.Provenance = true
+	sql, _, err = translator.Translate(res)
+	assert.NoError(t, err)
+	assert.Equal(t, res.SQLComment()+" (users.name = ? OR users.age = ?) AND users.deleted_at IS NULL AND users.tenant_id = 1", sql)
+}
+
+func TestSQLTranslatorNamedPlaceholders(t *testing.T) {
+	translator := &SQLTranslator{Columns: testSQLColumns, Placeholder: SQLPlaceholderNamed}
+	res, err := Parse(context.Background(), "name==Jo*;age=bt=18..65,status=in=[a+b]")
+	if !assert.NoError(t, err) {
+		return
+	}
+	sql, args, err := translator.Translate(res)
+	assert.NoError(t, err)
+	assert.Equal(t, "(users.name LIKE :fiql_arg_1 ESCAPE '!' AND users.age BETWEEN :fiql_arg_2 AND :fiql_arg_3) OR users.status IN (:fiql_arg_4, :fiql_arg_5)", sql)
+	assert.Equal(t, []interface{}{"Jo%", int64(18), int64(65), "a", "b"}, args)
+
+	positional := &SQLTranslator{Columns: testSQLColumns, PlaceholderPrefix: "p"}
+	sql, bindings, err := positional.TranslateNamed(res)
+	assert.NoError(t, err)
+	assert.Equal(t, "(users.name LIKE :p1 ESCAPE '!' AND users.age BETWEEN :p2 AND :p3) OR users.status IN (:p4, :p5)", sql)
+	assert.Equal(t, map[string]interface{}{"p1": "Jo%", "p2": int64(18), "p3": int64(65), "p4": "a", "p5": "b"}, bindings)
+	assert.Equal(t, SQLPlaceholderQuestion, positional.Placeholder)
+}
+
+func TestSQLTranslatorFullText(t *testing.T) {
+	var values = []struct {
+		fullText SQLFullTextFunc
+		sql      string
+	}{
+		{fullText: SQLFullTextPostgres(""), sql: "to_tsvector(users.name) @@ plainto_tsquery($1) AND users.age > $2"},
+		{fullText: SQLFullTextPostgres("english"), sql: "to_tsvector('english', users.name) @@ plainto_tsquery('english', $1) AND users.age > $2"},
+		{fullText: SQLFullTextMySQL, sql: "MATCH (users.name) AGAINST ($1 IN NATURAL LANGUAGE MODE) AND users.age > $2"},
+	}
+	res, err := Parse(context.Background(), `name=q="quick fox";age=gt=1`)
+	if !assert.NoError(t, err) {
+		return
+	}
+	for _, v := range values {
+		translator := &SQLTranslator{Columns: testSQLColumns, Placeholder: SQLPlaceholderDollar, FullText: v.fullText}
+		sql, args, err := translator.Translate(res)
+		if assert.NoError(t, err) {
+			assert.Equal(t, v.sql, sql)
+			assert.Equal(t, []interface{}{"quick fox", int64(1)}, args)
+		}
+	}
+
+	_, _, err = (&SQLTranslator{Columns: testSQLColumns}).Translate(res)
+	assert.EqualError(t, err, "unsupported comparison `QUERY`, no full text function configured")
+}
+
+func TestSQLTranslatorStatement(t *testing.T) {
+	translator := &SQLTranslator{Columns: testSQLColumns, Placeholder: SQLPlaceholderDollar}
+	statement := func(fiql string) SQLStatement {
+		res, err := Parse(context.Background(), fiql)
+		if !assert.NoError(t, err, fiql) {
+			return SQLStatement{}
+		}
+		st, err := translator.Statement(res)
+		assert.NoError(t, err, fiql)
+		return st
+	}
+	a := statement("name==John;age=gt=18")
+	assert.Equal(t, "users.name = $1 AND users.age > $2", a.SQL)
+	assert.Equal(t, []interface{}{"John", int64(18)}, a.Args)
+	assert.Len(t, a.Key, 32)
+
+	b := statement("name==Jane;age=gt=21")
+	assert.Equal(t, a.Key, b.Key)
+	assert.Equal(t, []interface{}{"Jane", int64(21)}, b.Args)
+
+	assert.NotEqual(t, a.Key, statement("name==Jane*;age=gt=21").Key)
+	assert.NotEqual(t, a.Key, statement("age=gt=21;name==Jane").Key)
+	assert.NotEqual(t, statement("age=in=[1+2]").Key, statement("age=in=[1+2+3]").Key)
+
+	translator.Provenance = true
+	c := statement("name==Jane;age=gt=21")
+	assert.NotEqual(t, b.SQL, c.SQL)
+	assert.Equal(t, a.Key, c.Key)
+	translator.Provenance = false
+
+	res, err := Parse(context.Background(), "password==x")
+	assert.NoError(t, err)
+	_, err = translator.Statement(res)
+	assert.ErrorIs(t, err, ErrUnknownSelector)
+}
+
+func TestSQLTranslatorUnknownSelector(t *testing.T) {
+	translator := &SQLTranslator{Columns: testSQLColumns}
+	res, err := Parse(context.Background(), "name==a;password==b")
+	assert.NoError(t, err)
+	_, _, err = translator.Translate(res)
+	assert.ErrorIs(t, err, ErrUnknownSelector)
+	assert.EqualError(t, err, "unknown selector `password`")
+
+	res, err = Parse(context.Background(), "name=in=[a+b*]")
+	assert.NoError(t, err)
+	_, _, err = translator.Translate(res)
+	assert.EqualError(t, err, "wildcards are not supported within tuples `b`")
+}
+
+func TestSQLTranslatorProvenance(t *testing.T) {
+	translator := &SQLTranslator{Columns: testSQLColumns, Provenance: true}
+	res, err := Parse(context.Background(), "urgent:(status==open;age=gt=18)")
+	if !assert.NoError(t, err) {
+		return
+	}
+	sql, args, err := translator.Translate(res)
+	if assert.NoError(t, err) {
+		assert.Equal(t, res.SQLComment()+" users.status = ? AND users.age > ?", sql)
+		assert.Equal(t, []interface{}{"open", int64(18)}, args)
+	}
+
+	res, err = Parse(context.Background(), "name==John")
+	if !assert.NoError(t, err) {
+		return
+	}
+	sql, _, err = translator.Translate(res)
+	if assert.NoError(t, err) {
+		assert.Equal(t, res.SQLComment()+" users.name = ?", sql)
+	}
+	where, _, err := translator.Where(res)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "WHERE "+sql, where)
+	}
+
+	sql, _, err = translator.Translate(Expression{})
+	assert.NoError(t, err)
+	assert.Equal(t, "", sql)
+}
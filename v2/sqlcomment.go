@@ -0,0 +1,52 @@
+package fiqlparser
+
+import (
+	"strings"
+	"unicode"
+)
+
+// SQLComment returns a comment with the fingerprint (see Fingerprint) and label of the expression
+// (e.g. `/* fiql:<fingerprint> urgent */`) for SQL generation to prepend, so slow queries
+// can be correlated with the filters causing them.
+// The label is sanitized, it can neither end the comment nor add placeholders.
+// A empty expression returns a empty string.
+func (e *Expression) SQLComment() string {
+	if e.node == nil {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("/* fiql:")
+	b.WriteString(e.Fingerprint())
+	if label := sanitizeSQLComment(expressionLabel(e)); label != "" {
+		b.WriteRune(' ')
+		b.WriteString(label)
+	}
+	b.WriteString(" */")
+	return b.String()
+}
+
+// expressionLabel returns the label of the expression or of the sub expression it consists of
+func expressionLabel(e *Expression) string {
+	if e.label == "" {
+		if sub, ok := e.node.(*Expression); ok {
+			return sub.label
+		}
+	}
+	return e.label
+}
+
+// sanitizeSQLComment keeps letters, digits and `-_.` of the text and replaces whitespace by spaces.
+// Everything else is dropped, so the text can not end the comment (`*/`), open a nested one (`/*`)
+// or contain characters drivers and ORMs treat as placeholders (`?`, `$1`, `:name`).
+func sanitizeSQLComment(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_' || r == '.':
+			b.WriteRune(r)
+		case unicode.IsSpace(r):
+			b.WriteRune(' ')
+		}
+	}
+	return strings.Join(strings.Fields(b.String()), " ")
+}
@@ -0,0 +1,56 @@
+package fiqlparser
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSQLComment(t *testing.T) {
+	res, err := Parse(context.Background(), "urgent:(status==open;age=gt=18)")
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, "/* fiql:"+res.Fingerprint()+" urgent */", res.SQLComment())
+
+	other, err := Parse(context.Background(), "name==John")
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Regexp(t, `^/\* fiql:[0-9a-f]{64} \*/$`, other.SQLComment())
+	assert.NotEqual(t, res.SQLComment()[:72], other.SQLComment()[:72])
+
+	again, err := Parse(context.Background(), "name==John")
+	if assert.NoError(t, err) {
+		assert.Equal(t, other.SQLComment(), again.SQLComment())
+	}
+	reordered, err := Parse(context.Background(), "urgent:(age=gt=18;status==open)")
+	if assert.NoError(t, err) {
+		assert.Equal(t, res.SQLComment(), reordered.SQLComment())
+	}
+
+	empty := Expression{}
+	assert.Equal(t, "", empty.SQLComment())
+
+	other.label = "x*/ OR 1=1 --\n"
+	assert.Regexp(t, `^/\* fiql:[0-9a-f]{64} x OR 11 -- \*/$`, other.SQLComment())
+}
+
+func TestSanitizeSQLComment(t *testing.T) {
+	var values = []struct {
+		label     string
+		sanitized string
+	}{
+		{label: "urgent", sanitized: "urgent"},
+		{label: "open-tickets_v1.2", sanitized: "open-tickets_v1.2"},
+		{label: "x*/ DROP TABLE users; --", sanitized: "x DROP TABLE users --"},
+		{label: "a\nb\r\nc", sanitized: "a b c"},
+		{label: "*/*/", sanitized: ""},
+		{label: "/* nested", sanitized: "nested"},
+		{label: "id=? or $1 or :name", sanitized: "id or 1 or name"},
+	}
+	for _, v := range values {
+		assert.Equal(t, v.sanitized, sanitizeSQLComment(v.label), v.label)
+	}
+}
@@ -0,0 +1,122 @@
+package fiqlparser
+
+import (
+	"bytes"
+)
+
+// SQLRelation describes a table related by a selector path,
+// e.g. the relation `customer` is used for the selector `customer.name`
+type SQLRelation struct {
+	// Table is the related table, optionally with a alias (e.g. `customers c`)
+	Table string
+	// On is the join condition referencing the parent table (e.g. `c.id = orders.customer_id`)
+	On string
+	// Many marks to-many relations, comparisons on them are translated to EXISTS subqueries
+	// so the rows of the parent table are not duplicated
+	Many bool
+	// Scopes are default conditions of the related table (e.g. `c.deleted_at IS NULL`),
+	// they are added to the join condition
+	Scopes []string
+}
+
+// relationChain returns the paths of the relations the selector passes, outermost first,
+// path segments which are not declared as relation are skipped
+func (t *SQLTranslator) relationChain(selector string) []string {
+	chain := make([]string, 0)
+	for i := 0; i < len(selector); i++ {
+		if selector[i] != '.' {
+			continue
+		}
+		if _, ok := t.Relations[selector[:i]]; ok {
+			chain = append(chain, selector[:i])
+		}
+	}
+	return chain
+}
+
+// splitRelationChain splits the chain into the relations joined by the outer query
+// and the relations starting at the first to-many relation
+func (t *SQLTranslator) splitRelationChain(chain []string) ([]string, []string) {
+	for i, path := range chain {
+		if t.Relations[path].Many {
+			return chain[:i], chain[i:]
+		}
+	}
+	return chain, nil
+}
+
+// writeOn writes the join condition of the relation including its scopes
+func (t *SQLTranslator) writeOn(b *bytes.Buffer, r SQLRelation) {
+	b.WriteString(r.On)
+	for _, scope := range t.scopes(r.Scopes) {
+		b.WriteString(" AND ")
+		b.WriteString(scope)
+	}
+}
+
+func (t *SQLTranslator) writeJoin(b *bytes.Buffer, r SQLRelation) {
+	b.WriteString("LEFT JOIN ")
+	b.WriteString(r.Table)
+	b.WriteString(" ON ")
+	t.writeOn(b, r)
+}
+
+// Joins returns the join clauses needed for the to-one relations of the selectors within the expression,
+// each relation is joined once in the order of its first use. LEFT JOIN is used so alternatives (OR)
+// on other selectors still match rows without related row. Unmapped selectors are rejected like in Translate.
+func (t *SQLTranslator) Joins(e Expression) ([]string, error) {
+	joins := make([]string, 0)
+	seen := make(map[string]bool)
+	s := &sqlBuilder{t: t}
+	var err error
+	var collect func(n Node)
+	collect = func(n Node) {
+		if c, ok := n.(*constantExpression); ok && (c.selector || c.recommended == ValueRecommendationField) && err == nil {
+			if _, err = s.column(c.value); err != nil {
+				return
+			}
+			outer, _ := t.splitRelationChain(t.relationChain(c.value))
+			for _, path := range outer {
+				if seen[path] {
+					continue
+				}
+				seen[path] = true
+				var b bytes.Buffer
+				t.writeJoin(&b, t.Relations[path])
+				joins = append(joins, b.String())
+			}
+		}
+		for _, child := range n.Children() {
+			collect(child)
+		}
+	}
+	collect(&e)
+	if err != nil {
+		return nil, err
+	}
+	return joins, nil
+}
+
+// writeExists wraps the comparison written by fn in a EXISTS subquery if the selector
+// passes a to-many relation
+func (s *sqlBuilder) writeExists(selector string, fn func() error) error {
+	_, many := s.t.splitRelationChain(s.t.relationChain(selector))
+	if len(many) == 0 {
+		return fn()
+	}
+	first := s.t.Relations[many[0]]
+	s.b.WriteString("EXISTS (SELECT 1 FROM ")
+	s.b.WriteString(first.Table)
+	for _, path := range many[1:] {
+		s.b.WriteRune(' ')
+		s.t.writeJoin(&s.b, s.t.Relations[path])
+	}
+	s.b.WriteString(" WHERE ")
+	s.t.writeOn(&s.b, first)
+	s.b.WriteString(" AND ")
+	if err := fn(); err != nil {
+		return err
+	}
+	s.b.WriteRune(')')
+	return nil
+}
@@ -0,0 +1,92 @@
+package fiqlparser
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testSQLRelationTranslator = &SQLTranslator{
+	Columns: map[string]string{
+		"total":                 "orders.total",
+		"customer.name":         "c.name",
+		"customer.address.city": "a.city",
+		"items.sku":             "i.sku",
+		"items.product.name":    "p.name",
+	},
+	Relations: map[string]SQLRelation{
+		"customer":         {Table: "customers c", On: "c.id = orders.customer_id"},
+		"customer.address": {Table: "addresses a", On: "a.id = c.address_id"},
+		"items":            {Table: "order_items i", On: "i.order_id = orders.id", Many: true},
+		"items.product":    {Table: "products p", On: "p.id = i.product_id"},
+	},
+}
+
+func TestSQLTranslatorJoins(t *testing.T) {
+	var values = []struct {
+		fiql  string
+		joins []string
+		sql   string
+	}{
+		{fiql: "total=gt=10", joins: []string{}, sql: "orders.total > ?"},
+		{
+			fiql:  "customer.address.city==Vienna;(customer.name==John,total=gt=10)",
+			joins: []string{"LEFT JOIN customers c ON c.id = orders.customer_id", "LEFT JOIN addresses a ON a.id = c.address_id"},
+			sql:   "a.city = ? AND (c.name = ? OR orders.total > ?)",
+		},
+		{
+			fiql:  "items.sku==A1;items.sku==B2",
+			joins: []string{},
+			sql:   "EXISTS (SELECT 1 FROM order_items i WHERE i.order_id = orders.id AND i.sku = ?) AND EXISTS (SELECT 1 FROM order_items i WHERE i.order_id = orders.id AND i.sku = ?)",
+		},
+		{
+			fiql:  "items.product.name==Chair*",
+			joins: []string{},
+			sql:   "EXISTS (SELECT 1 FROM order_items i LEFT JOIN products p ON p.id = i.product_id WHERE i.order_id = orders.id AND p.name LIKE ? ESCAPE '!')",
+		},
+	}
+	for _, v := range values {
+		res, err := Parse(context.Background(), v.fiql)
+		if !assert.NoError(t, err, v.fiql) {
+			continue
+		}
+		joins, err := testSQLRelationTranslator.Joins(res)
+		if assert.NoError(t, err, v.fiql) {
+			assert.Equal(t, v.joins, joins, v.fiql)
+		}
+		sql, _, err := testSQLRelationTranslator.Translate(res)
+		if assert.NoError(t, err, v.fiql) {
+			assert.Equal(t, v.sql, sql, v.fiql)
+		}
+	}
+}
+
+func TestSQLTranslatorJoinsUnknownSelector(t *testing.T) {
+	res, err := Parse(context.Background(), "customer.password==x")
+	assert.NoError(t, err)
+	_, err = testSQLRelationTranslator.Joins(res)
+	assert.ErrorIs(t, err, ErrUnknownSelector)
+}
+
+func TestSQLTranslatorRelationScopes(t *testing.T) {
+	translator := *testSQLRelationTranslator
+	translator.Relations = map[string]SQLRelation{
+		"customer": {Table: "customers c", On: "c.id = orders.customer_id", Scopes: []string{"c.deleted_at IS NULL"}},
+		"items":    {Table: "order_items i", On: "i.order_id = orders.id", Many: true, Scopes: []string{"i.deleted_at IS NULL"}},
+	}
+	res, err := Parse(context.Background(), "customer.name==John;items.sku==A1")
+	if !assert.NoError(t, err) {
+		return
+	}
+	joins, err := translator.Joins(res)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"LEFT JOIN customers c ON c.id = orders.customer_id AND c.deleted_at IS NULL"}, joins)
+	sql, _, err := translator.Translate(res)
+	assert.NoError(t, err)
+	assert.Equal(t, "c.name = ? AND EXISTS (SELECT 1 FROM order_items i WHERE i.order_id = orders.id AND i.deleted_at IS NULL AND i.sku = ?)", sql)
+
+	joins, err = translator.Unscoped().Joins(res)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"LEFT JOIN customers c ON c.id = orders.customer_id"}, joins)
+}
@@ -0,0 +1,106 @@
+package fiqlparser
+
+import "sort"
+
+// ExpressionStats describes the size and complexity of a expression, e.g. to reject or bill
+// complex queries or to log query complexity metrics
+type ExpressionStats struct {
+	// Nodes is the number of nodes of the tree, including the root expression and tuple elements
+	Nodes int
+	// NodeTypes is the number of nodes by type
+	NodeTypes map[NodeType]int
+	// Depth is the depth of the tree, the root expression has a depth of 1
+	Depth int
+	// Nesting is the deepest nesting of sub expressions (braces), 0 without braces
+	Nesting int
+	// Predicates is the number of comparisons and unary selectors
+	Predicates int
+	// Selectors are the distinct selectors in alphabetical order
+	Selectors []string
+	// OrBranches is the number of operands of OR operations, chained operations are counted as one
+	// (e.g. 3 for `a==1,b==2,c==3`), 0 without OR
+	OrBranches int
+	// Negations is the number of negated sub expressions
+	Negations int
+	// Wildcards is the number of arguments with a leading or trailing wildcard
+	Wildcards int
+	// TupleElements is the number of elements of all tuple arguments
+	TupleElements int
+}
+
+// Stats returns the size and complexity of the expression
+func (e *Expression) Stats() ExpressionStats {
+	s := expressionStats{ExpressionStats: ExpressionStats{NodeTypes: make(map[NodeType]int)}, selectors: make(map[string]bool)}
+	s.node(e, 1, 0)
+	s.branches(e)
+	s.Selectors = make([]string, 0, len(s.selectors))
+	for sel := range s.selectors {
+		s.Selectors = append(s.Selectors, sel)
+	}
+	sort.Strings(s.Selectors)
+	return s.ExpressionStats
+}
+
+// expressionStats is the state of Stats
+type expressionStats struct {
+	ExpressionStats
+	selectors map[string]bool
+}
+
+func (s *expressionStats) node(n Node, depth int, nesting int) {
+	if n == nil {
+		return
+	}
+	s.Nodes++
+	s.NodeTypes[n.NodeType()]++
+	if depth > s.Depth {
+		s.Depth = depth
+	}
+	if p, ok := predicateOf(n); ok {
+		s.Predicates++
+		s.selectors[p.Selector] = true
+	}
+	switch node := n.(type) {
+	case *Expression:
+		if !node.root {
+			nesting++
+		}
+		if nesting > s.Nesting {
+			s.Nesting = nesting
+		}
+	case *notExpression:
+		s.Negations++
+	case *constantExpression:
+		if node.prefixWildcard || node.suffixWildcard {
+			s.Wildcards++
+		}
+		if node.tuple != nil {
+			s.TupleElements += len(node.tuple.elements)
+		}
+	}
+	for _, c := range n.Children() {
+		s.node(c, depth+1, nesting)
+	}
+}
+
+// branches counts the operands of OR operations
+func (s *expressionStats) branches(n Node) {
+	if op, ok := logicalOperator(n); ok {
+		operands := flattenLogical(n, op, nil)
+		if op == string(OperatorOR) {
+			s.OrBranches += len(operands)
+		}
+		for _, o := range operands {
+			s.branches(o)
+		}
+		return
+	}
+	switch node := n.(type) {
+	case *Expression:
+		if node.node != nil {
+			s.branches(node.node)
+		}
+	case *notExpression:
+		s.branches(node.node)
+	}
+}
@@ -0,0 +1,65 @@
+package fiqlparser
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStats(t *testing.T) {
+	var values = []struct {
+		input      string
+		nodes      int
+		depth      int
+		nesting    int
+		predicates int
+		selectors  []string
+		branches   int
+		negations  int
+		wildcards  int
+		elements   int
+	}{
+		{input: "", nodes: 1, depth: 1, selectors: []string{}},
+		{input: "a==1", nodes: 4, depth: 3, predicates: 1, selectors: []string{"a"}},
+		{input: "a==1,b==2,a==3", nodes: 12, depth: 5, predicates: 3, selectors: []string{"a", "b"}, branches: 3},
+		{input: "(a==1,b==2),(c==3;d)", nodes: 16, depth: 6, nesting: 1, predicates: 4, selectors: []string{"a", "b", "c", "d"}, branches: 3},
+		{input: "a==1;(b==2,(c==3,d==x*))", nodes: 18, depth: 8, nesting: 2, predicates: 4, selectors: []string{"a", "b", "c", "d"}, branches: 3, wildcards: 1},
+		{input: "!(a=in=(1,2,3)),b==2", nodes: 13, depth: 7, nesting: 1, predicates: 2, selectors: []string{"a", "b"}, branches: 2, negations: 1, elements: 3},
+	}
+	p := New(WithNegation(), WithTupleDelimiters('(', ',', ')'))
+	for _, v := range values {
+		e, err := p.Parse(context.Background(), v.input)
+		if !assert.NoError(t, err, v.input) {
+			continue
+		}
+		s := e.Stats()
+		assert.Equal(t, v.nodes, s.Nodes, v.input)
+		assert.Equal(t, v.depth, s.Depth, v.input)
+		assert.Equal(t, v.nesting, s.Nesting, v.input)
+		assert.Equal(t, v.predicates, s.Predicates, v.input)
+		assert.Equal(t, v.selectors, s.Selectors, v.input)
+		assert.Equal(t, v.branches, s.OrBranches, v.input)
+		assert.Equal(t, v.negations, s.Negations, v.input)
+		assert.Equal(t, v.wildcards, s.Wildcards, v.input)
+		assert.Equal(t, v.elements, s.TupleElements, v.input)
+	}
+}
+
+func TestStatsNodeTypes(t *testing.T) {
+	e := mustParse(t, "a==1;(b==2,deleted)")
+	s := e.Stats()
+	assert.Equal(t, map[NodeType]int{
+		NodeTypeExpression: 2,
+		NodeTypeBinary:     4,
+		NodeTypeConstant:   4,
+		NodeTypeUnary:      1,
+	}, s.NodeTypes)
+
+	total := 0
+	Walk(&e, func(Node) bool {
+		total++
+		return true
+	})
+	assert.Equal(t, total, s.Nodes)
+}
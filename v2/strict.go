@@ -0,0 +1,225 @@
+package fiqlparser
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// specComparisons are the comparisons defined by the FIQL specification
+var specComparisons = []string{"==", "!=", "=lt=", "=le=", "=gt=", "=ge="}
+
+// WithStrictSpec enforces the grammar of draft-nottingham-atompub-fiql-00, deviations fail with ErrorCodeSpecViolation:
+//   - selectors consist of unreserved characters (ALPHA, DIGIT, `-`, `.`, `_`, `~`) and percent-encodings
+//   - arguments additionally allow `!`, `$`, `'`, `*`, `+` and `=`, quotes have no special meaning
+//   - only the comparisons of the specification are allowed (no =bt=, =in=, =q=)
+//   - wildcards are only allowed at the start or end of == and != arguments
+//   - whitespace, escapes, labels and tuples are not allowed
+//
+// Percent-encodings are validated but not decoded.
+func WithStrictSpec() Option {
+	return func(p *Parser) {
+		p.strictSpec = true
+	}
+}
+
+func isSpecUnreserved(r rune) bool {
+	return (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') ||
+		r == '-' || r == '.' || r == '_' || r == '~'
+}
+
+func isSpecArgChar(r rune) bool {
+	return isSpecUnreserved(r) || r == '!' || r == '$' || r == '\'' || r == '*' || r == '+' || r == '='
+}
+
+func isHexDigit(r rune) bool {
+	return (r >= '0' && r <= '9') || (r >= 'a' && r <= 'f') || (r >= 'A' && r <= 'F')
+}
+
+// specChecker validates the input against the FIQL grammar, it works on a copy of the lexer
+// so errors are positioned like all other errors
+type specChecker struct {
+	lex   lexer
+	depth int
+}
+
+// errSpecViolation is positioned at the offending input, which has not been consumed yet
+func (p *lexer) errSpecViolation(token string, expected []string, msg string) *ParseError {
+	return p.newParseError(ErrorCodeSpecViolation, token, expected, "specification violation ("+msg+")").at(p.position())
+}
+
+// checkSpec returns the first deviation from the FIQL grammar, a empty input is accepted
+func (p *lexer) checkSpec() error {
+	if _, ok := p.peek(); !ok {
+		return nil
+	}
+	c := &specChecker{lex: *p}
+	return c.filter()
+}
+
+// filter = expression *( ( ";" / "," ) expression ), expression = "(" filter ")" / constraint
+func (c *specChecker) filter() error {
+	for {
+		if err := c.expression(); err != nil {
+			return err
+		}
+		r, ok := c.lex.peek()
+		if !ok {
+			if c.depth > 0 {
+				return c.lex.errSpecViolation("", []string{")"}, "unclosed brace")
+			}
+			return nil
+		}
+		switch r {
+		case ';', ',':
+			c.lex.consume()
+		case ')':
+			if c.depth == 0 {
+				return c.lex.errSpecViolation(")", []string{";", ","}, "invalid closing brace")
+			}
+			c.lex.consume()
+			c.depth--
+			return nil
+		default:
+			return c.unexpected(r, []string{";", ",", ")"})
+		}
+	}
+}
+
+func (c *specChecker) expression() error {
+	if r, ok := c.lex.peek(); ok && r == '(' {
+		c.lex.consume()
+		c.depth++
+		return c.filter()
+	}
+	return c.constraint()
+}
+
+// constraint = selector [ comparison argument ]
+func (c *specChecker) constraint() error {
+	if err := c.chars("selector", isSpecUnreserved); err != nil {
+		return err
+	}
+	r, ok := c.lex.peek()
+	if !ok || (r != '=' && r != '!') {
+		return nil
+	}
+	comparison, err := c.comparison()
+	if err != nil {
+		return err
+	}
+	argStart := c.lex
+	if err := c.chars("argument", isSpecArgChar); err != nil {
+		return err
+	}
+	arg := c.lex.input[argStart.pos:c.lex.pos]
+	inner := strings.Trim(arg, "*")
+	if i := strings.IndexRune(inner, '*'); i >= 0 {
+		c.seek(argStart, argStart.pos+strings.Index(arg, inner)+i)
+		return c.lex.errSpecViolation(arg, nil, "wildcards are only allowed at the start or end of a argument")
+	}
+	if strings.Contains(arg, "*") && comparison != "==" && comparison != "!=" {
+		c.lex = argStart
+		return c.lex.errSpecViolation(arg, nil, fmt.Sprintf("wildcards are not allowed for `%s`", comparison))
+	}
+	return nil
+}
+
+// comparison = ( ( "=" *ALPHA ) / "!" ) "="
+func (c *specChecker) comparison() (string, error) {
+	start := c.lex
+	first := c.lex.consume()
+	for {
+		r, ok := c.lex.peek()
+		if !ok {
+			return "", c.lex.errSpecViolation(c.lex.input[start.pos:c.lex.pos], specComparisons, "incomplete comparison").startingAt(start.position())
+		}
+		c.lex.consume()
+		if r == '=' {
+			break
+		}
+		if first == '!' || !((r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')) {
+			cmp := c.lex.input[start.pos:c.lex.pos]
+			c.lex = start
+			return "", c.lex.errSpecViolation(cmp, specComparisons, fmt.Sprintf("invalid comparison `%s`", cmp))
+		}
+	}
+	cmp := c.lex.input[start.pos:c.lex.pos]
+	for _, v := range specComparisons {
+		if v == cmp {
+			return cmp, nil
+		}
+	}
+	c.lex = start
+	return "", c.lex.errSpecViolation(cmp, specComparisons, fmt.Sprintf("comparison `%s` is not part of the specification", cmp))
+}
+
+// chars reads 1*( allowed / pct-encoded ), the characters are terminated by a operator,
+// a closing brace or (for selectors) a comparison
+func (c *specChecker) chars(kind string, allowed func(rune) bool) error {
+	n := 0
+	for {
+		r, ok := c.lex.peek()
+		if !ok || (!allowed(r) && strings.ContainsRune(";,)=!", r)) {
+			break
+		}
+		if r == '%' {
+			if err := c.pctEncoded(); err != nil {
+				return err
+			}
+		} else if allowed(r) {
+			c.lex.consume()
+		} else {
+			return c.unexpected(r, []string{kind})
+		}
+		n++
+	}
+	if n == 0 {
+		token := ""
+		if r, ok := c.lex.peek(); ok {
+			token = string(r)
+		}
+		return c.lex.errSpecViolation(token, []string{kind}, "missing "+kind)
+	}
+	return nil
+}
+
+// seek moves to the byte position starting at start
+func (c *specChecker) seek(start lexer, pos int) {
+	c.lex = start
+	for c.lex.pos < pos {
+		c.lex.consume()
+	}
+}
+
+// pctEncoded = "%" HEXDIG HEXDIG
+func (c *specChecker) pctEncoded() error {
+	start := c.lex
+	c.lex.consume()
+	for i := 0; i < 2; i++ {
+		r, ok := c.lex.peek()
+		if !ok || !isHexDigit(r) {
+			end := c.lex.pos
+			if ok {
+				// the size within the input, invalid UTF-8 is read as a single byte
+				_, size := utf8.DecodeRuneInString(c.lex.input[end:])
+				end += size
+			}
+			token := c.lex.input[start.pos:end]
+			c.lex = start
+			return c.lex.errSpecViolation(token, []string{"%HH"}, fmt.Sprintf("invalid percent-encoding `%s`", token))
+		}
+		c.lex.consume()
+	}
+	return nil
+}
+
+func (c *specChecker) unexpected(r rune, expected []string) error {
+	switch {
+	case r == ' ' || r == '\t' || r == '\n' || r == '\r':
+		return c.lex.errSpecViolation(string(r), expected, "whitespace is not allowed")
+	case r == '\\':
+		return c.lex.errSpecViolation(string(r), expected, "escapes are not allowed, use percent-encoding")
+	}
+	return c.lex.errSpecViolation(string(r), expected, fmt.Sprintf("invalid character `%c`", r))
+}
@@ -0,0 +1,60 @@
+package fiqlparser
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStrictSpec(t *testing.T) {
+	parser := New(WithStrictSpec())
+	var valid = []struct {
+		fiql   string
+		output string
+	}{
+		{fiql: "", output: ""},
+		{fiql: "title==foo*;(updated=lt=-P1D,title==*bar)", output: "title==foo*;(updated=lt=-P1D,title==*bar)"},
+		{fiql: "name==O'Brien", output: `name==O\'Brien`},
+		{fiql: "name==a%20b;x_y.z~1=ge=+1", output: "name==a%20b;x_y.z~1=ge=+1"},
+		{fiql: "active,deleted", output: "active,deleted"},
+	}
+	for _, v := range valid {
+		res, err := parser.Parse(context.Background(), v.fiql)
+		if assert.NoError(t, err, v.fiql) {
+			assert.Equal(t, v.output, res.ToFIQL(), v.fiql)
+		}
+	}
+
+	var invalid = []struct {
+		fiql   string
+		column int
+		token  string
+		msg    string
+	}{
+		{fiql: "a =={b", column: 2, token: " ", msg: "ln:1:2 specification violation (whitespace is not allowed)"},
+		{fiql: "status=in=[a+b]", column: 7, token: "=in=", msg: "ln:1:7 specification violation (comparison `=in=` is not part of the specification)"},
+		{fiql: "title=q=fox", column: 6, token: "=q=", msg: "ln:1:6 specification violation (comparison `=q=` is not part of the specification)"},
+		{fiql: `name=="John"`, column: 7, token: `"`, msg: "ln:1:7 specification violation (invalid character `\"`)"},
+		{fiql: `name==a\,b`, column: 8, token: `\`, msg: "ln:1:8 specification violation (escapes are not allowed, use percent-encoding)"},
+		{fiql: "name==a%2x", column: 8, token: "%2x", msg: "ln:1:8 specification violation (invalid percent-encoding `%2x`)"},
+		{fiql: "name==a*b", column: 8, token: "a*b", msg: "ln:1:8 specification violation (wildcards are only allowed at the start or end of a argument)"},
+		{fiql: "age=gt=1*", column: 8, token: "1*", msg: "ln:1:8 specification violation (wildcards are not allowed for `=gt=`)"},
+		{fiql: "urgent:(a==b)", column: 7, token: ":", msg: "ln:1:7 specification violation (invalid character `:`)"},
+		{fiql: "a==b;", column: 6, token: "", msg: "ln:1:6 specification violation (missing selector)"},
+		{fiql: "a==;b==c", column: 4, token: ";", msg: "ln:1:4 specification violation (missing argument)"},
+		{fiql: "(a==b", column: 6, token: "", msg: "ln:1:6 specification violation (unclosed brace)"},
+	}
+	for _, v := range invalid {
+		_, err := parser.Parse(context.Background(), v.fiql)
+		var perr *ParseError
+		if !assert.True(t, errors.As(err, &perr), "expected ParseError for `%s`", v.fiql) {
+			continue
+		}
+		assert.Equal(t, ErrorCodeSpecViolation, perr.Code, v.fiql)
+		assert.Equal(t, v.column, perr.Column, v.fiql)
+		assert.Equal(t, v.token, perr.Token, v.fiql)
+		assert.EqualError(t, err, v.msg, v.fiql)
+	}
+}
@@ -0,0 +1,144 @@
+package fiqlparser
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// textWriter is implemented by strings.Builder and bufio.Writer
+type textWriter interface {
+	WriteString(s string) (int, error)
+	WriteRune(r rune) (int, error)
+}
+
+// writeNode writes the String representation of the node and all of its children
+// into a single writer, missing (nil) nodes of partially constructed trees are skipped
+func writeNode(w textWriter, n Node) {
+	switch node := n.(type) {
+	case nil:
+	case *Expression:
+		if node == nil {
+			return
+		}
+		if node.label != "" {
+			w.WriteString(node.label)
+			w.WriteRune(':')
+		}
+		w.WriteRune('(')
+		writeNode(w, node.node)
+		w.WriteRune(')')
+	case *binaryExpression:
+		if node == nil {
+			return
+		}
+		writeNode(w, node.nodes[0])
+		w.WriteRune(' ')
+		w.WriteString(node.operator)
+		w.WriteRune(' ')
+		writeNode(w, node.nodes[1])
+	case *logicalExpression:
+		if node == nil {
+			return
+		}
+		for i, c := range node.nodes {
+			if i > 0 {
+				w.WriteRune(' ')
+				w.WriteString(node.operator)
+				w.WriteRune(' ')
+			}
+			writeNode(w, c)
+		}
+	case *notExpression:
+		if node == nil {
+			return
+		}
+		w.WriteString(string(OperatorNOT))
+		w.WriteRune(' ')
+		writeNode(w, node.node)
+	case *constantExpression:
+		if node == nil {
+			return
+		}
+		if node.prefixWildcard {
+			w.WriteRune('*')
+		}
+		if node.quote != 0 {
+			writeQuotedValue(w, node.value, node.quote)
+		} else if node.recommended == ValueRecommendationField {
+			w.WriteRune('@')
+			w.WriteString(node.value)
+		} else if node.recommended == ValueRecommendationParameter {
+			w.WriteRune(':')
+			w.WriteString(node.value)
+		} else {
+			w.WriteString(node.value)
+		}
+		if node.suffixWildcard {
+			w.WriteRune('*')
+		}
+	}
+}
+
+// nodeString returns the String representation of the node
+func nodeString(n Node) string {
+	var b strings.Builder
+	writeNode(&b, n)
+	return b.String()
+}
+
+// byteAppender is a textWriter appending to a byte slice
+type byteAppender struct {
+	b []byte
+}
+
+func (a *byteAppender) WriteString(s string) (int, error) {
+	a.b = append(a.b, s...)
+	return len(s), nil
+}
+
+func (a *byteAppender) WriteRune(r rune) (int, error) {
+	n := len(a.b)
+	a.b = utf8.AppendRune(a.b, r)
+	return len(a.b) - n, nil
+}
+
+// appendText appends the output of fn to dst
+func appendText(dst []byte, fn func(w textWriter)) []byte {
+	a := &byteAppender{b: dst}
+	fn(a)
+	return a.b
+}
+
+// writeText writes the output of fn to w through a buffer and returns the number of bytes written
+func writeText(w io.Writer, fn func(w textWriter)) (int64, error) {
+	c := &countingWriter{w: w}
+	b := bufio.NewWriter(c)
+	fn(b)
+	err := b.Flush()
+	return c.n, err
+}
+
+// countingWriter counts the bytes written to w
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// WriteTo writes the String representation of the expression to w without building it in memory first,
+// e.g. to log filters. It implements io.WriterTo.
+func (e *Expression) WriteTo(w io.Writer) (int64, error) {
+	return writeText(w, func(w textWriter) { writeNode(w, e) })
+}
+
+// AppendTo appends the String representation of the expression to dst and returns the extended buffer
+func (e *Expression) AppendTo(dst []byte) []byte {
+	return appendText(dst, func(w textWriter) { writeNode(w, e) })
+}
@@ -0,0 +1,63 @@
+package fiqlparser
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStringPartialTrees(t *testing.T) {
+	var values = []struct {
+		node     Node
+		expected string
+	}{
+		{node: &Expression{root: true}, expected: "()"},
+		{node: &Expression{label: "x"}, expected: "x:()"},
+		{node: &binaryExpression{operator: string(OperatorAND)}, expected: " AND "},
+		{node: newBinary(string(ComparisonEq), &constantExpression{value: "a", selector: true}, nil), expected: "a == "},
+		{node: &logicalExpression{operator: string(OperatorOR), nodes: []Node{nil, newSubExpression(nil)}}, expected: " OR ()"},
+		{node: &Expression{node: (*binaryExpression)(nil)}, expected: "()"},
+	}
+	for _, v := range values {
+		assert.NotPanics(t, func() {
+			assert.Equal(t, v.expected, v.node.String())
+		})
+	}
+}
+
+func TestExpressionWriteTo(t *testing.T) {
+	res, err := Parse(context.Background(), `x:(name=="John Doe",age=gt=30);a==b*`)
+	if !assert.NoError(t, err) {
+		return
+	}
+	var b strings.Builder
+	n, err := res.WriteTo(&b)
+	assert.NoError(t, err)
+	assert.Equal(t, res.String(), b.String())
+	assert.Equal(t, int64(len(res.String())), n)
+
+	_, err = res.WriteTo(failingWriter{})
+	assert.Error(t, err)
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("write failed")
+}
+
+func BenchmarkStringDeep(b *testing.B) {
+	fiql := strings.Repeat("(a==b;", 500) + "c==d" + strings.Repeat(")", 500)
+	res, err := Parse(context.Background(), fiql)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = res.String()
+	}
+}
@@ -0,0 +1,186 @@
+package fiqlparser
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxSummaryArgument is the number of runes arguments are truncated to by Summarize
+const maxSummaryArgument = 32
+
+// SummaryPart is a condition of a Summary, the fields allow rendering it in other languages
+type SummaryPart struct {
+	// Predicate is the summarized comparison or unary selector, the zero value for nested expressions
+	Predicate Predicate
+	// Phrase describes the comparison in english, e.g. `is`, `is greater than` or `starts with`
+	Phrase string
+	// Argument is the argument as displayed, truncated to a reasonable length,
+	// tuple elements are joined with `, ` and the bounds of ranges with ` and `
+	Argument string
+	// Text is the condition in english, e.g. `status is open`, nested expressions are written in FIQL
+	Text string
+}
+
+// Summary is a short description of a expression, see Summarize
+type Summary struct {
+	// Operator combines the parts, empty for a single condition
+	Operator Operator
+	// Parts are the summarized conditions
+	Parts []SummaryPart
+	// More is the number of conditions left out
+	More int
+}
+
+// String returns the summary in english, e.g. `status is open and 3 more conditions`
+func (s Summary) String() string {
+	var b strings.Builder
+	conjunction := " and "
+	if s.Operator == OperatorOR {
+		conjunction = " or "
+	}
+	for i, part := range s.Parts {
+		if i > 0 {
+			if i == len(s.Parts)-1 && s.More == 0 {
+				b.WriteString(conjunction)
+			} else {
+				b.WriteString(", ")
+			}
+		}
+		b.WriteString(part.Text)
+	}
+	if s.More > 0 {
+		if len(s.Parts) > 0 {
+			b.WriteString(conjunction)
+		}
+		b.WriteString(strconv.Itoa(s.More))
+		if len(s.Parts) > 0 {
+			b.WriteString(" more")
+		}
+		if s.More == 1 {
+			b.WriteString(" condition")
+		} else {
+			b.WriteString(" conditions")
+		}
+	}
+	return b.String()
+}
+
+// Summarize describes the expression in a few words, e.g. for chips in list views.
+// The conditions of the top level logical operation are summarized up to maxPredicates (at least one),
+// the remaining conditions are counted. Long arguments are truncated at character boundaries.
+func Summarize(e Expression, maxPredicates int) Summary {
+	if maxPredicates < 1 {
+		maxPredicates = 1
+	}
+	s := Summary{Parts: make([]SummaryPart, 0)}
+	if e.node == nil {
+		return s
+	}
+	operands := []Node{unwrapExpression(&e)}
+	if op, ops, ok := logicalOperands(&e); ok {
+		s.Operator = Operator(op)
+		operands = ops
+	}
+	for i, n := range operands {
+		if i >= maxPredicates {
+			s.More = len(operands) - i
+			break
+		}
+		s.Parts = append(s.Parts, summarizeNode(n))
+	}
+	return s
+}
+
+// summarizeNode describes a operand of the top level operation
+func summarizeNode(n Node) SummaryPart {
+	p, ok := predicateOf(unwrapExpression(n))
+	if !ok {
+		return SummaryPart{Text: truncateText(canonicalFIQL(n), maxSummaryArgument)}
+	}
+	part := SummaryPart{Predicate: p, Phrase: summaryPhrase(p)}
+	if !p.IsUnary() {
+		part.Argument = truncateText(summaryArgument(p.Argument), maxSummaryArgument)
+	}
+	part.Text = p.Selector + " " + part.Phrase
+	if part.Argument != "" {
+		part.Text += " " + part.Argument
+	}
+	return part
+}
+
+var summaryPhrases = map[Comparison]string{
+	ComparisonEq:      "is",
+	ComparisonNeq:     "is not",
+	ComparisonGt:      "is greater than",
+	ComparisonGte:     "is at least",
+	ComparisonLt:      "is less than",
+	ComparisonLte:     "is at most",
+	ComparisonBetween: "is between",
+	ComparisonIn:      "is one of",
+	ComparisonQuery:   "matches",
+}
+
+// summaryPhrase returns the english phrase of the comparison, wildcards are described as well
+func summaryPhrase(p Predicate) string {
+	if p.IsUnary() {
+		return "is set"
+	}
+	if p.Comparison == ComparisonEq || p.Comparison == ComparisonNeq {
+		negated := p.Comparison == ComparisonNeq
+		switch prefix, suffix := p.Argument.StartsWithWildcard(), p.Argument.EndsWithWildcard(); {
+		case prefix && suffix && negated:
+			return "does not contain"
+		case prefix && suffix:
+			return "contains"
+		case suffix && negated:
+			return "does not start with"
+		case suffix:
+			return "starts with"
+		case prefix && negated:
+			return "does not end with"
+		case prefix:
+			return "ends with"
+		}
+	}
+	if phrase, ok := summaryPhrases[p.Comparison]; ok {
+		return phrase
+	}
+	return string(p.Comparison)
+}
+
+// summaryArgument returns the argument as displayed
+func summaryArgument(arg ArgumentContext) string {
+	switch arg.ValueRecommendation() {
+	case ValueRecommendationTuple:
+		if elements, err := arg.AsTuple(); err == nil {
+			values := make([]string, 0, len(elements))
+			for _, el := range elements {
+				values = append(values, el.AsString())
+			}
+			return strings.Join(values, ", ")
+		}
+	case ValueRecommendationRange:
+		if from, to, err := arg.AsRange(); err == nil {
+			return from.AsString() + " and " + to.AsString()
+		}
+	case ValueRecommendationField:
+		return "@" + arg.AsString()
+	}
+	return arg.AsString()
+}
+
+// truncateText shortens s to at most max runes, a truncated text ends with `…`
+func truncateText(s string, max int) string {
+	if utf8.RuneCountInString(s) <= max {
+		return s
+	}
+	runes := 0
+	for i := range s {
+		if runes == max-1 {
+			return s[:i] + "…"
+		}
+		runes++
+	}
+	return s
+}
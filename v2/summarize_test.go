@@ -0,0 +1,58 @@
+package fiqlparser
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSummarize(t *testing.T) {
+	var values = []struct {
+		input    string
+		max      int
+		expected string
+	}{
+		{input: "status==open", max: 2, expected: "status is open"},
+		{input: "status==open;age=gt=18", max: 2, expected: "status is open and age is greater than 18"},
+		{input: "status==open;a==1;b==2;c==3", max: 1, expected: "status is open and 3 more conditions"},
+		{input: "status==open;a==1;b==2", max: 2, expected: "status is open, a is 1 and 1 more condition"},
+		{input: "a==1,b==2,c==3", max: 3, expected: "a is 1, b is 2 or c is 3"},
+		{input: "a==1;b==2", max: 0, expected: "a is 1 and 1 more condition"},
+		{input: "(a==1;b==2)", max: 5, expected: "a is 1 and b is 2"},
+		{input: "name==Jo*;name!=*son;name==*oh*;name!=*x*", max: 4,
+			expected: "name starts with Jo, name does not end with son, name contains oh and name does not contain x"},
+		{input: "id=in=(1,2,3);age=bt=(18,30);deleted", max: 3, expected: "id is one of 1, 2, 3, age is between 18 and 30 and deleted is set"},
+		{input: "a==1;(b==2,c==3)", max: 2, expected: "a is 1 and b==2,c==3"},
+		{input: "a==1;!(b==2)", max: 2, expected: "a is 1 and !(b==2)"},
+	}
+	for _, v := range values {
+		e, err := New(WithNegation(), WithTupleDelimiters('(', ',', ')')).Parse(context.Background(), v.input)
+		if assert.NoError(t, err, v.input) {
+			assert.Equal(t, v.expected, Summarize(e, v.max).String(), v.input)
+		}
+	}
+}
+
+func TestSummarizeParts(t *testing.T) {
+	s := Summarize(mustParse(t, "status==open;age=gt=18;x==1"), 1)
+	assert.Equal(t, OperatorAND, s.Operator)
+	assert.Equal(t, 2, s.More)
+	if assert.Len(t, s.Parts, 1) {
+		assert.Equal(t, "status", s.Parts[0].Predicate.Selector)
+		assert.Equal(t, ComparisonEq, s.Parts[0].Predicate.Comparison)
+		assert.Equal(t, "is", s.Parts[0].Phrase)
+		assert.Equal(t, "open", s.Parts[0].Argument)
+	}
+
+	s = Summarize(Expression{root: true}, 3)
+	assert.Empty(t, s.Parts)
+	assert.Equal(t, "", s.String())
+}
+
+func TestSummarizeTruncation(t *testing.T) {
+	s := Summarize(mustParse(t, "name==\""+"äöüäöüäöüäöüäöüäöüäöüäöüäöüäöüäöüäöü\""), 1)
+	assert.Equal(t, "äöüäöüäöüäöüäöüäöüäöüäöüäöüäöüä…", s.Parts[0].Argument)
+	assert.Equal(t, "abc", truncateText("abc", 3))
+	assert.Equal(t, "ab…", truncateText("abcd", 3))
+}
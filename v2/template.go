@@ -0,0 +1,251 @@
+package fiqlparser
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// TemplateSnippets are the text/template snippets used by a TemplateRenderer per node kind,
+// empty snippets fall back to a FIQL like default
+type TemplateSnippets struct {
+	// Logical renders AND/OR operations with TemplateLogical, e.g. `{{join .Operands " && "}}`
+	Logical string
+	// Group renders sub expressions with TemplateGroup, e.g. `({{.Inner}})`
+	Group string
+	// Predicate renders comparisons with TemplatePredicate, e.g. `{{.Selector}} {{.Comparison}} {{.Value}}`
+	Predicate string
+	// Unary renders selectors without constraint with TemplateUnary
+	Unary string
+	// Not renders negated sub expressions (see WithNegation) with TemplateNot, e.g. `!{{.Inner}}`
+	Not string
+	// Values render arguments with TemplateValue by their recommendation,
+	// arguments without a matching snippet use the ValueRecommendationString snippet, if any
+	Values map[ValueRecommendation]string
+	// Funcs are additional functions available in all snippets
+	Funcs template.FuncMap
+}
+
+// TemplateLogical is passed to the Logical snippet
+type TemplateLogical struct {
+	// Operator is either AND or OR
+	Operator Operator
+	// Operands are the rendered operands, consecutive operations of the same operator are flattened
+	Operands []string
+}
+
+// TemplateGroup is passed to the Group snippet
+type TemplateGroup struct {
+	// Label is the label of the sub expression if any
+	Label string
+	// Inner is the rendered content of the sub expression
+	Inner string
+}
+
+// TemplatePredicate is passed to the Predicate snippet
+type TemplatePredicate struct {
+	Selector string
+	// Comparison is the comparison as defined by Comparison (e.g. `>=`)
+	Comparison Comparison
+	// FIQL is the comparison as written in FIQL (e.g. `=ge=`)
+	FIQL string
+	// Value is the rendered argument, tuple elements are joined by `, `
+	Value string
+	// Values are the rendered tuple elements, or the rendered argument
+	Values   []string
+	Argument ArgumentContext
+}
+
+// TemplateUnary is passed to the Unary snippet
+type TemplateUnary struct {
+	Selector string
+}
+
+// TemplateNot is passed to the Not snippet
+type TemplateNot struct {
+	// Inner is the rendered negated sub expression, including its group
+	Inner string
+}
+
+// TemplateValue is passed to the Values snippets
+type TemplateValue struct {
+	// Value is the argument without wildcards and quotes
+	Value    string
+	Argument ArgumentContext
+}
+
+// TemplateRenderer renders a expression by executing a template per node kind,
+// it is intended for simple target formats which do not justify a visitor.
+// A renderer is safe for concurrent use.
+type TemplateRenderer struct {
+	logical   *template.Template
+	group     *template.Template
+	predicate *template.Template
+	unary     *template.Template
+	not       *template.Template
+	values    map[ValueRecommendation]*template.Template
+}
+
+// defaultTemplateSnippets are used for missing snippets
+var defaultTemplateSnippets = TemplateSnippets{
+	Logical:   `{{join .Operands (printf " %s " .Operator)}}`,
+	Group:     `({{.Inner}})`,
+	Predicate: `{{.Selector}}{{.FIQL}}{{.Value}}`,
+	Unary:     `{{.Selector}}`,
+	Not:       `!{{.Inner}}`,
+}
+
+// NewTemplateRenderer parses the snippets, `join` (strings.Join) is available in all snippets
+func NewTemplateRenderer(snippets TemplateSnippets) (*TemplateRenderer, error) {
+	funcs := template.FuncMap{"join": strings.Join}
+	for k, v := range snippets.Funcs {
+		funcs[k] = v
+	}
+	parse := func(name string, snippet string, fallback string) (*template.Template, error) {
+		if snippet == "" {
+			snippet = fallback
+		}
+		t, err := template.New(name).Funcs(funcs).Parse(snippet)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s template: %w", name, err)
+		}
+		return t, nil
+	}
+	r := &TemplateRenderer{values: make(map[ValueRecommendation]*template.Template)}
+	var err error
+	if r.logical, err = parse("logical", snippets.Logical, defaultTemplateSnippets.Logical); err != nil {
+		return nil, err
+	}
+	if r.group, err = parse("group", snippets.Group, defaultTemplateSnippets.Group); err != nil {
+		return nil, err
+	}
+	if r.predicate, err = parse("predicate", snippets.Predicate, defaultTemplateSnippets.Predicate); err != nil {
+		return nil, err
+	}
+	if r.unary, err = parse("unary", snippets.Unary, defaultTemplateSnippets.Unary); err != nil {
+		return nil, err
+	}
+	if r.not, err = parse("not", snippets.Not, defaultTemplateSnippets.Not); err != nil {
+		return nil, err
+	}
+	for rec, snippet := range snippets.Values {
+		if r.values[rec], err = parse(string(rec)+" value", snippet, ""); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// Render renders the expression, a empty expression results in a empty string
+func (r *TemplateRenderer) Render(e Expression) (string, error) {
+	if e.node == nil {
+		return "", nil
+	}
+	return r.render(&e)
+}
+
+func (r *TemplateRenderer) execute(t *template.Template, data interface{}) (string, error) {
+	var b strings.Builder
+	if err := t.Execute(&b, data); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+func (r *TemplateRenderer) render(n Node) (string, error) {
+	switch node := n.(type) {
+	case *Expression:
+		if node.node == nil {
+			return "", nil
+		}
+		if node.isRoot() {
+			return r.render(node.node)
+		}
+		return r.renderGroup(node.label, node.node)
+	case *binaryExpression:
+		if isLogicalOperator(node.operator) {
+			return r.renderLogical(node)
+		}
+		return r.renderPredicate(node)
+	case *logicalExpression:
+		return r.renderLogical(node)
+	case *notExpression:
+		inner, err := r.render(node.node)
+		if err != nil {
+			return "", err
+		}
+		return r.execute(r.not, TemplateNot{Inner: inner})
+	case *constantExpression:
+		return r.execute(r.unary, TemplateUnary{Selector: node.value})
+	}
+	return "", fmt.Errorf("unsupported node `%s`", n.NodeType())
+}
+
+func (r *TemplateRenderer) renderGroup(label string, n Node) (string, error) {
+	inner, err := r.render(n)
+	if err != nil {
+		return "", err
+	}
+	return r.execute(r.group, TemplateGroup{Label: label, Inner: inner})
+}
+
+// renderLogical renders the flattened operands, nested logical operations are always grouped
+// so the output does not depend on the precedence rules of the target format
+func (r *TemplateRenderer) renderLogical(n Node) (string, error) {
+	op, _ := logicalOperator(n)
+	operands := flattenLogical(n, op, nil)
+	data := TemplateLogical{Operator: Operator(op), Operands: make([]string, 0, len(operands))}
+	for _, o := range operands {
+		var s string
+		var err error
+		if _, logical := logicalOperator(o); logical {
+			s, err = r.renderGroup("", o)
+		} else {
+			s, err = r.render(o)
+		}
+		if err != nil {
+			return "", err
+		}
+		data.Operands = append(data.Operands, s)
+	}
+	return r.execute(r.logical, data)
+}
+
+func (r *TemplateRenderer) renderPredicate(n *binaryExpression) (string, error) {
+	sel, arg, ok := predicateOperands(n)
+	if !ok {
+		return "", fmt.Errorf("unsupported comparison `%s`", n.operator)
+	}
+	data := TemplatePredicate{
+		Selector:   sel.value,
+		Comparison: Comparison(n.operator),
+		FIQL:       fiqlOperators[n.operator],
+		Argument:   arg.Argument(),
+	}
+	elements := []*constantExpression{arg}
+	if arg.tuple != nil {
+		elements = arg.tuple.elements
+	}
+	for _, el := range elements {
+		v, err := r.renderValue(el)
+		if err != nil {
+			return "", err
+		}
+		data.Values = append(data.Values, v)
+	}
+	data.Value = strings.Join(data.Values, ", ")
+	return r.execute(r.predicate, data)
+}
+
+// renderValue renders the argument with the snippet of its recommendation,
+// the plain value is used if there is no snippet
+func (r *TemplateRenderer) renderValue(arg *constantExpression) (string, error) {
+	t, ok := r.values[arg.recommended]
+	if !ok {
+		t, ok = r.values[ValueRecommendationString]
+	}
+	if !ok {
+		return arg.value, nil
+	}
+	return r.execute(t, TemplateValue{Value: arg.value, Argument: arg.Argument()})
+}
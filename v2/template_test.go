@@ -0,0 +1,85 @@
+package fiqlparser
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTemplateRenderer(t *testing.T) {
+	renderer, err := NewTemplateRenderer(TemplateSnippets{
+		Logical:   `{{if eq .Operator "AND"}}{{join .Operands " && "}}{{else}}{{join .Operands " || "}}{{end}}`,
+		Group:     `{{if .Label}}/*{{.Label}}*/ {{end}}({{.Inner}})`,
+		Predicate: `{{if eq .Comparison "IN"}}{{.Selector}} in [{{.Value}}]{{else}}{{.Selector}} {{.Comparison}} {{.Value}}{{end}}`,
+		Unary:     `has({{.Selector}})`,
+		Values: map[ValueRecommendation]string{
+			ValueRecommendationString: `{{quote .Value}}{{if .Argument.EndsWithWildcard}}*{{end}}`,
+			ValueRecommendationNumber: `{{.Value}}`,
+		},
+		Funcs: map[string]interface{}{"quote": func(s string) string { return `"` + strings.ReplaceAll(s, `"`, `\"`) + `"` }},
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+	var values = []struct {
+		fiql   string
+		output string
+	}{
+		{fiql: "", output: ""},
+		{fiql: "name==John", output: `name == "John"`},
+		{fiql: "age=gt=18;name==Jo*", output: `age > 18 && name == "Jo"*`},
+		{fiql: "a==1;b==2;c==3", output: `a == 1 && b == 2 && c == 3`},
+		{fiql: "a==1,b==2;c==3", output: `a == 1 || (b == 2 && c == 3)`},
+		{fiql: "(a==1,b==2);c==3", output: `(a == 1 || b == 2) && c == 3`},
+		{fiql: "deleted;urgent:(status==open)", output: `has(deleted) && /*urgent*/ (status == "open")`},
+		{fiql: "age=in=[1+2+x]", output: `age in [1, 2, "x"]`},
+	}
+	for _, v := range values {
+		res, err := Parse(context.Background(), v.fiql)
+		if !assert.NoError(t, err, v.fiql) {
+			continue
+		}
+		out, err := renderer.Render(res)
+		if assert.NoError(t, err, v.fiql) {
+			assert.Equal(t, v.output, out, v.fiql)
+		}
+	}
+}
+
+func TestTemplateRendererDefaults(t *testing.T) {
+	renderer, err := NewTemplateRenderer(TemplateSnippets{})
+	if !assert.NoError(t, err) {
+		return
+	}
+	res, err := Parse(context.Background(), "a==1;(b=ge=2,c)")
+	if !assert.NoError(t, err) {
+		return
+	}
+	out, err := renderer.Render(res)
+	assert.NoError(t, err)
+	assert.Equal(t, "a==1 AND (b=ge=2 OR c)", out)
+
+	_, err = NewTemplateRenderer(TemplateSnippets{Predicate: "{{.Selector"})
+	assert.Error(t, err)
+}
+
+func TestTemplateRendererNegation(t *testing.T) {
+	res, err := New(WithNegation()).Parse(context.Background(), "!(a==1);b==2")
+	if !assert.NoError(t, err) {
+		return
+	}
+	renderer, err := NewTemplateRenderer(TemplateSnippets{})
+	if assert.NoError(t, err) {
+		out, err := renderer.Render(res)
+		assert.NoError(t, err)
+		assert.Equal(t, "!(a==1) AND b==2", out)
+	}
+	renderer, err = NewTemplateRenderer(TemplateSnippets{Not: `NOT {{.Inner}}`})
+	if assert.NoError(t, err) {
+		out, err := renderer.Render(res)
+		assert.NoError(t, err)
+		assert.Equal(t, "NOT (a==1) AND b==2", out)
+	}
+}
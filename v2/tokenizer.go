@@ -0,0 +1,82 @@
+package fiqlparser
+
+// The token types reported by Tokenizer and WithTokenTrace,
+// comparisons are reported by their Comparison (e.g. `==`, `>` or `IN`)
+const (
+	TokenTypeValue    = "Value"
+	TokenTypeWildcard = "*"
+	TokenTypeLabel    = "Label"
+	TokenTypeNot      = "NOT"
+	TokenTypeOpen     = "("
+	TokenTypeClose    = ")"
+	TokenTypeAND      = "AND"
+	TokenTypeOR       = "OR"
+	TokenTypeEOF      = "eof"
+)
+
+// Tokenizer splits a input into the tokens consumed by the parser,
+// so syntax highlighters, formatters and completion can work on the same tokens.
+// Tuple arguments of `=in=` and `=bt=` are a single Value token (e.g. `[a+b]`).
+// The input is only tokenized, a sequence of valid tokens is not necessarily a valid expression.
+type Tokenizer struct {
+	lex   *lexer
+	tuple TupleDelimiters
+	// spaces allows whitespace within arguments, see WithSpacesInValues
+	spaces bool
+	// last is the type of the previous token
+	last tokenType
+	err  error
+}
+
+// NewTokenizer returns a tokenizer of input, the options affecting the tokens
+// (WithTupleDelimiters, WithNegation, WithSpacesInValues and WithStrictSpec) are applied, others are ignored
+func NewTokenizer(input string, opts ...Option) *Tokenizer {
+	p := New(opts...)
+	lex := &lexer{input: input, ln: 1, start: Position{Line: 1}, negation: p.negation, literalQuotes: p.strictSpec}
+	return &Tokenizer{lex: lex, tuple: p.tupleDelimiters(), spaces: p.spacesInValues, last: tokenEOF}
+}
+
+// Next returns the next token, a token of type TokenTypeEOF at the end of the input.
+// Once a error occurred (e.g. a unterminated quote) it is returned by every further call.
+func (t *Tokenizer) Next() (Token, error) {
+	if t.err != nil {
+		return Token{}, t.err
+	}
+	typ, err := t.next()
+	if err != nil {
+		t.err = err
+		return Token{}, err
+	}
+	t.last = typ
+	tok := Token{Type: typ.String(), Literal: t.lex.literal(typ), Position: t.lex.start, End: t.lex.position()}
+	if typ == tokenEOF {
+		tok.Position = tok.End
+	}
+	return tok, nil
+}
+
+// Tokens returns all tokens of the input up to the end or the first error
+func (t *Tokenizer) Tokens() ([]Token, error) {
+	res := make([]Token, 0)
+	for {
+		tok, err := t.Next()
+		if err != nil {
+			return res, err
+		}
+		if tok.Type == TokenTypeEOF {
+			return res, nil
+		}
+		res = append(res, tok)
+	}
+}
+
+func (t *Tokenizer) next() (tokenType, error) {
+	argument := isCompareToken(t.last) || (t.last == tokenWildcard && t.lex.valueSpaces)
+	t.lex.valueSpaces = t.spaces && argument
+	if t.last == tokenCompareIn || t.last == tokenCompareBetween {
+		if _, ok, err := t.lex.readTuple(t.tuple); ok || err != nil {
+			return tokenValue, err
+		}
+	}
+	return t.lex.nextToken()
+}
@@ -0,0 +1,75 @@
+package fiqlparser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenizer(t *testing.T) {
+	input := "l:(name==\"John Doe\";tags=in=[a+b]),\nage=gt=18*"
+	tokens, err := NewTokenizer(input).Tokens()
+	if !assert.NoError(t, err) {
+		return
+	}
+	var values = []struct {
+		typ     string
+		literal string
+		raw     string
+	}{
+		{typ: TokenTypeLabel, literal: "l:", raw: "l:"},
+		{typ: TokenTypeOpen, literal: "(", raw: "("},
+		{typ: TokenTypeValue, literal: "name", raw: "name"},
+		{typ: "==", literal: "==", raw: "=="},
+		{typ: TokenTypeValue, literal: "John Doe", raw: `"John Doe"`},
+		{typ: TokenTypeAND, literal: ";", raw: ";"},
+		{typ: TokenTypeValue, literal: "tags", raw: "tags"},
+		{typ: "IN", literal: "=in=", raw: "=in="},
+		{typ: TokenTypeValue, literal: "[a+b]", raw: "[a+b]"},
+		{typ: TokenTypeClose, literal: ")", raw: ")"},
+		{typ: TokenTypeOR, literal: ",", raw: ","},
+		{typ: TokenTypeValue, literal: "age", raw: "age"},
+		{typ: ">", literal: "=gt=", raw: "=gt="},
+		{typ: TokenTypeValue, literal: "18", raw: "18"},
+		{typ: TokenTypeWildcard, literal: "*", raw: "*"},
+	}
+	if !assert.Len(t, tokens, len(values)) {
+		return
+	}
+	for i, v := range values {
+		tok := tokens[i]
+		assert.Equal(t, v.typ, tok.Type, i)
+		assert.Equal(t, v.literal, tok.Literal, i)
+		assert.Equal(t, v.raw, input[tok.Position.ByteOffset:tok.End.ByteOffset], i)
+	}
+	assert.Equal(t, Position{Line: 2, Column: 0, Offset: 36, ByteOffset: 36}, tokens[11].Position)
+}
+
+func TestTokenizerOptions(t *testing.T) {
+	tokens, err := NewTokenizer("!(a=in=(x,y));b==John Doe", WithNegation(), WithTupleDelimiters('(', ',', ')'), WithSpacesInValues()).Tokens()
+	assert.NoError(t, err)
+	types := make([]string, 0, len(tokens))
+	for _, tok := range tokens {
+		types = append(types, tok.Type)
+	}
+	assert.Equal(t, []string{TokenTypeNot, TokenTypeOpen, TokenTypeValue, "IN", TokenTypeValue, TokenTypeClose, TokenTypeAND, TokenTypeValue, "==", TokenTypeValue}, types)
+	assert.Equal(t, "(x,y)", tokens[4].Literal)
+	assert.Equal(t, "John Doe", tokens[9].Literal)
+}
+
+func TestTokenizerError(t *testing.T) {
+	tz := NewTokenizer(`a=="b`)
+	tokens, err := tz.Tokens()
+	assert.Len(t, tokens, 2)
+	var perr *ParseError
+	if assert.ErrorAs(t, err, &perr) {
+		assert.Equal(t, ErrorCodeUnexpectedEOF, perr.Code)
+	}
+	_, again := tz.Next()
+	assert.Equal(t, err, again)
+
+	tok, err := NewTokenizer("  ").Next()
+	assert.NoError(t, err)
+	assert.Equal(t, TokenTypeEOF, tok.Type)
+	assert.Equal(t, 2, tok.Position.ByteOffset)
+}
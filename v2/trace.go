@@ -0,0 +1,36 @@
+package fiqlparser
+
+import "fmt"
+
+// Token is a token consumed by the parser, see WithTokenTrace and Tokenizer
+type Token struct {
+	// Type is the kind of token, e.g. `Value`, `AND` or `>=`
+	Type string
+	// Literal is the token as it appears in the input, values are unescaped and unquoted
+	Literal string
+	// Position is the start of the token
+	Position Position
+	// End is the position following the token, the input between Position and End is the token as written
+	End Position
+}
+
+// String returns the token in the form `ln:<line>:<column> <type> <literal>`
+func (t Token) String() string {
+	return fmt.Sprintf("ln:%d:%d %s %q", t.Position.Line, t.Position.Column+1, t.Type, t.Literal)
+}
+
+// WithTokenTrace records the consumed tokens and attaches them to a ParseError (ParseError.Tokens),
+// intended for diagnosing parse failures from logs. Successful parses are not affected.
+func WithTokenTrace() Option {
+	return func(p *Parser) {
+		p.tokenTrace = true
+	}
+}
+
+// record appends the token which was just consumed to the trace
+func (p *lexer) record(t tokenType) {
+	if t == tokenEOF {
+		return
+	}
+	p.tokens = append(p.tokens, Token{Type: t.String(), Literal: p.literal(t), Position: p.start, End: p.position()})
+}
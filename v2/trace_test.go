@@ -0,0 +1,56 @@
+package fiqlparser
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenTrace(t *testing.T) {
+	parser := New(WithTokenTrace())
+	_, err := parser.Parse(context.Background(), "a==b;(c=in=[x,y],d=gt=)")
+	var perr *ParseError
+	if !assert.True(t, errors.As(err, &perr)) {
+		return
+	}
+	var values = []struct {
+		typ     string
+		literal string
+		column  int
+	}{
+		{typ: "Value", literal: "a", column: 0},
+		{typ: "==", literal: "==", column: 1},
+		{typ: "Value", literal: "b", column: 3},
+		{typ: "AND", literal: ";", column: 4},
+		{typ: "(", literal: "(", column: 5},
+		{typ: "Value", literal: "c", column: 6},
+		{typ: "IN", literal: "=in=", column: 7},
+		{typ: "Value", literal: "[x,y]", column: 11},
+		{typ: "OR", literal: ",", column: 16},
+		{typ: "Value", literal: "d", column: 17},
+		{typ: ">", literal: "=gt=", column: 18},
+		{typ: ")", literal: ")", column: 22},
+	}
+	if !assert.Len(t, perr.Tokens, len(values)) {
+		return
+	}
+	for i, v := range values {
+		assert.Equal(t, v.typ, perr.Tokens[i].Type)
+		assert.Equal(t, v.literal, perr.Tokens[i].Literal)
+		assert.Equal(t, v.column, perr.Tokens[i].Position.Column)
+	}
+	assert.Equal(t, `ln:1:8 IN "=in="`, perr.Tokens[6].String())
+
+	// the offending trailing token is part of the trace
+	_, err = parser.Parse(context.Background(), "name==John Doe")
+	if assert.True(t, errors.As(err, &perr)) && assert.Len(t, perr.Tokens, 4) {
+		assert.Equal(t, "Doe", perr.Tokens[3].Literal)
+	}
+
+	_, err = Parse(context.Background(), "a==b;(c=in=[x,y],d=gt=)")
+	if assert.True(t, errors.As(err, &perr)) {
+		assert.Nil(t, perr.Tokens)
+	}
+}
@@ -0,0 +1,138 @@
+package fiqlparser
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+)
+
+// TupleDelimiters are the characters enclosing and separating the elements of a tuple argument
+type TupleDelimiters struct {
+	Open      rune
+	Separator rune
+	Close     rune
+}
+
+// DefaultTupleDelimiters are used for tuples unless configured otherwise, e.g. `status=in=[open+closed]`
+var DefaultTupleDelimiters = TupleDelimiters{Open: '[', Separator: '+', Close: ']'}
+
+// WithTupleDelimiters configures the delimiters of tuple arguments,
+// e.g. WithTupleDelimiters('(', ',', ')') for `status=in=(open,closed)`
+func WithTupleDelimiters(open rune, separator rune, close rune) Option {
+	return func(p *Parser) {
+		p.tuple = TupleDelimiters{Open: open, Separator: separator, Close: close}
+	}
+}
+
+// NodeTypeTuple is a tuple argument (e.g. `[a+b]` of `=in=`), it is a ConstantNode
+// with a ConstantNode per element as children
+const NodeTypeTuple NodeType = "Tuple"
+
+// ErrNoTuple is generated if a argument is not a tuple
+var ErrNoTuple = errors.New("argument is not a tuple")
+
+// tupleArgument is the parsed argument of a =in= comparison
+type tupleArgument struct {
+	delimiters TupleDelimiters
+	elements   []*constantExpression
+}
+
+// Elements returns the elements of a tuple argument, nil otherwise
+func (e *constantExpression) Elements() []ConstantNode {
+	if e.tuple == nil {
+		return nil
+	}
+	elements := make([]ConstantNode, 0, len(e.tuple.elements))
+	for _, el := range e.tuple.elements {
+		elements = append(elements, el)
+	}
+	return elements
+}
+
+func (p *Parser) tupleDelimiters() TupleDelimiters {
+	if p.tuple == (TupleDelimiters{}) {
+		return DefaultTupleDelimiters
+	}
+	return p.tuple
+}
+
+func (p *Parser) handleTupleArgument() (Node, error) {
+	d := p.tupleDelimiters()
+	elements, ok, err := p.lex.readTuple(d)
+	if err != nil {
+		return nil, err
+	}
+	pos := p.lex.start
+	if !ok {
+		t, err := p.lex.ConsumeToken()
+		if err != nil {
+			return nil, err
+		}
+		if t != tokenValue {
+			return nil, p.lex.errExpectedValue(t)
+		}
+		return nil, p.lex.errInvalidValue(p.lex.lastValue(), []string{"tuple"})
+	}
+	if len(elements) == 0 {
+		return nil, p.lex.errInvalidValue(p.lex.lastValue(), []string{"value"})
+	}
+	validator := p.dateTimeValidator(defaultValidator)
+	for _, el := range elements {
+		p.lex.countNode(constantSize, el.value)
+		_, el.recommended, _ = validator(el.value)
+		p.recommendDateTime(el)
+		p.recommendIdentifier(el)
+		el.plainLiteral()
+	}
+	p.lex.countNode(constantSize+tupleSize+len(elements)*pointerSize, p.lex.lastValue())
+	return &constantExpression{
+		value:       p.lex.lastValue(),
+		recommended: ValueRecommendationTuple,
+		tuple:       &tupleArgument{delimiters: d, elements: elements},
+		pos:         pos,
+	}, nil
+}
+
+// AsTuple returns the elements of a tuple argument (e.g. `[1+2+3]`),
+// each with its own value recommendation
+func (c ArgumentContext) AsTuple() ([]ArgumentContext, error) {
+	if c.tuple == nil {
+		return nil, ErrNoTuple
+	}
+	elements := make([]ArgumentContext, 0, len(c.tuple.elements))
+	for _, el := range c.tuple.elements {
+		elements = append(elements, el.Argument())
+	}
+	return elements, nil
+}
+
+// writeTuple writes the tuple escaping reserved characters and delimiters within the elements
+func writeTuple(b textWriter, t *tupleArgument) {
+	b.WriteRune(t.delimiters.Open)
+	for i, el := range t.elements {
+		if i > 0 {
+			b.WriteRune(t.delimiters.Separator)
+		}
+		if el.prefixWildcard {
+			b.WriteRune('*')
+		}
+		if el.quote != 0 || el.value == "" || strings.IndexFunc(el.value, unicode.IsSpace) >= 0 {
+			quote := el.quote
+			if quote == 0 {
+				quote = '"'
+			}
+			writeQuotedValue(b, el.value, quote)
+		} else {
+			for _, r := range el.value {
+				if isReservedFIQLRune(r) || r == t.delimiters.Separator || r == t.delimiters.Close {
+					b.WriteRune('\\')
+				}
+				b.WriteRune(r)
+			}
+		}
+		if el.suffixWildcard {
+			b.WriteRune('*')
+		}
+	}
+	b.WriteRune(t.delimiters.Close)
+}
@@ -0,0 +1,168 @@
+package fiqlparser
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type tupleVisitor struct {
+	testTypeVisitor
+	elements []ArgumentContext
+	err      error
+}
+
+func (v *tupleVisitor) VisitArgument(argumentCtx ArgumentContext) {
+	v.testTypeVisitor.VisitArgument(argumentCtx)
+	if elements, err := argumentCtx.AsTuple(); err == nil {
+		v.elements = elements
+	} else {
+		v.err = err
+	}
+}
+
+func TestTupleArgument(t *testing.T) {
+	var values = []struct {
+		fiql   string
+		values []string
+		recs   []ValueRecommendation
+	}{
+		{fiql: "status=in=[open+closed]", values: []string{"open", "closed"}, recs: []ValueRecommendation{ValueRecommendationString, ValueRecommendationString}},
+		{fiql: "id=in=[1+2.5+2003-12-13T00:00:00Z]", values: []string{"1", "2.5", "2003-12-13T00:00:00Z"},
+			recs: []ValueRecommendation{ValueRecommendationNumber, ValueRecommendationNumber, ValueRecommendationDateTime}},
+		{fiql: `city=in=[ "New York" + 'Paris' + a\+b\]c ]`, values: []string{"New York", "Paris", "a+b]c"},
+			recs: []ValueRecommendation{ValueRecommendationString, ValueRecommendationString, ValueRecommendationString}},
+		{fiql: `flag=in=[true+"null"+null]`, values: []string{"true", "null", "null"},
+			recs: []ValueRecommendation{ValueRecommendationBoolean, ValueRecommendationString, ValueRecommendationNull}},
+		{fiql: "name=in=[single]", values: []string{"single"}, recs: []ValueRecommendation{ValueRecommendationString}},
+	}
+	for _, v := range values {
+		res, err := Parse(context.Background(), v.fiql)
+		if !assert.NoError(t, err, v.fiql) {
+			continue
+		}
+		visitor := &tupleVisitor{}
+		res.Accept(visitor)
+		assert.Equal(t, "tuple", visitor.String(), v.fiql)
+		if !assert.NoError(t, visitor.err, v.fiql) {
+			continue
+		}
+		values := make([]string, 0)
+		recs := make([]ValueRecommendation, 0)
+		for _, el := range visitor.elements {
+			values = append(values, el.AsString())
+			recs = append(recs, el.ValueRecommendation())
+		}
+		assert.Equal(t, v.values, values, v.fiql)
+		assert.Equal(t, v.recs, recs, v.fiql)
+	}
+}
+
+func TestTupleArgumentWildcards(t *testing.T) {
+	res, err := Parse(context.Background(), "name=in=[Jo*+*son+Max]")
+	assert.NoError(t, err)
+	visitor := &tupleVisitor{}
+	res.Accept(visitor)
+	if assert.Len(t, visitor.elements, 3) {
+		assert.True(t, visitor.elements[0].EndsWithWildcard())
+		assert.True(t, visitor.elements[1].StartsWithWildcard())
+		assert.False(t, visitor.elements[2].StartsWithWildcard() || visitor.elements[2].EndsWithWildcard())
+	}
+}
+
+func TestTupleArgumentErrors(t *testing.T) {
+	var values = []struct {
+		fiql     string
+		code     ErrorCode
+		expected []string
+	}{
+		{fiql: "status=in=open", code: ErrorCodeInvalidValue, expected: []string{"tuple"}},
+		{fiql: "status=in=[]", code: ErrorCodeInvalidValue, expected: []string{"value"}},
+		{fiql: "status=in=[a++b]", code: ErrorCodeInvalidValue, expected: []string{"value"}},
+		{fiql: "status=in=[a b]", code: ErrorCodeInvalidValue, expected: []string{"+", "]"}},
+		{fiql: "status=in=[a+b", code: ErrorCodeUnexpectedEOF, expected: []string{"+", "]"}},
+		{fiql: "status=in=", code: ErrorCodeUnexpectedToken},
+	}
+	for _, v := range values {
+		_, err := Parse(context.Background(), v.fiql)
+		var parseErr *ParseError
+		if assert.ErrorAs(t, err, &parseErr, v.fiql) {
+			assert.Equal(t, v.code, parseErr.Code, v.fiql)
+			if v.expected != nil {
+				assert.Equal(t, v.expected, parseErr.Expected, v.fiql)
+			}
+		}
+	}
+}
+
+func TestTupleDelimiters(t *testing.T) {
+	p := New(WithTupleDelimiters('(', ',', ')'))
+	res, err := p.Parse(context.Background(), `status=in=(open, "in progress");prio==1`)
+	if !assert.NoError(t, err) {
+		return
+	}
+	visitor := &tupleVisitor{}
+	res.Accept(visitor)
+	if assert.Len(t, visitor.elements, 2) {
+		assert.Equal(t, "in progress", visitor.elements[1].AsString())
+	}
+	assert.Equal(t, `status=in=(open,"in progress");prio==1`, res.ToFIQL())
+}
+
+func TestTupleToFIQL(t *testing.T) {
+	res, err := Parse(context.Background(), `a=in=[ x\+y + "b c" + *d ];b==1`)
+	if !assert.NoError(t, err) {
+		return
+	}
+	out := res.ToFIQL()
+	assert.Equal(t, `a=in=[x\+y+"b c"+*d];b==1`, out)
+	again, err := Parse(context.Background(), out)
+	if assert.NoError(t, err) {
+		assert.Equal(t, out, again.ToFIQL())
+	}
+}
+
+func TestTupleNode(t *testing.T) {
+	res, err := Parse(context.Background(), `a=in=[x+*y+10]`)
+	if !assert.NoError(t, err) {
+		return
+	}
+	var tuple ConstantNode
+	Walk(&res, func(n Node) bool {
+		if c, ok := n.(ConstantNode); ok && c.NodeType() == NodeTypeTuple {
+			tuple = c
+		}
+		return true
+	})
+	if !assert.NotNil(t, tuple) {
+		return
+	}
+	elements := tuple.Elements()
+	if assert.Len(t, elements, 3) && assert.Len(t, tuple.Children(), 3) {
+		assert.Equal(t, NodeTypeConstant, elements[0].NodeType())
+		assert.Equal(t, "x", elements[0].String())
+		assert.True(t, elements[1].Argument().StartsWithWildcard())
+		assert.Equal(t, ValueRecommendationNumber, elements[2].Argument().ValueRecommendation())
+	}
+
+	data, err := json.Marshal(&res)
+	if assert.NoError(t, err) {
+		assert.Contains(t, string(data), `{"Type":"Tuple","Value":"[x+*y+10]","Nodes":[{"Type":"Const","Value":"x"},{"Type":"Const","Value":"*y"},{"Type":"Const","Value":"10"}]}`)
+	}
+}
+
+func TestTupleElementsNonTuple(t *testing.T) {
+	res, err := Parse(context.Background(), `a==b`)
+	if !assert.NoError(t, err) {
+		return
+	}
+	Walk(&res, func(n Node) bool {
+		if c, ok := n.(ConstantNode); ok {
+			assert.Nil(t, c.Elements())
+			assert.NotEqual(t, NodeTypeTuple, c.NodeType())
+		}
+		return true
+	})
+}
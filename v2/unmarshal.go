@@ -0,0 +1,249 @@
+package fiqlparser
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// ErrInvalidNodeJSON is generated by UnmarshalJSON if the JSON is no valid expression tree
+var ErrInvalidNodeJSON = errors.New("invalid expression JSON")
+
+// jsonNode holds the fields of both the JSON (MarshalJSON) and the detailed JSON (MarshalDetailedJSON) format
+type jsonNode struct {
+	Type           NodeType
+	Operator       string
+	Label          string
+	Value          string
+	Nodes          []*jsonNode
+	Unary          bool
+	Recommendation ValueRecommendation
+	PrefixWildcard bool
+	SuffixWildcard bool
+	Quoted         bool
+	Position       Position
+}
+
+// jsonSelector and jsonValue are the constant types of the detailed JSON format
+const jsonSelector = "Selector"
+const jsonValue = "Value"
+
+// UnmarshalJSON reads a expression written by MarshalJSON or MarshalDetailedJSON, so trees can be
+// passed between services. Unary selectors, labels, wildcards and quotes are restored,
+// the value recommendations are detected again unless contained in the detailed format.
+func (e *Expression) UnmarshalJSON(data []byte) error {
+	var root jsonNode
+	if err := json.Unmarshal(data, &root); err != nil {
+		return err
+	}
+	if root.Type != NodeTypeExpression {
+		return fmt.Errorf("%w: root is `%s` instead of `%s`", ErrInvalidNodeJSON, root.Type, NodeTypeExpression)
+	}
+	n, err := root.expression()
+	if err != nil {
+		return err
+	}
+	*e = *n
+	e.root = true
+	return nil
+}
+
+func (j *jsonNode) expression() (*Expression, error) {
+	if len(j.Nodes) > 1 {
+		return nil, fmt.Errorf("%w: expression with %d nodes", ErrInvalidNodeJSON, len(j.Nodes))
+	}
+	e := &Expression{label: j.Label}
+	if len(j.Nodes) == 1 && j.Nodes[0] != nil {
+		n, err := j.Nodes[0].node()
+		if err != nil {
+			return nil, err
+		}
+		e.node = n
+	}
+	return e, nil
+}
+
+func (j *jsonNode) node() (Node, error) {
+	switch j.Type {
+	case NodeTypeExpression:
+		return j.expression()
+	case NodeTypeBinary:
+		if isLogicalOperator(j.Operator) {
+			nodes, err := j.operands(2, 2)
+			if err != nil {
+				return nil, err
+			}
+			return &binaryExpression{operator: j.Operator, nodes: [2]Node{nodes[0], nodes[1]}}, nil
+		}
+		return j.comparison()
+	case NodeTypeLogical:
+		if !isLogicalOperator(j.Operator) {
+			return nil, fmt.Errorf("%w: unknown logical operator `%s`", ErrInvalidNodeJSON, j.Operator)
+		}
+		nodes, err := j.operands(2, -1)
+		if err != nil {
+			return nil, err
+		}
+		return &logicalExpression{operator: j.Operator, nodes: nodes}, nil
+	case NodeTypeUnaryLogical:
+		nodes, err := j.operands(1, 1)
+		if err != nil {
+			return nil, err
+		}
+		return &notExpression{node: nodes[0]}, nil
+	case NodeTypeUnary:
+		return j.selector(true), nil
+	case jsonSelector:
+		return j.selector(j.Unary), nil
+	}
+	return nil, fmt.Errorf("%w: unexpected `%s` node", ErrInvalidNodeJSON, j.Type)
+}
+
+func (j *jsonNode) operands(min int, max int) ([]Node, error) {
+	if len(j.Nodes) < min || (max >= 0 && len(j.Nodes) > max) {
+		return nil, fmt.Errorf("%w: `%s` with %d operands", ErrInvalidNodeJSON, j.Operator, len(j.Nodes))
+	}
+	nodes := make([]Node, 0, len(j.Nodes))
+	for _, c := range j.Nodes {
+		if c == nil {
+			return nil, fmt.Errorf("%w: `%s` with missing operand", ErrInvalidNodeJSON, j.Operator)
+		}
+		n, err := c.node()
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, n)
+	}
+	return nodes, nil
+}
+
+func (j *jsonNode) selector(unary bool) *constantExpression {
+	return &constantExpression{value: j.Value, selector: true, unary: unary, recommended: ValueRecommendationString, pos: j.Position}
+}
+
+func (j *jsonNode) comparison() (Node, error) {
+	if _, ok := fiqlOperators[j.Operator]; !ok {
+		return nil, fmt.Errorf("%w: unknown comparison `%s`", ErrInvalidNodeJSON, j.Operator)
+	}
+	if len(j.Nodes) != 2 || j.Nodes[0] == nil || j.Nodes[1] == nil {
+		return nil, fmt.Errorf("%w: comparison `%s` without selector and argument", ErrInvalidNodeJSON, j.Operator)
+	}
+	sel, arg := j.Nodes[0], j.Nodes[1]
+	if sel.Type != NodeTypeConstant && sel.Type != jsonSelector {
+		return nil, fmt.Errorf("%w: unexpected `%s` selector", ErrInvalidNodeJSON, sel.Type)
+	}
+	validator := defaultValidator
+	switch Comparison(j.Operator) {
+	case ComparisonGt, ComparisonLt, ComparisonGte, ComparisonLte:
+		validator = numberOrDateExpressionValidator
+	}
+	con, err := arg.argument(validator)
+	if err != nil {
+		return nil, err
+	}
+	if con.tuple != nil && j.Operator == string(ComparisonBetween) {
+		con.recommended = ValueRecommendationRange
+	}
+	return &binaryExpression{operator: j.Operator, nodes: [2]Node{sel.selector(false), con}}, nil
+}
+
+func (j *jsonNode) argument(validator argumentValidator) (*constantExpression, error) {
+	switch j.Type {
+	case NodeTypeTuple:
+		elements := make([]*constantExpression, 0, len(j.Nodes))
+		for _, c := range j.Nodes {
+			if c == nil {
+				return nil, fmt.Errorf("%w: tuple with missing element", ErrInvalidNodeJSON)
+			}
+			el, err := c.argument(defaultValidator)
+			if err != nil {
+				return nil, err
+			}
+			elements = append(elements, el)
+		}
+		if len(elements) == 0 {
+			return nil, fmt.Errorf("%w: empty tuple", ErrInvalidNodeJSON)
+		}
+		return &constantExpression{
+			value:       j.Value,
+			recommended: ValueRecommendationTuple,
+			tuple:       &tupleArgument{delimiters: jsonTupleDelimiters(j.Value, elements[0]), elements: elements},
+			pos:         j.Position,
+		}, nil
+	case jsonValue:
+		con := &constantExpression{value: j.Value, recommended: j.Recommendation, prefixWildcard: j.PrefixWildcard,
+			suffixWildcard: j.SuffixWildcard, pos: j.Position}
+		if j.Quoted {
+			con.quote = '"'
+		}
+		if con.recommended == "" {
+			_, con.recommended, _ = validator(con.value)
+			con.plainLiteral()
+		}
+		return con, nil
+	case NodeTypeConstant:
+		con := parseJSONConstant(j.Value)
+		_, con.recommended, _ = validator(con.value)
+		con.plainLiteral()
+		return con, nil
+	}
+	return nil, fmt.Errorf("%w: unexpected `%s` argument", ErrInvalidNodeJSON, j.Type)
+}
+
+// parseJSONConstant reverses the String representation of a argument, e.g. `*"a \"b\""`
+func parseJSONConstant(s string) *constantExpression {
+	con := &constantExpression{}
+	if strings.HasPrefix(s, "*") {
+		con.prefixWildcard = true
+		s = s[1:]
+	}
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') {
+		quote := rune(s[0])
+		end := strings.LastIndexByte(s, s[0])
+		if end > 0 {
+			var b strings.Builder
+			escaped := false
+			for _, r := range s[1:end] {
+				if r == '\\' && !escaped {
+					escaped = true
+					continue
+				}
+				escaped = false
+				b.WriteRune(r)
+			}
+			con.quote = quote
+			con.value = b.String()
+			con.suffixWildcard = s[end+1:] == "*"
+			return con
+		}
+	}
+	if strings.HasSuffix(s, "*") {
+		con.suffixWildcard = true
+		s = s[:len(s)-1]
+	}
+	con.value = s
+	return con
+}
+
+// jsonTupleDelimiters restores the delimiters from the raw tuple, the separator follows the first element
+func jsonTupleDelimiters(raw string, first *constantExpression) TupleDelimiters {
+	open, openSize := utf8.DecodeRuneInString(raw)
+	close, closeSize := utf8.DecodeLastRuneInString(raw)
+	if openSize == 0 || len(raw) < openSize+closeSize {
+		return DefaultTupleDelimiters
+	}
+	d := TupleDelimiters{Open: open, Separator: DefaultTupleDelimiters.Separator, Close: close}
+	if open == '(' {
+		d.Separator = ','
+	}
+	inner := strings.TrimLeft(raw[openSize:len(raw)-closeSize], " ")
+	if text := first.String(); strings.HasPrefix(inner, text) {
+		rest := strings.TrimLeft(inner[len(text):], " ")
+		if r, size := utf8.DecodeRuneInString(rest); size > 0 && len(rest) > size {
+			d.Separator = r
+		}
+	}
+	return d
+}
@@ -0,0 +1,118 @@
+package fiqlparser
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnmarshalJSONRoundTrip(t *testing.T) {
+	var values = []struct {
+		fiql string
+		opts []Option
+	}{
+		{fiql: "name==John"},
+		{fiql: "deleted"},
+		{fiql: "deleted;name==Jo*,archived"},
+		{fiql: `name=="John \"J\" Doe";title==*x*`},
+		{fiql: "urgent:(status==open;priority=gt=2)"},
+		{fiql: "age=in=[18+21+*5];created=bt=[2024-01-01+2024-02-01]"},
+		{fiql: "status=in=(open,closed)", opts: []Option{WithTupleDelimiters('(', ',', ')')}},
+		{fiql: "a==1,b==2,c", opts: []Option{WithLogicalNodes()}},
+		{fiql: "!(a==1;b);c==null", opts: []Option{WithNegation()}},
+		{fiql: "created=gt=-P1D;active==true;age=bt=1..5"},
+	}
+	for _, v := range values {
+		res, err := New(v.opts...).Parse(context.Background(), v.fiql)
+		if !assert.NoError(t, err, v.fiql) {
+			continue
+		}
+		for _, detailed := range []bool{false, true} {
+			var data []byte
+			if detailed {
+				data, err = res.MarshalDetailedJSON()
+			} else {
+				data, err = json.Marshal(&res)
+			}
+			if !assert.NoError(t, err, v.fiql) {
+				continue
+			}
+			var back Expression
+			if !assert.NoError(t, json.Unmarshal(data, &back), "%s: %s", v.fiql, data) {
+				continue
+			}
+			assert.Equal(t, res.ToFIQL(), back.ToFIQL(), v.fiql)
+			assert.Equal(t, res.String(), back.String(), v.fiql)
+			assert.True(t, res.Equal(&back), v.fiql)
+			again, err := json.Marshal(&back)
+			if assert.NoError(t, err) {
+				plain, _ := json.Marshal(&res)
+				assert.Equal(t, string(plain), string(again), v.fiql)
+			}
+			assert.Equal(t, predicateSummary(&res), predicateSummary(&back), "%s detailed=%v", v.fiql, detailed)
+		}
+	}
+}
+
+// predicateSummary lists the selectors with the unary flag and the argument recommendations
+func predicateSummary(e *Expression) []string {
+	res := make([]string, 0)
+	Walk(e, func(n Node) bool {
+		if c, ok := n.(ConstantNode); ok {
+			if c.IsSelector() {
+				res = append(res, c.String()+":selector:"+boolString(c.IsUnary()))
+			} else {
+				res = append(res, c.String()+":"+string(c.ValueRecommendation()))
+			}
+		}
+		return true
+	})
+	return res
+}
+
+func boolString(b bool) string {
+	if b {
+		return "unary"
+	}
+	return "binary"
+}
+
+func TestUnmarshalJSONUnary(t *testing.T) {
+	var e Expression
+	err := json.Unmarshal([]byte(`{"Type":"Expr","Operator":"","Nodes":[{"Type":"Unary","Value":"deleted"}]}`), &e)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, "deleted", e.ToFIQL())
+	v := &recordingVisitor{}
+	assert.NoError(t, e.Visit(&unaryRecorder{recordingVisitor: v}))
+	assert.Contains(t, v.events, "unary deleted")
+}
+
+type unaryRecorder struct {
+	*recordingVisitor
+}
+
+func (u *unaryRecorder) VisitSelector(ctx SelectorContext) error {
+	if ctx.IsUnary() {
+		return u.record("unary " + ctx.Selector())
+	}
+	return u.record("selector " + ctx.Selector())
+}
+
+func TestUnmarshalJSONInvalid(t *testing.T) {
+	for _, input := range []string{
+		`{"Type":"Binary","Operator":"==","Nodes":[]}`,
+		`{"Type":"Expr","Operator":"","Nodes":[{"Type":"Binary","Operator":"~~","Nodes":[{"Type":"Const","Value":"a"},{"Type":"Const","Value":"b"}]}]}`,
+		`{"Type":"Expr","Operator":"","Nodes":[{"Type":"Binary","Operator":"==","Nodes":[{"Type":"Const","Value":"a"}]}]}`,
+		`{"Type":"Expr","Operator":"","Nodes":[{"Type":"Binary","Operator":"AND","Nodes":[{"Type":"Unary","Value":"a"},null]}]}`,
+		`{"Type":"Expr","Operator":"","Nodes":[{"Type":"Const","Value":"a"}]}`,
+	} {
+		var e Expression
+		assert.ErrorIs(t, json.Unmarshal([]byte(input), &e), ErrInvalidNodeJSON, input)
+	}
+	var e Expression
+	assert.Error(t, json.Unmarshal([]byte(`{"Type":`), &e))
+}
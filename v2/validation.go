@@ -0,0 +1,229 @@
+package fiqlparser
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ValidationOptions configures Schema.ValidateDetailed
+type ValidationOptions struct {
+	// RemoveInvalid removes predicates violating the schema from the normalized expression,
+	// they are reported as warnings instead of errors (e.g. for lenient endpoints ignoring unknown selectors)
+	RemoveInvalid bool
+	// Coerce converts arguments to the type of their selector if this is unambiguous,
+	// e.g. `TRUE` or the quoted `"true"` to `true` for boolean selectors
+	Coerce bool
+}
+
+// ValidationIssue is a violation of the schema reported by Schema.ValidateDetailed
+type ValidationIssue struct {
+	// Position is the position of the selector
+	Position Position
+	Selector string
+	// Err wraps ErrUnknownSelector, ErrComparisonNotAllowed or ErrInvalidArgumentType
+	Err error
+}
+
+// String returns the issue in the form `ln:<line>:<column> <error>`
+func (i ValidationIssue) String() string {
+	return fmt.Sprintf("ln:%d:%d %s", i.Position.Line, i.Position.Column+1, i.Err)
+}
+
+// Coercion is a argument converted to the type of its selector, see ValidationOptions.Coerce
+type Coercion struct {
+	// Position is the position of the argument
+	Position Position
+	Selector string
+	// From and To are the argument before and after the conversion in FIQL notation
+	From string
+	To   string
+}
+
+// ValidationResult is the outcome of Schema.ValidateDetailed
+type ValidationResult struct {
+	// Expression is the normalized expression: coerced and without removed predicates,
+	// the empty expression if all predicates were removed
+	Expression Expression
+	// Errors are the violations of the schema in order of their position
+	Errors []ValidationIssue
+	// Warnings are the violations which were resolved by removing the predicate
+	Warnings []ValidationIssue
+	// Coercions are the converted arguments
+	Coercions []Coercion
+	// Removed are the removed predicates, their nodes belong to the validated expression
+	Removed []Predicate
+}
+
+// Valid indicates that the expression satisfies the schema, possibly after normalization
+func (r ValidationResult) Valid() bool {
+	return len(r.Errors) == 0
+}
+
+// Err returns the first error, nil if the expression is valid
+func (r ValidationResult) Err() error {
+	if len(r.Errors) == 0 {
+		return nil
+	}
+	return r.Errors[0].Err
+}
+
+// ValidateDetailed checks all predicates of the expression against the schema like Validate,
+// but does not stop at the first violation and normalizes the expression according to opts,
+// so API layers can implement lenient modes and report all issues in one pass.
+// Removing a predicate also removes its negation and braces which become empty.
+// The validated expression is not modified.
+func (s Schema) ValidateDetailed(e Expression, opts ValidationOptions) ValidationResult {
+	v := schemaValidation{schema: s, opts: opts}
+	v.res.Errors = make([]ValidationIssue, 0)
+	v.res.Warnings = make([]ValidationIssue, 0)
+	v.res.Coercions = make([]Coercion, 0)
+	v.res.Removed = make([]Predicate, 0)
+	n := v.node(&e)
+	if exp, ok := n.(*Expression); ok {
+		v.res.Expression = *exp
+	} else {
+		v.res.Expression = Expression{root: true, node: n}
+	}
+	return v.res
+}
+
+// schemaValidation is the state of ValidateDetailed
+type schemaValidation struct {
+	schema Schema
+	opts   ValidationOptions
+	res    ValidationResult
+}
+
+// node returns the normalized copy of n, nil if it was removed entirely
+func (v *schemaValidation) node(n Node) Node {
+	if p, ok := predicateOf(n); ok {
+		return v.predicate(p)
+	}
+	switch node := n.(type) {
+	case *Expression:
+		c := *node
+		c.node = nil
+		if node.node != nil {
+			c.node = v.node(node.node)
+		}
+		if c.node == nil && !c.root {
+			return nil
+		}
+		return &c
+	case *binaryExpression:
+		lhs, rhs := v.node(node.nodes[0]), v.node(node.nodes[1])
+		if lhs == nil || rhs == nil {
+			if lhs == nil {
+				return rhs
+			}
+			return lhs
+		}
+		return newBinary(node.operator, lhs, rhs)
+	case *logicalExpression:
+		operands := make([]Node, 0, len(node.nodes))
+		for _, o := range node.nodes {
+			if c := v.node(o); c != nil {
+				operands = append(operands, c)
+			}
+		}
+		switch len(operands) {
+		case 0:
+			return nil
+		case 1:
+			return operands[0]
+		}
+		return &logicalExpression{operator: node.operator, nodes: operands}
+	case *notExpression:
+		inner := v.node(node.node)
+		if inner == nil {
+			return nil
+		}
+		return &notExpression{node: inner}
+	}
+	return n
+}
+
+// predicate validates the predicate and returns its normalized copy, nil if it was removed
+func (v *schemaValidation) predicate(p Predicate) Node {
+	res := rewriteNode(p.Node, func(n Node) Node { return n })
+	if v.opts.Coerce && !p.IsUnary() {
+		if bin, ok := res.(*binaryExpression); ok {
+			if sel, arg, ok := predicateOperands(bin); ok {
+				v.coerce(sel.value, arg)
+				p.Argument = arg.Argument()
+			}
+		}
+	}
+	err := v.schema.validatePredicate(p)
+	if err == nil {
+		return res
+	}
+	issue := ValidationIssue{Position: predicatePosition(p), Selector: p.Selector, Err: err}
+	if !v.opts.RemoveInvalid {
+		v.res.Errors = append(v.res.Errors, issue)
+		return res
+	}
+	v.res.Warnings = append(v.res.Warnings, issue)
+	v.res.Removed = append(v.res.Removed, p)
+	return nil
+}
+
+// coerce converts the argument (a copy) to the type of the selector if it is not accepted as is
+func (v *schemaValidation) coerce(selector string, arg *constantExpression) {
+	sel, ok := v.schema.selector(selector)
+	if !ok || sel.accepts(arg.Argument()) || arg.recommended == ValueRecommendationField {
+		return
+	}
+	from := coercionNotation(arg)
+	if arg.tuple != nil {
+		tuple := &tupleArgument{delimiters: arg.tuple.delimiters, elements: make([]*constantExpression, 0, len(arg.tuple.elements))}
+		for _, el := range arg.tuple.elements {
+			c := *el
+			coerceConstant(sel.Type, &c)
+			tuple.elements = append(tuple.elements, &c)
+		}
+		var b strings.Builder
+		writeTuple(&b, tuple)
+		arg.tuple, arg.value = tuple, b.String()
+	} else {
+		coerceConstant(sel.Type, arg)
+	}
+	if to := coercionNotation(arg); to != from {
+		v.res.Coercions = append(v.res.Coercions, Coercion{Position: arg.pos, Selector: selector, From: from, To: to})
+	}
+}
+
+// coerceConstant converts the constant to typ if its value unambiguously represents it,
+// arguments with wildcards are not converted
+func coerceConstant(typ ValueRecommendation, c *constantExpression) {
+	if c.prefixWildcard || c.suffixWildcard || c.recommended == typ {
+		return
+	}
+	value := c.value
+	if typ == ValueRecommendationBoolean {
+		value = strings.ToLower(value)
+	}
+	if _, rec, _ := defaultValidator(value); rec == typ && typ != ValueRecommendationRange {
+		c.value, c.recommended, c.quote = value, rec, 0
+	}
+}
+
+// coercionNotation returns the argument in FIQL notation
+func coercionNotation(c *constantExpression) string {
+	var b strings.Builder
+	writeFIQL(&b, c)
+	return b.String()
+}
+
+// predicatePosition returns the position of the selector of the predicate
+func predicatePosition(p Predicate) Position {
+	switch node := p.Node.(type) {
+	case *binaryExpression:
+		if sel, ok := node.nodes[0].(*constantExpression); ok {
+			return sel.pos
+		}
+	case *constantExpression:
+		return node.pos
+	}
+	return Position{}
+}
@@ -0,0 +1,78 @@
+package fiqlparser
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var validationSchema = Schema{Selectors: []SelectorSchema{
+	{Name: "name"},
+	{Name: "age", Type: ValueRecommendationNumber, Comparisons: []Comparison{ComparisonEq, ComparisonGt, ComparisonIn}},
+	{Name: "active", Type: ValueRecommendationBoolean, Unary: true},
+}}
+
+func TestValidateDetailed(t *testing.T) {
+	var values = []struct {
+		input     string
+		opts      ValidationOptions
+		expected  string
+		errors    int
+		warnings  int
+		coercions []string
+	}{
+		{input: "name==John;age=gt=18", expected: "name==John;age=gt=18"},
+		{input: "name==John;x==1;age=lt=3", expected: "name==John;x==1;age=lt=3", errors: 2},
+		{input: "name==John;x==1;age=lt=3", opts: ValidationOptions{RemoveInvalid: true}, expected: "name==John", warnings: 2},
+		{input: "x==1;(y==2,z==3)", opts: ValidationOptions{RemoveInvalid: true}, expected: "", warnings: 3},
+		{input: "name==a;(x==1,age==2)", opts: ValidationOptions{RemoveInvalid: true}, expected: "name==a;(age==2)", warnings: 1},
+		{input: "name==a;!(x==1)", opts: ValidationOptions{RemoveInvalid: true}, expected: "name==a", warnings: 1},
+		{input: `age=="18";active=="true";active==TRUE`, opts: ValidationOptions{Coerce: true}, expected: `age=="18";active==true;active==true`,
+			coercions: []string{`"true"->true`, "TRUE->true"}},
+		{input: `active=in=(TRUE,false)`, opts: ValidationOptions{Coerce: true}, expected: "active=in=(true,false)", coercions: []string{`(TRUE,false)->(true,false)`}},
+		{input: `active==TRUE`, expected: "active==TRUE", errors: 1},
+		{input: `age==abc;name=="1"`, opts: ValidationOptions{Coerce: true}, expected: `age==abc;name=="1"`, errors: 1},
+		{input: `active==yes;active==False`, opts: ValidationOptions{Coerce: true, RemoveInvalid: true}, expected: "active==false", warnings: 1,
+			coercions: []string{"False->false"}},
+	}
+	p := New(WithNegation(), WithTupleDelimiters('(', ',', ')'))
+	for _, v := range values {
+		e, err := p.Parse(context.Background(), v.input)
+		if !assert.NoError(t, err, v.input) {
+			continue
+		}
+		res := validationSchema.ValidateDetailed(e, v.opts)
+		assert.Equal(t, v.expected, res.Expression.ToFIQL(), v.input)
+		assert.Len(t, res.Errors, v.errors, v.input)
+		assert.Len(t, res.Warnings, v.warnings, v.input)
+		assert.Len(t, res.Removed, v.warnings, v.input)
+		assert.Equal(t, v.errors == 0, res.Valid(), v.input)
+		coercions := make([]string, 0)
+		for _, c := range res.Coercions {
+			coercions = append(coercions, c.From+"->"+c.To)
+		}
+		assert.Equal(t, append([]string{}, v.coercions...), coercions, v.input)
+		// the input is not modified
+		assert.Equal(t, v.input, e.ToFIQL(), v.input)
+	}
+}
+
+func TestValidateDetailedIssues(t *testing.T) {
+	e := mustParse(t, "name==a;\nx==1;age==abc")
+	res := validationSchema.ValidateDetailed(e, ValidationOptions{})
+	if assert.Len(t, res.Errors, 2) {
+		assert.ErrorIs(t, res.Errors[0].Err, ErrUnknownSelector)
+		assert.Equal(t, "x", res.Errors[0].Selector)
+		assert.Equal(t, "ln:2:1 unknown selector `x`", res.Errors[0].String())
+		assert.ErrorIs(t, res.Errors[1].Err, ErrInvalidArgumentType)
+	}
+	assert.Equal(t, validationSchema.Validate(e), res.Err())
+
+	res = validationSchema.ValidateDetailed(e, ValidationOptions{RemoveInvalid: true})
+	assert.NoError(t, res.Err())
+	if assert.Len(t, res.Removed, 2) {
+		assert.Equal(t, "x", res.Removed[0].Selector)
+		assert.Equal(t, "age", res.Removed[1].Selector)
+	}
+}
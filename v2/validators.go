@@ -0,0 +1,88 @@
+package fiqlparser
+
+// ValueValidator validates the argument of a comparison at parse time, e.g. the format of ids,
+// the returned error is wrapped by the positioned ParseError (ErrorCodeInvalidValue)
+type ValueValidator func(p Predicate) error
+
+// ValidatorRegistry holds the value validators attached to comparisons and selectors, see WithValidators.
+// It must not be modified once it is passed to a parser.
+type ValidatorRegistry struct {
+	comparisons map[Comparison][]ValueValidator
+	selectors   map[string][]ValueValidator
+	builtins    map[Comparison]ValueValidator
+}
+
+// NewValidatorRegistry creates a empty registry
+func NewValidatorRegistry() *ValidatorRegistry {
+	return &ValidatorRegistry{
+		comparisons: make(map[Comparison][]ValueValidator),
+		selectors:   make(map[string][]ValueValidator),
+		builtins:    make(map[Comparison]ValueValidator),
+	}
+}
+
+// AddComparison attaches v to every comparison c, e.g. to restrict `=q=` to a minimum length
+func (r *ValidatorRegistry) AddComparison(c Comparison, v ValueValidator) *ValidatorRegistry {
+	r.comparisons[c] = append(r.comparisons[c], v)
+	return r
+}
+
+// AddSelector attaches v to every comparison of the selector (as it appears in the expression,
+// after aliases and the selector mapper are applied), e.g. to require UUIDs for `id`
+func (r *ValidatorRegistry) AddSelector(selector string, v ValueValidator) *ValidatorRegistry {
+	r.selectors[selector] = append(r.selectors[selector], v)
+	return r
+}
+
+// ReplaceBuiltin replaces the built-in check of `=gt=`, `=ge=`, `=lt=` and `=le=` requiring a number,
+// date or duration by v, e.g. to allow the lexical comparison of strings. The argument is typed
+// like the argument of a equality comparison. Other comparisons have no built-in check and are ignored.
+func (r *ValidatorRegistry) ReplaceBuiltin(c Comparison, v ValueValidator) *ValidatorRegistry {
+	switch c {
+	case ComparisonGt, ComparisonGte, ComparisonLt, ComparisonLte:
+		r.builtins[c] = v
+	}
+	return r
+}
+
+// WithValidators validates the arguments of all comparisons with the validators of r while parsing.
+// The validators of the comparison are called before the ones of the selector, the first error aborts the parse.
+// Field references and named parameters are not validated.
+func WithValidators(r *ValidatorRegistry) Option {
+	return func(p *Parser) {
+		p.validators = r
+	}
+}
+
+// replacesBuiltin reports whether the built-in check of the comparison token is replaced
+func (r *ValidatorRegistry) replacesBuiltin(t tokenType) bool {
+	if r == nil {
+		return false
+	}
+	_, ok := r.builtins[Comparison(t.String())]
+	return ok
+}
+
+// validate calls the validators attached to the comparison and selector of bin
+func (r *ValidatorRegistry) validate(bin *binaryExpression) error {
+	p, ok := predicateOf(bin)
+	if !ok {
+		return nil
+	}
+	switch p.Argument.ValueRecommendation() {
+	case ValueRecommendationField, ValueRecommendationParameter:
+		return nil
+	}
+	validators := make([]ValueValidator, 0)
+	if v, ok := r.builtins[p.Comparison]; ok {
+		validators = append(validators, v)
+	}
+	validators = append(validators, r.comparisons[p.Comparison]...)
+	validators = append(validators, r.selectors[p.Selector]...)
+	for _, v := range validators {
+		if err := v(p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
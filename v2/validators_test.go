@@ -0,0 +1,96 @@
+package fiqlparser
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var errNoUUID = errors.New("not a uuid")
+var errNotText = errors.New("full text search on non text field")
+
+var testUUIDRegex = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`)
+
+func testValidators() *ValidatorRegistry {
+	return NewValidatorRegistry().
+		AddSelector("id", func(p Predicate) error {
+			values := []ArgumentContext{p.Argument}
+			if elements, err := p.Argument.AsTuple(); err == nil {
+				values = elements
+			}
+			for _, v := range values {
+				if !testUUIDRegex.MatchString(v.AsString()) {
+					return errNoUUID
+				}
+			}
+			return nil
+		}).
+		AddComparison(ComparisonQuery, func(p Predicate) error {
+			if p.Selector != "title" && p.Selector != "body" {
+				return errNotText
+			}
+			return nil
+		})
+}
+
+func TestWithValidators(t *testing.T) {
+	var values = []struct {
+		input string
+		err   error
+	}{
+		{input: "id==123e4567-e89b-12d3-a456-426614174000"},
+		{input: "id==123", err: errNoUUID},
+		{input: "id=in=[123e4567-e89b-12d3-a456-426614174000+x]", err: errNoUUID},
+		{input: "title=q=fox;body=q=dog"},
+		{input: "title=q=fox;id=q=1", err: errNotText},
+		{input: "name==x;deleted"},
+	}
+	p := New(WithValidators(testValidators()))
+	for _, v := range values {
+		_, err := p.Parse(context.Background(), v.input)
+		if v.err == nil {
+			assert.NoError(t, err, v.input)
+			continue
+		}
+		assert.ErrorIs(t, err, v.err, v.input)
+		var perr *ParseError
+		if assert.True(t, errors.As(err, &perr), v.input) {
+			assert.Equal(t, ErrorCodeInvalidValue, perr.Code, v.input)
+		}
+	}
+}
+
+func TestWithValidatorsPosition(t *testing.T) {
+	_, err := New(WithValidators(testValidators())).Parse(context.Background(), "name==x;\n id==123")
+	var perr *ParseError
+	if assert.True(t, errors.As(err, &perr)) {
+		assert.Equal(t, "123", perr.Token)
+		assert.Equal(t, 2, perr.Line)
+		assert.Equal(t, 6, perr.Column)
+		assert.Equal(t, "ln:2:6 invalid value `123` (not a uuid)", perr.Error())
+	}
+}
+
+func TestValidatorRegistryReplaceBuiltin(t *testing.T) {
+	_, err := Parse(context.Background(), "name=gt=M")
+	assert.Error(t, err)
+
+	r := NewValidatorRegistry().ReplaceBuiltin(ComparisonGt, func(p Predicate) error { return nil })
+	p := New(WithValidators(r))
+	e, err := p.Parse(context.Background(), "name=gt=M")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "name=gt=M", e.ToFIQL())
+	}
+	// only the replaced comparison is relaxed
+	_, err = p.Parse(context.Background(), "name=lt=M")
+	assert.Error(t, err)
+}
+
+func TestWithValidatorsSkipsReferences(t *testing.T) {
+	p := New(WithValidators(testValidators()), WithFieldReferences(), WithParameters())
+	_, err := p.Parse(context.Background(), "id==@other;id==:id")
+	assert.NoError(t, err)
+}
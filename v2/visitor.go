@@ -0,0 +1,261 @@
+package fiqlparser
+
+// Visitor is the stable visitor interface superseding NodeVisitor, see Visit.
+// Every method returns an error, the first error stops the traversal and is returned by Visit.
+//
+// Unlike NodeVisitor, logical operations and comparisons are entered and left (EnterBinary, LeaveBinary),
+// expressions carry their label and tuple arguments (e.g. of `=in=`) are visited element by element.
+// Embed BaseVisitor to implement only the methods of interest,
+// existing NodeVisitor implementations can be adapted with AdaptNodeVisitor.
+type Visitor interface {
+	// EnterExpression is called when a expression is entered
+	EnterExpression(ctx ExpressionContext) error
+	// LeaveExpression is called when a expression is left
+	LeaveExpression(ctx ExpressionContext) error
+	// EnterBinary is called before the operands of a logical operation or a comparison are visited
+	EnterBinary(ctx BinaryContext) error
+	// LeaveBinary is called after the operands of a logical operation or a comparison are visited
+	LeaveBinary(ctx BinaryContext) error
+	// VisitOperator is called between the operands of a logical operation,
+	// for OperatorNOT it is called before the operand
+	VisitOperator(ctx OperatorContext) error
+	// VisitSelector is called when a selector is visited
+	VisitSelector(ctx SelectorContext) error
+	// VisitComparison is called between the selector and the argument of a comparison
+	VisitComparison(ctx ComparisonContext) error
+	// VisitArgument is called when a argument is visited, for tuple arguments it is called for every element
+	VisitArgument(ctx ArgumentContext) error
+	// EnterTuple is called before the elements of a tuple argument are visited
+	EnterTuple(ctx TupleContext) error
+	// LeaveTuple is called after the elements of a tuple argument are visited
+	LeaveTuple(ctx TupleContext) error
+}
+
+// ExpressionContext contains the expression details
+type ExpressionContext struct {
+	label string
+	root  bool
+}
+
+// Label returns the label of the expression, empty if it has none
+func (c ExpressionContext) Label() string {
+	return c.label
+}
+
+// IsRoot indicates the root expression
+func (c ExpressionContext) IsRoot() bool {
+	return c.root
+}
+
+// BinaryContext contains the details of a logical operation or a comparison
+type BinaryContext struct {
+	node BinaryNode
+}
+
+// Operator returns the operator, either a Operator or a Comparison
+func (c BinaryContext) Operator() string {
+	return c.node.Operator()
+}
+
+// IsLogical indicates a logical operation (AND, OR) instead of a comparison
+func (c BinaryContext) IsLogical() bool {
+	return c.node.IsLogical()
+}
+
+// Node returns the visited node
+func (c BinaryContext) Node() BinaryNode {
+	return c.node
+}
+
+// TupleContext contains the tuple argument details
+type TupleContext struct {
+	arg ArgumentContext
+}
+
+// Argument returns the whole tuple argument
+func (c TupleContext) Argument() ArgumentContext {
+	return c.arg
+}
+
+// Len returns the number of elements
+func (c TupleContext) Len() int {
+	return len(c.arg.tuple.elements)
+}
+
+// Delimiters returns the delimiters the tuple was written with
+func (c TupleContext) Delimiters() TupleDelimiters {
+	return c.arg.tuple.delimiters
+}
+
+// Visit traverses the tree in the same order as Accept and calls the visitor,
+// the first error returned by the visitor stops the traversal and is returned
+func Visit(n Node, visitor Visitor) error {
+	switch node := n.(type) {
+	case *Expression:
+		if node == nil {
+			return nil
+		}
+		ctx := ExpressionContext{label: node.label, root: node.isRoot()}
+		if err := visitor.EnterExpression(ctx); err != nil {
+			return err
+		}
+		if err := Visit(node.node, visitor); err != nil {
+			return err
+		}
+		return visitor.LeaveExpression(ctx)
+	case *binaryExpression:
+		if node == nil {
+			return nil
+		}
+		ctx := BinaryContext{node: node}
+		if err := visitor.EnterBinary(ctx); err != nil {
+			return err
+		}
+		if err := Visit(node.nodes[0], visitor); err != nil {
+			return err
+		}
+		var err error
+		if node.IsLogical() {
+			err = visitor.VisitOperator(OperatorContext{op: Operator(node.operator)})
+		} else {
+			err = visitor.VisitComparison(ComparisonContext{comparison: Comparison(node.operator)})
+		}
+		if err != nil {
+			return err
+		}
+		if err := Visit(node.nodes[1], visitor); err != nil {
+			return err
+		}
+		return visitor.LeaveBinary(ctx)
+	case *notExpression:
+		if node == nil {
+			return nil
+		}
+		if err := visitor.VisitOperator(OperatorContext{op: OperatorNOT}); err != nil {
+			return err
+		}
+		return Visit(node.node, visitor)
+	case *constantExpression:
+		if node == nil {
+			return nil
+		}
+		if node.selector {
+			return visitor.VisitSelector(SelectorContext{unary: node.unary, selector: node.value, separator: node.pathSeparator})
+		}
+		if node.tuple == nil {
+			return visitor.VisitArgument(node.Argument())
+		}
+		ctx := TupleContext{arg: node.Argument()}
+		if err := visitor.EnterTuple(ctx); err != nil {
+			return err
+		}
+		for _, el := range node.tuple.elements {
+			if err := visitor.VisitArgument(el.Argument()); err != nil {
+				return err
+			}
+		}
+		return visitor.LeaveTuple(ctx)
+	}
+	return nil
+}
+
+// Visit traverses the expression with the visitor, see Visit
+func (e *Expression) Visit(visitor Visitor) error {
+	return Visit(e, visitor)
+}
+
+// BaseVisitor implements Visitor without doing anything,
+// embed it to implement only the methods of interest
+type BaseVisitor struct{}
+
+var _ Visitor = BaseVisitor{}
+
+// EnterExpression does nothing
+func (BaseVisitor) EnterExpression(ExpressionContext) error { return nil }
+
+// LeaveExpression does nothing
+func (BaseVisitor) LeaveExpression(ExpressionContext) error { return nil }
+
+// EnterBinary does nothing
+func (BaseVisitor) EnterBinary(BinaryContext) error { return nil }
+
+// LeaveBinary does nothing
+func (BaseVisitor) LeaveBinary(BinaryContext) error { return nil }
+
+// VisitOperator does nothing
+func (BaseVisitor) VisitOperator(OperatorContext) error { return nil }
+
+// VisitSelector does nothing
+func (BaseVisitor) VisitSelector(SelectorContext) error { return nil }
+
+// VisitComparison does nothing
+func (BaseVisitor) VisitComparison(ComparisonContext) error { return nil }
+
+// VisitArgument does nothing
+func (BaseVisitor) VisitArgument(ArgumentContext) error { return nil }
+
+// EnterTuple does nothing
+func (BaseVisitor) EnterTuple(TupleContext) error { return nil }
+
+// LeaveTuple does nothing
+func (BaseVisitor) LeaveTuple(TupleContext) error { return nil }
+
+// AdaptNodeVisitor adapts a NodeVisitor to Visitor, so code written against Visitor can drive existing visitors.
+// The NodeVisitor is called exactly like by Accept (including VisitLabel of a LabelVisitor),
+// tuple arguments are passed as a single argument and the adapter never returns an error.
+func AdaptNodeVisitor(visitor NodeVisitor) Visitor {
+	return &nodeVisitorAdapter{visitor: visitor}
+}
+
+type nodeVisitorAdapter struct {
+	BaseVisitor
+	visitor NodeVisitor
+	// tuple is set while the elements of a tuple are visited
+	tuple bool
+}
+
+func (a *nodeVisitorAdapter) EnterExpression(ctx ExpressionContext) error {
+	a.visitor.VisitExpressionEntered()
+	if lv, ok := a.visitor.(LabelVisitor); ok && ctx.label != "" {
+		lv.VisitLabel(ctx.label)
+	}
+	return nil
+}
+
+func (a *nodeVisitorAdapter) LeaveExpression(ExpressionContext) error {
+	a.visitor.VisitExpressionLeft()
+	return nil
+}
+
+func (a *nodeVisitorAdapter) VisitOperator(ctx OperatorContext) error {
+	a.visitor.VisitOperator(ctx)
+	return nil
+}
+
+func (a *nodeVisitorAdapter) VisitSelector(ctx SelectorContext) error {
+	a.visitor.VisitSelector(ctx)
+	return nil
+}
+
+func (a *nodeVisitorAdapter) VisitComparison(ctx ComparisonContext) error {
+	a.visitor.VisitComparison(ctx)
+	return nil
+}
+
+func (a *nodeVisitorAdapter) VisitArgument(ctx ArgumentContext) error {
+	if !a.tuple {
+		a.visitor.VisitArgument(ctx)
+	}
+	return nil
+}
+
+func (a *nodeVisitorAdapter) EnterTuple(ctx TupleContext) error {
+	a.visitor.VisitArgument(ctx.Argument())
+	a.tuple = true
+	return nil
+}
+
+func (a *nodeVisitorAdapter) LeaveTuple(TupleContext) error {
+	a.tuple = false
+	return nil
+}
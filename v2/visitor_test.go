@@ -0,0 +1,142 @@
+package fiqlparser
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingVisitor struct {
+	events []string
+	failOn string
+}
+
+func (r *recordingVisitor) record(event string) error {
+	r.events = append(r.events, event)
+	if r.failOn != "" && event == r.failOn {
+		return errors.New("stop at " + event)
+	}
+	return nil
+}
+
+func (r *recordingVisitor) EnterExpression(ctx ExpressionContext) error {
+	if ctx.IsRoot() {
+		return r.record("enter root")
+	}
+	return r.record("enter " + ctx.Label())
+}
+func (r *recordingVisitor) LeaveExpression(ctx ExpressionContext) error {
+	return r.record("leave")
+}
+func (r *recordingVisitor) EnterBinary(ctx BinaryContext) error {
+	return r.record("enter binary " + ctx.Operator())
+}
+func (r *recordingVisitor) LeaveBinary(ctx BinaryContext) error {
+	return r.record("leave binary " + ctx.Operator())
+}
+func (r *recordingVisitor) VisitOperator(ctx OperatorContext) error {
+	return r.record("operator " + string(ctx.Operator()))
+}
+func (r *recordingVisitor) VisitSelector(ctx SelectorContext) error {
+	return r.record("selector " + ctx.Selector())
+}
+func (r *recordingVisitor) VisitComparison(ctx ComparisonContext) error {
+	return r.record("comparison " + string(ctx.Comparison()))
+}
+func (r *recordingVisitor) VisitArgument(ctx ArgumentContext) error {
+	return r.record("argument " + ctx.AsString())
+}
+func (r *recordingVisitor) EnterTuple(ctx TupleContext) error {
+	return r.record("enter tuple " + ctx.Argument().AsString())
+}
+func (r *recordingVisitor) LeaveTuple(ctx TupleContext) error {
+	return r.record("leave tuple")
+}
+
+func TestVisit(t *testing.T) {
+	res, err := New(WithNegation()).Parse(context.Background(), "a==1;x:(b=in=[c+d]),!(e)")
+	if !assert.NoError(t, err) {
+		return
+	}
+	v := &recordingVisitor{}
+	assert.NoError(t, res.Visit(v))
+	assert.Equal(t, []string{
+		"enter root",
+		"enter binary OR",
+		"enter binary AND",
+		"enter binary ==",
+		"selector a", "comparison ==", "argument 1",
+		"leave binary ==",
+		"operator AND",
+		"enter x",
+		"enter binary IN",
+		"selector b", "comparison IN",
+		"enter tuple [c+d]", "argument c", "argument d", "leave tuple",
+		"leave binary IN",
+		"leave",
+		"leave binary AND",
+		"operator OR",
+		"operator NOT",
+		"enter ", "selector e", "leave",
+		"leave binary OR",
+		"leave",
+	}, v.events)
+}
+
+func TestVisitStopsOnError(t *testing.T) {
+	res, err := Parse(context.Background(), "a==1;b=in=[c+d];e==f")
+	if !assert.NoError(t, err) {
+		return
+	}
+	v := &recordingVisitor{failOn: "argument c"}
+	assert.EqualError(t, res.Visit(v), "stop at argument c")
+	assert.Equal(t, "argument c", v.events[len(v.events)-1])
+	for _, e := range v.events {
+		assert.False(t, strings.HasPrefix(e, "selector e"))
+	}
+}
+
+type selectorCollector struct {
+	BaseVisitor
+	names []string
+}
+
+func (c *selectorCollector) VisitSelector(ctx SelectorContext) error {
+	c.names = append(c.names, ctx.Selector())
+	return nil
+}
+
+func TestVisitBaseVisitor(t *testing.T) {
+	res, err := Parse(context.Background(), "a==1;(b==2,c)")
+	if !assert.NoError(t, err) {
+		return
+	}
+	v := &selectorCollector{}
+	assert.NoError(t, Visit(&res, v))
+	assert.Equal(t, []string{"a", "b", "c"}, v.names)
+}
+
+func TestAdaptNodeVisitor(t *testing.T) {
+	inputs := []string{
+		"a==1",
+		"urgent:(status==open;priority==high)",
+		"a==b,urgent:(status==open)",
+		"a=in=[x+y];b=bt=[1+2];c",
+		"!(a==1;b==2),c==*d*",
+	}
+	p := New(WithNegation())
+	for _, input := range inputs {
+		res, err := p.Parse(context.Background(), input)
+		if !assert.NoError(t, err, input) {
+			continue
+		}
+		accepted := &labelVisitor{}
+		res.Accept(accepted)
+		adapted := &labelVisitor{}
+		assert.NoError(t, res.Visit(AdaptNodeVisitor(adapted)), input)
+		assert.Equal(t, accepted.String(), adapted.String(), input)
+	}
+}
@@ -0,0 +1,58 @@
+package fiqlparser
+
+// Walk traverses the tree in pre-order (parents before their children, operands from left to right)
+// and calls fn for every node, the walk stops as soon as fn returns false
+func Walk(n Node, fn func(n Node) bool) {
+	walk(n, fn)
+}
+
+func walk(n Node, fn func(n Node) bool) bool {
+	if n == nil {
+		return true
+	}
+	if !fn(n) {
+		return false
+	}
+	for _, c := range n.Children() {
+		if !walk(c, fn) {
+			return false
+		}
+	}
+	return true
+}
+
+// Predicate is a comparison of a selector with a argument (e.g. `age=gt=18`) or a unary selector (e.g. `deleted`)
+type Predicate struct {
+	Selector string
+	// Comparison is empty for unary selectors
+	Comparison Comparison
+	// Argument is the zero value for unary selectors
+	Argument ArgumentContext
+	// Node is the comparison (BinaryNode) or the unary selector (ConstantNode)
+	Node Node
+}
+
+// IsUnary indicates a selector without constraint
+func (p Predicate) IsUnary() bool {
+	return p.Comparison == ""
+}
+
+// predicateOf returns the predicate if the node is a comparison or a unary selector
+func predicateOf(n Node) (Predicate, bool) {
+	switch node := n.(type) {
+	case *binaryExpression:
+		if isLogicalOperator(node.operator) {
+			return Predicate{}, false
+		}
+		sel, arg, ok := predicateOperands(node)
+		if !ok {
+			return Predicate{}, false
+		}
+		return Predicate{Selector: sel.value, Comparison: Comparison(node.operator), Argument: arg.Argument(), Node: node}, true
+	case *constantExpression:
+		if node.unary {
+			return Predicate{Selector: node.value, Node: node}, true
+		}
+	}
+	return Predicate{}, false
+}
@@ -0,0 +1,30 @@
+//go:build go1.23
+
+package fiqlparser
+
+import "iter"
+
+// Nodes returns a iterator over all nodes of the expression in the order of Walk,
+// including the expression itself
+func (e *Expression) Nodes() iter.Seq[Node] {
+	return func(yield func(Node) bool) {
+		Walk(e, yield)
+	}
+}
+
+// All is the same as Nodes
+func (e *Expression) All() iter.Seq[Node] {
+	return e.Nodes()
+}
+
+// Predicates returns a iterator over all comparisons and unary selectors in the order of Walk
+func (e *Expression) Predicates() iter.Seq[Predicate] {
+	return func(yield func(Predicate) bool) {
+		Walk(e, func(n Node) bool {
+			if p, ok := predicateOf(n); ok {
+				return yield(p)
+			}
+			return true
+		})
+	}
+}
@@ -0,0 +1,63 @@
+//go:build go1.23
+
+package fiqlparser
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAll(t *testing.T) {
+	res, err := Parse(context.Background(), "a==1;(b==2,c)")
+	if !assert.NoError(t, err) {
+		return
+	}
+	selectors := make([]string, 0)
+	for n := range res.All() {
+		if c, ok := n.(ConstantNode); ok && c.IsSelector() {
+			selectors = append(selectors, c.Value())
+			if len(selectors) == 2 {
+				break
+			}
+		}
+	}
+	assert.Equal(t, []string{"a", "b"}, selectors)
+}
+
+func TestNodes(t *testing.T) {
+	res, err := Parse(context.Background(), "a==1;(b==2,c)")
+	if !assert.NoError(t, err) {
+		return
+	}
+	count := 0
+	for n := range res.Nodes() {
+		count++
+		if n.NodeType() == NodeTypeUnary {
+			break
+		}
+	}
+	assert.Equal(t, 11, count)
+}
+
+func TestPredicates(t *testing.T) {
+	res, err := Parse(context.Background(), "a==1;(b=gt=2,c);d=in=[x+y]")
+	if !assert.NoError(t, err) {
+		return
+	}
+	predicates := make([]string, 0)
+	for p := range res.Predicates() {
+		if p.IsUnary() {
+			predicates = append(predicates, p.Selector)
+			continue
+		}
+		predicates = append(predicates, p.Selector+" "+string(p.Comparison)+" "+p.Argument.AsString())
+	}
+	assert.Equal(t, []string{"a == 1", "b > 2", "c", "d IN [x+y]"}, predicates)
+
+	for p := range res.Predicates() {
+		assert.Equal(t, "a", p.Selector)
+		break
+	}
+}
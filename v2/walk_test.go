@@ -0,0 +1,44 @@
+package fiqlparser
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWalk(t *testing.T) {
+	res, err := Parse(context.Background(), "a==1;(b==2,c)")
+	if !assert.NoError(t, err) {
+		return
+	}
+	types := make([]NodeType, 0)
+	Walk(&res, func(n Node) bool {
+		types = append(types, n.NodeType())
+		return true
+	})
+	assert.Equal(t, []NodeType{
+		NodeTypeExpression, NodeTypeBinary, NodeTypeBinary, NodeTypeConstant, NodeTypeConstant,
+		NodeTypeExpression, NodeTypeBinary, NodeTypeBinary, NodeTypeConstant, NodeTypeConstant, NodeTypeUnary,
+	}, types)
+
+	// early exit at the first selector
+	var found Node
+	visited := 0
+	Walk(&res, func(n Node) bool {
+		visited++
+		if c, ok := n.(ConstantNode); ok && c.IsSelector() {
+			found = n
+			return false
+		}
+		return true
+	})
+	assert.Equal(t, 4, visited)
+	assert.Equal(t, "a", found.(ConstantNode).Value())
+
+	empty := Expression{root: true}
+	Walk(&empty, func(n Node) bool {
+		assert.Equal(t, NodeTypeExpression, n.NodeType())
+		return true
+	})
+}
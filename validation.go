@@ -0,0 +1,15 @@
+package fiqlparser
+
+import v2 "github.com/eisenwinter/fiql-parser/v2"
+
+// ValidationOptions configures Schema.ValidateDetailed
+type ValidationOptions = v2.ValidationOptions
+
+// ValidationIssue is a violation of the schema reported by Schema.ValidateDetailed
+type ValidationIssue = v2.ValidationIssue
+
+// Coercion is a argument converted to the type of its selector, see ValidationOptions.Coerce
+type Coercion = v2.Coercion
+
+// ValidationResult is the outcome of Schema.ValidateDetailed
+type ValidationResult = v2.ValidationResult
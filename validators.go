@@ -0,0 +1,27 @@
+package fiqlparser
+
+import v2 "github.com/eisenwinter/fiql-parser/v2"
+
+// ValueValidator validates the argument of a comparison at parse time, e.g. the format of ids,
+// the returned error is wrapped by the positioned ParseError (ErrorCodeInvalidValue)
+type ValueValidator = v2.ValueValidator
+
+// ValidatorRegistry holds the value validators attached to comparisons and selectors, see WithValidators.
+// It must not be modified once it is passed to a parser.
+type ValidatorRegistry = v2.ValidatorRegistry
+
+// NewValidatorRegistry creates a empty registry
+//
+// Deprecated: use NewValidatorRegistry of github.com/eisenwinter/fiql-parser/v2.
+func NewValidatorRegistry() *ValidatorRegistry {
+	return v2.NewValidatorRegistry()
+}
+
+// WithValidators validates the arguments of all comparisons with the validators of r while parsing.
+// The validators of the comparison are called before the ones of the selector, the first error aborts the parse.
+// Field references and named parameters are not validated.
+//
+// Deprecated: use WithValidators of github.com/eisenwinter/fiql-parser/v2.
+func WithValidators(r *ValidatorRegistry) Option {
+	return v2.WithValidators(r)
+}
@@ -0,0 +1,42 @@
+package fiqlparser
+
+import v2 "github.com/eisenwinter/fiql-parser/v2"
+
+// Visitor is the stable visitor interface superseding NodeVisitor, see Visit.
+// Every method returns an error, the first error stops the traversal and is returned by Visit.
+//
+// Unlike NodeVisitor, logical operations and comparisons are entered and left (EnterBinary, LeaveBinary),
+// expressions carry their label and tuple arguments (e.g. of `=in=`) are visited element by element.
+// Embed BaseVisitor to implement only the methods of interest,
+// existing NodeVisitor implementations can be adapted with AdaptNodeVisitor.
+type Visitor = v2.Visitor
+
+// ExpressionContext contains the expression details
+type ExpressionContext = v2.ExpressionContext
+
+// BinaryContext contains the details of a logical operation or a comparison
+type BinaryContext = v2.BinaryContext
+
+// TupleContext contains the tuple argument details
+type TupleContext = v2.TupleContext
+
+// Visit traverses the tree in the same order as Accept and calls the visitor,
+// the first error returned by the visitor stops the traversal and is returned
+//
+// Deprecated: use Visit of github.com/eisenwinter/fiql-parser/v2.
+func Visit(n Node, visitor Visitor) error {
+	return v2.Visit(n, visitor)
+}
+
+// BaseVisitor implements Visitor without doing anything,
+// embed it to implement only the methods of interest
+type BaseVisitor = v2.BaseVisitor
+
+// AdaptNodeVisitor adapts a NodeVisitor to Visitor, so code written against Visitor can drive existing visitors.
+// The NodeVisitor is called exactly like by Accept (including VisitLabel of a LabelVisitor),
+// tuple arguments are passed as a single argument and the adapter never returns an error.
+//
+// Deprecated: use AdaptNodeVisitor of github.com/eisenwinter/fiql-parser/v2.
+func AdaptNodeVisitor(visitor NodeVisitor) Visitor {
+	return v2.AdaptNodeVisitor(visitor)
+}
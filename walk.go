@@ -0,0 +1,14 @@
+package fiqlparser
+
+import v2 "github.com/eisenwinter/fiql-parser/v2"
+
+// Walk traverses the tree in pre-order (parents before their children, operands from left to right)
+// and calls fn for every node, the walk stops as soon as fn returns false
+//
+// Deprecated: use Walk of github.com/eisenwinter/fiql-parser/v2.
+func Walk(n Node, fn func(n Node) bool) {
+	v2.Walk(n, fn)
+}
+
+// Predicate is a comparison of a selector with a argument (e.g. `age=gt=18`) or a unary selector (e.g. `deleted`)
+type Predicate = v2.Predicate